@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -33,10 +34,91 @@ func NewAnalyticsClient(accountID, authEmail, authKey, authToken string) *Analyt
 }
 
 // TimeRange defines a time window for analytics queries.
+// Presets are relative (the last Duration up to now); custom ranges created
+// with CustomTimeRange are absolute and carry explicit Start/End times.
 type TimeRange struct {
 	Label    string        // Display label (e.g. "1h", "6h", "24h", "7d", "30d")
 	Duration time.Duration // How far back to query
 	GroupBy  string        // GraphQL groupBy field (e.g. "datetimeMinute", "datetimeHour")
+	Start    time.Time     // Absolute window start (zero for relative presets)
+	End      time.Time     // Absolute window end (zero for relative presets)
+}
+
+// IsAbsolute reports whether the range has explicit Start/End times.
+func (tr TimeRange) IsAbsolute() bool {
+	return !tr.Start.IsZero() && !tr.End.IsZero()
+}
+
+// Window returns the [since, until] interval the range covers, in UTC.
+// Relative presets are resolved against now.
+func (tr TimeRange) Window(now time.Time) (since, until time.Time) {
+	if tr.IsAbsolute() {
+		return tr.Start.UTC(), tr.End.UTC()
+	}
+	now = now.UTC()
+	return now.Add(-tr.Duration), now
+}
+
+// Previous returns the window of equal length immediately preceding this one,
+// used for period-over-period comparison. The result is always absolute.
+func (tr TimeRange) Previous(now time.Time) TimeRange {
+	since, until := tr.Window(now)
+	d := until.Sub(since)
+	return TimeRange{
+		Label:    "prev " + tr.Label,
+		Duration: d,
+		GroupBy:  tr.GroupBy,
+		Start:    since.Add(-d),
+		End:      since,
+	}
+}
+
+// CustomTimeRange builds an absolute time range between start and end,
+// choosing a GroupBy granularity that keeps the bucket count chartable.
+func CustomTimeRange(start, end time.Time) TimeRange {
+	d := end.Sub(start)
+	return TimeRange{
+		Label:    formatRangeLabel(start, end),
+		Duration: d,
+		GroupBy:  groupByFor(d),
+		Start:    start,
+		End:      end,
+	}
+}
+
+// groupByFor picks the coarsest-needed datetime dimension for a window length,
+// mirroring the presets (1h→minute, 6h→5min, 24h→15min, longer→hour).
+func groupByFor(d time.Duration) string {
+	switch {
+	case d <= 2*time.Hour:
+		return "datetimeMinute"
+	case d <= 12*time.Hour:
+		return "datetimeFiveMinutes"
+	case d <= 3*24*time.Hour:
+		return "datetimeFifteenMinutes"
+	default:
+		return "datetimeHour"
+	}
+}
+
+// formatRangeLabel renders a compact label for an absolute range, omitting the
+// date on the end time when both fall on the same day.
+func formatRangeLabel(start, end time.Time) string {
+	start, end = start.Local(), end.Local()
+	if start.Year() == end.Year() && start.YearDay() == end.YearDay() {
+		return start.Format("Jan 02 15:04") + "–" + end.Format("15:04")
+	}
+	return start.Format("Jan 02 15:04") + "–" + end.Format("Jan 02 15:04")
+}
+
+// datetimeDimension returns the GraphQL dimension selection for the range's
+// bucket granularity, aliased to "datetime" so the response shape is the same
+// for every granularity.
+func (tr TimeRange) datetimeDimension() string {
+	if tr.GroupBy == "" {
+		return "datetime"
+	}
+	return "datetime: " + tr.GroupBy
 }
 
 // Predefined time ranges for the analytics dashboard.
@@ -51,7 +133,10 @@ var TimeRanges = []TimeRange{
 // WorkerMetrics holds the aggregated analytics data for a single worker.
 type WorkerMetrics struct {
 	ScriptName string
+	VersionID  string // set only for per-version breakdowns (FetchWorkerVersionMetrics)
 	TimeRange  TimeRange
+	Since      time.Time // resolved query window (UTC)
+	Until      time.Time
 
 	// Totals
 	TotalRequests    int64
@@ -117,9 +202,10 @@ type analyticsData struct {
 
 type adaptiveBucket struct {
 	Dimensions struct {
-		Datetime   string `json:"datetime"`
-		ScriptName string `json:"scriptName"`
-		Status     string `json:"status"`
+		Datetime      string `json:"datetime"`
+		ScriptName    string `json:"scriptName"`
+		ScriptVersion string `json:"scriptVersion"`
+		Status        string `json:"status"`
 	} `json:"dimensions"`
 	Sum struct {
 		Requests    int64 `json:"requests"`
@@ -132,6 +218,8 @@ type adaptiveBucket struct {
 	} `json:"quantiles"`
 }
 
+// The analytics queries below take the datetime dimension selection as a
+// format argument (see TimeRange.datetimeDimension).
 const analyticsQuery = `
 query WorkerAnalytics($accountTag: String!, $scriptName: String!, $since: Time!, $until: Time!) {
   viewer {
@@ -146,7 +234,7 @@ query WorkerAnalytics($accountTag: String!, $scriptName: String!, $since: Time!,
         limit: 10000
       ) {
         dimensions {
-          datetime
+          %s
           scriptName
           status
         }
@@ -167,17 +255,16 @@ query WorkerAnalytics($accountTag: String!, $scriptName: String!, $since: Time!,
 
 // FetchWorkerMetrics queries the Cloudflare GraphQL Analytics API for a single worker's metrics.
 func (c *AnalyticsClient) FetchWorkerMetrics(ctx context.Context, scriptName string, tr TimeRange) (*WorkerMetrics, error) {
-	now := time.Now().UTC()
-	since := now.Add(-tr.Duration)
+	since, until := tr.Window(time.Now())
 
 	variables := map[string]interface{}{
 		"accountTag": c.accountID,
 		"scriptName": scriptName,
 		"since":      since.Format(time.RFC3339),
-		"until":      now.Format(time.RFC3339),
+		"until":      until.Format(time.RFC3339),
 	}
 
-	body, err := c.doGraphQL(ctx, analyticsQuery, variables)
+	body, err := c.doGraphQL(ctx, fmt.Sprintf(analyticsQuery, tr.datetimeDimension()), variables)
 	if err != nil {
 		return nil, err
 	}
@@ -187,12 +274,230 @@ func (c *AnalyticsClient) FetchWorkerMetrics(ctx context.Context, scriptName str
 		return nil, fmt.Errorf("parsing analytics response: %w", err)
 	}
 
+	var buckets []adaptiveBucket
+	if len(data.Viewer.Accounts) > 0 {
+		buckets = data.Viewer.Accounts[0].WorkersInvocationsAdaptive
+	}
+	m := buildMetrics(scriptName, tr, buckets)
+	m.Since, m.Until = since, until
+	return m, nil
+}
+
+const versionAnalyticsQuery = `
+query WorkerVersionAnalytics($accountTag: String!, $scriptName: String!, $since: Time!, $until: Time!) {
+  viewer {
+    accounts(filter: {accountTag: $accountTag}) {
+      workersInvocationsAdaptive(
+        filter: {
+          scriptName: $scriptName,
+          datetime_geq: $since,
+          datetime_leq: $until
+        }
+        orderBy: [datetime_ASC]
+        limit: 10000
+      ) {
+        dimensions {
+          %s
+          scriptVersion
+          status
+        }
+        sum {
+          requests
+          errors
+          subrequests
+        }
+        quantiles {
+          cpuTimeP50
+          cpuTimeP99
+        }
+      }
+    }
+  }
+}
+`
+
+// FetchWorkerVersionMetrics queries analytics for a single worker grouped by
+// scriptVersion, so versions of a gradual deployment can be compared side by
+// side. Results are sorted by total requests, busiest version first.
+func (c *AnalyticsClient) FetchWorkerVersionMetrics(ctx context.Context, scriptName string, tr TimeRange) ([]*WorkerMetrics, error) {
+	since, until := tr.Window(time.Now())
+
+	variables := map[string]interface{}{
+		"accountTag": c.accountID,
+		"scriptName": scriptName,
+		"since":      since.Format(time.RFC3339),
+		"until":      until.Format(time.RFC3339),
+	}
+
+	body, err := c.doGraphQL(ctx, fmt.Sprintf(versionAnalyticsQuery, tr.datetimeDimension()), variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var data analyticsData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("parsing analytics response: %w", err)
+	}
 	if len(data.Viewer.Accounts) == 0 {
-		return &WorkerMetrics{ScriptName: scriptName, TimeRange: tr}, nil
+		return nil, nil
+	}
+
+	byVersion := make(map[string][]adaptiveBucket)
+	var order []string
+	for _, b := range data.Viewer.Accounts[0].WorkersInvocationsAdaptive {
+		v := b.Dimensions.ScriptVersion
+		if _, ok := byVersion[v]; !ok {
+			order = append(order, v)
+		}
+		byVersion[v] = append(byVersion[v], b)
+	}
+
+	result := make([]*WorkerMetrics, 0, len(order))
+	for _, v := range order {
+		m := buildMetrics(scriptName, tr, byVersion[v])
+		m.VersionID = v
+		result = append(result, m)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].TotalRequests > result[j].TotalRequests
+	})
+	return result, nil
+}
+
+// maxScriptsPerQuery caps how many scripts are batched into one GraphQL
+// request by FetchMultiWorkerMetrics.
+const maxScriptsPerQuery = 25
+
+// analyticsRowLimit is the most rows the adaptive dataset returns per query.
+// Rows are ordered oldest first, so a truncated result loses the newest
+// buckets.
+const analyticsRowLimit = 10000
+
+// rowsPerBucket estimates how many rows one script yields per time bucket:
+// one per status seen in it, usually "success" plus the odd error.
+const rowsPerBucket = 2
+
+const multiAnalyticsQuery = `
+query MultiWorkerAnalytics($accountTag: String!, $scriptNames: [String!]!, $since: Time!, $until: Time!) {
+  viewer {
+    accounts(filter: {accountTag: $accountTag}) {
+      workersInvocationsAdaptive(
+        filter: {
+          scriptName_in: $scriptNames,
+          datetime_geq: $since,
+          datetime_leq: $until
+        }
+        orderBy: [datetime_ASC]
+        limit: %d
+      ) {
+        dimensions {
+          %s
+          scriptName
+          status
+        }
+        sum {
+          requests
+          errors
+          subrequests
+        }
+        quantiles {
+          cpuTimeP50
+          cpuTimeP99
+        }
+      }
+    }
+  }
+}
+`
+
+// bucketSize returns the length of one time bucket of a GroupBy granularity.
+func bucketSize(groupBy string) time.Duration {
+	switch groupBy {
+	case "datetimeMinute":
+		return time.Minute
+	case "datetimeFiveMinutes":
+		return 5 * time.Minute
+	case "datetimeFifteenMinutes":
+		return 15 * time.Minute
+	case "datetimeHour":
+		return time.Hour
+	}
+	// Ungrouped "datetime" rows can be as fine as a minute
+	return time.Minute
+}
+
+// scriptsPerQuery returns how many scripts fit in one query over tr without
+// the expected row count reaching analyticsRowLimit: 25 for the presets up to
+// 7d, 6 for 30d.
+func scriptsPerQuery(tr TimeRange, now time.Time) int {
+	since, until := tr.Window(now)
+	buckets := int(until.Sub(since)/bucketSize(tr.GroupBy)) + 1
+	n := analyticsRowLimit / (buckets * rowsPerBucket)
+	return max(1, min(n, maxScriptsPerQuery))
+}
+
+// FetchMultiWorkerMetrics queries analytics for several workers at once,
+// batching as many scripts into each GraphQL request as the row limit allows
+// for the range. A batch that still fills the limit is split and queried
+// again, so no script loses its newest buckets.
+// The result always contains an entry for every requested script (with zero
+// totals when the script had no invocations in the window).
+func (c *AnalyticsClient) FetchMultiWorkerMetrics(ctx context.Context, scriptNames []string, tr TimeRange) (map[string]*WorkerMetrics, error) {
+	now := time.Now()
+	batch := scriptsPerQuery(tr, now)
+
+	byScript := make(map[string][]adaptiveBucket, len(scriptNames))
+	for start := 0; start < len(scriptNames); start += batch {
+		end := min(start+batch, len(scriptNames))
+		if err := c.fetchMultiBatch(ctx, scriptNames[start:end], tr, now, byScript); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[string]*WorkerMetrics, len(scriptNames))
+	for _, name := range scriptNames {
+		result[name] = buildMetrics(name, tr, byScript[name])
+	}
+	return result, nil
+}
+
+// fetchMultiBatch queries one batch of scripts into byScript. When the batch
+// hits analyticsRowLimit it's halved and each half queried on its own; a
+// single script at the limit keeps what was returned.
+func (c *AnalyticsClient) fetchMultiBatch(ctx context.Context, scriptNames []string, tr TimeRange, now time.Time, byScript map[string][]adaptiveBucket) error {
+	since, until := tr.Window(now)
+	variables := map[string]interface{}{
+		"accountTag":  c.accountID,
+		"scriptNames": scriptNames,
+		"since":       since.Format(time.RFC3339),
+		"until":       until.Format(time.RFC3339),
+	}
+
+	body, err := c.doGraphQL(ctx, fmt.Sprintf(multiAnalyticsQuery, analyticsRowLimit, tr.datetimeDimension()), variables)
+	if err != nil {
+		return err
 	}
 
-	buckets := data.Viewer.Accounts[0].WorkersInvocationsAdaptive
-	return buildMetrics(scriptName, tr, buckets), nil
+	var data analyticsData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("parsing analytics response: %w", err)
+	}
+	if len(data.Viewer.Accounts) == 0 {
+		return nil
+	}
+	rows := data.Viewer.Accounts[0].WorkersInvocationsAdaptive
+	if len(rows) >= analyticsRowLimit && len(scriptNames) > 1 {
+		half := len(scriptNames) / 2
+		if err := c.fetchMultiBatch(ctx, scriptNames[:half], tr, now, byScript); err != nil {
+			return err
+		}
+		return c.fetchMultiBatch(ctx, scriptNames[half:], tr, now, byScript)
+	}
+	for _, b := range rows {
+		name := b.Dimensions.ScriptName
+		byScript[name] = append(byScript[name], b)
+	}
+	return nil
 }
 
 func buildMetrics(scriptName string, tr TimeRange, raw []adaptiveBucket) *WorkerMetrics {
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScriptsPerQuery(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	preset := func(label string) TimeRange {
+		for _, tr := range TimeRanges {
+			if tr.Label == label {
+				return tr
+			}
+		}
+		t.Fatalf("no preset %q", label)
+		return TimeRange{}
+	}
+	custom := func(d time.Duration) TimeRange {
+		return CustomTimeRange(now.Add(-d), now)
+	}
+
+	tests := []struct {
+		name string
+		tr   TimeRange
+		want int
+	}{
+		{"1h", preset("1h"), 25},   // 61 minute buckets
+		{"6h", preset("6h"), 25},   // 73 five-minute buckets
+		{"24h", preset("24h"), 25}, // 97 fifteen-minute buckets
+		{"7d", preset("7d"), 25},   // 169 hour buckets: 29 would fit
+		{"30d", preset("30d"), 6},  // 721 hour buckets
+		{"custom 3d", custom(3 * 24 * time.Hour), 17},
+		{"custom 90d", custom(90 * 24 * time.Hour), 2},
+		{"custom 2y", custom(2 * 365 * 24 * time.Hour), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scriptsPerQuery(tt.tr, now)
+			if got != tt.want {
+				t.Errorf("scriptsPerQuery = %d, want %d", got, tt.want)
+			}
+			// The expected rows of a full batch stay under the limit
+			since, until := tt.tr.Window(now)
+			buckets := int(until.Sub(since)/bucketSize(tt.tr.GroupBy)) + 1
+			if got > 1 && got*buckets*rowsPerBucket >= analyticsRowLimit {
+				t.Errorf("%d scripts × %d buckets reach the row limit", got, buckets)
+			}
+		})
+	}
+}
+
+// roundTripFunc fakes the GraphQL endpoint.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestFetchMultiWorkerMetricsSplitsFullBatches checks that a batch that
+// returns analyticsRowLimit rows is split, and that only the rows of the
+// split queries end up in the result.
+func TestFetchMultiWorkerMetricsSplitsFullBatches(t *testing.T) {
+	var batches [][]string
+	c := NewAnalyticsClient("acct", "", "", "token")
+	c.http = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var req struct {
+			Query     string `json:"query"`
+			Variables struct {
+				ScriptNames []string `json:"scriptNames"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, err
+		}
+		if !strings.Contains(req.Query, fmt.Sprintf("limit: %d", analyticsRowLimit)) {
+			t.Errorf("query without the row limit:\n%s", req.Query)
+		}
+		names := req.Variables.ScriptNames
+		batches = append(batches, names)
+
+		// More than two scripts fill the limit with rows of the first one;
+		// otherwise every script gets one bucket of 10 requests
+		var rows []map[string]any
+		row := func(name string, requests int) map[string]any {
+			return map[string]any{
+				"dimensions": map[string]any{"datetime": "2026-10-18T11:00:00Z", "scriptName": name, "status": "success"},
+				"sum":        map[string]any{"requests": requests},
+			}
+		}
+		if len(names) > 2 {
+			for range analyticsRowLimit {
+				rows = append(rows, row(names[0], 1))
+			}
+		} else {
+			for _, name := range names {
+				rows = append(rows, row(name, 10))
+			}
+		}
+		body, _ := json.Marshal(map[string]any{"data": map[string]any{"viewer": map[string]any{
+			"accounts": []any{map[string]any{"workersInvocationsAdaptive": rows}},
+		}}})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body))), Header: http.Header{}}, nil
+	})}
+
+	scripts := []string{"a", "b", "c", "d", "e"}
+	got, err := c.FetchMultiWorkerMetrics(context.Background(), scripts, TimeRanges[len(TimeRanges)-1]) // 30d: 6 per batch
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"a", "b", "c", "d", "e"}, {"a", "b"}, {"c", "d", "e"}, {"c"}, {"d", "e"}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+	for _, name := range scripts {
+		m := got[name]
+		if m == nil || m.TotalRequests != 10 || len(m.Buckets) != 1 {
+			t.Errorf("%s: got %+v, want one bucket of 10 requests", name, m)
+		}
+	}
+}
+
+func TestTimeRangePrevious(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// A preset resolves against now
+	prev := TimeRanges[2].Previous(now) // 24h
+	if !prev.IsAbsolute() || prev.Label != "prev 24h" || prev.GroupBy != "datetimeFifteenMinutes" {
+		t.Errorf("got %+v", prev)
+	}
+	if want := now.Add(-48 * time.Hour); !prev.Start.Equal(want) || !prev.End.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("got %v–%v, want %v–%v", prev.Start, prev.End, want, now.Add(-24*time.Hour))
+	}
+
+	// A custom range keeps its length and ends where it starts
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	custom := CustomTimeRange(start, start.Add(90*time.Minute))
+	prev = custom.Previous(now)
+	if !prev.Start.Equal(start.Add(-90*time.Minute)) || !prev.End.Equal(start) || prev.Duration != 90*time.Minute {
+		t.Errorf("got %+v", prev)
+	}
+	if prev.GroupBy != custom.GroupBy {
+		t.Errorf("group by %s, want %s", prev.GroupBy, custom.GroupBy)
+	}
+}
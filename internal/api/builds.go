@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -165,6 +166,20 @@ type BuildResult struct {
 	StoppedOn            *time.Time       `json:"stopped_on"`
 	BuildTriggerMetadata BuildTriggerMeta `json:"build_trigger_metadata"`
 	VersionID            string           `json:"version_id,omitempty"` // present in builds-by-version response
+	Trigger              *Trigger         `json:"trigger,omitempty"`    // the trigger that started the build
+}
+
+// Active returns true while the build is queued or running.
+func (r BuildResult) Active() bool {
+	return r.Status == "queued" || r.Status == "initializing" || r.Status == "running"
+}
+
+// State returns the build outcome once it has stopped, else its status.
+func (r BuildResult) State() string {
+	if r.Active() || r.BuildOutcome == "" {
+		return r.Status
+	}
+	return r.BuildOutcome
 }
 
 // BuildTriggerMeta contains git metadata from a CI build.
@@ -237,6 +252,36 @@ func (b *BuildsClient) ListBuilds(ctx context.Context, scriptName string, perPag
 	return resp.Result, nil
 }
 
+// GetBuild fetches a single build, e.g. to poll the status of a running one.
+// endpoint: GET /accounts/{account_id}/builds/builds/{build_uuid}
+func (b *BuildsClient) GetBuild(ctx context.Context, buildUUID string) (*BuildResult, error) {
+	body, err := b.doRequest(ctx, http.MethodGet, fmt.Sprintf("builds/builds/%s", buildUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Success bool        `json:"success"`
+		Result  BuildResult `json:"result"`
+		Errors  []cfError   `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing build: %w", err)
+	}
+	if !resp.Success && len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("builds API error: %s", resp.Errors[0].Message)
+	}
+
+	return &resp.Result, nil
+}
+
+// CancelBuild cancels a queued or running build.
+// endpoint: PUT /accounts/{account_id}/builds/builds/{build_uuid}/cancel
+func (b *BuildsClient) CancelBuild(ctx context.Context, buildUUID string) error {
+	_, err := b.doRequest(ctx, http.MethodPut, fmt.Sprintf("builds/builds/%s/cancel", buildUUID))
+	return err
+}
+
 // GetBuildsByVersionIDs fetches build data for specific version IDs.
 // endpoint: GET /accounts/{account_id}/builds/builds?version_ids=id1,id2,...
 func (b *BuildsClient) GetBuildsByVersionIDs(ctx context.Context, versionIDs []string) (map[string]BuildResult, error) {
@@ -425,6 +470,45 @@ type TriggerCreateRequest struct {
 	BuildTokenUUID string   `json:"build_token_uuid,omitempty"`
 }
 
+// normalize turns nil slices into empty ones so JSON marshals them as []
+// not null. The Builds API rejects null arrays in the request body.
+func (r *TriggerCreateRequest) normalize() {
+	if r.BranchIncludes == nil {
+		r.BranchIncludes = []string{}
+	}
+	if r.BranchExcludes == nil {
+		r.BranchExcludes = []string{}
+	}
+	if r.PathIncludes == nil {
+		r.PathIncludes = []string{}
+	}
+	if r.PathExcludes == nil {
+		r.PathExcludes = []string{}
+	}
+}
+
+// TriggerRequest returns an update request carrying all of the trigger's
+// current settings, to be modified and sent back with UpdateTrigger or
+// used to create a copy with CreateTrigger.
+func (t Trigger) TriggerRequest() TriggerCreateRequest {
+	req := TriggerCreateRequest{
+		TriggerName:    t.Name,
+		ScriptID:       t.ScriptID,
+		BranchIncludes: t.BranchIncludes,
+		BranchExcludes: t.BranchExcludes,
+		PathIncludes:   t.PathIncludes,
+		PathExcludes:   t.PathExcludes,
+		BuildCommand:   t.BuildCommand,
+		DeployCommand:  t.DeployCommand,
+		RootDirectory:  t.RootDirectory,
+		BuildTokenUUID: t.BuildTokenUUID,
+	}
+	if t.RepoConnection != nil {
+		req.RepoConnUUID = t.RepoConnection.UUID
+	}
+	return req
+}
+
 type buildTokenRequest struct {
 	Name              string `json:"build_token_name"`
 	Secret            string `json:"build_token_secret"`
@@ -573,21 +657,7 @@ func (b *BuildsClient) PutRepoConnection(ctx context.Context, req RepoConnection
 // CreateTrigger creates a new CI/CD trigger linking a worker to a repo connection.
 // endpoint: POST /accounts/{account_id}/builds/triggers
 func (b *BuildsClient) CreateTrigger(ctx context.Context, req TriggerCreateRequest) (*Trigger, error) {
-	// Normalize nil slices to empty slices so JSON marshals as [] not null.
-	// The Builds API rejects null arrays in the request body.
-	if req.BranchIncludes == nil {
-		req.BranchIncludes = []string{}
-	}
-	if req.BranchExcludes == nil {
-		req.BranchExcludes = []string{}
-	}
-	if req.PathIncludes == nil {
-		req.PathIncludes = []string{}
-	}
-	if req.PathExcludes == nil {
-		req.PathExcludes = []string{}
-	}
-
+	req.normalize()
 	payload, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling trigger: %w", err)
@@ -613,6 +683,7 @@ func (b *BuildsClient) CreateTrigger(ctx context.Context, req TriggerCreateReque
 // Only the fields present in the request body are updated.
 // endpoint: PATCH /accounts/{account_id}/builds/triggers/{trigger_uuid}
 func (b *BuildsClient) UpdateTrigger(ctx context.Context, triggerUUID string, req TriggerCreateRequest) (*Trigger, error) {
+	req.normalize()
 	payload, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling trigger update: %w", err)
@@ -636,6 +707,70 @@ func (b *BuildsClient) UpdateTrigger(ctx context.Context, triggerUUID string, re
 	return &resp.Result, nil
 }
 
+// DeleteTrigger deletes a CI/CD trigger. Builds already run are kept.
+// endpoint: DELETE /accounts/{account_id}/builds/triggers/{trigger_uuid}
+func (b *BuildsClient) DeleteTrigger(ctx context.Context, triggerUUID string) error {
+	path := fmt.Sprintf("builds/triggers/%s", triggerUUID)
+	_, err := b.doRequest(ctx, http.MethodDelete, path)
+	return err
+}
+
+// BuildEnvVar is a build-time environment variable of a trigger. Secret
+// values are write-only: the API returns them with an empty Value.
+type BuildEnvVar struct {
+	Value    string `json:"value"`
+	IsSecret bool   `json:"is_secret"`
+}
+
+type buildEnvVarsResponse struct {
+	Success bool                   `json:"success"`
+	Result  map[string]BuildEnvVar `json:"result"`
+	Errors  []cfError              `json:"errors"`
+}
+
+// GetTriggerEnvVars lists the build environment variables of a trigger.
+// endpoint: GET /accounts/{account_id}/builds/triggers/{trigger_uuid}/environment_variables
+func (b *BuildsClient) GetTriggerEnvVars(ctx context.Context, triggerUUID string) (map[string]BuildEnvVar, error) {
+	path := fmt.Sprintf("builds/triggers/%s/environment_variables", triggerUUID)
+
+	body, err := b.doRequest(ctx, http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp buildEnvVarsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing build environment variables: %w", err)
+	}
+	if !resp.Success && len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("builds API error: %s", resp.Errors[0].Message)
+	}
+
+	return resp.Result, nil
+}
+
+// SetTriggerEnvVars creates or overwrites build environment variables of a
+// trigger; variables not in vars are left untouched.
+// endpoint: PATCH /accounts/{account_id}/builds/triggers/{trigger_uuid}/environment_variables
+func (b *BuildsClient) SetTriggerEnvVars(ctx context.Context, triggerUUID string, vars map[string]BuildEnvVar) error {
+	payload, err := json.Marshal(vars)
+	if err != nil {
+		return fmt.Errorf("marshalling build environment variables: %w", err)
+	}
+
+	path := fmt.Sprintf("builds/triggers/%s/environment_variables", triggerUUID)
+	_, err = b.doRequestWithBody(ctx, http.MethodPatch, path, bytes.NewReader(payload))
+	return err
+}
+
+// DeleteTriggerEnvVar deletes a build environment variable of a trigger.
+// endpoint: DELETE /accounts/{account_id}/builds/triggers/{trigger_uuid}/environment_variables/{key}
+func (b *BuildsClient) DeleteTriggerEnvVar(ctx context.Context, triggerUUID, key string) error {
+	path := fmt.Sprintf("builds/triggers/%s/environment_variables/%s", triggerUUID, url.PathEscape(key))
+	_, err := b.doRequest(ctx, http.MethodDelete, path)
+	return err
+}
+
 // ListBuildTokens returns all registered build tokens for the account.
 // endpoint: GET /accounts/{account_id}/builds/tokens
 func (b *BuildsClient) ListBuildTokens(ctx context.Context) ([]BuildToken, error) {
@@ -673,12 +808,13 @@ type manualBuildRequest struct {
 }
 
 // CreateManualBuild triggers a manual build for a specific trigger.
-// This is useful for verifying the pipeline works without a git push.
+// This is useful for verifying the pipeline works without a git push, or for
+// retrying a build: commitHash pins the commit (empty = branch head).
 // endpoint: POST /accounts/{account_id}/builds/triggers/{trigger_uuid}/builds
-func (b *BuildsClient) CreateManualBuild(ctx context.Context, triggerUUID, branch string) (*ManualBuildResult, error) {
+func (b *BuildsClient) CreateManualBuild(ctx context.Context, triggerUUID, branch, commitHash string) (*ManualBuildResult, error) {
 	path := fmt.Sprintf("builds/triggers/%s/builds", triggerUUID)
 
-	reqBody := manualBuildRequest{Branch: branch}
+	reqBody := manualBuildRequest{Branch: branch, CommitHash: commitHash}
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling manual build request: %w", err)
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -224,7 +225,7 @@ func (a *OAuthAuth) Logout(ctx context.Context) error {
 	a.cfg.OAuthRefreshToken = ""
 	a.cfg.OAuthExpiresAt = time.Time{}
 	a.cfg.OAuthScopes = nil
-	return a.cfg.Save()
+	return a.save()
 }
 
 func (a *OAuthAuth) exchangeCode(ctx context.Context, code, verifier string) error {
@@ -295,7 +296,7 @@ func (a *OAuthAuth) doTokenRequest(ctx context.Context, data url.Values) error {
 	}
 	a.cfg.AuthMethod = config.AuthMethodOAuth
 
-	return a.cfg.Save()
+	return a.save()
 }
 
 func buildAuthURL(state, challenge string) string {
@@ -337,3 +338,13 @@ func generateRandomString(n int) (string, error) {
 	}
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
+
+// save persists the tokens. Inside the TUI a new secrets file waits for its
+// passphrase, which the app asks for once the login returns, so that isn't
+// an error here.
+func (a *OAuthAuth) save() error {
+	if err := a.cfg.Save(); err != nil && !errors.Is(err, config.ErrSecretsLocked) {
+		return err
+	}
+	return nil
+}
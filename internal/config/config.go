@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -34,7 +35,15 @@ type AIBackendType string
 const (
 	AIBackendWorkersAI AIBackendType = "workers_ai" // Default: deployed Workers AI proxy
 	AIBackendHTTP      AIBackendType = "http"       // OpenAI-compatible or OpenCode serve endpoint
-	// AIBackendLocal  AIBackendType = "local"       // Future: stdin/stdout local agent
+	AIBackendLocal     AIBackendType = "local"      // Local command speaking over stdin/stdout
+)
+
+// AILocalProtocol identifies the stdin/stdout protocol for the local backend.
+type AILocalProtocol string
+
+const (
+	AILocalProtocolJSONL AILocalProtocol = "jsonl" // Long-lived agent exchanging JSON lines
+	AILocalProtocolText  AILocalProtocol = "text"  // One process per prompt, plain text in/out
 )
 
 // AIHTTPProtocol identifies the wire protocol for the HTTP backend.
@@ -54,8 +63,70 @@ const (
 	AIModelDeep     AIModelPreset = "deep"     // deepseek-r1-distill-qwen-32b
 )
 
+// AlertSource identifies what an alert rule is evaluated against.
+type AlertSource string
+
+const (
+	AlertSourceTail      AlertSource = "tail"      // Incoming tail log lines (live, sliding window)
+	AlertSourceAnalytics AlertSource = "analytics" // Periodic GraphQL analytics polls
+)
+
+// AlertNotifyMode selects how fired alerts are signalled to the terminal,
+// in addition to the header badge and toast.
+type AlertNotifyMode string
+
+const (
+	AlertNotifyBell AlertNotifyMode = "bell" // Terminal bell (default)
+	AlertNotifyOSC9 AlertNotifyMode = "osc9" // OSC 9 desktop notification (iTerm2, WezTerm, kitty, ...)
+	AlertNotifyBoth AlertNotifyMode = "both"
+	AlertNotifyNone AlertNotifyMode = "none"
+)
+
+// AlertRule is a locally defined alert. Tail rules count matching log lines
+// in a sliding window; analytics rules compare a metric over the window with
+// the threshold. The rule fires when the value exceeds Threshold.
+//
+//	[[alerts]]
+//	name = "api exceptions"
+//	source = "tail"
+//	worker = "my-api"
+//	metric = "exceptions"
+//	threshold = 5
+//	window = "1m"
+type AlertRule struct {
+	Name   string      `toml:"name"`
+	Source AlertSource `toml:"source"`
+	Worker string      `toml:"worker,omitempty"` // script name; empty or "*" matches any tailed worker (tail rules only)
+	// Tail metrics: "exceptions", "errors" (error + exception lines), "matches" (lines containing Match).
+	// Analytics metrics: "error_rate" (%), "errors", "requests", "cpu_p99" (ms).
+	Metric    string  `toml:"metric"`
+	Match     string  `toml:"match,omitempty"`
+	Threshold float64 `toml:"threshold"`
+	Window    string  `toml:"window,omitempty"`   // Go duration; default 1m (tail) / 15m (analytics)
+	Cooldown  string  `toml:"cooldown,omitempty"` // minimum gap between firings; default 5m
+}
+
+// Workspace is a saved monorepo view: a root directory, the tag filter applied
+// to its project list and the environment selected by default.
+//
+//	[[workspaces]]
+//	name = "billing"
+//	root = "/home/me/src/shop"
+//	tags = ["billing"]
+//	env = "staging"
+type Workspace struct {
+	Name string   `toml:"name"`
+	Root string   `toml:"root"`
+	Tags []string `toml:"tags,omitempty"`
+	Env  string   `toml:"env,omitempty"`
+}
+
 // Config holds all persistent configuration for orangeshell.
 type Config struct {
+	// Name of the profile the auth settings below belong to ("" = "default").
+	// Other profiles are stored in Profiles; see UseProfile.
+	Profile string `toml:"profile,omitempty"`
+
 	// Auth settings
 	AuthMethod AuthMethod `toml:"auth_method"`
 	AccountID  string     `toml:"account_id"`
@@ -92,9 +163,40 @@ type Config struct {
 	AIHTTPModel    string         `toml:"ai_http_model,omitempty"`    // model ID for HTTP backend
 	AIHTTPAPIKey   string         `toml:"ai_http_api_key,omitempty"`  // optional API key for HTTP backend
 
+	// Local backend — a command spawned on this machine (e.g. "ollama run llama3.1").
+	AILocalCommand  string          `toml:"ai_local_command,omitempty"`
+	AILocalProtocol AILocalProtocol `toml:"ai_local_protocol,omitempty"` // "jsonl" or "text"
+
+	// Alert rules evaluated against tail streams and analytics polls.
+	Alerts      []AlertRule     `toml:"alerts,omitempty"`
+	AlertNotify AlertNotifyMode `toml:"alert_notify,omitempty"` // "bell" (default), "osc9", "both", "none"
+
+	// Saved monorepo workspaces, reopened from the launcher (ctrl+l).
+	Workspaces []Workspace `toml:"workspaces,omitempty"`
+
+	// Inactive credential profiles by name (the active one is in the fields above).
+	Profiles map[string]Profile `toml:"profiles,omitempty"`
+
+	// Git hosts whose provider can't be guessed from the hostname, e.g.
+	// GitHub Enterprise or self-hosted GitLab: host → "github", "gitlab" or "bitbucket".
+	GitHosts map[string]string `toml:"git_hosts,omitempty"`
+
+	// Where secret values are stored: "keyring", "file" or "plaintext".
+	// Empty picks the keyring if one is reachable, else the encrypted file,
+	// and records the choice on the next save.
+	SecretBackend SecretBackend `toml:"secret_backend,omitempty"`
+
+	// Store for the secret values, which Save() keeps out of config.toml
+	// unless SecretBackend is "plaintext".
+	secrets SecretStore `toml:"-"`
+
 	// Tracks which fields were set from environment variables (never serialized).
 	// Save() uses these to strip env-sourced values so they don't leak to disk.
 	envOverrides map[string]bool `toml:"-"`
+
+	// The profile that was active at load time, which the environment
+	// variable overrides apply to.
+	envProfile string `toml:"-"`
 }
 
 // configDir returns the path to ~/.orangeshell/
@@ -115,8 +217,10 @@ func ConfigPath() (string, error) {
 	return filepath.Join(dir, "config.toml"), nil
 }
 
-// Load reads the config from disk and applies environment variable overrides.
-// If the config file does not exist, it returns a zero-value Config (not an error).
+// Load reads the config from disk, fills in the secrets from the secret store
+// and applies environment variable overrides. Secrets still in config.toml in
+// plaintext are moved to the store. If the config file does not exist, it
+// returns a zero-value Config (not an error).
 func Load() (*Config, error) {
 	cfg := &Config{}
 
@@ -131,44 +235,117 @@ func Load() (*Config, error) {
 		}
 	}
 
-	// Environment variable overrides (highest priority).
-	// Track which fields come from env vars so Save() can strip them.
-	cfg.envOverrides = make(map[string]bool)
+	if err := cfg.loadSecrets(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	cfg.envProfile = cfg.ActiveProfile()
+	cfg.applyEnvOverrides()
+
+	return cfg, nil
+}
+
+// loadSecrets opens the secret store and fills in the secrets from it.
+// Plaintext values found in config.toml take precedence (they predate the
+// store or were edited in by hand) and are migrated into the store.
+func (c *Config) loadSecrets(dir string) error {
+	if c.SecretBackend == SecretBackendPlaintext {
+		return nil
+	}
+	store, backend, err := openSecretStore(c.SecretBackend, dir)
+	if err != nil {
+		return err
+	}
+
+	var stored map[string]string
+	for attempt := 0; attempt < 3; attempt++ {
+		stored, err = store.Load()
+		if !errors.Is(err, errWrongPassphrase) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read credentials from %s: %w", store.Name(), err)
+	}
+	c.secrets = store
+	c.SecretBackend = backend
+
+	plain := c.extractSecrets()
+	c.applySecrets(stored)
+	c.applySecrets(plain)
+	if len(plain) > 0 {
+		if err := c.Save(); err != nil {
+			return fmt.Errorf("failed to move credentials to %s: %w", store.Name(), err)
+		}
+	}
+	return nil
+}
+
+// SecretStoreName describes where secrets are stored, for display.
+func (c *Config) SecretStoreName() string {
+	if c.secrets == nil {
+		return "config.toml (plaintext)"
+	}
+	return c.secrets.Name()
+}
+
+// SecretsLocked reports whether a save is waiting for the passphrase of the
+// encrypted secrets file; see UnlockSecrets.
+func (c *Config) SecretsLocked() bool {
+	fs, ok := c.secrets.(*fileStore)
+	return ok && fs.locked()
+}
+
+// UnlockSecrets sets the passphrase of the encrypted secrets file; Save
+// again to store the waiting credentials. An empty passphrase gives up on
+// the waiting save, and the next save that needs the file asks again.
+func (c *Config) UnlockSecrets(passphrase string) {
+	if fs, ok := c.secrets.(*fileStore); ok {
+		fs.setPassphrase(passphrase)
+	}
+}
+
+// applyEnvOverrides applies the CLOUDFLARE_* environment variables to the
+// active credentials (highest priority) and infers the auth method from them
+// if it isn't set. Tracks which fields come from env vars so Save() can strip
+// them.
+func (c *Config) applyEnvOverrides() {
+	c.envOverrides = make(map[string]bool)
 	if v := os.Getenv("CLOUDFLARE_API_KEY"); v != "" {
-		cfg.APIKey = v
-		cfg.envOverrides["APIKey"] = true
+		c.APIKey = v
+		c.envOverrides["APIKey"] = true
 	}
 	if v := os.Getenv("CLOUDFLARE_EMAIL"); v != "" {
-		cfg.Email = v
-		cfg.envOverrides["Email"] = true
+		c.Email = v
+		c.envOverrides["Email"] = true
 	}
 	if v := os.Getenv("CLOUDFLARE_API_TOKEN"); v != "" {
-		cfg.APIToken = v
-		cfg.envOverrides["APIToken"] = true
+		c.APIToken = v
+		c.envOverrides["APIToken"] = true
 	}
 	if v := os.Getenv("CLOUDFLARE_ACCOUNT_ID"); v != "" {
-		cfg.AccountID = v
-		cfg.envOverrides["AccountID"] = true
+		c.AccountID = v
+		c.envOverrides["AccountID"] = true
 	}
 
 	// Infer auth method from env vars if not set in config
-	if cfg.AuthMethod == AuthMethodNone {
+	if c.AuthMethod == AuthMethodNone {
 		switch {
-		case cfg.APIToken != "":
-			cfg.AuthMethod = AuthMethodAPIToken
-		case cfg.APIKey != "" && cfg.Email != "":
-			cfg.AuthMethod = AuthMethodAPIKey
-		case cfg.OAuthAccessToken != "":
-			cfg.AuthMethod = AuthMethodOAuth
+		case c.APIToken != "":
+			c.AuthMethod = AuthMethodAPIToken
+		case c.APIKey != "" && c.Email != "":
+			c.AuthMethod = AuthMethodAPIKey
+		case c.OAuthAccessToken != "":
+			c.AuthMethod = AuthMethodOAuth
 		}
 	}
-
-	return cfg, nil
 }
 
 // Save writes the config to disk, creating the directory if needed.
 // Fields that were populated from environment variables are stripped before
 // writing so that secrets from env vars never leak into the config file.
+// Secret values go to the secret store rather than config.toml, unless the
+// backend is "plaintext".
 func (c *Config) Save() error {
 	dir, err := configDir()
 	if err != nil {
@@ -186,6 +363,23 @@ func (c *Config) Save() error {
 	saved := c.stripTransientFields()
 	defer c.restoreTransientFields(saved)
 
+	if c.SecretBackend != SecretBackendPlaintext {
+		if c.secrets == nil {
+			store, backend, err := openSecretStore(c.SecretBackend, dir)
+			if err != nil {
+				return err
+			}
+			c.secrets, c.SecretBackend = store, backend
+		}
+		secrets := c.extractSecrets()
+		defer c.applySecrets(secrets)
+		// Store the secrets first: if that fails, config.toml keeps its
+		// previous contents and nothing is lost
+		if err := c.secrets.Save(secrets); err != nil {
+			return fmt.Errorf("failed to save credentials to %s: %w", c.secrets.Name(), err)
+		}
+	}
+
 	path := filepath.Join(dir, "config.toml")
 	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
@@ -318,3 +512,33 @@ func (c *Config) IsConfigured() bool {
 		return false
 	}
 }
+
+// Workspace returns the saved workspace with the given name, or nil.
+func (c *Config) Workspace(name string) *Workspace {
+	for i := range c.Workspaces {
+		if c.Workspaces[i].Name == name {
+			return &c.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+// SaveWorkspace adds a workspace, replacing any existing one with the same name.
+func (c *Config) SaveWorkspace(ws Workspace) {
+	if existing := c.Workspace(ws.Name); existing != nil {
+		*existing = ws
+		return
+	}
+	c.Workspaces = append(c.Workspaces, ws)
+}
+
+// DeleteWorkspace removes the named workspace. Returns false if there was none.
+func (c *Config) DeleteWorkspace(name string) bool {
+	for i, ws := range c.Workspaces {
+		if ws.Name == name {
+			c.Workspaces = append(c.Workspaces[:i], c.Workspaces[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// d1RestoresFileName is the log of D1 Time Travel restores, kept in
+// ~/.orangeshell/ so a restore can be undone later.
+const d1RestoresFileName = "d1-restores.json"
+
+// maxD1Restores caps the restore log; the oldest entries are dropped first.
+const maxD1Restores = 200
+
+// D1Restore records one Time Travel restore of a D1 database.
+type D1Restore struct {
+	Time             time.Time `json:"time"`
+	DatabaseID       string    `json:"database_id"`
+	DatabaseName     string    `json:"database_name"`
+	Target           string    `json:"target"`            // what was asked for: a time or a bookmark
+	Bookmark         string    `json:"bookmark"`          // state after the restore
+	PreviousBookmark string    `json:"previous_bookmark"` // state before; restoring to it undoes the restore
+}
+
+// RecordD1Restore appends a restore to the log.
+func RecordD1Restore(r D1Restore) error {
+	path, err := d1RestoresPath()
+	if err != nil {
+		return err
+	}
+	all := loadD1Restores(path)
+	all = append(all, r)
+	if over := len(all) - maxD1Restores; over > 0 {
+		all = all[over:]
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// D1Restores returns the recorded restores of a database, newest first.
+func D1Restores(databaseID string) []D1Restore {
+	path, err := d1RestoresPath()
+	if err != nil {
+		return nil
+	}
+	var out []D1Restore
+	for _, r := range loadD1Restores(path) {
+		if r.DatabaseID == databaseID {
+			out = append(out, r)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	return out
+}
+
+func d1RestoresPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, d1RestoresFileName), nil
+}
+
+func loadD1Restores(path string) []D1Restore {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var all []D1Restore
+	if json.Unmarshal(data, &all) != nil {
+		return nil
+	}
+	return all
+}
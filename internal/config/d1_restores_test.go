@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestD1Restores(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, r := range []D1Restore{
+		{DatabaseID: "db1", Bookmark: "b1", PreviousBookmark: "p1"},
+		{DatabaseID: "db2", Bookmark: "b2", PreviousBookmark: "p2"},
+		{DatabaseID: "db1", Bookmark: "b3", PreviousBookmark: "p3"},
+	} {
+		r.Time = base.Add(time.Duration(i) * time.Hour)
+		if err := RecordD1Restore(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := D1Restores("db1")
+	if len(got) != 2 || got[0].Bookmark != "b3" || got[1].PreviousBookmark != "p1" {
+		t.Fatalf("got %+v", got)
+	}
+	if len(D1Restores("db3")) != 0 {
+		t.Fatal("unexpected restores for db3")
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultProfile names the credentials of a config that predates profiles.
+const DefaultProfile = "default"
+
+// Profile is a named set of credentials: its auth method, keys or tokens,
+// default account and per-account fallback tokens. The active profile's
+// credentials live in the top-level Config fields; the others are stored
+// under [profiles.<name>]:
+//
+//	profile = "acme"
+//
+//	[profiles.default]
+//	auth_method = "oauth"
+//	account_id = "..."
+//
+//	[profiles.globex]
+//	auth_method = "apitoken"
+//	api_token = "..."
+//	account_id = "..."
+type Profile struct {
+	AuthMethod AuthMethod `toml:"auth_method"`
+	AccountID  string     `toml:"account_id"`
+	Email      string     `toml:"email,omitempty"`
+	APIKey     string     `toml:"api_key,omitempty"`
+	APIToken   string     `toml:"api_token,omitempty"`
+
+	OAuthAccessToken  string    `toml:"oauth_access_token,omitempty"`
+	OAuthRefreshToken string    `toml:"oauth_refresh_token,omitempty"`
+	OAuthExpiresAt    time.Time `toml:"oauth_expires_at,omitempty"`
+	OAuthScopes       []string  `toml:"oauth_scopes,omitempty"`
+
+	FallbackTokens   map[string]string `toml:"fallback_tokens,omitempty"`
+	FallbackTokenIDs map[string]string `toml:"fallback_token_ids,omitempty"`
+}
+
+// ActiveProfile returns the name of the active profile.
+func (c *Config) ActiveProfile() string {
+	if c.Profile == "" {
+		return DefaultProfile
+	}
+	return c.Profile
+}
+
+// ProfileNames returns all profile names, sorted, including the active one.
+func (c *Config) ProfileNames() []string {
+	names := []string{c.ActiveProfile()}
+	for name := range c.Profiles {
+		if name != c.ActiveProfile() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasProfile reports whether a profile with the given name exists.
+func (c *Config) HasProfile(name string) bool {
+	if name == c.ActiveProfile() {
+		return true
+	}
+	_, ok := c.Profiles[name]
+	return ok
+}
+
+// UseProfile makes the named profile active: the current credentials are
+// stored under their profile name and the named profile's are loaded into
+// the top-level fields. Credentials from environment variables belong to the
+// profile that was active at startup and are re-applied when it is selected
+// again.
+func (c *Config) UseProfile(name string) error {
+	if name == c.ActiveProfile() {
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("no profile named %q", name)
+	}
+	c.stashProfile()
+	delete(c.Profiles, name)
+	c.setCredentials(p)
+	c.Profile = name
+	if name == c.envProfile {
+		c.applyEnvOverrides()
+	}
+	return nil
+}
+
+// NewProfile stores the current credentials under their profile name and
+// starts an empty profile with the given name, to be filled in by setup.
+func (c *Config) NewProfile(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if c.HasProfile(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	c.stashProfile()
+	c.setCredentials(Profile{})
+	c.Profile = name
+	return nil
+}
+
+// DeleteProfile removes an inactive profile. Returns false if there was none.
+func (c *Config) DeleteProfile(name string) bool {
+	if _, ok := c.Profiles[name]; !ok {
+		return false
+	}
+	delete(c.Profiles, name)
+	return true
+}
+
+// stashProfile moves the active credentials into Profiles, without the
+// values that Save would not persist either.
+func (c *Config) stashProfile() {
+	saved := c.stripTransientFields()
+	p := c.credentials()
+	c.restoreTransientFields(saved)
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[c.ActiveProfile()] = p
+	c.envOverrides = make(map[string]bool)
+}
+
+// credentials returns the active credentials as a Profile.
+func (c *Config) credentials() Profile {
+	return Profile{
+		AuthMethod:        c.AuthMethod,
+		AccountID:         c.AccountID,
+		Email:             c.Email,
+		APIKey:            c.APIKey,
+		APIToken:          c.APIToken,
+		OAuthAccessToken:  c.OAuthAccessToken,
+		OAuthRefreshToken: c.OAuthRefreshToken,
+		OAuthExpiresAt:    c.OAuthExpiresAt,
+		OAuthScopes:       c.OAuthScopes,
+		FallbackTokens:    c.FallbackTokens,
+		FallbackTokenIDs:  c.FallbackTokenIDs,
+	}
+}
+
+// setCredentials replaces the active credentials with a profile's.
+func (c *Config) setCredentials(p Profile) {
+	c.AuthMethod = p.AuthMethod
+	c.AccountID = p.AccountID
+	c.Email = p.Email
+	c.APIKey = p.APIKey
+	c.APIToken = p.APIToken
+	c.OAuthAccessToken = p.OAuthAccessToken
+	c.OAuthRefreshToken = p.OAuthRefreshToken
+	c.OAuthExpiresAt = p.OAuthExpiresAt
+	c.OAuthScopes = p.OAuthScopes
+	c.FallbackTokens = p.FallbackTokens
+	c.FallbackTokenIDs = p.FallbackTokenIDs
+}
+
+// ProfileByName returns the credentials of a profile (active or not).
+func (c *Config) ProfileByName(name string) (Profile, bool) {
+	if name == c.ActiveProfile() {
+		return c.credentials(), true
+	}
+	p, ok := c.Profiles[name]
+	return p, ok
+}
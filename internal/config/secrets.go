@@ -0,0 +1,427 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// SecretBackend selects where credentials are stored.
+type SecretBackend string
+
+const (
+	SecretBackendAuto      SecretBackend = ""          // Keyring if available, else the encrypted file
+	SecretBackendKeyring   SecretBackend = "keyring"   // Secret Service (Linux) or Keychain (macOS)
+	SecretBackendFile      SecretBackend = "file"      // ~/.orangeshell/secrets.enc, passphrase-encrypted
+	SecretBackendPlaintext SecretBackend = "plaintext" // Legacy: secrets stay in config.toml
+)
+
+// SecretStore persists the secret values of the config — API keys and
+// tokens, OAuth tokens, fallback tokens and the AI secrets — outside of
+// config.toml. Values are keyed by their TOML path, e.g. "api_token",
+// "fallback_tokens.<account>" or "profiles.<name>.oauth_refresh_token".
+type SecretStore interface {
+	Name() string
+	Load() (map[string]string, error)
+	Save(map[string]string) error
+}
+
+// PromptPassphrase asks for the passphrase of the encrypted secrets file;
+// create is set when the file doesn't exist yet. The passphrase can also be
+// given in ORANGESHELL_PASSPHRASE. main sets this to a terminal prompt while
+// the config loads, and clears it once the TUI owns the terminal.
+var PromptPassphrase func(create bool) (string, error)
+
+// ErrSecretsLocked is returned when the secrets file needs a passphrase and
+// PromptPassphrase is nil. A save that fails with it waits for UnlockSecrets.
+var ErrSecretsLocked = errors.New("secrets file is locked")
+
+// secretsFileName is the encrypted secrets file in the config directory.
+const secretsFileName = "secrets.enc"
+
+// openSecretStore returns the store for a backend. The auto backend resolves
+// to the keyring when one is reachable and to the encrypted file otherwise.
+func openSecretStore(backend SecretBackend, dir string) (SecretStore, SecretBackend, error) {
+	if backend == SecretBackendAuto {
+		backend = SecretBackendFile
+		if keyringAvailable() {
+			backend = SecretBackendKeyring
+		}
+	}
+	switch backend {
+	case SecretBackendKeyring:
+		if !keyringAvailable() {
+			return nil, backend, fmt.Errorf("no keyring available (set secret_backend = \"file\" in config.toml)")
+		}
+		return keyringStore{}, backend, nil
+	case SecretBackendFile:
+		return &fileStore{path: filepath.Join(dir, secretsFileName)}, backend, nil
+	case SecretBackendPlaintext:
+		return nil, backend, nil
+	}
+	return nil, backend, fmt.Errorf("unknown secret_backend %q", backend)
+}
+
+// --- Secret fields ---
+
+// secretFields returns the secret values of the top-level config by key.
+func (c *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"api_key":             &c.APIKey,
+		"api_token":           &c.APIToken,
+		"oauth_access_token":  &c.OAuthAccessToken,
+		"oauth_refresh_token": &c.OAuthRefreshToken,
+		"ai_worker_secret":    &c.AIWorkerSecret,
+		"ai_http_api_key":     &c.AIHTTPAPIKey,
+	}
+}
+
+// secretFields returns the secret values of a stored profile by key.
+func (p *Profile) secretFields() map[string]*string {
+	return map[string]*string{
+		"api_key":             &p.APIKey,
+		"api_token":           &p.APIToken,
+		"oauth_access_token":  &p.OAuthAccessToken,
+		"oauth_refresh_token": &p.OAuthRefreshToken,
+	}
+}
+
+// takeSecrets moves non-empty values out of fields and tokens into out.
+func takeSecrets(out map[string]string, prefix string, fields map[string]*string, tokens map[string]string) {
+	for key, f := range fields {
+		if *f != "" {
+			out[prefix+key] = *f
+			*f = ""
+		}
+	}
+	for account, token := range tokens {
+		out[prefix+"fallback_tokens."+account] = token
+	}
+}
+
+// extractSecrets clears every secret value from the config and returns them,
+// so the config can be encoded without them. applySecrets puts them back.
+func (c *Config) extractSecrets() map[string]string {
+	secrets := make(map[string]string)
+	takeSecrets(secrets, "", c.secretFields(), c.FallbackTokens)
+	c.FallbackTokens = nil
+	for name, p := range c.Profiles {
+		takeSecrets(secrets, "profiles."+name+".", p.secretFields(), p.FallbackTokens)
+		p.FallbackTokens = nil
+		c.Profiles[name] = p
+	}
+	return secrets
+}
+
+// applySecrets sets secret values by key. Keys of profiles that no longer
+// exist are ignored.
+func (c *Config) applySecrets(secrets map[string]string) {
+	for key, value := range secrets {
+		if rest, ok := strings.CutPrefix(key, "profiles."); ok {
+			name, field, ok := strings.Cut(rest, ".")
+			p, exists := c.Profiles[name]
+			if !ok || !exists {
+				continue
+			}
+			setSecret(p.secretFields(), &p.FallbackTokens, field, value)
+			c.Profiles[name] = p
+			continue
+		}
+		setSecret(c.secretFields(), &c.FallbackTokens, key, value)
+	}
+}
+
+// setSecret sets one secret field or fallback token.
+func setSecret(fields map[string]*string, tokens *map[string]string, key, value string) {
+	if account, ok := strings.CutPrefix(key, "fallback_tokens."); ok {
+		if *tokens == nil {
+			*tokens = make(map[string]string)
+		}
+		(*tokens)[account] = value
+		return
+	}
+	if f, ok := fields[key]; ok {
+		*f = value
+	}
+}
+
+// --- Keyring ---
+
+const (
+	keyringService = "orangeshell"
+	keyringAccount = "secrets"
+)
+
+// keyringStore keeps all secrets as a single item in the OS keyring, via
+// secret-tool (libsecret) on Linux and security on macOS. The value is
+// base64-encoded JSON, so it never needs quoting.
+type keyringStore struct{}
+
+// keyringAvailable reports whether the OS keyring can be reached.
+func keyringAvailable() bool {
+	switch runtime.GOOS {
+	case "linux":
+		_, err := exec.LookPath("secret-tool")
+		return err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	}
+	return false
+}
+
+func (keyringStore) Name() string {
+	if runtime.GOOS == "darwin" {
+		return "macOS Keychain"
+	}
+	return "Secret Service keyring"
+}
+
+func (keyringStore) Load() (map[string]string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	default:
+		return nil, fmt.Errorf("no keyring on %s", runtime.GOOS)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	value := strings.TrimSpace(string(out))
+	if err != nil {
+		// Both tools exit non-zero without output when there's no item yet
+		if (value == "" && stderr.Len() == 0) || strings.Contains(stderr.String(), "could not be found") {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("keyring lookup failed: %s", firstLine(stderr.String(), err))
+	}
+	if value == "" {
+		return map[string]string{}, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("keyring item is corrupt: %w", err)
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("keyring item is corrupt: %w", err)
+	}
+	return secrets, nil
+}
+
+func (keyringStore) Save(secrets map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	value := base64.StdEncoding.EncodeToString(data)
+
+	// The value goes in on stdin so it never shows up in the process list
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label=orangeshell credentials",
+			"service", keyringService, "account", keyringAccount)
+		cmd.Stdin = strings.NewReader(value)
+	case "darwin":
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			keyringService, keyringAccount, value))
+	default:
+		return fmt.Errorf("no keyring on %s", runtime.GOOS)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("keyring store failed: %s", firstLine(stderr.String(), err))
+	}
+	return nil
+}
+
+// firstLine returns the first line of a tool's stderr, or err if it's empty.
+func firstLine(stderr string, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(stderr), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
+
+// --- Encrypted file ---
+
+// Encrypted file layout: magic, PBKDF2 salt, GCM nonce, then the sealed JSON.
+const (
+	secretsMagic   = "OSSECRETS1"
+	saltSize       = 16
+	pbkdf2Rounds   = 600_000
+	secretsKeySize = 32
+)
+
+// errWrongPassphrase is returned when the secrets file can't be decrypted.
+var errWrongPassphrase = errors.New("wrong passphrase for secrets file")
+
+// fileStore keeps secrets in an AES-256-GCM encrypted file, with the key
+// derived from a passphrase. The passphrase is asked for once per session,
+// and not at all until there is a file to read or a secret to write. Saves
+// may come from any goroutine, so mu guards the passphrase and the file.
+type fileStore struct {
+	mu         sync.Mutex
+	path       string
+	passphrase string
+	waiting    bool // a save failed with ErrSecretsLocked
+}
+
+func (s *fileStore) Name() string { return "encrypted file " + s.path }
+
+// setPassphrase sets the passphrase for the next save; an empty one drops
+// the waiting save, so the next save asks again.
+func (s *fileStore) setPassphrase(passphrase string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passphrase = passphrase
+	s.waiting = false
+}
+
+// locked reports whether a save is waiting for the passphrase.
+func (s *fileStore) locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiting
+}
+
+// unlock obtains the passphrase from ORANGESHELL_PASSPHRASE or the prompt.
+// Callers hold mu.
+func (s *fileStore) unlock(create bool) error {
+	if s.passphrase != "" {
+		return nil
+	}
+	if v := os.Getenv("ORANGESHELL_PASSPHRASE"); v != "" {
+		s.passphrase = v
+		return nil
+	}
+	if PromptPassphrase == nil {
+		return fmt.Errorf("%w: set ORANGESHELL_PASSPHRASE", ErrSecretsLocked)
+	}
+	p, err := PromptPassphrase(create)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return fmt.Errorf("empty passphrase")
+	}
+	s.passphrase = p
+	return nil
+}
+
+func (s *fileStore) Load() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.unlock(false); err != nil {
+		return nil, err
+	}
+
+	if len(data) < len(secretsMagic)+saltSize || string(data[:len(secretsMagic)]) != secretsMagic {
+		return nil, fmt.Errorf("%s is not an orangeshell secrets file", s.path)
+	}
+	data = data[len(secretsMagic):]
+	salt, sealed := data[:saltSize], data[saltSize:]
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is truncated", s.path)
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(secretsMagic))
+	if err != nil {
+		s.passphrase = ""
+		return nil, errWrongPassphrase
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %w", s.path, err)
+	}
+	return secrets, nil
+}
+
+func (s *fileStore) Save(secrets map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := os.Stat(s.path)
+	create := os.IsNotExist(err)
+	if create && len(secrets) == 0 {
+		return nil
+	}
+	if err := s.unlock(create); err != nil {
+		s.waiting = errors.Is(err, ErrSecretsLocked)
+		return err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	out := append([]byte(secretsMagic), salt...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plain, []byte(secretsMagic))
+
+	// Write to a temp file and rename so a crash never leaves a torn file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	s.waiting = false
+	return nil
+}
+
+// cipher derives the file key from the passphrase and salt.
+func (s *fileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, s.passphrase, salt, pbkdf2Rounds, secretsKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
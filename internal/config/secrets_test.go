@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), secretsFileName)
+	want := map[string]string{"api_token": "tok", "fallback_tokens.abc": "fb"}
+
+	s := &fileStore{path: path, passphrase: "hunter2"}
+	if err := s.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "tok") {
+		t.Fatal("secrets file contains plaintext")
+	}
+
+	got, err := (&fileStore{path: path, passphrase: "hunter2"}).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["api_token"] != "tok" || got["fallback_tokens.abc"] != "fb" {
+		t.Fatalf("got %v", got)
+	}
+
+	if _, err := (&fileStore{path: path, passphrase: "wrong"}).Load(); err != errWrongPassphrase {
+		t.Fatalf("wrong passphrase: got err %v", err)
+	}
+}
+
+// TestLoadMigratesPlaintext checks that secrets in a legacy config.toml are
+// moved to the secret store and never written back to the TOML.
+func TestLoadMigratesPlaintext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ORANGESHELL_PASSPHRASE", "hunter2")
+	for _, v := range []string{"CLOUDFLARE_API_KEY", "CLOUDFLARE_EMAIL", "CLOUDFLARE_API_TOKEN", "CLOUDFLARE_ACCOUNT_ID"} {
+		t.Setenv(v, "")
+	}
+
+	dir := filepath.Join(home, ".orangeshell")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `secret_backend = "file"
+auth_method = "apitoken"
+account_id = "acct"
+api_token = "primary-token"
+ai_worker_secret = "ai-secret"
+
+[fallback_tokens]
+acct = "fallback-token"
+
+[profiles.globex]
+auth_method = "oauth"
+account_id = "globex-acct"
+oauth_refresh_token = "globex-refresh"
+`
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIToken != "primary-token" || cfg.AIWorkerSecret != "ai-secret" || cfg.FallbackTokenFor("acct") != "fallback-token" {
+		t.Fatalf("secrets not loaded: %+v", cfg)
+	}
+
+	assertNoSecrets := func() {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"primary-token", "ai-secret", "fallback-token", "globex-refresh"} {
+			if strings.Contains(string(data), secret) {
+				t.Fatalf("config.toml still contains %q:\n%s", secret, data)
+			}
+		}
+	}
+	assertNoSecrets()
+
+	// Secrets survive a save and reload, profiles included
+	if err := cfg.UseProfile("globex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	assertNoSecrets()
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OAuthRefreshToken != "globex-refresh" {
+		t.Fatalf("active profile secret lost: %q", cfg.OAuthRefreshToken)
+	}
+	p, _ := cfg.ProfileByName(DefaultProfile)
+	if p.APIToken != "primary-token" || p.FallbackTokens["acct"] != "fallback-token" {
+		t.Fatalf("stored profile secrets lost: %+v", p)
+	}
+}
+
+// TestLoadWithoutSecretsFile checks that a fresh install with the file
+// backend loads without a passphrase, which is only needed once a secret is
+// written.
+func TestLoadWithoutSecretsFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ORANGESHELL_PASSPHRASE", "")
+	t.Setenv("CLOUDFLARE_API_TOKEN", "env-token")
+	t.Setenv("CLOUDFLARE_ACCOUNT_ID", "acct")
+	prompt := PromptPassphrase
+	t.Cleanup(func() { PromptPassphrase = prompt })
+	prompted := false
+	PromptPassphrase = func(bool) (string, error) {
+		prompted = true
+		return "hunter2", nil
+	}
+
+	dir := filepath.Join(home, ".orangeshell")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("secret_backend = \"file\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompted {
+		t.Fatal("passphrase asked for without a secrets file")
+	}
+	if cfg.APIToken != "env-token" {
+		t.Fatalf("env token not applied: %q", cfg.APIToken)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if prompted {
+		t.Fatal("passphrase asked for with no secret to write")
+	}
+	if _, err := os.Stat(filepath.Join(dir, secretsFileName)); !os.IsNotExist(err) {
+		t.Fatalf("secrets file created with no secrets: %v", err)
+	}
+
+	// The first secret written creates the file
+	cfg.AIWorkerSecret = "ai-secret"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if !prompted {
+		t.Fatal("no passphrase asked for when creating the secrets file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, secretsFileName)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSaveWaitsForPassphrase checks that without a prompt (inside the TUI) a
+// save that would create the secrets file waits for UnlockSecrets.
+func TestSaveWaitsForPassphrase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ORANGESHELL_PASSPHRASE", "")
+	for _, v := range []string{"CLOUDFLARE_API_KEY", "CLOUDFLARE_EMAIL", "CLOUDFLARE_API_TOKEN", "CLOUDFLARE_ACCOUNT_ID"} {
+		t.Setenv(v, "")
+	}
+	prompt := PromptPassphrase
+	t.Cleanup(func() { PromptPassphrase = prompt })
+	PromptPassphrase = nil
+
+	dir := filepath.Join(home, ".orangeshell")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte("secret_backend = \"file\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.APIToken = "tok"
+	if err := cfg.Save(); !errors.Is(err, ErrSecretsLocked) {
+		t.Fatalf("save without a passphrase: got err %v", err)
+	}
+	if !cfg.SecretsLocked() || cfg.APIToken != "tok" {
+		t.Fatal("save not waiting for the passphrase, or the token was lost")
+	}
+
+	// Giving up drops the waiting save until the next one
+	cfg.UnlockSecrets("")
+	if cfg.SecretsLocked() {
+		t.Fatal("still waiting after giving up")
+	}
+
+	cfg.UnlockSecrets("hunter2")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SecretsLocked() {
+		t.Fatal("still waiting after the save")
+	}
+	got, err := (&fileStore{path: filepath.Join(dir, secretsFileName), passphrase: "hunter2"}).Load()
+	if err != nil || got["api_token"] != "tok" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+}
+
+// TestConcurrentSavesPromptOnce checks that saves from several goroutines
+// share one passphrase prompt.
+func TestConcurrentSavesPromptOnce(t *testing.T) {
+	t.Setenv("ORANGESHELL_PASSPHRASE", "")
+	prompt := PromptPassphrase
+	t.Cleanup(func() { PromptPassphrase = prompt })
+	var prompts atomic.Int32
+	PromptPassphrase = func(bool) (string, error) {
+		prompts.Add(1)
+		return "hunter2", nil
+	}
+
+	s := &fileStore{path: filepath.Join(t.TempDir(), secretsFileName)}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Save(map[string]string{"api_token": "tok"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := prompts.Load(); n != 1 {
+		t.Errorf("prompted %d times, want 1", n)
+	}
+}
@@ -17,6 +17,48 @@ type D1QueryResult struct {
 	Output    string // formatted ASCII table or "Query OK" for mutations
 	Meta      string // "Rows: 2 | Duration: 0.5ms"
 	ChangedDB bool   // true if the query mutated the DB (triggers schema refresh)
+
+	// Sets holds one result per statement, in order, for callers that
+	// render or export the rows themselves.
+	Sets []D1ResultSet
+}
+
+// D1ResultSet is the result of one statement of a query.
+type D1ResultSet struct {
+	Columns []string        // empty for statements that return no rows
+	Rows    [][]interface{} // one value per column
+	Meta    string
+	Changes float64
+}
+
+// NewD1QueryResult builds a query result from its statements' results,
+// rendering each row set as an ASCII table.
+func NewD1QueryResult(sets []D1ResultSet, changedDB bool) *D1QueryResult {
+	if len(sets) == 0 {
+		return &D1QueryResult{Output: "No results", ChangedDB: changedDB}
+	}
+	outputs := make([]string, len(sets))
+	for i, set := range sets {
+		outputs[i] = set.Summary()
+	}
+	return &D1QueryResult{
+		Output:    strings.Join(outputs, "\n\n"),
+		Meta:      sets[len(sets)-1].Meta,
+		ChangedDB: changedDB,
+		Sets:      sets,
+	}
+}
+
+// Summary renders the rows as an ASCII table, or for a statement without
+// rows, how many rows it changed.
+func (r D1ResultSet) Summary() string {
+	if len(r.Columns) > 0 {
+		return FormatASCIITable(r.Columns, r.Rows)
+	}
+	if r.Changes > 0 {
+		return fmt.Sprintf("Query OK, %.0f row(s) affected", r.Changes)
+	}
+	return "Query OK"
 }
 
 // D1Service implements the Service interface for Cloudflare D1 SQL databases.
@@ -151,35 +193,19 @@ func (s *D1Service) ExecuteQuery(id, sql string) (*D1QueryResult, error) {
 		return nil, err
 	}
 
-	results := resp.Result
-	if len(results) == 0 {
-		return &D1QueryResult{Output: "No results", Meta: ""}, nil
-	}
-
-	r := results[0]
-	meta := formatQueryMeta(r.Meta)
-
-	// If the query didn't return columns, it was a mutation (CREATE, INSERT, etc.)
-	if len(r.Results.Columns) == 0 {
-		output := "Query OK"
-		if r.Meta.Changes > 0 {
-			output = fmt.Sprintf("Query OK, %.0f row(s) affected", r.Meta.Changes)
+	// A multi-statement query returns one result per statement
+	sets := make([]D1ResultSet, len(resp.Result))
+	changed := false
+	for i, r := range resp.Result {
+		sets[i] = D1ResultSet{
+			Columns: r.Results.Columns,
+			Rows:    r.Results.Rows,
+			Meta:    formatQueryMeta(r.Meta),
+			Changes: r.Meta.Changes,
 		}
-		return &D1QueryResult{
-			Output:    output,
-			Meta:      meta,
-			ChangedDB: r.Meta.ChangedDB,
-		}, nil
+		changed = changed || r.Meta.ChangedDB
 	}
-
-	// Format as ASCII table
-	output := FormatASCIITable(r.Results.Columns, r.Results.Rows)
-
-	return &D1QueryResult{
-		Output:    output,
-		Meta:      meta,
-		ChangedDB: r.Meta.ChangedDB,
-	}, nil
+	return NewD1QueryResult(sets, changed), nil
 }
 
 // QuerySchema introspects the database schema and returns structured table data.
@@ -212,6 +238,12 @@ func formatQueryMeta(meta d1.DatabaseRawResponseMeta) string {
 // FormatASCIITable renders columns and rows as an aligned ASCII table.
 // Exported for use by the local emulator D1 query result converter.
 func FormatASCIITable(columns []string, rows [][]interface{}) string {
+	return FormatASCIITableWidth(columns, rows, 30)
+}
+
+// FormatASCIITableWidth renders an ASCII table whose columns are at most
+// maxColWidth characters wide.
+func FormatASCIITableWidth(columns []string, rows [][]interface{}, maxColWidth int) string {
 	if len(columns) == 0 {
 		return "(empty result)"
 	}
@@ -239,7 +271,6 @@ func FormatASCIITable(columns []string, rows [][]interface{}) string {
 	}
 
 	// Cap column widths to prevent excessively wide tables
-	maxColWidth := 30
 	for i := range colWidths {
 		if colWidths[i] > maxColWidth {
 			colWidths[i] = maxColWidth
@@ -347,8 +378,10 @@ func formatFileSize(bytes float64) string {
 // SchemaTable represents a database table with its columns and foreign keys.
 type SchemaTable struct {
 	Name    string
+	SQL     string // CREATE TABLE statement
 	Columns []SchemaColumn
 	FKs     []SchemaFK
+	Indexes []SchemaIndex
 }
 
 // SchemaColumn represents a single column in a table.
@@ -357,6 +390,13 @@ type SchemaColumn struct {
 	Type    string
 	NotNull bool
 	PK      bool
+	Default string // default value expression, empty for none
+}
+
+// SchemaIndex represents an explicitly created index.
+type SchemaIndex struct {
+	Name string
+	SQL  string // CREATE INDEX statement
 }
 
 // SchemaFK represents a foreign key relationship.
@@ -370,7 +410,7 @@ type SchemaFK struct {
 func (s *D1Service) querySchema(ctx context.Context, databaseID string) ([]SchemaTable, error) {
 	// Step 1: Get all user table names
 	tableNames, err := s.queryD1(ctx, databaseID,
-		"SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE '_cf_%' ORDER BY name")
+		"SELECT name, sql FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE '_cf_%' ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -387,7 +427,7 @@ func (s *D1Service) querySchema(ctx context.Context, databaseID string) ([]Schem
 			continue
 		}
 
-		table := SchemaTable{Name: tableName}
+		table := SchemaTable{Name: tableName, SQL: strVal(row, "sql")}
 
 		// Step 2: Get columns via PRAGMA table_info
 		colRows, err := s.queryD1(ctx, databaseID,
@@ -395,8 +435,9 @@ func (s *D1Service) querySchema(ctx context.Context, databaseID string) ([]Schem
 		if err == nil {
 			for _, cr := range colRows {
 				col := SchemaColumn{
-					Name: strVal(cr, "name"),
-					Type: strVal(cr, "type"),
+					Name:    strVal(cr, "name"),
+					Type:    strVal(cr, "type"),
+					Default: strVal(cr, "dflt_value"),
 				}
 				if numVal(cr, "notnull") == 1 {
 					col.NotNull = true
@@ -427,6 +468,19 @@ func (s *D1Service) querySchema(ctx context.Context, databaseID string) ([]Schem
 		tables = append(tables, table)
 	}
 
+	// Step 4: Get explicit indexes (automatic ones have no SQL)
+	idxRows, err := s.queryD1(ctx, databaseID,
+		"SELECT name, tbl_name, sql FROM sqlite_master WHERE type='index' AND sql IS NOT NULL ORDER BY name")
+	if err == nil {
+		for _, ir := range idxRows {
+			for i := range tables {
+				if tables[i].Name == strVal(ir, "tbl_name") {
+					tables[i].Indexes = append(tables[i].Indexes, SchemaIndex{Name: strVal(ir, "name"), SQL: strVal(ir, "sql")})
+				}
+			}
+		}
+	}
+
 	return tables, nil
 }
 
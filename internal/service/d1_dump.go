@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	cloudflare "github.com/cloudflare/cloudflare-go/v6"
+	"github.com/cloudflare/cloudflare-go/v6/d1"
+)
+
+// D1DumpMode selects what a D1 export contains.
+type D1DumpMode int
+
+const (
+	D1DumpFull   D1DumpMode = iota // schema and data
+	D1DumpSchema                   // table definitions only
+	D1DumpData                     // table contents only
+)
+
+func (d D1DumpMode) String() string {
+	switch d {
+	case D1DumpSchema:
+		return "schema only"
+	case D1DumpData:
+		return "data only"
+	default:
+		return "schema + data"
+	}
+}
+
+// NoData reports whether the dump leaves out table contents.
+func (d D1DumpMode) NoData() bool { return d == D1DumpSchema }
+
+// NoSchema reports whether the dump leaves out table definitions.
+func (d D1DumpMode) NoSchema() bool { return d == D1DumpData }
+
+// Export dumps a D1 database as SQL into w. The export runs server-side and
+// is polled until the dump is ready; progress receives its log messages.
+func (s *D1Service) Export(ctx context.Context, id string, mode D1DumpMode, w io.Writer, progress func(string)) error {
+	params := d1.DatabaseExportParams{
+		AccountID:    cloudflare.F(s.accountID),
+		OutputFormat: cloudflare.F(d1.DatabaseExportParamsOutputFormatPolling),
+		DumpOptions: cloudflare.F(d1.DatabaseExportParamsDumpOptions{
+			NoData:   cloudflare.F(mode.NoData()),
+			NoSchema: cloudflare.F(mode.NoSchema()),
+		}),
+	}
+
+	var signedURL string
+	for signedURL == "" {
+		resp, err := s.client.D1.Database.Export(ctx, id, params)
+		if err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		for _, line := range resp.Messages {
+			progress(line)
+		}
+		switch resp.Status {
+		case d1.DatabaseExportResponseStatusComplete:
+			signedURL = resp.Result.SignedURL
+			if signedURL == "" {
+				return fmt.Errorf("export finished without a download URL")
+			}
+		case d1.DatabaseExportResponseStatusError:
+			return fmt.Errorf("export failed: %s", resp.Error)
+		default:
+			params.CurrentBookmark = cloudflare.F(resp.AtBookmark)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	progress("Downloading dump...")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signedURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("downloading export: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading export: HTTP %d", resp.StatusCode)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("downloading export: %w", err)
+	}
+	return nil
+}
+
+// ExecBatch runs one or more semicolon-separated statements against a D1
+// database. D1 runs the statements of one request as a single batch, so a
+// failing statement rolls back the whole batch.
+func (s *D1Service) ExecBatch(ctx context.Context, id, sql string) error {
+	_, err := s.client.D1.Database.Query(ctx, id, d1.DatabaseQueryParams{
+		AccountID: cloudflare.F(s.accountID),
+		Body: d1.DatabaseQueryParamsBodyD1SingleQuery{
+			Sql: cloudflare.F(sql),
+		},
+	})
+	return err
+}
+
+// DatabaseID returns the UUID of the database with the given name, listing
+// the databases when the cache is empty or doesn't have it.
+func (s *D1Service) DatabaseID(name string) (string, error) {
+	for _, r := range s.SearchItems() {
+		if r.Name == name {
+			return r.ID, nil
+		}
+	}
+	resources, err := s.List()
+	if err != nil {
+		return "", err
+	}
+	for _, r := range resources {
+		if r.Name == name {
+			return r.ID, nil
+		}
+	}
+	return "", fmt.Errorf("D1 database %q not found", name)
+}
+
+// --- SQL import ---
+
+// SQLStatement is one statement of a SQL script and the line it starts on.
+type SQLStatement struct {
+	SQL  string
+	Line int
+}
+
+// SplitSQLStatements splits a SQL script into statements, honouring quoted
+// strings and identifiers, comments and CREATE TRIGGER ... END bodies.
+// Explicit transaction statements (BEGIN TRANSACTION, COMMIT, ...) are
+// dropped because D1 manages transactions itself and rejects them.
+func SplitSQLStatements(script string) []SQLStatement {
+	var (
+		stmts []SQLStatement
+		cur   strings.Builder
+		start = 0 // line of the current statement's first token
+		line  = 1
+
+		quote        rune // ', ", ` or ] while inside a quoted token
+		lineComment  bool
+		blockComment bool
+
+		word      strings.Builder
+		words     []string // leading keywords, to spot CREATE TRIGGER
+		inTrigger bool
+		caseDepth int
+		sawEnd    bool
+	)
+
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.ToUpper(word.String())
+		word.Reset()
+		if len(words) < 4 {
+			words = append(words, w)
+			if len(words) >= 2 && words[0] == "CREATE" && (words[1] == "TRIGGER" ||
+				(len(words) >= 3 && (words[1] == "TEMP" || words[1] == "TEMPORARY") && words[2] == "TRIGGER")) {
+				inTrigger = true
+			}
+		}
+		if !inTrigger {
+			return
+		}
+		switch w {
+		case "CASE":
+			caseDepth++
+		case "END":
+			if caseDepth > 0 {
+				caseDepth--
+			} else {
+				sawEnd = true
+			}
+		}
+	}
+	flush := func() {
+		endWord()
+		sql := strings.TrimSpace(cur.String())
+		if sql != "" && !isTransactionStatement(sql) {
+			stmts = append(stmts, SQLStatement{SQL: sql, Line: start})
+		}
+		cur.Reset()
+		start = 0
+		words = words[:0]
+		inTrigger, caseDepth, sawEnd = false, 0, false
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+			}
+		case blockComment:
+			if r == '*' && next == '/' {
+				blockComment = false
+				i++
+				continue
+			}
+		case quote != 0:
+			cur.WriteRune(r)
+			if r == quote {
+				if next == quote && quote != ']' {
+					// Doubled quote is an escaped quote character
+					cur.WriteRune(next)
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		case r == '-' && next == '-':
+			endWord()
+			lineComment = true
+			i++
+		case r == '/' && next == '*':
+			endWord()
+			blockComment = true
+			i++
+		case r == ';':
+			endWord()
+			if inTrigger && !sawEnd {
+				cur.WriteRune(r)
+				break
+			}
+			flush()
+		default:
+			if start == 0 && !unicode.IsSpace(r) {
+				start = line
+			}
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+				word.WriteRune(r)
+			} else {
+				endWord()
+			}
+			switch r {
+			case '\'', '"', '`':
+				quote = r
+			case '[':
+				quote = ']'
+			}
+			cur.WriteRune(r)
+		}
+		if r == '\n' {
+			line++
+		}
+	}
+	flush()
+	return stmts
+}
+
+// isTransactionStatement reports whether a statement starts or ends an
+// explicit transaction.
+func isTransactionStatement(sql string) bool {
+	fields := strings.Fields(strings.ToUpper(strings.TrimRight(sql, "; \t\n")))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "BEGIN":
+		return len(fields) == 1 || fields[len(fields)-1] == "TRANSACTION"
+	case "COMMIT", "ROLLBACK":
+		return len(fields) <= 2
+	case "END":
+		return len(fields) == 1 || (len(fields) == 2 && fields[1] == "TRANSACTION")
+	}
+	return false
+}
+
+// ImportError reports the statement an import stopped at.
+type ImportError struct {
+	Line    int // first line of the failing statement (or batch)
+	EndLine int // last statement's line when only the batch is known; 0 otherwise
+	SQL     string
+	Err     error
+}
+
+func (e *ImportError) Error() string {
+	if e.EndLine > e.Line {
+		return fmt.Sprintf("statements at lines %d–%d: %v", e.Line, e.EndLine, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportError) Unwrap() error { return e.Err }
+
+// ImportOptions tunes ImportStatements.
+type ImportOptions struct {
+	BatchSize  int // statements per request
+	BatchBytes int // soft cap on the SQL sent per request
+
+	// Atomic means a failed batch is rolled back, so its statements can be
+	// replayed one by one to find the failing line. Otherwise the error
+	// names the line range of the batch.
+	Atomic bool
+
+	// Progress is called after each batch with the statements applied so far.
+	Progress func(done, total int)
+}
+
+// ImportStatements applies statements in batches through exec, stopping at
+// the first failure with an *ImportError.
+func ImportStatements(ctx context.Context, stmts []SQLStatement, exec func(ctx context.Context, sql string) error, opts ImportOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = 90 * 1024
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(int, int) {}
+	}
+
+	done := 0
+	for done < len(stmts) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Fill the batch up to the statement and size limits (at least one)
+		end, size := done, 0
+		for end < len(stmts) && end-done < opts.BatchSize {
+			if end > done && size+len(stmts[end].SQL) > opts.BatchBytes {
+				break
+			}
+			size += len(stmts[end].SQL) + 2
+			end++
+		}
+		batch := stmts[done:end]
+
+		if err := exec(ctx, joinStatements(batch)); err != nil {
+			if len(batch) == 1 {
+				return &ImportError{Line: batch[0].Line, SQL: batch[0].SQL, Err: err}
+			}
+			if !opts.Atomic {
+				return &ImportError{Line: batch[0].Line, EndLine: batch[len(batch)-1].Line, Err: err}
+			}
+			// Replay the rolled-back batch statement by statement. If every
+			// one succeeds (the batch as a whole was too much, not a bad
+			// statement), it's applied and the import carries on.
+			for _, st := range batch {
+				if err := exec(ctx, st.SQL); err != nil {
+					return &ImportError{Line: st.Line, SQL: st.SQL, Err: err}
+				}
+				done++
+				progress(done, len(stmts))
+			}
+			continue
+		}
+		done = end
+		progress(done, len(stmts))
+	}
+	return nil
+}
+
+func joinStatements(stmts []SQLStatement) string {
+	parts := make([]string, len(stmts))
+	for i, st := range stmts {
+		parts[i] = strings.TrimRight(st.SQL, ";")
+	}
+	return strings.Join(parts, ";\n") + ";"
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	script := `PRAGMA defer_foreign_keys=TRUE;
+BEGIN TRANSACTION;
+-- users; with a semicolon in a comment
+CREATE TABLE users (id INTEGER, name TEXT);
+INSERT INTO users VALUES (1, 'a;b'), (2, 'it''s');
+/* block;
+   comment */ INSERT INTO "odd;name" VALUES (3);
+CREATE TRIGGER t AFTER INSERT ON users BEGIN
+  UPDATE users SET name = CASE WHEN name = '' THEN 'x' ELSE name END;
+  DELETE FROM users WHERE id < 0;
+END;
+COMMIT;
+SELECT 1`
+
+	var got []SQLStatement
+	for _, st := range SplitSQLStatements(script) {
+		got = append(got, SQLStatement{SQL: strings.Fields(st.SQL)[0] + " " + strings.Fields(st.SQL)[1], Line: st.Line})
+	}
+	want := []SQLStatement{
+		{"PRAGMA defer_foreign_keys=TRUE", 1},
+		{"CREATE TABLE", 4},
+		{"INSERT INTO", 5},
+		{"INSERT INTO", 7},
+		{"CREATE TRIGGER", 8},
+		{"SELECT 1", 13},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	stmts := SplitSQLStatements(script)
+	if s := stmts[2].SQL; s != "INSERT INTO users VALUES (1, 'a;b'), (2, 'it''s')" {
+		t.Fatalf("quoted semicolon split: %q", s)
+	}
+	if s := stmts[4].SQL; !strings.HasSuffix(s, "DELETE FROM users WHERE id < 0;\nEND") {
+		t.Fatalf("trigger body split: %q", s)
+	}
+}
+
+func TestImportStatementsReportsLine(t *testing.T) {
+	stmts := SplitSQLStatements("CREATE TABLE a (x);\nINSERT INTO a VALUES (1);\n\nINSERT INTO nope VALUES (2);\nINSERT INTO a VALUES (3);\n")
+	exec := func(_ context.Context, sql string) error {
+		if strings.Contains(sql, "nope") {
+			return errors.New("no such table: nope")
+		}
+		return nil
+	}
+
+	var progress []int
+	err := ImportStatements(context.Background(), stmts, exec, ImportOptions{
+		BatchSize: 3,
+		Atomic:    true,
+		Progress:  func(done, _ int) { progress = append(progress, done) },
+	})
+	var ie *ImportError
+	if !errors.As(err, &ie) || ie.Line != 4 || ie.EndLine != 0 {
+		t.Fatalf("atomic: got %v", err)
+	}
+	if !reflect.DeepEqual(progress, []int{1, 2}) {
+		t.Fatalf("atomic progress: got %v", progress)
+	}
+
+	err = ImportStatements(context.Background(), stmts, exec, ImportOptions{BatchSize: 3})
+	if !errors.As(err, &ie) || ie.Line != 1 || ie.EndLine != 4 {
+		t.Fatalf("non-atomic: got %v", err)
+	}
+}
+
+// TestImportStatementsContinuesAfterReplay checks that a batch rejected as a
+// whole but accepted statement by statement counts as applied.
+func TestImportStatementsContinuesAfterReplay(t *testing.T) {
+	stmts := SplitSQLStatements("INSERT INTO a VALUES (1);\nINSERT INTO a VALUES (2);\nINSERT INTO a VALUES (3);\nINSERT INTO a VALUES (4);\n")
+	var applied []string
+	exec := func(_ context.Context, sql string) error {
+		if strings.Count(sql, ";") > 1 && strings.Contains(sql, "(1)") {
+			return errors.New("statement too long")
+		}
+		applied = append(applied, sql)
+		return nil
+	}
+
+	var progress []int
+	err := ImportStatements(context.Background(), stmts, exec, ImportOptions{
+		BatchSize: 2,
+		Atomic:    true,
+		Progress:  func(done, _ int) { progress = append(progress, done) },
+	})
+	if err != nil {
+		t.Fatalf("got %v", err)
+	}
+	if !reflect.DeepEqual(progress, []int{1, 2, 4}) {
+		t.Fatalf("progress: got %v", progress)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("applied: got %q", applied)
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go/v6"
+	"github.com/cloudflare/cloudflare-go/v6/d1"
+)
+
+// D1Statement is one parameterized SQL statement: each ? in SQL is bound to
+// the next value of Params. D1 binds parameters as text; column affinity
+// converts them on comparison and storage.
+type D1Statement struct {
+	SQL    string
+	Params []string
+}
+
+// String renders the statement for review, with its parameters.
+func (st D1Statement) String() string {
+	if len(st.Params) == 0 {
+		return st.SQL
+	}
+	quoted := make([]string, len(st.Params))
+	for i, p := range st.Params {
+		quoted[i] = strconv.Quote(p)
+	}
+	return st.SQL + "  -- " + strings.Join(quoted, ", ")
+}
+
+// Inline returns the statement with its parameters substituted as quoted
+// literals, for runners that cannot bind parameters (wrangler --command).
+func (st D1Statement) Inline() string {
+	var b strings.Builder
+	next := 0
+	var quote byte
+	for i := 0; i < len(st.SQL); i++ {
+		c := st.SQL[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && next < len(st.Params):
+			b.WriteString("'" + strings.ReplaceAll(st.Params[next], "'", "''") + "'")
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// ExecuteBatch runs statements against a D1 database as one batch. D1 runs a
+// batch in a single transaction: if a statement fails, none of them apply.
+func (s *D1Service) ExecuteBatch(id string, stmts []D1Statement) (*D1QueryResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	batch := make([]d1.DatabaseRawParamsBodyMultipleQueriesBatch, len(stmts))
+	for i, st := range stmts {
+		batch[i] = d1.DatabaseRawParamsBodyMultipleQueriesBatch{Sql: cloudflare.F(st.SQL)}
+		if len(st.Params) > 0 {
+			batch[i].Params = cloudflare.F(st.Params)
+		}
+	}
+	resp, err := s.client.D1.Database.Raw(ctx, id, d1.DatabaseRawParams{
+		AccountID: cloudflare.F(s.accountID),
+		Body:      d1.DatabaseRawParamsBodyMultipleQueries{Batch: cloudflare.F(batch)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]D1ResultSet, len(resp.Result))
+	changed := false
+	for i, r := range resp.Result {
+		sets[i] = D1ResultSet{
+			Columns: r.Results.Columns,
+			Rows:    r.Results.Rows,
+			Meta:    formatQueryMeta(r.Meta),
+			Changes: r.Meta.Changes,
+		}
+		changed = changed || r.Meta.ChangedDB
+	}
+	return NewD1QueryResult(sets, changed), nil
+}
+
+// --- Table grid ---
+
+// RowIDColumn is the key the grid pages and edits by for a table without a
+// primary key.
+const RowIDColumn = "rowid"
+
+// RowKey returns the columns that identify a row of t: its primary key, or
+// the implicit rowid when it has none.
+func RowKey(t SchemaTable) []string {
+	var key []string
+	for _, c := range t.Columns {
+		if c.PK {
+			key = append(key, c.Name)
+		}
+	}
+	if len(key) == 0 {
+		return []string{RowIDColumn}
+	}
+	return key
+}
+
+// RowPageQuery returns the query for one page of t ordered by key, starting
+// after the row whose key values are after (nil for the first page). Pages
+// are keyset-paginated so a page stays cheap deep into a large table.
+//
+// The key columns are selected first, cast to text, then every column: D1
+// returns numbers as JSON, so an INTEGER key beyond 2^53 would otherwise
+// come back as a different number and match no row when edited.
+func RowPageQuery(table string, key []string, after []interface{}, limit int) D1Statement {
+	quoted := make([]string, len(key))
+	cols := make([]string, len(key)+1)
+	for i, k := range key {
+		quoted[i] = QuoteIdent(k)
+		cols[i] = "CAST(" + quoted[i] + " AS TEXT)"
+	}
+	cols[len(key)] = "*"
+
+	st := D1Statement{SQL: fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), QuoteIdent(table))}
+	if after != nil {
+		marks := strings.TrimSuffix(strings.Repeat("?, ", len(key)), ", ")
+		if len(key) == 1 {
+			st.SQL += fmt.Sprintf(" WHERE %s > %s", quoted[0], marks)
+		} else {
+			st.SQL += fmt.Sprintf(" WHERE (%s) > (%s)", strings.Join(quoted, ", "), marks)
+		}
+		for _, v := range after {
+			st.Params = append(st.Params, ParamValue(v))
+		}
+	}
+	st.SQL += fmt.Sprintf(" ORDER BY %s LIMIT %d", strings.Join(quoted, ", "), limit)
+	return st
+}
+
+// RowChangeKind is what a RowChange does to a row.
+type RowChangeKind int
+
+const (
+	RowUpdate RowChangeKind = iota
+	RowInsert
+	RowDelete
+)
+
+// RowChange is a pending edit of a table's rows.
+type RowChange struct {
+	Kind RowChangeKind
+	// Key holds the key column values of the row to update or delete.
+	Key []interface{}
+	// Values maps columns to their new value for an update or insert; a nil
+	// value sets NULL. An insert leaves the columns it omits at their default.
+	Values map[string]*string
+	// Columns orders Values in the generated SQL.
+	Columns []string
+}
+
+// RowChangeStatements generates the statements that apply changes to table,
+// with every value passed as a parameter.
+func RowChangeStatements(table string, key []string, changes []RowChange) []D1Statement {
+	var stmts []D1Statement
+	for _, c := range changes {
+		var st D1Statement
+		switch c.Kind {
+		case RowInsert:
+			if len(c.Columns) == 0 {
+				st.SQL = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES;", QuoteIdent(table))
+				break
+			}
+			cols := make([]string, len(c.Columns))
+			vals := make([]string, len(c.Columns))
+			for i, col := range c.Columns {
+				cols[i] = QuoteIdent(col)
+				vals[i] = st.bind(c.Values[col])
+			}
+			st.SQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", QuoteIdent(table), strings.Join(cols, ", "), strings.Join(vals, ", "))
+		case RowUpdate:
+			if len(c.Columns) == 0 {
+				continue
+			}
+			sets := make([]string, len(c.Columns))
+			for i, col := range c.Columns {
+				sets[i] = QuoteIdent(col) + " = " + st.bind(c.Values[col])
+			}
+			st.SQL = fmt.Sprintf("UPDATE %s SET %s WHERE %s;", QuoteIdent(table), strings.Join(sets, ", "), st.where(key, c.Key))
+		case RowDelete:
+			st.SQL = fmt.Sprintf("DELETE FROM %s WHERE %s;", QuoteIdent(table), st.where(key, c.Key))
+		}
+		stmts = append(stmts, st)
+	}
+	return stmts
+}
+
+// UnmatchedRowChanges returns the updates and deletes among stmts that didn't
+// change exactly one row, going by their result sets. A row edited or deleted
+// since it was loaded matches nothing, and D1 reports that as success. Sets
+// that don't line up with stmts can't be checked and yield nil.
+func UnmatchedRowChanges(stmts []D1Statement, sets []D1ResultSet) []D1Statement {
+	if len(sets) != len(stmts) {
+		return nil
+	}
+	var unmatched []D1Statement
+	for i, st := range stmts {
+		if (strings.HasPrefix(st.SQL, "UPDATE ") || strings.HasPrefix(st.SQL, "DELETE ")) && sets[i].Changes != 1 {
+			unmatched = append(unmatched, st)
+		}
+	}
+	return unmatched
+}
+
+// bind returns the placeholder for v, adding it to the parameters. NULL is
+// written inline since parameters are always text.
+func (st *D1Statement) bind(v *string) string {
+	if v == nil {
+		return "NULL"
+	}
+	st.Params = append(st.Params, *v)
+	return "?"
+}
+
+// where matches the row whose key columns hold values.
+func (st *D1Statement) where(key []string, values []interface{}) string {
+	conds := make([]string, len(key))
+	for i, k := range key {
+		var v interface{}
+		if i < len(values) {
+			v = values[i]
+		}
+		if v == nil {
+			conds[i] = QuoteIdent(k) + " IS NULL"
+			continue
+		}
+		p := ParamValue(v)
+		conds[i] = QuoteIdent(k) + " = " + st.bind(&p)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// ParamValue renders a result value as a statement parameter. Unlike the
+// table rendering, numbers keep their full precision.
+func ParamValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	}
+	return fmt.Sprintf("%v", v)
+}
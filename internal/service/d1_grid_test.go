@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRowPageQuery(t *testing.T) {
+	users := SchemaTable{Name: "users", Columns: []SchemaColumn{{Name: "id", PK: true}, {Name: "email"}}}
+	st := RowPageQuery("users", RowKey(users), []interface{}{"9007199254740993"}, 51)
+	if st.SQL != `SELECT CAST("id" AS TEXT), * FROM "users" WHERE "id" > ? ORDER BY "id" LIMIT 51` || strings.Join(st.Params, ",") != "9007199254740993" {
+		t.Errorf("single key: %s %v", st.SQL, st.Params)
+	}
+
+	members := SchemaTable{Name: "members", Columns: []SchemaColumn{{Name: "org", PK: true}, {Name: "user", PK: true}}}
+	st = RowPageQuery("members", RowKey(members), []interface{}{"acme", "7"}, 51)
+	if st.SQL != `SELECT CAST("org" AS TEXT), CAST("user" AS TEXT), * FROM "members" WHERE ("org", "user") > (?, ?) ORDER BY "org", "user" LIMIT 51` {
+		t.Errorf("composite key: %s", st.SQL)
+	}
+
+	logs := SchemaTable{Name: "logs", Columns: []SchemaColumn{{Name: "line"}}}
+	st = RowPageQuery("logs", RowKey(logs), nil, 51)
+	if st.SQL != `SELECT CAST("rowid" AS TEXT), * FROM "logs" ORDER BY "rowid" LIMIT 51` || st.Params != nil {
+		t.Errorf("rowid: %s %v", st.SQL, st.Params)
+	}
+}
+
+func TestRowChangeStatements(t *testing.T) {
+	str := func(s string) *string { return &s }
+	stmts := RowChangeStatements("users", []string{"id"}, []RowChange{
+		{Kind: RowUpdate, Key: []interface{}{float64(1)}, Columns: []string{"email", "bio"}, Values: map[string]*string{"email": str("a@b.c"), "bio": nil}},
+		{Kind: RowInsert, Columns: []string{"email"}, Values: map[string]*string{"email": str("o'neil@x.y")}},
+		{Kind: RowInsert},
+		{Kind: RowDelete, Key: []interface{}{float64(3)}},
+		{Kind: RowUpdate, Key: []interface{}{float64(4)}},
+	})
+
+	var got []string
+	for _, st := range stmts {
+		got = append(got, st.String())
+	}
+	want := []string{
+		`UPDATE "users" SET "email" = ?, "bio" = NULL WHERE "id" = ?;  -- "a@b.c", "1"`,
+		`INSERT INTO "users" ("email") VALUES (?);  -- "o'neil@x.y"`,
+		`INSERT INTO "users" DEFAULT VALUES;`,
+		`DELETE FROM "users" WHERE "id" = ?;  -- "3"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("statements:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if inline := stmts[1].Inline(); inline != `INSERT INTO "users" ("email") VALUES ('o''neil@x.y');` {
+		t.Errorf("inline: %s", inline)
+	}
+}
+
+func TestUnmatchedRowChanges(t *testing.T) {
+	stmts := RowChangeStatements("users", []string{"id"}, []RowChange{
+		{Kind: RowUpdate, Key: []interface{}{"9007199254740993"}, Columns: []string{"email"}, Values: map[string]*string{"email": nil}},
+		{Kind: RowDelete, Key: []interface{}{"2"}},
+		{Kind: RowInsert},
+	})
+	sets := []D1ResultSet{{Changes: 0}, {Changes: 1}, {Changes: 1}}
+	unmatched := UnmatchedRowChanges(stmts, sets)
+	if len(unmatched) != 1 || unmatched[0].SQL != stmts[0].SQL || unmatched[0].Params[0] != "9007199254740993" {
+		t.Fatalf("unmatched: %v", unmatched)
+	}
+	if UnmatchedRowChanges(stmts, sets[:1]) != nil {
+		t.Error("misaligned sets should not be checked")
+	}
+}
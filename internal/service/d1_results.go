@@ -0,0 +1,184 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Result export formats.
+const (
+	ResultFormatCSV      = "csv"
+	ResultFormatJSON     = "json"
+	ResultFormatMarkdown = "md"
+)
+
+// ResultFormats lists the formats WriteResultSet accepts.
+var ResultFormats = []string{ResultFormatCSV, ResultFormatJSON, ResultFormatMarkdown}
+
+// PagedQuery wraps a single read query in LIMIT/OFFSET so a large result can
+// be fetched a page at a time. It reports false, leaving the query alone,
+// for scripts, writes and queries that set their own LIMIT.
+func PagedQuery(sql string, limit, offset int) (string, bool) {
+	stmts := SplitSQLStatements(sql)
+	if len(stmts) != 1 {
+		return sql, false
+	}
+	words := topLevelWords(stmts[0].SQL)
+	if len(words) == 0 || (words[0] != "SELECT" && words[0] != "WITH" && words[0] != "VALUES") {
+		return sql, false
+	}
+	for _, w := range words {
+		switch w {
+		case "LIMIT", "INSERT", "UPDATE", "DELETE", "REPLACE":
+			return sql, false
+		}
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) LIMIT %d OFFSET %d", strings.TrimRight(stmts[0].SQL, "; \t\n"), limit, offset), true
+}
+
+// topLevelWords returns the upper-cased keywords of a statement that sit
+// outside parentheses, quotes and comments.
+func topLevelWords(sql string) []string {
+	var (
+		words []string
+		word  strings.Builder
+		depth int
+		quote byte
+	)
+	endWord := func() {
+		if word.Len() > 0 && depth == 0 {
+			words = append(words, strings.ToUpper(word.String()))
+		}
+		word.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			endWord()
+			quote = c
+		case c == '[':
+			endWord()
+			quote = ']'
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			endWord()
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			endWord()
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sql)
+			}
+		case c == '(':
+			endWord()
+			depth++
+		case c == ')':
+			endWord()
+			depth--
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			word.WriteByte(c)
+		default:
+			endWord()
+		}
+	}
+	endWord()
+	return words
+}
+
+// WriteResultSet writes rows as CSV, a JSON array of objects or a Markdown
+// table.
+func WriteResultSet(w io.Writer, format string, set D1ResultSet) error {
+	switch format {
+	case ResultFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(set.Columns); err != nil {
+			return err
+		}
+		for _, row := range set.Rows {
+			rec := make([]string, len(set.Columns))
+			for i := range rec {
+				if i < len(row) && row[i] != nil {
+					rec[i] = cellToString(row[i])
+				}
+			}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case ResultFormatJSON:
+		// Objects are written by hand to keep the columns in query order
+		var b strings.Builder
+		b.WriteString("[")
+		for ri, row := range set.Rows {
+			if ri > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n  {")
+			for i, col := range set.Columns {
+				var v interface{}
+				if i < len(row) {
+					v = row[i]
+				}
+				key, _ := json.Marshal(col)
+				val, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.Write(key)
+				b.WriteString(": ")
+				b.Write(val)
+			}
+			b.WriteString("}")
+		}
+		if len(set.Rows) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("]\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+
+	case ResultFormatMarkdown:
+		cell := func(s string) string {
+			s = strings.ReplaceAll(s, "|", `\|`)
+			return strings.ReplaceAll(s, "\n", "<br>")
+		}
+		var b strings.Builder
+		header := make([]string, len(set.Columns))
+		rule := make([]string, len(set.Columns))
+		for i, col := range set.Columns {
+			header[i] = cell(col)
+			rule[i] = "---"
+		}
+		fmt.Fprintf(&b, "| %s |\n| %s |\n", strings.Join(header, " | "), strings.Join(rule, " | "))
+		for _, row := range set.Rows {
+			vals := make([]string, len(set.Columns))
+			for i := range vals {
+				if i < len(row) {
+					vals[i] = cell(cellToString(row[i]))
+				}
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(vals, " | "))
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	return fmt.Errorf("unknown format %q (use %s)", format, strings.Join(ResultFormats, ", "))
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPagedQuery(t *testing.T) {
+	for _, tc := range []struct {
+		sql   string
+		paged bool
+	}{
+		{"SELECT * FROM users;", true},
+		{"WITH t AS (SELECT 1 LIMIT 5) SELECT * FROM t", true},
+		{"select name from users where note = 'limit'", true},
+		{"SELECT * FROM users LIMIT 10", false},
+		{"SELECT 1; SELECT 2", false},
+		{"INSERT INTO users VALUES (1)", false},
+		{"WITH t AS (SELECT 1) DELETE FROM users", false},
+		{"PRAGMA table_info(users)", false},
+	} {
+		got, paged := PagedQuery(tc.sql, 101, 200)
+		if paged != tc.paged {
+			t.Errorf("%q: paged = %v, want %v", tc.sql, paged, tc.paged)
+			continue
+		}
+		if paged && !strings.HasSuffix(got, ") LIMIT 101 OFFSET 200") {
+			t.Errorf("%q: got %q", tc.sql, got)
+		}
+	}
+}
+
+func TestWriteResultSet(t *testing.T) {
+	set := D1ResultSet{
+		Columns: []string{"id", "name", "note"},
+		Rows:    [][]interface{}{{float64(1), "a|b", nil}, {float64(2), "x,y", "two\nlines"}},
+	}
+	want := map[string]string{
+		ResultFormatCSV:      "id,name,note\n1,a|b,\n2,\"x,y\",\"two\nlines\"\n",
+		ResultFormatJSON:     "[\n  {\"id\": 1, \"name\": \"a|b\", \"note\": null},\n  {\"id\": 2, \"name\": \"x,y\", \"note\": \"two\\nlines\"}\n]\n",
+		ResultFormatMarkdown: "| id | name | note |\n| --- | --- | --- |\n| 1 | a\\|b | NULL |\n| 2 | x,y | two<br>lines |\n",
+	}
+	for format, w := range want {
+		var b strings.Builder
+		if err := WriteResultSet(&b, format, set); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if b.String() != w {
+			t.Errorf("%s:\ngot  %q\nwant %q", format, b.String(), w)
+		}
+	}
+	if err := WriteResultSet(&strings.Builder{}, "xml", set); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaChangeKind classifies a difference between two D1 schemas.
+type SchemaChangeKind int
+
+const (
+	SchemaTableAdded    SchemaChangeKind = iota // table only in the source
+	SchemaTableRemoved                          // table only in the target
+	SchemaColumnAdded                           // column only in the source
+	SchemaColumnRemoved                         // column only in the target
+	SchemaColumnChanged                         // type or constraints differ
+	SchemaFKChanged                             // foreign keys differ
+	SchemaIndexAdded                            // index only in the source
+	SchemaIndexRemoved                          // index only in the target
+	SchemaIndexChanged                          // index definition differs
+)
+
+// SchemaChange is one difference found by DiffSchemas. Name is the column
+// or index; Detail says how it differs.
+type SchemaChange struct {
+	Kind   SchemaChangeKind
+	Table  string
+	Name   string
+	Detail string
+}
+
+// String renders the change as a one-line summary, prefixed with + for
+// something the target lacks, - for something only the target has and ~ for
+// a difference.
+func (c SchemaChange) String() string {
+	switch c.Kind {
+	case SchemaTableAdded:
+		return fmt.Sprintf("+ table %s", c.Table)
+	case SchemaTableRemoved:
+		return fmt.Sprintf("- table %s", c.Table)
+	case SchemaColumnAdded:
+		return fmt.Sprintf("+ column %s.%s %s", c.Table, c.Name, c.Detail)
+	case SchemaColumnRemoved:
+		return fmt.Sprintf("- column %s.%s %s", c.Table, c.Name, c.Detail)
+	case SchemaColumnChanged:
+		return fmt.Sprintf("~ column %s.%s: %s", c.Table, c.Name, c.Detail)
+	case SchemaFKChanged:
+		return fmt.Sprintf("~ foreign keys of %s: %s", c.Table, c.Detail)
+	case SchemaIndexAdded:
+		return fmt.Sprintf("+ index %s on %s", c.Name, c.Table)
+	case SchemaIndexRemoved:
+		return fmt.Sprintf("- index %s on %s", c.Name, c.Table)
+	default:
+		return fmt.Sprintf("~ index %s on %s", c.Name, c.Table)
+	}
+}
+
+// DiffSchemas lists the differences that would have to change in target for
+// it to match source, table by table in name order.
+func DiffSchemas(source, target []SchemaTable) []SchemaChange {
+	src, dst := tablesByName(source), tablesByName(target)
+	var changes []SchemaChange
+
+	for _, name := range unionKeys(src, dst) {
+		s, inSrc := src[name]
+		t, inDst := dst[name]
+		switch {
+		case !inDst:
+			changes = append(changes, SchemaChange{Kind: SchemaTableAdded, Table: name})
+			continue
+		case !inSrc:
+			changes = append(changes, SchemaChange{Kind: SchemaTableRemoved, Table: name})
+			continue
+		}
+
+		// Columns, in the source's order, then columns only the target has
+		tcols := make(map[string]SchemaColumn)
+		for _, c := range t.Columns {
+			tcols[c.Name] = c
+		}
+		scols := make(map[string]bool)
+		for _, c := range s.Columns {
+			scols[c.Name] = true
+			tc, ok := tcols[c.Name]
+			switch {
+			case !ok:
+				changes = append(changes, SchemaChange{Kind: SchemaColumnAdded, Table: name, Name: c.Name, Detail: columnDesc(c)})
+			case !columnsEqual(c, tc):
+				changes = append(changes, SchemaChange{Kind: SchemaColumnChanged, Table: name, Name: c.Name,
+					Detail: fmt.Sprintf("%s → %s", columnDesc(tc), columnDesc(c))})
+			}
+		}
+		for _, c := range t.Columns {
+			if !scols[c.Name] {
+				changes = append(changes, SchemaChange{Kind: SchemaColumnRemoved, Table: name, Name: c.Name, Detail: columnDesc(c)})
+			}
+		}
+
+		if sf, tf := fkList(s.FKs), fkList(t.FKs); sf != tf {
+			changes = append(changes, SchemaChange{Kind: SchemaFKChanged, Table: name, Detail: fmt.Sprintf("%s → %s", orNone(tf), orNone(sf))})
+		}
+
+		sidx, tidx := indexesByName(s.Indexes), indexesByName(t.Indexes)
+		for _, idx := range unionKeys(sidx, tidx) {
+			si, inS := sidx[idx]
+			ti, inT := tidx[idx]
+			switch {
+			case !inT:
+				changes = append(changes, SchemaChange{Kind: SchemaIndexAdded, Table: name, Name: idx})
+			case !inS:
+				changes = append(changes, SchemaChange{Kind: SchemaIndexRemoved, Table: name, Name: idx})
+			case normalizeSQL(si.SQL) != normalizeSQL(ti.SQL):
+				changes = append(changes, SchemaChange{Kind: SchemaIndexChanged, Table: name, Name: idx})
+			}
+		}
+	}
+	return changes
+}
+
+// SchemaMigration drafts the SQL that changes target to match source,
+// returning "" when they already match. Columns are added with ALTER TABLE;
+// tables whose columns or foreign keys changed are rebuilt, copying the
+// columns both sides share. Statements that drop data only the target has
+// are written commented out.
+func SchemaMigration(source, target []SchemaTable, sourceName, targetName string) string {
+	changes := DiffSchemas(source, target)
+	if len(changes) == 0 {
+		return ""
+	}
+	src, dst := tablesByName(source), tablesByName(target)
+	byTable := make(map[string][]SchemaChange)
+	for _, c := range changes {
+		byTable[c.Table] = append(byTable[c.Table], c)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Draft migration: make %s match %s\n", targetName, sourceName)
+	fmt.Fprintf(&b, "-- Generated by orangeshell on %s. Review before applying.\n", time.Now().Format("2006-01-02 15:04"))
+
+	var dropped []string
+	for _, name := range unionKeys(src, dst) {
+		tc := byTable[name]
+		if len(tc) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		switch tc[0].Kind {
+		case SchemaTableAdded:
+			fmt.Fprintf(&b, "-- New table %s\n", name)
+			writeCreateTable(&b, src[name], name)
+			continue
+		case SchemaTableRemoved:
+			dropped = append(dropped, name)
+			continue
+		}
+
+		if reason := rebuildReason(tc); reason != "" {
+			writeRebuild(&b, src[name], dst[name], reason, referencing(target, name))
+			continue
+		}
+		for _, c := range tc {
+			switch c.Kind {
+			case SchemaColumnAdded:
+				col := findColumn(src[name].Columns, c.Name)
+				fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN %s;\n", QuoteIdent(name), columnDef(col))
+			case SchemaColumnRemoved:
+				fmt.Fprintf(&b, "-- Drops data: column %s is only in %s\n", c.Name, targetName)
+				fmt.Fprintf(&b, "-- ALTER TABLE %s DROP COLUMN %s;\n", QuoteIdent(name), QuoteIdent(c.Name))
+			case SchemaIndexRemoved:
+				fmt.Fprintf(&b, "DROP INDEX IF EXISTS %s;\n", QuoteIdent(c.Name))
+			case SchemaIndexChanged:
+				fmt.Fprintf(&b, "DROP INDEX IF EXISTS %s;\n", QuoteIdent(c.Name))
+				fmt.Fprintf(&b, "%s;\n", strings.TrimRight(indexesByName(src[name].Indexes)[c.Name].SQL, "; \n"))
+			case SchemaIndexAdded:
+				fmt.Fprintf(&b, "%s;\n", strings.TrimRight(indexesByName(src[name].Indexes)[c.Name].SQL, "; \n"))
+			}
+		}
+	}
+
+	if len(dropped) > 0 {
+		fmt.Fprintf(&b, "\n-- Drops data: these tables are only in %s\n", targetName)
+		for _, name := range dropped {
+			fmt.Fprintf(&b, "-- DROP TABLE %s;\n", QuoteIdent(name))
+		}
+	}
+	return b.String()
+}
+
+// rebuildReason says why a table's changes need a rebuild, or "" when
+// ALTER TABLE can apply them.
+func rebuildReason(changes []SchemaChange) string {
+	for _, c := range changes {
+		switch c.Kind {
+		case SchemaColumnChanged:
+			return fmt.Sprintf("column %s changed (%s)", c.Name, c.Detail)
+		case SchemaFKChanged:
+			return "foreign keys changed"
+		case SchemaColumnAdded:
+			// SQLite can't add a primary key, or a NOT NULL column without a default
+			if strings.Contains(c.Detail, "PRIMARY KEY") ||
+				(strings.Contains(c.Detail, "NOT NULL") && !strings.Contains(c.Detail, "DEFAULT")) {
+				return fmt.Sprintf("column %s can't be added with ALTER TABLE", c.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// writeRebuild writes SQLite's table rebuild: create the new definition
+// under a temporary name, copy shared columns, swap, then recreate indexes.
+// children are the other tables with foreign keys to the table: deferring
+// foreign keys doesn't stop the DROP TABLE from running their ON DELETE
+// actions, so their rows are flagged as at risk.
+func writeRebuild(b *strings.Builder, s, t SchemaTable, reason string, children []string) {
+	tmp := "_" + s.Name + "_new"
+	var shared, lost []string
+	for _, c := range s.Columns {
+		if findColumn(t.Columns, c.Name).Name != "" {
+			shared = append(shared, QuoteIdent(c.Name))
+		}
+	}
+	for _, c := range t.Columns {
+		if findColumn(s.Columns, c.Name).Name == "" {
+			lost = append(lost, c.Name)
+		}
+	}
+
+	fmt.Fprintf(b, "-- Rebuild %s: %s\n", s.Name, reason)
+	if len(lost) > 0 {
+		fmt.Fprintf(b, "-- Drops data: columns %s are not copied\n", strings.Join(lost, ", "))
+	}
+	if len(children) > 0 {
+		fmt.Fprintf(b, "-- Drops data: %s reference %s; dropping it runs their ON DELETE actions\n", strings.Join(children, ", "), s.Name)
+		b.WriteString("-- (CASCADE deletes their rows, SET NULL clears their keys). Back them up and restore them after.\n")
+	}
+	b.WriteString("PRAGMA defer_foreign_keys = on;\n")
+	writeCreateTable(b, SchemaTable{Name: tmp, SQL: renameCreateTable(s.SQL, tmp), Columns: s.Columns, FKs: s.FKs}, tmp)
+	if len(shared) > 0 {
+		cols := strings.Join(shared, ", ")
+		fmt.Fprintf(b, "INSERT INTO %s (%s) SELECT %s FROM %s;\n", QuoteIdent(tmp), cols, cols, QuoteIdent(s.Name))
+	}
+	fmt.Fprintf(b, "DROP TABLE %s;\n", QuoteIdent(s.Name))
+	fmt.Fprintf(b, "ALTER TABLE %s RENAME TO %s;\n", QuoteIdent(tmp), QuoteIdent(s.Name))
+	for _, idx := range s.Indexes {
+		fmt.Fprintf(b, "%s;\n", strings.TrimRight(idx.SQL, "; \n"))
+	}
+	b.WriteString("PRAGMA defer_foreign_keys = off;\n")
+}
+
+// referencing returns the tables other than name with a foreign key to it,
+// sorted.
+func referencing(tables []SchemaTable, name string) []string {
+	var children []string
+	for _, t := range tables {
+		if strings.EqualFold(t.Name, name) {
+			continue
+		}
+		for _, fk := range t.FKs {
+			if strings.EqualFold(fk.ToTable, name) {
+				children = append(children, t.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// writeCreateTable writes a table's CREATE statement and its indexes,
+// building the statement from the columns when the SQL is unknown.
+func writeCreateTable(b *strings.Builder, t SchemaTable, name string) {
+	if t.SQL != "" {
+		fmt.Fprintf(b, "%s;\n", strings.TrimRight(t.SQL, "; \n"))
+	} else {
+		var defs, pk []string
+		for _, c := range t.Columns {
+			if c.PK {
+				pk = append(pk, QuoteIdent(c.Name))
+			}
+		}
+		for _, c := range t.Columns {
+			def := columnDef(c)
+			if c.PK && len(pk) > 1 {
+				def = strings.Replace(def, " PRIMARY KEY", "", 1)
+			}
+			defs = append(defs, def)
+		}
+		if len(pk) > 1 {
+			defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pk, ", ")))
+		}
+		for _, fk := range t.FKs {
+			defs = append(defs, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)", QuoteIdent(fk.FromCol), QuoteIdent(fk.ToTable), QuoteIdent(fk.ToCol)))
+		}
+		fmt.Fprintf(b, "CREATE TABLE %s (\n  %s\n);\n", QuoteIdent(name), strings.Join(defs, ",\n  "))
+	}
+	if name == t.Name {
+		for _, idx := range t.Indexes {
+			fmt.Fprintf(b, "%s;\n", strings.TrimRight(idx.SQL, "; \n"))
+		}
+	}
+}
+
+var createTableName = regexp.MustCompile("(?is)^(\\s*CREATE\\s+TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?)(\"(?:[^\"]|\"\")+\"|`[^`]+`|\\[[^\\]]+\\]|[^\\s(]+)")
+
+// renameCreateTable rewrites the table name of a CREATE TABLE statement,
+// returning "" if the statement can't be read.
+func renameCreateTable(sql, name string) string {
+	loc := createTableName.FindStringSubmatchIndex(sql)
+	if loc == nil {
+		return ""
+	}
+	return sql[:loc[4]] + QuoteIdent(name) + sql[loc[5]:]
+}
+
+// QuoteIdent quotes an SQLite identifier.
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columnDesc describes a column's type and constraints.
+func columnDesc(c SchemaColumn) string {
+	parts := []string{c.Type}
+	if c.Type == "" {
+		parts[0] = "ANY"
+	}
+	if c.PK {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if c.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.Default != "" {
+		parts = append(parts, "DEFAULT "+c.Default)
+	}
+	return strings.Join(parts, " ")
+}
+
+// columnDef is a column definition for CREATE or ALTER TABLE.
+func columnDef(c SchemaColumn) string {
+	def := QuoteIdent(c.Name)
+	if c.Type != "" {
+		def += " " + c.Type
+	}
+	if c.PK {
+		def += " PRIMARY KEY"
+	}
+	if c.NotNull {
+		def += " NOT NULL"
+	}
+	if c.Default != "" {
+		def += " DEFAULT " + c.Default
+	}
+	return def
+}
+
+func columnsEqual(a, b SchemaColumn) bool {
+	return strings.EqualFold(strings.TrimSpace(a.Type), strings.TrimSpace(b.Type)) &&
+		a.NotNull == b.NotNull && a.PK == b.PK && a.Default == b.Default
+}
+
+func findColumn(cols []SchemaColumn, name string) SchemaColumn {
+	for _, c := range cols {
+		if c.Name == name {
+			return c
+		}
+	}
+	return SchemaColumn{}
+}
+
+// fkList renders foreign keys as a sorted, comparable list.
+func fkList(fks []SchemaFK) string {
+	list := make([]string, len(fks))
+	for i, fk := range fks {
+		list[i] = fmt.Sprintf("%s → %s.%s", fk.FromCol, fk.ToTable, fk.ToCol)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// normalizeSQL collapses whitespace so formatting doesn't count as a change.
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(strings.TrimRight(sql, "; \n")), " ")
+}
+
+func tablesByName(tables []SchemaTable) map[string]SchemaTable {
+	m := make(map[string]SchemaTable, len(tables))
+	for _, t := range tables {
+		m[t.Name] = t
+	}
+	return m
+}
+
+func indexesByName(indexes []SchemaIndex) map[string]SchemaIndex {
+	m := make(map[string]SchemaIndex, len(indexes))
+	for _, idx := range indexes {
+		m[idx.Name] = idx
+	}
+	return m
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	local := []SchemaTable{
+		{
+			Name: "users",
+			SQL:  "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, bio TEXT DEFAULT '')",
+			Columns: []SchemaColumn{
+				{Name: "id", Type: "INTEGER", PK: true},
+				{Name: "email", Type: "TEXT", NotNull: true},
+				{Name: "bio", Type: "TEXT", Default: "''"},
+			},
+			Indexes: []SchemaIndex{{Name: "users_email", SQL: "CREATE UNIQUE INDEX users_email ON users (email)"}},
+		},
+		{
+			Name:    "posts",
+			SQL:     "CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id))",
+			Columns: []SchemaColumn{{Name: "id", Type: "INTEGER", PK: true}, {Name: "user_id", Type: "INTEGER"}},
+			FKs:     []SchemaFK{{FromCol: "user_id", ToTable: "users", ToCol: "id"}},
+		},
+	}
+	remote := []SchemaTable{
+		{
+			Name: "users",
+			SQL:  "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, legacy TEXT)",
+			Columns: []SchemaColumn{
+				{Name: "id", Type: "integer", PK: true},
+				{Name: "email", Type: "TEXT"},
+				{Name: "legacy", Type: "TEXT"},
+			},
+		},
+		{Name: "sessions", SQL: "CREATE TABLE sessions (id TEXT)", Columns: []SchemaColumn{{Name: "id", Type: "TEXT"}}},
+	}
+
+	var got []string
+	for _, c := range DiffSchemas(local, remote) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"+ table posts",
+		"- table sessions",
+		"~ column users.email: TEXT → TEXT NOT NULL",
+		"+ column users.bio TEXT DEFAULT ''",
+		"- column users.legacy TEXT",
+		"+ index users_email on users",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("diff:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if DiffSchemas(local, local) != nil || SchemaMigration(local, local, "a", "b") != "" {
+		t.Fatal("identical schemas should have no changes")
+	}
+}
+
+func TestSchemaMigration(t *testing.T) {
+	src := []SchemaTable{{
+		Name: "users",
+		SQL:  "CREATE TABLE \"users\" (id INTEGER PRIMARY KEY, email TEXT NOT NULL)",
+		Columns: []SchemaColumn{
+			{Name: "id", Type: "INTEGER", PK: true},
+			{Name: "email", Type: "TEXT", NotNull: true},
+		},
+		Indexes: []SchemaIndex{{Name: "users_email", SQL: "CREATE INDEX users_email ON users (email)"}},
+	}, {
+		Name:    "tags",
+		SQL:     "CREATE TABLE tags (name TEXT)",
+		Columns: []SchemaColumn{{Name: "name", Type: "TEXT"}},
+	}}
+	dst := []SchemaTable{{
+		Name:    "users",
+		SQL:     "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, old TEXT)",
+		Columns: []SchemaColumn{{Name: "id", Type: "INTEGER", PK: true}, {Name: "email", Type: "TEXT"}, {Name: "old", Type: "TEXT"}},
+	}, {
+		Name:    "tags",
+		SQL:     "CREATE TABLE tags (name TEXT, color TEXT)",
+		Columns: []SchemaColumn{{Name: "name", Type: "TEXT"}, {Name: "color", Type: "TEXT"}},
+	}, {
+		Name:    "audit",
+		SQL:     "CREATE TABLE audit (id INTEGER)",
+		Columns: []SchemaColumn{{Name: "id", Type: "INTEGER"}},
+	}}
+	// A child of users on both sides: rebuilding users puts its rows at risk
+	sessions := SchemaTable{
+		Name:    "sessions",
+		SQL:     "CREATE TABLE sessions (user_id INTEGER REFERENCES users(id) ON DELETE CASCADE)",
+		Columns: []SchemaColumn{{Name: "user_id", Type: "INTEGER"}},
+		FKs:     []SchemaFK{{FromCol: "user_id", ToTable: "users", ToCol: "id"}},
+	}
+	src = append(src, sessions)
+	dst = append(dst, sessions)
+
+	sql := SchemaMigration(src, dst, "local", "remote")
+	for _, want := range []string{
+		"-- Rebuild users: column email changed (TEXT → TEXT NOT NULL)\n",
+		"-- Drops data: columns old are not copied\n",
+		"-- Drops data: sessions reference users; dropping it runs their ON DELETE actions\n",
+		"CREATE TABLE \"_users_new\" (id INTEGER PRIMARY KEY, email TEXT NOT NULL);\n",
+		"INSERT INTO \"_users_new\" (\"id\", \"email\") SELECT \"id\", \"email\" FROM \"users\";\n",
+		"DROP TABLE \"users\";\nALTER TABLE \"_users_new\" RENAME TO \"users\";\nCREATE INDEX users_email ON users (email);\n",
+		"-- ALTER TABLE \"tags\" DROP COLUMN \"color\";\n",
+		"-- DROP TABLE \"audit\";\n",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("migration lacks %q:\n%s", want, sql)
+		}
+	}
+
+	// Adding a nullable column needs no rebuild
+	sql = SchemaMigration(dst[1:2], src[1:2], "remote", "local")
+	if !strings.Contains(sql, "ALTER TABLE \"tags\" ADD COLUMN \"color\" TEXT;\n") || strings.Contains(sql, "Rebuild") {
+		t.Errorf("unexpected migration:\n%s", sql)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go/v6"
+	"github.com/cloudflare/cloudflare-go/v6/d1"
+)
+
+// D1TimeTravelWindow is how far back Time Travel can restore a database on
+// the Workers Paid plan (the Free plan keeps 7 days).
+const D1TimeTravelWindow = 30 * 24 * time.Hour
+
+// D1RestoreResult is the outcome of a Time Travel restore.
+type D1RestoreResult struct {
+	Bookmark         string // state of the database after the restore
+	PreviousBookmark string // state before; restoring to it undoes the restore
+	Message          string
+}
+
+// Bookmark returns the Time Travel bookmark of a database at a point in
+// time, or its current bookmark when at is zero.
+func (s *D1Service) Bookmark(ctx context.Context, id string, at time.Time) (string, error) {
+	params := d1.DatabaseTimeTravelGetBookmarkParams{AccountID: cloudflare.F(s.accountID)}
+	if !at.IsZero() {
+		params.Timestamp = cloudflare.F(at.UTC())
+	}
+	resp, err := s.client.D1.Database.TimeTravel.GetBookmark(ctx, id, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get bookmark: %w", err)
+	}
+	return resp.Bookmark, nil
+}
+
+// Restore restores a database to the state recorded by a bookmark. The
+// result's PreviousBookmark undoes the restore.
+func (s *D1Service) Restore(ctx context.Context, id, bookmark string) (*D1RestoreResult, error) {
+	resp, err := s.client.D1.Database.TimeTravel.Restore(ctx, id, d1.DatabaseTimeTravelRestoreParams{
+		AccountID: cloudflare.F(s.accountID),
+		Bookmark:  cloudflare.F(bookmark),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("restore failed: %w", err)
+	}
+
+	s.mu.Lock()
+	s.cacheTime = time.Time{}
+	s.mu.Unlock()
+
+	return &D1RestoreResult{
+		Bookmark:         resp.Bookmark,
+		PreviousBookmark: resp.PreviousBookmark,
+		Message:          resp.Message,
+	}, nil
+}
+
+var bookmarkRe = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{8}-[0-9a-f]{8}-[0-9a-f]{32}$`)
+
+// IsD1Bookmark reports whether s looks like a Time Travel bookmark, e.g.
+// 00000085-0000024c-00004c6d-8e61117bf38d7adb71b934ebbf891683.
+func IsD1Bookmark(s string) bool {
+	return bookmarkRe.MatchString(s)
+}
+
+// ParsePointInTime parses a point in time typed by the user: an RFC 3339
+// timestamp, a local "2006-01-02 15:04[:05]" time or date, "now", or a
+// duration ago such as "90m", "2h30m ago" or "3d".
+func ParsePointInTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return time.Time{}, fmt.Errorf("enter a time")
+	}
+	if s == "now" {
+		return now, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02t15:04:05", "2006-01-02t15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	ago := strings.TrimSpace(strings.TrimSuffix(s, "ago"))
+	ago = strings.TrimPrefix(ago, "-")
+	if d, err := parseDays(ago); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time — use e.g. 2h ago, 3d or 2024-05-01 14:30", s)
+}
+
+// parseDays is time.ParseDuration with a leading "Nd" for days.
+func parseDays(s string) (time.Duration, error) {
+	var days time.Duration
+	if i := strings.IndexByte(s, 'd'); i > 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, err
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[i+1:]
+		if s == "" {
+			return days, nil
+		}
+	}
+	d, err := time.ParseDuration(strings.ReplaceAll(s, " ", ""))
+	return days + d, err
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePointInTime(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for in, want := range map[string]time.Time{
+		"now":                  now,
+		"2h ago":               now.Add(-2 * time.Hour),
+		"90m":                  now.Add(-90 * time.Minute),
+		"-1h30m":               now.Add(-90 * time.Minute),
+		"3d":                   now.Add(-72 * time.Hour),
+		"1d12h ago":            now.Add(-36 * time.Hour),
+		"2024-05-01":           time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-01 14:30":     time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC),
+		"2024-05-01T14:30:00Z": time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC),
+	} {
+		got, err := ParsePointInTime(in, now)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("%q = %v, want %v", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "yesterday", "0m", "2024-13-01"} {
+		if _, err := ParsePointInTime(in, now); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestIsD1Bookmark(t *testing.T) {
+	if !IsD1Bookmark("00000085-0000024c-00004c6d-8e61117bf38d7adb71b934ebbf891683") {
+		t.Error("valid bookmark rejected")
+	}
+	if IsD1Bookmark("2h ago") || IsD1Bookmark("00000085-0000024c") {
+		t.Error("invalid bookmark accepted")
+	}
+}
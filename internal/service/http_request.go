@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxResponseBody caps how much of a response body is kept for display.
+const maxResponseBody = 1 << 20
+
+// HTTPHeader is one request or response header.
+type HTTPHeader struct {
+	Name  string
+	Value string
+}
+
+// ParseHTTPHeader parses a "Name: value" line. Reports false for a line
+// without a name.
+func ParseHTTPHeader(line string) (HTTPHeader, bool) {
+	name, value, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return HTTPHeader{}, false
+	}
+	return HTTPHeader{Name: name, Value: strings.TrimSpace(value)}, true
+}
+
+// HTTPRequest is a request composed by hand.
+type HTTPRequest struct {
+	Method  string
+	URL     string
+	Headers []HTTPHeader
+	Body    string
+}
+
+// HTTPTiming breaks down how long a request took. Phases that didn't happen
+// (a reused connection, plain HTTP) are zero.
+type HTTPTiming struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	TTFB    time.Duration // from sending the request to the first response byte
+	Total   time.Duration
+}
+
+// HTTPResponse is the response to an HTTPRequest.
+type HTTPResponse struct {
+	Proto      string
+	Status     string // e.g. "200 OK"
+	StatusCode int
+	Headers    []HTTPHeader // sorted by name
+	Body       []byte
+	Truncated  bool // Body holds only the first maxResponseBody bytes
+	Timing     HTTPTiming
+}
+
+// SendHTTPRequest sends req and reads its response. Redirects are returned
+// rather than followed, so the response shows what the Worker answered.
+func SendHTTPRequest(ctx context.Context, req HTTPRequest) (*HTTPResponse, error) {
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range req.Headers {
+		if strings.EqualFold(h.Name, "Host") {
+			httpReq.Host = h.Value
+			continue
+		}
+		httpReq.Header.Add(h.Name, h.Value)
+	}
+
+	var t HTTPTiming
+	var dnsStart, connectStart, tlsStart, wrote time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { t.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.TLS = time.Since(tlsStart) },
+		WroteRequest:      func(httptrace.WroteRequestInfo) { wrote = time.Now() },
+		GotFirstResponseByte: func() {
+			if !wrote.IsZero() {
+				t.TTFB = time.Since(wrote)
+			}
+		},
+	}
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	client := &http.Client{
+		// A fresh transport so every request shows its full connection setup
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DisableKeepAlives: true},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	t.Total = time.Since(start)
+
+	out := &HTTPResponse{
+		Proto:      resp.Proto,
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Body:       data,
+		Timing:     t,
+	}
+	if len(data) > maxResponseBody {
+		out.Body = data[:maxResponseBody]
+		out.Truncated = true
+	}
+	for name, values := range resp.Header {
+		for _, v := range values {
+			out.Headers = append(out.Headers, HTTPHeader{Name: name, Value: v})
+		}
+	}
+	sort.SliceStable(out.Headers, func(i, j int) bool { return out.Headers[i].Name < out.Headers[j].Name })
+	return out, nil
+}
+
+// MatchRequestLines returns the tail lines of the invocations that served a
+// request to method and url: each matching "request" line with the log
+// lines that follow it, up to the next invocation.
+func MatchRequestLines(lines []TailLine, method, url string) []TailLine {
+	prefix := method + " " + url + " "
+	var matched []TailLine
+	in := false
+	for _, l := range lines {
+		if l.Level == "request" {
+			in = strings.HasPrefix(l.Text, prefix)
+		}
+		if in {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
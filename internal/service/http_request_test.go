@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendHTTPRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Echo", r.Header.Get("X-Token"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(append([]byte(r.Method+" "), body...))
+	}))
+	defer srv.Close()
+
+	resp, err := SendHTTPRequest(context.Background(), HTTPRequest{
+		Method:  "POST",
+		URL:     srv.URL + "/items",
+		Headers: []HTTPHeader{{Name: "X-Token", Value: "abc"}},
+		Body:    `{"a":1}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 201 || string(resp.Body) != `POST {"a":1}` {
+		t.Errorf("got %d %q", resp.StatusCode, resp.Body)
+	}
+	echoed := false
+	for _, h := range resp.Headers {
+		echoed = echoed || (h.Name == "X-Echo" && h.Value == "abc")
+	}
+	if !echoed {
+		t.Errorf("request header not sent: %v", resp.Headers)
+	}
+
+	resp, err = SendHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL + "/old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("redirect was followed: %d", resp.StatusCode)
+	}
+}
+
+func TestMatchRequestLines(t *testing.T) {
+	lines := []TailLine{
+		{Level: "request", Text: "GET https://api.example.com/users  ok"},
+		{Level: "log", Text: "listing users"},
+		{Level: "request", Text: "GET https://api.example.com/users/1  ok"},
+		{Level: "log", Text: "one user"},
+		{Level: "request", Text: "GET https://api.example.com/users  exception"},
+		{Level: "exception", Text: "boom"},
+	}
+	got := MatchRequestLines(lines, "GET", "https://api.example.com/users")
+	want := []string{"GET https://api.example.com/users  ok", "listing users", "GET https://api.example.com/users  exception", "boom"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Text != want[i] {
+			t.Errorf("line %d: %q, want %q", i, got[i].Text, want[i])
+		}
+	}
+}
@@ -114,10 +114,13 @@ func (m *Model) SetDeployProgress(status string) {
 // IsProvisioned returns true if the AI backend is ready to use.
 // For Workers AI, this means the proxy Worker is deployed.
 // For HTTP backends, this means an endpoint URL is configured.
+// For the local backend, this means a command is configured.
 func (m Model) IsProvisioned() bool {
 	switch m.settings.backendType {
 	case config.AIBackendHTTP:
 		return m.settings.httpEndpoint != ""
+	case config.AIBackendLocal:
+		return strings.TrimSpace(m.settings.localCommand) != ""
 	default:
 		return m.settings.workerURL != ""
 	}
@@ -171,6 +174,15 @@ func (m *Model) RebuildBackend() {
 			HTTPProtocol(m.settings.httpProtocol),
 			m.settings.httpAPIKey,
 		))
+	case config.AIBackendLocal:
+		if strings.TrimSpace(m.settings.localCommand) == "" {
+			m.SetBackend(nil)
+			return
+		}
+		m.SetBackend(NewLocalBackend(
+			m.settings.localCommand,
+			LocalProtocol(m.settings.localProtocol),
+		))
 	default: // Workers AI
 		if m.settings.workerURL == "" {
 			m.SetBackend(nil)
@@ -193,6 +205,8 @@ func (m Model) backendDisplayName() string {
 	switch m.settings.backendType {
 	case config.AIBackendHTTP:
 		return "HTTP Endpoint"
+	case config.AIBackendLocal:
+		return "Local Command"
 	default:
 		return ModelDisplayName(m.settings.modelPreset)
 	}
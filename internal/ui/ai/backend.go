@@ -4,7 +4,8 @@ import "context"
 
 // Backend is the provider-agnostic interface for AI chat backends.
 // Implementations include Workers AI (via deployed proxy), HTTP endpoints
-// (OpenCode serve, Ollama, LM Studio), and future local agent processes.
+// (OpenCode serve, Ollama, LM Studio), and local agent processes spoken to
+// over stdin/stdout.
 //
 // All backends produce a streaming channel of text chunks using the same
 // protocol as the existing Workers AI client — the app-layer bridge
@@ -24,3 +25,10 @@ type Backend interface {
 	// child processes, etc.). It is safe to call Close multiple times.
 	Close() error
 }
+
+// Aborter is implemented by backends that keep server- or process-side state
+// which must be told explicitly to stop generating when the user presses ESC.
+// Cancelling the stream context alone only stops the client from reading.
+type Aborter interface {
+	Abort()
+}
@@ -0,0 +1,526 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// LocalProtocol identifies how the local backend talks to its child process.
+type LocalProtocol string
+
+const (
+	// LocalProtocolJSONL keeps one agent process alive for the whole
+	// conversation and exchanges newline-delimited JSON messages with it.
+	//
+	// Requests written to the agent's stdin:
+	//
+	//	{"type":"chat","id":1,"messages":[{"role":"system","content":"..."}, ...]}
+	//	{"type":"abort","id":1}
+	//
+	// Events read from the agent's stdout:
+	//
+	//	{"type":"token","id":1,"content":"..."}
+	//	{"type":"done","id":1}
+	//	{"type":"error","id":1,"error":"..."}
+	//
+	// Events carrying an id other than the active request are dropped, so a
+	// late "token" from an aborted request never bleeds into the next answer.
+	LocalProtocolJSONL LocalProtocol = "jsonl"
+
+	// LocalProtocolText spawns the command once per prompt, writes the
+	// flattened conversation to stdin, closes it, and streams stdout back
+	// verbatim. Works with plain CLIs such as "ollama run <model>" or
+	// "llama-cli -m model.gguf".
+	LocalProtocolText LocalProtocol = "text"
+)
+
+// localStderrLines is how many trailing stderr lines are kept for error reports.
+const localStderrLines = 5
+
+// LocalBackend implements Backend by running a command on this machine and
+// speaking to it over stdin/stdout. No network access is required.
+type LocalBackend struct {
+	Command  string        // full command line, e.g. "ollama run llama3.1"
+	Protocol LocalProtocol // wire protocol
+
+	// JSONL state: the long-lived agent process and the request it is serving.
+	mu       sync.Mutex
+	proc     *localProcess
+	nextID   int
+	activeID int
+}
+
+// NewLocalBackend creates a Backend that spawns the given command locally.
+func NewLocalBackend(command string, protocol LocalProtocol) *LocalBackend {
+	return &LocalBackend{
+		Command:  strings.TrimSpace(command),
+		Protocol: protocol,
+	}
+}
+
+// StreamResponse implements Backend.
+func (b *LocalBackend) StreamResponse(ctx context.Context, messages []ChatMessage) <-chan string {
+	switch b.Protocol {
+	case LocalProtocolText:
+		return b.streamText(ctx, messages)
+	default:
+		return b.streamJSONL(ctx, messages)
+	}
+}
+
+// Name implements Backend.
+func (b *LocalBackend) Name() string {
+	args := splitCommandLine(b.Command)
+	if len(args) == 0 {
+		return "Local Agent"
+	}
+	return "Local Agent (" + args[0] + ")"
+}
+
+// Close implements Backend. Terminates the long-lived JSONL agent, if any.
+// The next StreamResponse call starts a fresh process, which also gives the
+// agent a clean conversation state.
+func (b *LocalBackend) Close() error {
+	b.mu.Lock()
+	p := b.proc
+	b.proc = nil
+	b.activeID = 0
+	b.mu.Unlock()
+
+	if p != nil {
+		p.kill()
+	}
+	return nil
+}
+
+// Abort asks the JSONL agent to stop the active request but keeps the process
+// (and its conversation state) alive. Text-mode processes are per-prompt and
+// are already killed when the stream context is cancelled.
+func (b *LocalBackend) Abort() {
+	b.mu.Lock()
+	p := b.proc
+	id := b.activeID
+	b.mu.Unlock()
+	if p != nil && id != 0 {
+		_ = p.send(localRequest{Type: "abort", ID: id})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// JSONL agent
+// ---------------------------------------------------------------------------
+
+// localRequest is a single JSON line written to the agent's stdin.
+type localRequest struct {
+	Type     string        `json:"type"`
+	ID       int           `json:"id"`
+	Messages []ChatMessage `json:"messages,omitempty"`
+}
+
+// localEvent is a single JSON line read from the agent's stdout.
+type localEvent struct {
+	Type    string `json:"type"`
+	ID      int    `json:"id"`
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// localProcess is a running JSONL agent.
+type localProcess struct {
+	cmd    *exec.Cmd
+	events chan localEvent // closed when stdout hits EOF
+	stderr *tailBuffer
+
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+}
+
+// send writes one request line to the agent.
+func (p *localProcess) send(req localRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err = p.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// kill closes stdin (a polite shutdown for well-behaved agents) and then
+// terminates the process.
+func (p *localProcess) kill() {
+	p.writeMu.Lock()
+	_ = p.stdin.Close()
+	p.writeMu.Unlock()
+	if p.cmd.Process != nil {
+		_ = p.cmd.Process.Kill()
+	}
+}
+
+// ensureProcess returns the running agent, starting it if necessary.
+func (b *LocalBackend) ensureProcess() (*localProcess, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.proc != nil {
+		return b.proc, nil
+	}
+
+	args := splitCommandLine(b.Command)
+	if len(args) == 0 {
+		return nil, errors.New("no local command configured")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr := newTailBuffer(localStderrLines)
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	p := &localProcess{
+		cmd:    cmd,
+		events: make(chan localEvent, 64),
+		stderr: stderr,
+		stdin:  stdin,
+	}
+
+	go func() {
+		defer close(p.events)
+		readLocalEvents(stdout, p.events)
+		_ = cmd.Wait()
+	}()
+
+	b.proc = p
+	return p, nil
+}
+
+// readLocalEvents parses the agent's stdout into events until EOF. Blank
+// lines are skipped; a line that isn't protocol JSON becomes an untagged
+// text token, so misbehaving agents still produce visible output.
+func readLocalEvents(r io.Reader, events chan<- localEvent) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var ev localEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			ev = localEvent{Type: "token", Content: line + "\n"}
+		}
+		events <- ev
+	}
+}
+
+// dropProcess forgets p if it is still the current agent (it has exited).
+func (b *LocalBackend) dropProcess(p *localProcess) {
+	b.mu.Lock()
+	if b.proc == p {
+		b.proc = nil
+		b.activeID = 0
+	}
+	b.mu.Unlock()
+}
+
+func (b *LocalBackend) streamJSONL(ctx context.Context, messages []ChatMessage) <-chan string {
+	ch := make(chan string, 64)
+
+	go func() {
+		defer close(ch)
+
+		p, err := b.ensureProcess()
+		if err != nil {
+			ch <- fmt.Sprintf("error: %v", err)
+			return
+		}
+
+		b.mu.Lock()
+		b.nextID++
+		id := b.nextID
+		b.activeID = id
+		b.mu.Unlock()
+
+		defer func() {
+			b.mu.Lock()
+			if b.activeID == id {
+				b.activeID = 0
+			}
+			b.mu.Unlock()
+		}()
+
+		if err := p.send(localRequest{Type: "chat", ID: id, Messages: messages}); err != nil {
+			b.dropProcess(p)
+			p.kill()
+			ch <- fmt.Sprintf("error: failed to write to local agent: %v%s", err, p.stderr.suffix())
+			return
+		}
+
+		if !relayLocalEvents(ctx, p.events, id, ch) {
+			b.dropProcess(p)
+			ch <- fmt.Sprintf("\n\n[local agent exited%s]", p.stderr.suffix())
+			return
+		}
+		if ctx.Err() != nil {
+			_ = p.send(localRequest{Type: "abort", ID: id})
+		}
+	}()
+
+	return ch
+}
+
+// relayLocalEvents forwards request id's tokens and error to ch until it is
+// done, fails or ctx is cancelled. Reports false if the agent's stdout ended
+// first.
+func relayLocalEvents(ctx context.Context, events <-chan localEvent, id int, ch chan<- string) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case ev, ok := <-events:
+			if !ok {
+				return false
+			}
+			// Untagged events (id 0) belong to whoever is streaming.
+			if ev.ID != 0 && ev.ID != id {
+				continue
+			}
+			switch ev.Type {
+			case "token":
+				if ev.Content != "" {
+					ch <- ev.Content
+				}
+			case "done":
+				return true
+			case "error":
+				ch <- fmt.Sprintf("\n\n[error: %s]", ev.Error)
+				return true
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Plain text CLI
+// ---------------------------------------------------------------------------
+
+func (b *LocalBackend) streamText(ctx context.Context, messages []ChatMessage) <-chan string {
+	ch := make(chan string, 64)
+
+	go func() {
+		defer close(ch)
+
+		args := splitCommandLine(b.Command)
+		if len(args) == 0 {
+			ch <- "error: no local command configured"
+			return
+		}
+
+		// CommandContext kills the process when ESC cancels the stream.
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Env = os.Environ()
+		cmd.Stdin = strings.NewReader(flattenPrompt(messages))
+		stderr := newTailBuffer(localStderrLines)
+		cmd.Stderr = stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			ch <- fmt.Sprintf("error: failed to create stdout pipe: %v", err)
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			ch <- fmt.Sprintf("error: failed to start %s: %v", args[0], err)
+			return
+		}
+
+		sent := streamChunks(stdout, ch)
+
+		err = cmd.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if !sent {
+				ch <- fmt.Sprintf("error: %s failed: %v%s", args[0], err, stderr.suffix())
+				return
+			}
+			ch <- fmt.Sprintf("\n\n[%s failed: %v%s]", args[0], err, stderr.suffix())
+		}
+	}()
+
+	return ch
+}
+
+// streamChunks forwards r to ch in raw chunks rather than lines, so tokens
+// appear as they are produced. Incomplete UTF-8 sequences are carried to the
+// next read; whatever is left at EOF is sent as is. Reports whether anything
+// was sent.
+func streamChunks(r io.Reader, ch chan<- string) bool {
+	buf := make([]byte, 4096)
+	var pending []byte
+	sent := false
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+			cut := validUTF8Prefix(pending)
+			if cut > 0 {
+				ch <- string(pending[:cut])
+				sent = true
+				pending = append(pending[:0], pending[cut:]...)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if len(pending) > 0 {
+		ch <- string(pending)
+		sent = true
+	}
+	return sent
+}
+
+// flattenPrompt renders a conversation as a single plain-text prompt for
+// CLIs that have no notion of chat roles.
+func flattenPrompt(messages []ChatMessage) string {
+	var sb strings.Builder
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			sb.WriteString("System:\n")
+		case RoleUser:
+			sb.WriteString("User:\n")
+		case RoleAssistant:
+			sb.WriteString("Assistant:\n")
+		}
+		sb.WriteString(m.Content)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("Assistant:\n")
+	return sb.String()
+}
+
+// validUTF8Prefix returns the length of the longest prefix of b that does not
+// end in the middle of a multi-byte UTF-8 sequence.
+func validUTF8Prefix(b []byte) int {
+	end := len(b)
+	// A rune is at most 4 bytes — only the trailing 3 bytes can be partial.
+	for i := end - 1; i >= 0 && i >= end-3; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:end]) {
+				return i
+			}
+			break
+		}
+	}
+	return end
+}
+
+// splitCommandLine splits a command line into arguments, honouring single
+// and double quotes and backslash escapes. It does not invoke a shell.
+func splitCommandLine(s string) []string {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// tailBuffer is an io.Writer that keeps the last n lines written to it.
+// Used to attach a child's stderr to error messages.
+type tailBuffer struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial string
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+// Write implements io.Writer.
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parts := strings.Split(t.partial+string(p), "\n")
+	t.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		t.lines = append(t.lines, line)
+		if len(t.lines) > t.max {
+			t.lines = t.lines[len(t.lines)-t.max:]
+		}
+	}
+	return len(p), nil
+}
+
+// suffix returns ": <last stderr lines>" or "" when nothing was captured.
+func (t *tailBuffer) suffix() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := t.lines
+	if p := strings.TrimSpace(t.partial); p != "" {
+		lines = append(append([]string(nil), lines...), p)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(lines, " | ")
+}
@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadLocalEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+		want   []localEvent
+	}{
+		{
+			name:   "events",
+			stdout: `{"type":"token","id":1,"content":"Hel"}` + "\n" + `{"type":"token","id":1,"content":"lo"}` + "\n" + `{"type":"done","id":1}` + "\n",
+			want: []localEvent{
+				{Type: "token", ID: 1, Content: "Hel"},
+				{Type: "token", ID: 1, Content: "lo"},
+				{Type: "done", ID: 1},
+			},
+		},
+		{
+			name:   "blank lines and CRLF",
+			stdout: "\n  \r\n" + `{"type":"done","id":2}` + "\r\n",
+			want:   []localEvent{{Type: "done", ID: 2}},
+		},
+		{
+			name:   "malformed JSON becomes text",
+			stdout: `{"type":"token",` + "\nplain output\n",
+			want: []localEvent{
+				{Type: "token", Content: `{"type":"token",` + "\n"},
+				{Type: "token", Content: "plain output\n"},
+			},
+		},
+		{
+			name:   "last line without newline",
+			stdout: `{"type":"token","id":3,"content":"x"}`,
+			want:   []localEvent{{Type: "token", ID: 3, Content: "x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One byte per read: every line arrives in pieces
+			events := make(chan localEvent, 16)
+			readLocalEvents(iotest.OneByteReader(strings.NewReader(tt.stdout)), events)
+			close(events)
+			var got []localEvent
+			for ev := range events {
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelayLocalEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []localEvent
+		closed bool // stdout ends after the events
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "done",
+			events: []localEvent{{Type: "token", ID: 2, Content: "a"}, {Type: "token", Content: "b"}, {Type: "done", ID: 2}, {Type: "token", ID: 2, Content: "late"}},
+			want:   "ab",
+			ok:     true,
+		},
+		{
+			name:   "other requests dropped",
+			events: []localEvent{{Type: "token", ID: 1, Content: "stale"}, {Type: "done", ID: 1}, {Type: "token", ID: 2, Content: "fresh"}, {Type: "done", ID: 2}},
+			want:   "fresh",
+			ok:     true,
+		},
+		{
+			name:   "error",
+			events: []localEvent{{Type: "token", ID: 2, Content: "a"}, {Type: "error", ID: 2, Error: "model not found"}},
+			want:   "a\n\n[error: model not found]",
+			ok:     true,
+		},
+		{
+			name:   "stream ends early",
+			events: []localEvent{{Type: "token", ID: 2, Content: "partial"}},
+			closed: true,
+			want:   "partial",
+			ok:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan localEvent, len(tt.events))
+			for _, ev := range tt.events {
+				events <- ev
+			}
+			if tt.closed {
+				close(events)
+			}
+			ch := make(chan string, 16)
+			ok := relayLocalEvents(context.Background(), events, 2, ch)
+			close(ch)
+			var got strings.Builder
+			for s := range ch {
+				got.WriteString(s)
+			}
+			if got.String() != tt.want || ok != tt.ok {
+				t.Errorf("got %q, %v; want %q, %v", got.String(), ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	// A cancelled stream returns without waiting for the agent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if !relayLocalEvents(ctx, make(chan localEvent), 1, make(chan string)) {
+		t.Error("cancelled: reported the agent as exited")
+	}
+}
+
+func TestStreamChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+		sent   bool
+	}{
+		{"multi-byte runes split across reads", "héllo → wörld 🎉\n", true},
+		{"stream ends mid-rune", "ok \xf0\x9f", true},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan string, 64)
+			sent := streamChunks(iotest.OneByteReader(strings.NewReader(tt.stdout)), ch)
+			close(ch)
+			var chunks []string
+			for s := range ch {
+				chunks = append(chunks, s)
+			}
+			if sent != tt.sent || strings.Join(chunks, "") != tt.stdout {
+				t.Fatalf("got %q (sent %v), want %q", chunks, sent, tt.stdout)
+			}
+			// Only the final chunk at EOF may hold a partial rune
+			for _, c := range chunks[:max(0, len(chunks)-1)] {
+				if !validRunes(c) {
+					t.Errorf("chunk %q splits a rune", c)
+				}
+			}
+		})
+	}
+}
+
+func validRunes(s string) bool {
+	return validUTF8Prefix([]byte(s)) == len(s)
+}
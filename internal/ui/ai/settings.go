@@ -17,7 +17,7 @@ import (
 type settingsSection int
 
 const (
-	sectionBackend settingsSection = iota // Backend type: Workers AI / HTTP Endpoint
+	sectionBackend settingsSection = iota // Backend type: Workers AI / HTTP Endpoint / Local
 
 	// Workers AI sections
 	sectionModel  // Model preset (Fast / Balanced / Deep)
@@ -28,12 +28,16 @@ const (
 	sectionProtocol   // openai / opencode
 	sectionHTTPModel  // Model ID input
 	sectionHTTPAPIKey // API key input
+
+	// Local Command sections
+	sectionLocalCommand  // Command line input
+	sectionLocalProtocol // jsonl / text
 )
 
 // settingsModel holds the state for the Settings mode.
 type settingsModel struct {
 	section        settingsSection      // currently focused section
-	backendType    config.AIBackendType // "workers_ai" or "http"
+	backendType    config.AIBackendType // "workers_ai", "http" or "local"
 	provider       config.AIProvider    // legacy: selected provider (kept for backward compat)
 	modelPreset    config.AIModelPreset // Workers AI model preset
 	workerURL      string               // deployed worker URL (empty = not deployed)
@@ -47,17 +51,22 @@ type settingsModel struct {
 	httpModel    string                // model ID for HTTP backend
 	httpAPIKey   string                // optional API key
 
+	// Local Command fields
+	localCommand  string                 // e.g. "ollama run llama3.1"
+	localProtocol config.AILocalProtocol // "jsonl" or "text"
+
 	// Text input state for the currently focused HTTP field
 	inputCursor int
 }
 
 func newSettingsModel() settingsModel {
 	return settingsModel{
-		section:      sectionBackend,
-		backendType:  config.AIBackendWorkersAI,
-		provider:     config.AIProviderWorkersAI,
-		modelPreset:  config.AIModelBalanced,
-		httpProtocol: config.AIHTTPProtocolOpenAI,
+		section:       sectionBackend,
+		backendType:   config.AIBackendWorkersAI,
+		provider:      config.AIProviderWorkersAI,
+		modelPreset:   config.AIModelBalanced,
+		httpProtocol:  config.AIHTTPProtocolOpenAI,
+		localProtocol: config.AILocalProtocolText,
 	}
 }
 
@@ -87,6 +96,12 @@ func (s *settingsModel) LoadFromConfig(cfg *config.Config) {
 	if cfg.AIHTTPAPIKey != "" {
 		s.httpAPIKey = cfg.AIHTTPAPIKey
 	}
+	if cfg.AILocalCommand != "" {
+		s.localCommand = cfg.AILocalCommand
+	}
+	if cfg.AILocalProtocol != "" {
+		s.localProtocol = cfg.AILocalProtocol
+	}
 }
 
 // visibleSections returns the ordered list of sections visible for the
@@ -95,6 +110,8 @@ func (s settingsModel) visibleSections() []settingsSection {
 	switch s.backendType {
 	case config.AIBackendHTTP:
 		return []settingsSection{sectionBackend, sectionEndpoint, sectionProtocol, sectionHTTPModel, sectionHTTPAPIKey}
+	case config.AIBackendLocal:
+		return []settingsSection{sectionBackend, sectionLocalCommand, sectionLocalProtocol}
 	default: // Workers AI
 		return []settingsSection{sectionBackend, sectionModel, sectionDeploy}
 	}
@@ -124,7 +141,7 @@ func (s *settingsModel) prevSection() {
 	}
 }
 
-// currentFieldText returns the text for the currently focused HTTP text input.
+// currentFieldText returns the text for the currently focused text input.
 func (s settingsModel) currentFieldText() string {
 	switch s.section {
 	case sectionEndpoint:
@@ -133,11 +150,13 @@ func (s settingsModel) currentFieldText() string {
 		return s.httpModel
 	case sectionHTTPAPIKey:
 		return s.httpAPIKey
+	case sectionLocalCommand:
+		return s.localCommand
 	}
 	return ""
 }
 
-// setCurrentFieldText sets the text for the currently focused HTTP text input.
+// setCurrentFieldText sets the text for the currently focused text input.
 func (s *settingsModel) setCurrentFieldText(text string) {
 	switch s.section {
 	case sectionEndpoint:
@@ -146,13 +165,15 @@ func (s *settingsModel) setCurrentFieldText(text string) {
 		s.httpModel = text
 	case sectionHTTPAPIKey:
 		s.httpAPIKey = text
+	case sectionLocalCommand:
+		s.localCommand = text
 	}
 }
 
 // isTextInputSection returns true if the current section is a text input field.
 func (s settingsModel) isTextInputSection() bool {
 	switch s.section {
-	case sectionEndpoint, sectionHTTPModel, sectionHTTPAPIKey:
+	case sectionEndpoint, sectionHTTPModel, sectionHTTPAPIKey, sectionLocalCommand:
 		return true
 	}
 	return false
@@ -169,6 +190,9 @@ type AIConfigSaveMsg struct {
 	HTTPProtocol config.AIHTTPProtocol
 	HTTPModel    string
 	HTTPAPIKey   string
+
+	LocalCommand  string
+	LocalProtocol config.AILocalProtocol
 }
 
 // AIProvisionRequestMsg is emitted when the user requests to deploy the AI Worker.
@@ -188,6 +212,9 @@ func (s settingsModel) emitSave() tea.Cmd {
 			HTTPProtocol: s.httpProtocol,
 			HTTPModel:    s.httpModel,
 			HTTPAPIKey:   s.httpAPIKey,
+
+			LocalCommand:  s.localCommand,
+			LocalProtocol: s.localProtocol,
 		}
 	}
 }
@@ -197,7 +224,7 @@ func (s settingsModel) emitSave() tea.Cmd {
 func (s settingsModel) update(msg tea.Msg) (settingsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle text input for HTTP fields first
+		// Handle text input fields first
 		if s.isTextInputSection() {
 			switch msg.String() {
 			case "j", "down":
@@ -305,6 +332,9 @@ func (s settingsModel) update(msg tea.Msg) (settingsModel, tea.Cmd) {
 			case sectionProtocol:
 				s.httpProtocol = prevHTTPProtocol(s.httpProtocol)
 				return s, s.emitSave()
+			case sectionLocalProtocol:
+				s.localProtocol = prevLocalProtocol(s.localProtocol)
+				return s, s.emitSave()
 			}
 		case "l", "right":
 			switch s.section {
@@ -318,6 +348,9 @@ func (s settingsModel) update(msg tea.Msg) (settingsModel, tea.Cmd) {
 			case sectionProtocol:
 				s.httpProtocol = nextHTTPProtocol(s.httpProtocol)
 				return s, s.emitSave()
+			case sectionLocalProtocol:
+				s.localProtocol = nextLocalProtocol(s.localProtocol)
+				return s, s.emitSave()
 			}
 		case "enter":
 			switch s.section {
@@ -368,6 +401,18 @@ func (s settingsModel) view(w, h int) string {
 			"", apiKeyHeader, apiKeyContent,
 		)
 
+	case config.AIBackendLocal:
+		commandHeader := sectionHeader("Command", s.section == sectionLocalCommand)
+		commandContent := s.renderTextInput(s.localCommand, "e.g. ollama run llama3.1", s.section == sectionLocalCommand)
+
+		protocolHeader := sectionHeader("Protocol", s.section == sectionLocalProtocol)
+		protocolContent := s.renderLocalProtocol()
+
+		parts = append(parts,
+			"", commandHeader, commandContent,
+			"", protocolHeader, protocolContent,
+		)
+
 	default: // Workers AI
 		modelHeader := sectionHeader("Model Preset", s.section == sectionModel)
 		modelContent := s.renderModel()
@@ -411,6 +456,7 @@ func (s settingsModel) renderBackend() string {
 	}{
 		{config.AIBackendWorkersAI, "Workers AI", "Deploys a proxy Worker to your Cloudflare account"},
 		{config.AIBackendHTTP, "HTTP Endpoint", "OpenAI-compatible or OpenCode serve endpoint"},
+		{config.AIBackendLocal, "Local Command", "Spawns a local model CLI or agent over stdin/stdout"},
 	}
 
 	var lines []string
@@ -504,6 +550,29 @@ func (s settingsModel) renderProtocol() string {
 	return strings.Join(lines, "\n")
 }
 
+func (s settingsModel) renderLocalProtocol() string {
+	protocols := []struct {
+		p    config.AILocalProtocol
+		name string
+		desc string
+	}{
+		{config.AILocalProtocolText, "Plain text", "Prompt on stdin, answer streamed from stdout (one process per prompt)"},
+		{config.AILocalProtocolJSONL, "JSON lines", "Long-lived agent exchanging chat/token/done JSON messages"},
+	}
+
+	var lines []string
+	for _, p := range protocols {
+		selected := s.localProtocol == p.p
+		line := radioItem(p.name, selected, s.section == sectionLocalProtocol)
+		lines = append(lines, line)
+		lines = append(lines, theme.DimStyle.Render("    "+p.desc))
+	}
+	if s.section == sectionLocalProtocol {
+		lines = append(lines, theme.DimStyle.Render("  (h/l to change)"))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (s settingsModel) renderTextInput(value, placeholder string, active bool) string {
 	if !active {
 		if value == "" {
@@ -602,14 +671,14 @@ func nextBackendType(bt config.AIBackendType) config.AIBackendType {
 	case config.AIBackendWorkersAI:
 		return config.AIBackendHTTP
 	default:
-		return config.AIBackendHTTP
+		return config.AIBackendLocal
 	}
 }
 
 func prevBackendType(bt config.AIBackendType) config.AIBackendType {
 	switch bt {
-	case config.AIBackendHTTP:
-		return config.AIBackendWorkersAI
+	case config.AIBackendLocal:
+		return config.AIBackendHTTP
 	default:
 		return config.AIBackendWorkersAI
 	}
@@ -632,3 +701,21 @@ func prevHTTPProtocol(p config.AIHTTPProtocol) config.AIHTTPProtocol {
 		return config.AIHTTPProtocolOpenAI
 	}
 }
+
+func nextLocalProtocol(p config.AILocalProtocol) config.AILocalProtocol {
+	switch p {
+	case config.AILocalProtocolText:
+		return config.AILocalProtocolJSONL
+	default:
+		return config.AILocalProtocolJSONL
+	}
+}
+
+func prevLocalProtocol(p config.AILocalProtocol) config.AILocalProtocol {
+	switch p {
+	case config.AILocalProtocolJSONL:
+		return config.AILocalProtocolText
+	default:
+		return config.AILocalProtocolText
+	}
+}
@@ -0,0 +1,154 @@
+// Package alertspopup provides the alerts history overlay: a scrollable list
+// of alert rule firings, newest first.
+package alertspopup
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/monitoring"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// CloseMsg signals that the alerts popup should be dismissed.
+type CloseMsg struct{}
+
+// ClearMsg asks the app to clear the alert history.
+type ClearMsg struct{}
+
+// Model represents the alerts history overlay.
+type Model struct {
+	events    []monitoring.AlertEvent // newest first
+	ruleCount int
+	ruleErrs  []string // rules skipped because of config errors
+	scroll    int
+}
+
+// New creates the popup for the given history.
+func New(events []monitoring.AlertEvent, ruleCount int, ruleErrs []string) Model {
+	return Model{
+		events:    events,
+		ruleCount: ruleCount,
+		ruleErrs:  ruleErrs,
+	}
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "!":
+			return m, func() tea.Msg { return CloseMsg{} }
+		case "c":
+			m.events = nil
+			m.scroll = 0
+			return m, func() tea.Msg { return ClearMsg{} }
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		case "down", "j":
+			if m.scroll < len(m.lines())-1 {
+				m.scroll++
+			}
+		}
+	}
+	return m, nil
+}
+
+// lines renders the content lines (config errors first, then events).
+func (m Model) lines() []string {
+	timeStyle := lipgloss.NewStyle().Foreground(theme.ColorGray)
+	ruleStyle := lipgloss.NewStyle().Foreground(theme.ColorOrange).Bold(true)
+
+	var lines []string
+	for _, e := range m.ruleErrs {
+		lines = append(lines, theme.ErrorStyle.Render("config: "+e))
+	}
+	if len(m.ruleErrs) > 0 {
+		lines = append(lines, "")
+	}
+
+	if m.ruleCount == 0 {
+		lines = append(lines,
+			theme.DimStyle.Render("No alert rules configured."),
+			"",
+			theme.DimStyle.Render("Add [[alerts]] tables to ~/.orangeshell/config.toml, e.g."),
+			theme.DimStyle.Render(`  name = "api exceptions"`),
+			theme.DimStyle.Render(`  source = "tail"        # or "analytics"`),
+			theme.DimStyle.Render(`  worker = "my-api"`),
+			theme.DimStyle.Render(`  metric = "exceptions"  # errors, matches | error_rate, errors, requests, cpu_p99`),
+			theme.DimStyle.Render(`  threshold = 5`),
+			theme.DimStyle.Render(`  window = "1m"`))
+		return lines
+	}
+	if len(m.events) == 0 {
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("No alerts fired yet (%d rules active).", m.ruleCount)))
+		return lines
+	}
+
+	for _, ev := range m.events {
+		lines = append(lines, fmt.Sprintf("%s  %s  %s",
+			timeStyle.Render(ev.Time.Format("15:04:05")),
+			ruleStyle.Render(ev.Rule),
+			ev.Message))
+	}
+	return lines
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 2 / 3
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render(fmt.Sprintf("  Alerts (%d)", len(m.events)))
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+
+	maxVisible := termHeight/2 - 6
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	all := m.lines()
+	scroll := m.scroll
+	if maxScroll := len(all) - maxVisible; scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + maxVisible
+	if end > len(all) {
+		end = len(all)
+	}
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+	var visible []string
+	for _, l := range all[scroll:end] {
+		visible = append(visible, lineStyle.Render(l))
+	}
+
+	help := theme.DimStyle.Render("  esc close  |  j/k scroll  |  c clear")
+
+	var parts []string
+	parts = append(parts, title, sep)
+	parts = append(parts, visible...)
+	parts = append(parts, sep, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
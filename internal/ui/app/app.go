@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -17,22 +18,36 @@ import (
 	svc "github.com/oarafat/orangeshell/internal/service"
 	"github.com/oarafat/orangeshell/internal/ui/actions"
 	uiai "github.com/oarafat/orangeshell/internal/ui/ai"
+	"github.com/oarafat/orangeshell/internal/ui/alertspopup"
+	"github.com/oarafat/orangeshell/internal/ui/buildspopup"
 	"github.com/oarafat/orangeshell/internal/ui/cicdpopup"
 	uiconfig "github.com/oarafat/orangeshell/internal/ui/config"
+	"github.com/oarafat/orangeshell/internal/ui/d1dumppopup"
+	"github.com/oarafat/orangeshell/internal/ui/d1gridpopup"
 	"github.com/oarafat/orangeshell/internal/ui/deletepopup"
 	"github.com/oarafat/orangeshell/internal/ui/deployallpopup"
 	"github.com/oarafat/orangeshell/internal/ui/detail"
+	"github.com/oarafat/orangeshell/internal/ui/devsessionspopup"
+	"github.com/oarafat/orangeshell/internal/ui/diagpopup"
 	"github.com/oarafat/orangeshell/internal/ui/envpopup"
 	"github.com/oarafat/orangeshell/internal/ui/header"
 	"github.com/oarafat/orangeshell/internal/ui/helppopup"
+	"github.com/oarafat/orangeshell/internal/ui/historypopup"
 	"github.com/oarafat/orangeshell/internal/ui/launcher"
 	"github.com/oarafat/orangeshell/internal/ui/monitoring"
+	"github.com/oarafat/orangeshell/internal/ui/profilepopup"
 	"github.com/oarafat/orangeshell/internal/ui/projectpopup"
 	"github.com/oarafat/orangeshell/internal/ui/removeprojectpopup"
+	"github.com/oarafat/orangeshell/internal/ui/requestpopup"
 	"github.com/oarafat/orangeshell/internal/ui/resourcepopup"
+	"github.com/oarafat/orangeshell/internal/ui/schemadiffpopup"
 	"github.com/oarafat/orangeshell/internal/ui/search"
 	"github.com/oarafat/orangeshell/internal/ui/setup"
 	"github.com/oarafat/orangeshell/internal/ui/tabbar"
+	"github.com/oarafat/orangeshell/internal/ui/tagpopup"
+	"github.com/oarafat/orangeshell/internal/ui/timetravelpopup"
+	"github.com/oarafat/orangeshell/internal/ui/triggerspopup"
+	"github.com/oarafat/orangeshell/internal/ui/workspacepopup"
 	uiwrangler "github.com/oarafat/orangeshell/internal/ui/wrangler"
 	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
 )
@@ -214,6 +229,19 @@ type Model struct {
 	monitoring  monitoring.Model
 	devSessions []devSession // active wrangler dev sessions (for monitoring tab dev tailing)
 
+	// Dev sessions manager popup; devHealthTicking is set while dev servers
+	// are probed
+	showDevSessionsPopup bool
+	devSessionsPopup     devsessionspopup.Model
+	devHealthTicking     bool
+
+	// HTTP request composer; requestGen drops responses for a closed popup
+	showRequestPopup bool
+	requestPopup     requestpopup.Model
+	requestGen       int
+	requestProject   string
+	requestCapture   *requestCapture
+
 	// Active tail session (nil when no tail is running)
 	tailSession *svc.TailSession
 	tailSource  string // "wrangler", "detail", or "monitoring" — which view owns the current tail
@@ -262,9 +290,92 @@ type Model struct {
 	showCICDPopup bool
 	cicdPopup     cicdpopup.Model
 
+	// Workers Builds dashboard; buildsGen invalidates polls of a closed
+	// dashboard, buildsTags caches script name → Builds API script tag
+	showBuildsPopup bool
+	buildsPopup     buildspopup.Model
+	buildsGen       int
+	buildsTags      map[string]string
+
+	// Workers Builds triggers popup; buildTriggersGen drops loads for a closed popup
+	showBuildTriggersPopup bool
+	buildTriggersPopup     triggerspopup.Model
+	buildTriggersGen       int
+
+	// D1 backup popup; d1DumpGen drops progress of a closed popup or a
+	// cancelled job, d1DumpCancel stops the running export or import
+	showD1DumpPopup bool
+	d1DumpPopup     d1dumppopup.Model
+	d1DumpGen       int
+	d1DumpTarget    d1DumpTarget
+	d1DumpCancel    context.CancelFunc
+
+	// D1 Time Travel popup; timeTravelGen drops results for a closed popup
+	showTimeTravelPopup bool
+	timeTravelPopup     timetravelpopup.Model
+	timeTravelGen       int
+
+	// D1 schema diff popup: the database in the detail view, the databases it
+	// can be compared with, and the one being compared
+	showSchemaDiffPopup bool
+	schemaDiffPopup     schemadiffpopup.Model
+	schemaDiffGen       int
+	schemaDiffCurrent   d1SchemaSide
+	schemaDiffSides     []d1SchemaSide
+	schemaDiffOther     d1SchemaSide
+
+	// D1 table browser popup; d1GridGen drops results for a closed popup
+	showD1GridPopup bool
+	d1GridPopup     d1gridpopup.Model
+	d1GridGen       int
+	d1GridTarget    d1DumpTarget
+
+	// Scheduled D1 snapshots: a round is running, and when each failed;
+	// d1SnapshotTicking is set once the check loop runs
+	d1SnapshotBusy    bool
+	d1SnapshotFailed  map[string]time.Time
+	d1SnapshotTicking bool
+
 	// Log exporter for monitoring tab
 	logExporter *monitoring.LogExporter
 
+	// Alert rules (config [[alerts]]) and the alerts history overlay
+	alerts          *monitoring.AlertEngine
+	alertRuleErrs   []string // rules skipped because of config errors
+	alertPollGen    int      // drops ticks of a superseded poll loop
+	showAlertsPopup bool
+	alertsPopup     alertspopup.Model
+
+	// The passphrase prompt of a new secrets file is running
+	secretsPrompting bool
+
+	// Config diagnostics overlay; diagPending is a deploy held by the lint gate
+	showDiagPopup bool
+	diagPopup     diagpopup.Model
+	diagPending   *pendingDeploy
+
+	// Journal of config edits (undo/redo) and the edit history overlay
+	journal          *wcfg.Journal
+	showHistoryPopup bool
+	historyPopup     historypopup.Model
+
+	// Watches the discovered configs and project root for changes on disk
+	configWatcher *wcfg.Watcher
+
+	// Project tag checklist and save workspace overlays; pendingWorkspace is a
+	// saved workspace being opened, applied once its root is discovered
+	showTagPopup       bool
+	tagPopup           tagpopup.Model
+	showWorkspacePopup bool
+	workspacePopup     workspacepopup.Model
+	pendingWorkspace   *config.Workspace
+
+	// Credential profile switcher; boundProfile is the profile the open
+	// directory's .orangeshell.toml asks for ("" = none)
+	showProfilePopup bool
+	profilePopup     profilepopup.Model
+	boundProfile     string
+
 	// AI stream cancellation — set when streaming starts, called on ESC.
 	// aiStreamGen is incremented each time a new stream starts; stale messages
 	// from cancelled streams carry an old generation and are silently dropped.
@@ -292,6 +403,22 @@ type Model struct {
 // scanDir is an optional directory path to scan for wrangler projects; if empty,
 // no auto-scan is performed and the empty-state menu is shown immediately.
 func NewModel(cfg *config.Config, scanDir string) Model {
+	// Self-hosted Git hosts the CI/CD wizard should recognize
+	wcfg.SetGitHosts(cfg.GitHosts)
+
+	// A repo can bind a credential profile in its .orangeshell.toml; switch
+	// before authenticating so the right credentials are used from the start
+	var boundProfile string
+	if scanDir != "" {
+		if abs, err := filepath.Abs(scanDir); err == nil {
+			settings, _ := wcfg.LoadRepoSettings(abs)
+			boundProfile = settings.Profile
+			if boundProfile != "" && cfg.HasProfile(boundProfile) {
+				_ = cfg.UseProfile(boundProfile)
+			}
+		}
+	}
+
 	phase := PhaseSetup
 	if cfg.IsConfigured() {
 		phase = PhaseDashboard
@@ -317,6 +444,13 @@ func NewModel(cfg *config.Config, scanDir string) Model {
 		cmdRunners:           make(map[string]*cmdRunner),
 		restrictedToastShown: make(map[string]bool),
 		logExporter:          monitoring.NewLogExporter(),
+		boundProfile:         boundProfile,
+		d1SnapshotTicking:    phase == PhaseDashboard, // Init starts the loop
+	}
+	m.initAlerts()
+	m.header = m.newHeader()
+	if dir, err := wcfg.DefaultJournalDir(); err == nil {
+		m.journal = wcfg.NewJournal(dir)
 	}
 
 	return m
@@ -325,7 +459,7 @@ func NewModel(cfg *config.Config, scanDir string) Model {
 // Init returns the initial command.
 func (m Model) Init() tea.Cmd {
 	if m.phase == PhaseDashboard {
-		cmds := []tea.Cmd{m.initDashboardCmd(), m.wrangler.SpinnerInit()}
+		cmds := []tea.Cmd{m.initDashboardCmd(), m.wrangler.SpinnerInit(), m.alertPollCmd(), d1SnapshotTick()}
 
 		if m.scanDir != "" {
 			// A directory was provided on the CLI — scan it for wrangler projects.
@@ -412,8 +546,20 @@ func (m Model) initDashboardCmd() tea.Cmd {
 	}
 }
 
-// Update handles all messages for the application.
+// Update handles all messages for the application. A save that left
+// credentials waiting for the passphrase of a new secrets file — from any
+// handler, or from a command such as the OAuth login — is followed by the
+// passphrase prompt.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	result, cmd := m.update(msg)
+	next, ok := result.(Model)
+	if !ok || next.secretsPrompting || !next.cfg.SecretsLocked() {
+		return result, cmd
+	}
+	return next, tea.Batch(cmd, next.unlockSecretsCmd())
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Delegate to domain-specific message handlers (Tier 3).
 	// Each returns (Model, Cmd, handled). If handled, we're done.
 	handlers := []func(*Model, tea.Msg) (Model, tea.Cmd, bool){
@@ -431,7 +577,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		(*Model).handleDetailMsg,
 		(*Model).handleWranglerMsg,
 		(*Model).handleMonitoringMsg,
+		(*Model).handleAlertsMsg,
+		(*Model).handleDiagnosticsMsg,
+		(*Model).handleHistoryMsg,
+		(*Model).handleConfigWatchMsg,
+		(*Model).handleWorkspaceMsg,
+		(*Model).handleProfileMsg,
+		(*Model).handleBuildsMsg,
+		(*Model).handleBuildTriggersMsg,
+		(*Model).handleD1DumpMsg,
+		(*Model).handleD1TimeTravelMsg,
+		(*Model).handleD1SchemaDiffMsg,
+		(*Model).handleD1GridMsg,
+		(*Model).handleDevSessionsMsg,
+		(*Model).handleRequestMsg,
 		(*Model).handleAIMsg,
+		(*Model).handleSecretsMsg,
 		(*Model).handleOverlayMsg,
 	}
 	for _, h := range handlers {
@@ -569,6 +730,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cicdPopup, cmd = m.cicdPopup.Update(msg)
 			cmds = append(cmds, cmd)
 		}
+		if m.showBuildsPopup {
+			var cmd tea.Cmd
+			m.buildsPopup, cmd = m.buildsPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showBuildTriggersPopup {
+			var cmd tea.Cmd
+			m.buildTriggersPopup, cmd = m.buildTriggersPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showD1DumpPopup {
+			var cmd tea.Cmd
+			m.d1DumpPopup, cmd = m.d1DumpPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showTimeTravelPopup {
+			var cmd tea.Cmd
+			m.timeTravelPopup, cmd = m.timeTravelPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showSchemaDiffPopup {
+			var cmd tea.Cmd
+			m.schemaDiffPopup, cmd = m.schemaDiffPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showD1GridPopup {
+			var cmd tea.Cmd
+			m.d1GridPopup, cmd = m.d1GridPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.showRequestPopup {
+			var cmd tea.Cmd
+			m.requestPopup, cmd = m.requestPopup.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 		if m.aiTab.NeedsSpinner() {
 			cmds = append(cmds, m.aiTab.UpdateSpinner(msg))
 		}
@@ -594,12 +790,16 @@ func (m Model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if m.setup.Done() {
 		m.cfg = m.setup.Config()
-		m.header = header.New(m.cfg.AuthMethod)
+		m.initAlerts()
+		m.header = m.newHeader()
 		m.phase = PhaseDashboard
 		m.layout()
 
-		cmds := []tea.Cmd{m.initDashboardCmd(), m.wrangler.SpinnerInit()}
-		if m.scanDir != "" {
+		cmds := []tea.Cmd{m.initDashboardCmd(), m.wrangler.SpinnerInit(), m.restartAlertPolls(), m.startD1SnapshotTicks()}
+		if m.wrangler.IsMonorepo() || m.wrangler.HasConfig() {
+			// Set up a new profile mid-session: the projects are already
+			// loaded and get their deployments once the dashboard is ready
+		} else if m.scanDir != "" {
 			cmds = append(cmds, m.discoverProjectsFromDir(m.scanDir))
 		} else {
 			cmds = append(cmds, func() tea.Msg {
@@ -666,6 +866,102 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// If builds dashboard is active, route everything there
+	if m.showBuildsPopup {
+		var cmd tea.Cmd
+		m.buildsPopup, cmd = m.buildsPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If build triggers popup is active, route everything there
+	if m.showBuildTriggersPopup {
+		var cmd tea.Cmd
+		m.buildTriggersPopup, cmd = m.buildTriggersPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If D1 backup popup is active, route everything there
+	if m.showD1DumpPopup {
+		var cmd tea.Cmd
+		m.d1DumpPopup, cmd = m.d1DumpPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If D1 Time Travel popup is active, route everything there
+	if m.showTimeTravelPopup {
+		var cmd tea.Cmd
+		m.timeTravelPopup, cmd = m.timeTravelPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If D1 schema diff popup is active, route everything there
+	if m.showSchemaDiffPopup {
+		var cmd tea.Cmd
+		m.schemaDiffPopup, cmd = m.schemaDiffPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If D1 table browser popup is active, route everything there
+	if m.showD1GridPopup {
+		var cmd tea.Cmd
+		m.d1GridPopup, cmd = m.d1GridPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If HTTP request composer is active, route everything there
+	if m.showRequestPopup {
+		var cmd tea.Cmd
+		m.requestPopup, cmd = m.requestPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If dev sessions popup is active, route everything there
+	if m.showDevSessionsPopup {
+		var cmd tea.Cmd
+		m.devSessionsPopup, cmd = m.devSessionsPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If alerts popup is active, route everything there
+	if m.showAlertsPopup {
+		var cmd tea.Cmd
+		m.alertsPopup, cmd = m.alertsPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If diagnostics popup is active, route everything there
+	if m.showDiagPopup {
+		var cmd tea.Cmd
+		m.diagPopup, cmd = m.diagPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If edit history popup is active, route everything there
+	if m.showHistoryPopup {
+		var cmd tea.Cmd
+		m.historyPopup, cmd = m.historyPopup.Update(msg)
+		return m, cmd
+	}
+
+	// If tag or workspace popup is active, route everything there
+	if m.showTagPopup {
+		var cmd tea.Cmd
+		m.tagPopup, cmd = m.tagPopup.Update(msg)
+		return m, cmd
+	}
+	if m.showWorkspacePopup {
+		var cmd tea.Cmd
+		m.workspacePopup, cmd = m.workspacePopup.Update(msg)
+		return m, cmd
+	}
+
+	// If profile switcher is active, route everything there
+	if m.showProfilePopup {
+		var cmd tea.Cmd
+		m.profilePopup, cmd = m.profilePopup.Update(msg)
+		return m, cmd
+	}
+
 	// If help popup is active, route everything there
 	if m.showHelpPopup {
 		var cmd tea.Cmd
@@ -717,6 +1013,11 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, cmd
 					}
 				}
+				// Check header profile switcher click.
+				if z := zone.Get(header.ProfileZoneID); z != nil && z.InBounds(msg) {
+					m.openProfilePopup()
+					return m, nil
+				}
 				// Check header account tab clicks.
 				for i := 0; i < m.header.AccountCount(); i++ {
 					if z := zone.Get(header.AccountZoneID(i)); z != nil && z.InBounds(msg) {
@@ -848,6 +1149,7 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				projectName = m.wrangler.Config().Name
 			}
 			m.launcher = launcher.New(projectName)
+			m.launcher.SetWorkspaces(m.workspaceNames())
 			m.showLauncher = true
 			return m, nil
 		case "ctrl+k":
@@ -901,6 +1203,16 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			}
+		case "!":
+			// Alerts history (also clears the header badge)
+			if !m.isTextInputActive() && m.activeTab != tabbar.TabAI {
+				m.openAlertsPopup()
+				return m, nil
+			}
+		case "ctrl+o":
+			// Credential profile switcher
+			m.openProfilePopup()
+			return m, nil
 		case "]":
 			if !m.isTextInputActive() {
 				if m.header.NextAccount() {
@@ -918,11 +1230,22 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			// Esc on the Monitoring tab — dual-pane navigation
 			if m.activeTab == tabbar.TabMonitoring {
+				// Custom range picker open → let the analytics view cancel it
+				if m.monitoring.AnalyticsPickerActive() {
+					var cmd tea.Cmd
+					m.monitoring, cmd = m.monitoring.Update(msg)
+					return m, cmd
+				}
 				// Analytics view open → close it, return to grid
 				if m.monitoring.ShowAnalytics() {
 					m.monitoring.CloseAnalytics()
 					return m, nil
 				}
+				// Environment dashboard open → close it, return to grid
+				if m.monitoring.ShowDashboard() {
+					m.monitoring.CloseDashboard()
+					return m, nil
+				}
 				if m.monitoring.Focus() == monitoring.FocusRight {
 					// Right pane focused → switch to left pane
 					m.monitoring.SetFocusLeft()
@@ -1036,6 +1359,10 @@ func (m Model) isTextInputActive() bool {
 	if m.detail.QueueActive() && m.detail.Interacting() && m.detail.QueueInputFocused() {
 		return true
 	}
+	// Analytics custom range picker
+	if m.activeTab == tabbar.TabMonitoring && m.monitoring.AnalyticsPickerActive() {
+		return true
+	}
 	// AI tab chat input is active when the chat pane is focused
 	if m.activeTab == tabbar.TabAI && m.aiTab.IsTextInputActive() {
 		return true
@@ -1194,6 +1521,11 @@ func (m *Model) layout() {
 	m.monitoring.SetSize(contentWidth, contentHeight)
 	m.configView.SetSize(contentWidth, contentHeight)
 	m.aiTab.SetSize(contentWidth, contentHeight)
+	m.buildsPopup.SetSize(m.height)
+	m.buildTriggersPopup.SetSize(m.height)
+	m.schemaDiffPopup.SetSize(m.height)
+	m.d1GridPopup.SetSize(m.width, m.height)
+	m.requestPopup.SetSize(m.width, m.height)
 	// Detail content starts after: header(1) + tab bar(3) + dropdown(1) + right pane border(1)
 	m.detail.SetYOffset(headerHeight + tabBarHeight + 2)
 }
@@ -38,7 +38,7 @@ func (m Model) buildMonorepoActionsPopup() actions.Model {
 			})
 		} else {
 			items = append(items, actions.Item{
-				Label:       "Parallel Tail...",
+				Label:       m.scopedLabel("Parallel Tail"),
 				Description: "Stream all live logs from an environment",
 				Section:     "Monitoring",
 				Action:      "parallel_tail",
@@ -49,13 +49,59 @@ func (m Model) buildMonorepoActionsPopup() actions.Model {
 	// Commands section: Deploy All
 	if len(envNames) > 0 && m.client != nil && !m.showDeployAllPopup {
 		items = append(items, actions.Item{
-			Label:       "Deploy All...",
+			Label:       m.scopedLabel("Deploy All"),
 			Description: "Deploy all projects for an environment",
 			Section:     "Commands",
 			Action:      "deploy_all",
 		})
 	}
 
+	// Commands section: run several dev servers side by side
+	if len(envNames) > 0 {
+		items = append(items, actions.Item{
+			Label:       m.scopedLabel("Dev Sessions"),
+			Description: "Run wrangler dev for several projects on their own ports",
+			Section:     "Commands",
+			Action:      "dev_sessions",
+		})
+	}
+
+	// Configuration section: lint every project
+	if len(envNames) > 0 {
+		items = append(items, actions.Item{
+			Label:       "Config Diagnostics",
+			Description: "Check all project configs for problems",
+			Section:     "Configuration",
+			Action:      "config_diagnostics",
+		})
+	}
+
+	// Projects section: tags, filter and saved workspaces
+	if m.wrangler.SelectedProjectRelPath() != "" {
+		items = append(items, actions.Item{
+			Label:       "Tag Project...",
+			Description: "Add or remove tags on the selected project",
+			Section:     "Projects",
+			Action:      "tag_project",
+		})
+	}
+	if len(m.wrangler.AllTags()) > 0 {
+		items = append(items, actions.Item{
+			Label:       "Filter by Tag...",
+			Description: "Show only projects with some tags",
+			Section:     "Projects",
+			Action:      "tag_filter",
+		})
+	}
+	if m.cfg != nil {
+		items = append(items, actions.Item{
+			Label:       "Save Workspace...",
+			Description: "Save this root, tag filter and default env for ctrl+l",
+			Section:     "Projects",
+			Action:      "save_workspace",
+		})
+	}
+
 	// CI/CD section (when a project with config is selected)
 	if m.wrangler.SelectedProjectConfig() != nil {
 		items = append(items, actions.Item{
@@ -64,6 +110,28 @@ func (m Model) buildMonorepoActionsPopup() actions.Model {
 			Section:     "CI/CD",
 			Action:      "setup_cicd_monorepo",
 		})
+		if m.client != nil {
+			items = append(items, actions.Item{
+				Label:       "Builds",
+				Description: "Recent builds and live logs of this project",
+				Section:     "CI/CD",
+				Action:      "builds_selected",
+			})
+			items = append(items, actions.Item{
+				Label:       "Build Triggers",
+				Description: "View, edit, copy and delete this project's build triggers",
+				Section:     "CI/CD",
+				Action:      "build_triggers_selected",
+			})
+		}
+	}
+	if len(envNames) > 0 && m.client != nil {
+		items = append(items, actions.Item{
+			Label:       m.scopedLabel("All Builds"),
+			Description: "Recent builds across the projects",
+			Section:     "CI/CD",
+			Action:      "builds_all",
+		})
 	}
 
 	// Create project action
@@ -92,6 +160,18 @@ func (m Model) buildMonorepoActionsPopup() actions.Model {
 			Section:     "Configuration",
 			Action:      "show_env_vars",
 		})
+		items = append(items, actions.Item{
+			Label:       "Config Diagnostics",
+			Description: "Check the config for problems before deploying",
+			Section:     "Configuration",
+			Action:      "config_diagnostics",
+		})
+		items = append(items, actions.Item{
+			Label:       "Edit History",
+			Description: "Review, undo and redo config edits made here",
+			Section:     "Configuration",
+			Action:      "config_history",
+		})
 		items = append(items, actions.Item{
 			Label:       "Add Environment",
 			Description: "Add a new environment to the selected project",
@@ -122,11 +202,14 @@ func (m Model) buildMonorepoActionsPopup() actions.Model {
 // buildParallelTailEnvPopup creates a sub-popup listing environments for parallel tailing.
 func (m Model) buildParallelTailEnvPopup() actions.Model {
 	title := "Parallel Tail — Select Environment"
+	if scope := m.wrangler.ScopeLabel(); scope != "" {
+		title = fmt.Sprintf("Parallel Tail (%s) — Select Environment", scope)
+	}
 	var items []actions.Item
-	for _, envName := range m.wrangler.AllEnvNames() {
+	for _, envName := range m.wrangler.ScopedEnvNames() {
 		// Count how many workers actually define this environment
 		count := 0
-		for _, pc := range m.wrangler.ProjectConfigs() {
+		for _, pc := range m.wrangler.ScopedProjectConfigs() {
 			if pc.Config == nil {
 				continue
 			}
@@ -150,10 +233,13 @@ func (m Model) buildParallelTailEnvPopup() actions.Model {
 // buildDeployAllEnvPopup creates a sub-popup listing environments for Deploy All.
 func (m Model) buildDeployAllEnvPopup() actions.Model {
 	title := "Deploy All — Select Environment"
+	if scope := m.wrangler.ScopeLabel(); scope != "" {
+		title = fmt.Sprintf("Deploy All (%s) — Select Environment", scope)
+	}
 	var items []actions.Item
-	for _, envName := range m.wrangler.AllEnvNames() {
+	for _, envName := range m.wrangler.ScopedEnvNames() {
 		count := 0
-		for _, pc := range m.wrangler.ProjectConfigs() {
+		for _, pc := range m.wrangler.ScopedProjectConfigs() {
 			if pc.Config == nil {
 				continue
 			}
@@ -235,6 +321,20 @@ func (m Model) buildWranglerActionsPopup() actions.Model {
 				})
 			}
 		}
+		if len(m.devSessions) > 0 {
+			items = append(items, actions.Item{
+				Label:       "Dev Sessions...",
+				Description: "Ports, health and restarts of all running dev servers",
+				Section:     "Commands",
+				Action:      "dev_sessions",
+			})
+		}
+		items = append(items, actions.Item{
+			Label:       "HTTP Request...",
+			Description: "Send a request to the dev server or deployed Worker",
+			Section:     "Commands",
+			Action:      "http_request",
+		})
 
 		// Delete worker action
 		items = append(items, actions.Item{
@@ -305,6 +405,20 @@ func (m Model) buildWranglerActionsPopup() actions.Model {
 			Section:     "CI/CD",
 			Action:      "setup_cicd",
 		})
+		if m.client != nil {
+			items = append(items, actions.Item{
+				Label:       "Builds",
+				Description: "Recent builds, live logs, retry and manual builds",
+				Section:     "CI/CD",
+				Action:      "builds_project",
+			})
+			items = append(items, actions.Item{
+				Label:       "Build Triggers",
+				Description: "Branches, paths, commands and build variables per environment",
+				Section:     "CI/CD",
+				Action:      "build_triggers_project",
+			})
+		}
 	}
 
 	// Configuration section actions (only when config is loaded)
@@ -411,7 +525,9 @@ func (m Model) buildActionsPopup() actions.Model {
 	switch serviceName {
 	case "Workers":
 		items = m.buildWorkerActions()
-	case "KV", "R2", "D1":
+	case "D1":
+		items = append(m.buildD1Actions(), m.buildBoundWorkersActions()...)
+	case "KV", "R2":
 		items = m.buildBoundWorkersActions()
 	}
 	items = m.appendRestrictedAction(items)
@@ -452,6 +568,39 @@ func (m Model) buildWorkerActions() []actions.Item {
 	return items
 }
 
+// buildD1Actions builds the data, backup and schema actions for a D1 detail
+// view. Time Travel is only offered for remote databases.
+func (m Model) buildD1Actions() []actions.Item {
+	if m.detail.ResourceDetail() == nil {
+		return nil
+	}
+	items := []actions.Item{{
+		Label:       "Browse / edit rows",
+		Description: "Page through a table, edit cells, insert and delete rows",
+		Section:     "Data",
+		Action:      "d1_grid",
+	}, {
+		Label:       "Export / Import / Snapshots",
+		Description: "Back up to or restore from a .sql file",
+		Section:     "Backup",
+		Action:      "d1_backup",
+	}, {
+		Label:       "Compare schema",
+		Description: "Diff against local dev, another environment or database",
+		Section:     "Backup",
+		Action:      "d1_schema_diff",
+	}}
+	if !m.detail.IsLocalResource() {
+		items = append(items, actions.Item{
+			Label:       "Time Travel",
+			Description: "Bookmarks and point-in-time restore",
+			Section:     "Backup",
+			Action:      "d1_time_travel",
+		})
+	}
+	return items
+}
+
 // buildBoundWorkersActions builds the action items for KV/R2/D1 detail views,
 // showing a "Workers" section with navigable links to Workers that bind to this resource.
 func (m Model) buildBoundWorkersActions() []actions.Item {
@@ -554,6 +703,37 @@ func (m *Model) handleActionSelect(item actions.Item) tea.Cmd {
 		return m.cicdPopup.Init()
 	}
 
+	// Builds dashboard actions
+	switch item.Action {
+	case "builds_selected":
+		cfg := m.wrangler.SelectedProjectConfig()
+		return m.openBuildsPopup(m.wrangler.SelectedProjectName(), scriptNames(cfg))
+	case "builds_project":
+		return m.openBuildsPopup(m.wrangler.FocusedProjectName(), scriptNames(m.wrangler.Config()))
+	case "builds_all":
+		var configs []*wcfg.WranglerConfig
+		for _, pc := range m.wrangler.ScopedProjectConfigs() {
+			configs = append(configs, pc.Config)
+		}
+		title := m.wrangler.RootName()
+		if scope := m.wrangler.ScopeLabel(); scope != "" {
+			title += " (" + scope + ")"
+		}
+		return m.openBuildsPopup(title, scriptNames(configs...))
+	case "build_triggers_selected":
+		return m.openBuildTriggersPopup(m.wrangler.SelectedProjectName(), triggerEnvs(m.wrangler.SelectedProjectConfig()))
+	case "build_triggers_project":
+		return m.openBuildTriggersPopup(m.wrangler.FocusedProjectName(), triggerEnvs(m.wrangler.Config()))
+	case "d1_backup":
+		return m.openD1DumpPopup()
+	case "d1_time_travel":
+		return m.openTimeTravelPopup()
+	case "d1_schema_diff":
+		return m.openSchemaDiffPopup()
+	case "d1_grid":
+		return m.openD1GridPopup()
+	}
+
 	// Setup CI/CD action (from drilled-in project view — git detected at project level)
 	if item.Action == "setup_cicd" {
 		gitInfo := m.wrangler.GitInfo()
@@ -668,7 +848,7 @@ func (m *Model) handleActionSelect(item actions.Item) tea.Cmd {
 		envName := strings.TrimPrefix(item.Action, "parallel_tail_env_")
 		var targets []uiwrangler.ParallelTailTarget
 		caches := m.registry.GetAllDeploymentCaches()
-		for _, pc := range m.wrangler.ProjectConfigs() {
+		for _, pc := range m.wrangler.ScopedProjectConfigs() {
 			if pc.Config == nil {
 				continue
 			}
@@ -697,6 +877,38 @@ func (m *Model) handleActionSelect(item actions.Item) tea.Cmd {
 		}
 	}
 
+	if item.Action == "config_diagnostics" {
+		m.openDiagnosticsPopup()
+		return nil
+	}
+	if item.Action == "config_history" {
+		m.openHistoryPopup()
+		return nil
+	}
+
+	// Project tags, tag filter and saved workspaces
+	if item.Action == "tag_project" {
+		m.openTagPopup()
+		return nil
+	}
+	if item.Action == "tag_filter" {
+		m.showActions = true
+		m.actionsPopup = m.buildTagFilterPopup()
+		return nil
+	}
+	if item.Action == "tag_filter_clear" {
+		m.wrangler.SetTagFilter(nil)
+		return nil
+	}
+	if strings.HasPrefix(item.Action, "tag_filter:") {
+		m.wrangler.ToggleTagFilter(strings.TrimPrefix(item.Action, "tag_filter:"))
+		return nil
+	}
+	if item.Action == "save_workspace" {
+		m.openWorkspacePopup()
+		return nil
+	}
+
 	// Deploy All: open environment sub-popup
 	if item.Action == "deploy_all" {
 		m.showActions = true
@@ -707,7 +919,7 @@ func (m *Model) handleActionSelect(item actions.Item) tea.Cmd {
 	// Deploy All: start deploying for selected environment
 	if strings.HasPrefix(item.Action, "deploy_all_env_") {
 		envName := strings.TrimPrefix(item.Action, "deploy_all_env_")
-		return m.startDeployAll(envName)
+		return m.gateDeployAll(envName)
 	}
 
 	// Wrangler version picker actions (must be checked before generic wrangler_ prefix)
@@ -755,6 +967,13 @@ func (m *Model) handleActionSelect(item actions.Item) tea.Cmd {
 
 		return m.startDevServer(action, projectName, envName, configPath, scriptName)
 	}
+	if item.Action == "http_request" {
+		return m.openRequestPopup()
+	}
+	if item.Action == "dev_sessions" {
+		m.openDevSessionsPopup()
+		return nil
+	}
 	if item.Action == "wrangler_stop_dev" {
 		projectName := m.wrangler.FocusedProjectName()
 		envName := m.wrangler.FocusedEnvName()
@@ -470,10 +470,11 @@ func (m *Model) handleAIMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 			// The sawBusy flag in readOpenCodeEvents protects against stale
 			// idle events, so we can safely reuse the session for multi-turn
 			// context. For stateless backends (Workers AI, OpenAI) this is a
-			// no-op. Only HTTPBackend (OpenCode) has Abort().
+			// no-op. HTTPBackend (OpenCode) and LocalBackend (JSONL agent)
+			// implement Aborter.
 			if b := m.aiTab.Backend(); b != nil {
-				if hb, ok := b.(*uiai.HTTPBackend); ok {
-					hb.Abort()
+				if ab, ok := b.(uiai.Aborter); ok {
+					ab.Abort()
 				}
 			}
 			// Tell the chat model the stream was cancelled (not an error)
@@ -490,6 +491,8 @@ func (m *Model) handleAIMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		m.cfg.AIHTTPProtocol = msg.HTTPProtocol
 		m.cfg.AIHTTPModel = msg.HTTPModel
 		m.cfg.AIHTTPAPIKey = msg.HTTPAPIKey
+		m.cfg.AILocalCommand = msg.LocalCommand
+		m.cfg.AILocalProtocol = msg.LocalProtocol
 		_ = m.cfg.Save()
 		m.aiTab.RebuildBackend()
 		return *m, nil, true
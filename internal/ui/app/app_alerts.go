@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/config"
+	svc "github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/alertspopup"
+	"github.com/oarafat/orangeshell/internal/ui/monitoring"
+)
+
+// alertPollInterval is how often analytics alert rules are re-evaluated.
+const alertPollInterval = time.Minute
+
+// alertPollTickMsg triggers a round of analytics alert polls. Ticks of an
+// older loop (gen != Model.alertPollGen) are dropped.
+type alertPollTickMsg struct{ gen int }
+
+// alertMetricsMsg carries the analytics result for one analytics alert rule.
+type alertMetricsMsg struct {
+	ruleIndex int
+	accountID string
+	data      *api.WorkerMetrics
+	err       error
+}
+
+// handleAlertsMsg handles alert polling and the alerts popup.
+// Returns (model, cmd, handled).
+func (m *Model) handleAlertsMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case alertPollTickMsg:
+		polls := m.alerts.AnalyticsPolls()
+		if msg.gen != m.alertPollGen || len(polls) == 0 {
+			return *m, nil, true
+		}
+		var cmds []tea.Cmd
+		for _, p := range polls {
+			cmds = append(cmds, m.fetchAlertMetricsCmd(p))
+		}
+		cmds = append(cmds, alertPollTick(msg.gen))
+		return *m, tea.Batch(cmds...), true
+
+	case alertMetricsMsg:
+		// Poll failures are not surfaced — the analytics view reports
+		// credential problems, and the next poll retries.
+		if msg.err != nil || m.isStaleAccount(msg.accountID) {
+			return *m, nil, true
+		}
+		fired := m.alerts.ObserveMetrics(msg.ruleIndex, msg.data, time.Now())
+		return *m, m.raiseAlerts(fired), true
+
+	case alertspopup.CloseMsg:
+		m.showAlertsPopup = false
+		return *m, nil, true
+
+	case alertspopup.ClearMsg:
+		m.alerts.ClearHistory()
+		m.header.SetAlertCount(0)
+		return *m, nil, true
+	}
+	return *m, nil, false
+}
+
+// initAlerts builds the alert engine from the config's [[alerts]] rules.
+func (m *Model) initAlerts() {
+	engine, errs := monitoring.NewAlertEngine(m.cfg.Alerts)
+	m.alerts = engine
+	m.alertRuleErrs = nil
+	for _, err := range errs {
+		m.alertRuleErrs = append(m.alertRuleErrs, err.Error())
+	}
+}
+
+// alertPollCmd starts analytics alert polling if any analytics rule exists.
+func (m Model) alertPollCmd() tea.Cmd {
+	if len(m.alerts.AnalyticsPolls()) == 0 {
+		return nil
+	}
+	gen := m.alertPollGen
+	return func() tea.Msg { return alertPollTickMsg{gen: gen} }
+}
+
+// restartAlertPolls replaces a running poll loop, e.g. after setup has
+// reloaded the rules and possibly switched accounts.
+func (m *Model) restartAlertPolls() tea.Cmd {
+	m.alertPollGen++
+	return m.alertPollCmd()
+}
+
+func alertPollTick(gen int) tea.Cmd {
+	return tea.Tick(alertPollInterval, func(time.Time) tea.Msg { return alertPollTickMsg{gen: gen} })
+}
+
+// fetchAlertMetricsCmd fetches the analytics window of an analytics rule.
+func (m *Model) fetchAlertMetricsCmd(p monitoring.AlertPoll) tea.Cmd {
+	client := m.getAnalyticsClient()
+	accountID := m.registry.ActiveAccountID()
+	return func() tea.Msg {
+		now := time.Now()
+		tr := api.CustomTimeRange(now.Add(-p.Window), now)
+		data, err := client.FetchWorkerMetrics(context.Background(), p.Worker, tr)
+		return alertMetricsMsg{ruleIndex: p.RuleIndex, accountID: accountID, data: data, err: err}
+	}
+}
+
+// observeTailAlerts feeds tail lines to the alert engine and raises any alerts
+// that fired.
+func (m *Model) observeTailAlerts(scriptName string, lines []svc.TailLine) tea.Cmd {
+	if !m.alerts.HasRules() || scriptName == "" {
+		return nil
+	}
+	return m.raiseAlerts(m.alerts.ObserveTail(scriptName, lines, time.Now()))
+}
+
+// raiseAlerts surfaces fired alerts: header badge, toast, and the configured
+// terminal notification.
+func (m *Model) raiseAlerts(fired []monitoring.AlertEvent) tea.Cmd {
+	if len(fired) == 0 {
+		return nil
+	}
+	m.header.SetAlertCount(m.alerts.Unread())
+
+	last := fired[len(fired)-1]
+	toast := "⚠ " + last.Rule + " — " + last.Message
+	if len(fired) > 1 {
+		toast += fmt.Sprintf(" (+%d more, ! to view)", len(fired)-1)
+	}
+	m.setToast(toast)
+
+	return tea.Batch(toastTick(), notifyTerminalCmd(m.cfg.AlertNotify, last))
+}
+
+// openAlertsPopup shows the alert history and marks all alerts as read.
+func (m *Model) openAlertsPopup() {
+	m.alertsPopup = alertspopup.New(m.alerts.History(), m.alertRuleCount(), m.alertRuleErrs)
+	m.showAlertsPopup = true
+	m.alerts.MarkRead()
+	m.header.SetAlertCount(0)
+}
+
+func (m Model) alertRuleCount() int {
+	return len(m.cfg.Alerts) - len(m.alertRuleErrs)
+}
+
+// Output is the terminal the program renders to. main passes it to
+// tea.WithOutput so that notifications written from commands go out between
+// frames rather than in the middle of one.
+var Output = &terminalOutput{File: os.Stdout}
+
+// terminalOutput serializes writes to the terminal: the renderer writes each
+// frame in a single Write, so holding the lock keeps a frame whole.
+type terminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (o *terminalOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(p)
+}
+
+// notifyTerminalCmd emits a terminal bell and/or an OSC 9 desktop notification.
+func notifyTerminalCmd(mode config.AlertNotifyMode, ev monitoring.AlertEvent) tea.Cmd {
+	if mode == config.AlertNotifyNone {
+		return nil
+	}
+	return func() tea.Msg {
+		var seq strings.Builder
+		if mode == config.AlertNotifyOSC9 || mode == config.AlertNotifyBoth {
+			// Strip control characters so the message can't terminate the sequence early
+			text := strings.Map(func(r rune) rune {
+				if r < 0x20 || r == 0x7f {
+					return ' '
+				}
+				return r
+			}, "orangeshell: "+ev.Rule+" — "+ev.Message)
+			seq.WriteString("\x1b]9;" + text + "\x07")
+		}
+		if mode == "" || mode == config.AlertNotifyBell || mode == config.AlertNotifyBoth {
+			seq.WriteString("\a")
+		}
+		_, _ = Output.Write([]byte(seq.String()))
+		return nil
+	}
+}
@@ -0,0 +1,358 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/buildspopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// buildsPollInterval is how often running builds and their logs are polled
+// while the builds dashboard is open.
+const buildsPollInterval = 3 * time.Second
+
+// buildsPerWorker caps the builds listed per Worker.
+const buildsPerWorker = 25
+
+// buildsLoadedMsg delivers the build list along with the script tags that
+// were resolved for it, cached for the polls and actions that follow.
+type buildsLoadedMsg struct {
+	gen    int
+	builds []buildspopup.Build
+	tags   map[string]string
+	err    error
+}
+
+// buildsPollMsg drives polling of the builds dashboard. Stale generations
+// (from a closed or reopened dashboard) end their loop.
+type buildsPollMsg struct {
+	gen int
+}
+
+// handleBuildsMsg handles the Workers Builds dashboard. Returns (model, cmd, handled).
+func (m *Model) handleBuildsMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case buildspopup.CloseMsg:
+		m.showBuildsPopup = false
+		m.buildsGen++
+		return *m, nil, true
+
+	case buildspopup.RefreshMsg:
+		return *m, m.loadBuildsCmd(), true
+
+	case buildsLoadedMsg:
+		if msg.gen != m.buildsGen {
+			return *m, nil, true
+		}
+		if m.buildsTags == nil {
+			m.buildsTags = make(map[string]string)
+		}
+		for script, tag := range msg.tags {
+			m.buildsTags[script] = tag
+		}
+		var cmd tea.Cmd
+		m.buildsPopup, cmd = m.buildsPopup.Update(buildspopup.LoadedMsg{
+			Builds:     msg.builds,
+			Err:        msg.err,
+			Restricted: api.IsAuthError(msg.err),
+		})
+		return *m, cmd, true
+
+	case buildspopup.LogMsg, buildspopup.ActionDoneMsg:
+		if !m.showBuildsPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.buildsPopup, cmd = m.buildsPopup.Update(msg)
+		if done, ok := msg.(buildspopup.ActionDoneMsg); ok && done.Err == nil {
+			// Show the new or cancelled build right away
+			cmd = tea.Batch(cmd, m.loadBuildsCmd())
+		}
+		return *m, cmd, true
+
+	case buildspopup.OpenLogMsg:
+		return *m, m.buildLogCmd(msg.Build), true
+
+	case buildspopup.RetryMsg:
+		return *m, m.retryBuildCmd(msg.Build), true
+
+	case buildspopup.CancelBuildMsg:
+		return *m, m.cancelBuildCmd(msg.Build), true
+
+	case buildspopup.NewBuildMsg:
+		return *m, m.manualBuildCmd(msg.Script, msg.Branch), true
+
+	case buildsPollMsg:
+		if msg.gen != m.buildsGen || !m.showBuildsPopup {
+			return *m, nil, true
+		}
+		cmds := []tea.Cmd{m.buildsPollTick()}
+		if b, ok := m.buildsPopup.StreamingBuild(); ok {
+			cmds = append(cmds, m.buildLogCmd(b))
+		}
+		if m.buildsPopup.HasActiveBuilds() {
+			cmds = append(cmds, m.loadBuildsCmd())
+		}
+		return *m, tea.Batch(cmds...), true
+	}
+	return *m, nil, false
+}
+
+// openBuildsPopup shows the builds dashboard for the given Worker scripts.
+func (m *Model) openBuildsPopup(title string, scripts []string) tea.Cmd {
+	if len(scripts) == 0 {
+		m.setToast("No Workers to show builds for")
+		return toastTick()
+	}
+	m.buildsGen++
+	m.buildsPopup = buildspopup.New(title, scripts)
+	m.buildsPopup.SetSize(m.height)
+	m.showBuildsPopup = true
+	return tea.Batch(m.buildsPopup.SpinnerInit(), m.loadBuildsCmd(), m.buildsPollTick())
+}
+
+// buildsPollTick schedules the next poll of the open dashboard.
+func (m Model) buildsPollTick() tea.Cmd {
+	gen := m.buildsGen
+	return tea.Tick(buildsPollInterval, func(time.Time) tea.Msg {
+		return buildsPollMsg{gen: gen}
+	})
+}
+
+// scriptNames returns the unique Worker script names of the given configs,
+// across all their environments.
+func scriptNames(configs ...*wcfg.WranglerConfig) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		for _, env := range cfg.EnvNames() {
+			if name := cfg.ResolvedEnvName(env); name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// buildsTagsCopy returns a copy of the cached script tags for a command's
+// goroutine.
+func (m Model) buildsTagsCopy() map[string]string {
+	tags := make(map[string]string, len(m.buildsTags))
+	for k, v := range m.buildsTags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// resolveScriptTag returns the Builds API identifier of a Worker, using the
+// tags cached by the last list load when possible.
+func resolveScriptTag(ctx context.Context, client *api.BuildsClient, tags map[string]string, script string) (string, error) {
+	if tag := tags[script]; tag != "" {
+		return tag, nil
+	}
+	return client.GetScriptTag(ctx, script)
+}
+
+// loadBuildsCmd lists the recent builds of every Worker on the dashboard,
+// newest first. Workers that aren't found or have no builds are skipped; an
+// auth error is reported since it applies to all of them.
+func (m Model) loadBuildsCmd() tea.Cmd {
+	client := m.getBuildsClient()
+	scripts := m.buildsPopup.Scripts()
+	tags := m.buildsTagsCopy()
+	gen := m.buildsGen
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			builds  []buildspopup.Build
+			resolve = make(map[string]string)
+			authErr error
+			lastErr error
+		)
+		for _, script := range scripts {
+			wg.Add(1)
+			go func(script string) {
+				defer wg.Done()
+				tag, err := resolveScriptTag(ctx, client, tags, script)
+				var results []api.BuildResult
+				if err == nil {
+					results, err = client.ListBuilds(ctx, tag, buildsPerWorker)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					if api.IsAuthError(err) {
+						authErr = err
+					}
+					lastErr = err
+					return
+				}
+				resolve[script] = tag
+				for _, r := range results {
+					builds = append(builds, buildspopup.Build{Script: script, BuildResult: r})
+				}
+			}(script)
+		}
+		wg.Wait()
+
+		if authErr != nil {
+			return buildsLoadedMsg{gen: gen, err: authErr}
+		}
+		if len(builds) == 0 && lastErr != nil && len(scripts) == 1 {
+			return buildsLoadedMsg{gen: gen, err: lastErr}
+		}
+		sort.Slice(builds, func(i, j int) bool {
+			return builds[i].CreatedOn.After(builds[j].CreatedOn)
+		})
+		return buildsLoadedMsg{gen: gen, builds: builds, tags: resolve}
+	}
+}
+
+// buildLogCmd fetches the log of a build so far together with its current
+// status. Polled while the build runs, it streams the log.
+func (m Model) buildLogCmd(b buildspopup.Build) tea.Cmd {
+	client := m.getBuildsClient()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		fresh, _ := client.GetBuild(ctx, b.BuildUUID)
+
+		// The log endpoint has no "since" parameter for a running build, so
+		// each poll fetches the whole log and the popup replaces its lines
+		logLines, err := client.GetBuildLog(ctx, b.BuildUUID)
+		if err != nil {
+			if fresh != nil && fresh.Active() {
+				// A queued build has no log yet; keep polling
+				return buildspopup.LogMsg{BuildUUID: b.BuildUUID, Build: fresh}
+			}
+			return buildspopup.LogMsg{BuildUUID: b.BuildUUID, Err: err}
+		}
+		lines := make([]string, len(logLines))
+		for i, l := range logLines {
+			lines[i] = l.Message
+		}
+		return buildspopup.LogMsg{BuildUUID: b.BuildUUID, Lines: lines, Build: fresh}
+	}
+}
+
+// pickTrigger returns the trigger that builds a branch: the first one whose
+// branch includes list it (or a wildcard), else the first trigger.
+func pickTrigger(triggers []api.Trigger, branch string) *api.Trigger {
+	if len(triggers) == 0 {
+		return nil
+	}
+	for i, t := range triggers {
+		for _, inc := range t.BranchIncludes {
+			if inc == branch || inc == "*" {
+				return &triggers[i]
+			}
+		}
+	}
+	return &triggers[0]
+}
+
+// workerTrigger resolves the trigger to build a Worker's branch with.
+func (m Model) workerTrigger(ctx context.Context, client *api.BuildsClient, tags map[string]string, script, branch string) (*api.Trigger, error) {
+	tag, err := resolveScriptTag(ctx, client, tags, script)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := client.GetWorkerTriggers(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	t := pickTrigger(triggers, branch)
+	if t == nil {
+		return nil, fmt.Errorf("%s has no build trigger — set up CI/CD first", script)
+	}
+	return t, nil
+}
+
+// retryBuildCmd starts a new build of the same branch and commit, through
+// the trigger that ran the original build.
+func (m Model) retryBuildCmd(b buildspopup.Build) tea.Cmd {
+	client := m.getBuildsClient()
+	tags := m.buildsTagsCopy()
+	branch := b.BuildTriggerMetadata.Branch
+	commit := b.BuildTriggerMetadata.CommitHash
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		triggerUUID := ""
+		if b.Trigger != nil {
+			triggerUUID = b.Trigger.UUID
+		}
+		if triggerUUID == "" {
+			t, err := m.workerTrigger(ctx, client, tags, b.Script, branch)
+			if err != nil {
+				return buildspopup.ActionDoneMsg{Err: err}
+			}
+			triggerUUID = t.UUID
+		}
+		res, err := client.CreateManualBuild(ctx, triggerUUID, branch, commit)
+		if err != nil {
+			return buildspopup.ActionDoneMsg{Err: fmt.Errorf("retry failed: %w", err)}
+		}
+		return buildspopup.ActionDoneMsg{Status: fmt.Sprintf("Started build %s of %s (%s)", shortBuildID(res.BuildUUID), b.Script, branch)}
+	}
+}
+
+// cancelBuildCmd cancels a queued or running build.
+func (m Model) cancelBuildCmd(b buildspopup.Build) tea.Cmd {
+	client := m.getBuildsClient()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := client.CancelBuild(ctx, b.BuildUUID); err != nil {
+			return buildspopup.ActionDoneMsg{Err: fmt.Errorf("cancel failed: %w", err)}
+		}
+		return buildspopup.ActionDoneMsg{Status: fmt.Sprintf("Cancelled build %s of %s", shortBuildID(b.BuildUUID), b.Script)}
+	}
+}
+
+// manualBuildCmd starts a build of a branch through the Worker's trigger.
+func (m Model) manualBuildCmd(script, branch string) tea.Cmd {
+	client := m.getBuildsClient()
+	tags := m.buildsTagsCopy()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		t, err := m.workerTrigger(ctx, client, tags, script, branch)
+		if err != nil {
+			return buildspopup.ActionDoneMsg{Err: err}
+		}
+		res, err := client.CreateManualBuild(ctx, t.UUID, branch, "")
+		if err != nil {
+			return buildspopup.ActionDoneMsg{Err: fmt.Errorf("manual build failed: %w", err)}
+		}
+		return buildspopup.ActionDoneMsg{Status: fmt.Sprintf("Started build %s of %s (%s) via %s", shortBuildID(res.BuildUUID), script, branch, t.Name)}
+	}
+}
+
+func shortBuildID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -108,6 +108,7 @@ func (m *Model) checkCICDInstallCmd(msg cicdpopup.CheckInstallMsg) tea.Cmd {
 	// Copy git info for the goroutine
 	gitInfo := &wcfg.GitInfo{
 		ProviderType: msg.Provider,
+		Host:         msg.Host,
 		Owner:        msg.ProviderAccountID,
 		RepoName:     msg.RepoID,
 	}
@@ -118,8 +119,8 @@ func (m *Model) checkCICDInstallCmd(msg cicdpopup.CheckInstallMsg) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		// Resolve string names to numeric GitHub/GitLab IDs.
-		// The Cloudflare Builds API requires numeric IDs.
+		// Resolve string names to GitHub/GitLab/Bitbucket IDs.
+		// The Cloudflare Builds API requires provider IDs.
 		if err := gitInfo.ResolveGitIDs(ctx); err != nil {
 			// Non-fatal — proceed without numeric IDs, API calls may fail
 			// but we'll show proper errors
@@ -391,7 +392,7 @@ func (m *Model) setupCICDCmd(msg cicdpopup.SetupCICDMsg) tea.Cmd {
 		if len(trigger.BranchIncludes) > 0 {
 			buildBranch = trigger.BranchIncludes[0]
 		}
-		_, _ = client.CreateManualBuild(ctx, trigger.UUID, buildBranch)
+		_, _ = client.CreateManualBuild(ctx, trigger.UUID, buildBranch, "")
 
 		return cicdpopup.SetupCICDDoneMsg{
 			Trigger:            trigger,
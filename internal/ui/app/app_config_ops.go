@@ -31,7 +31,9 @@ func (m Model) updateEnvPopup(msg tea.Msg) (tea.Model, tea.Cmd) {
 // createEnvCmd writes a new empty environment section into the wrangler config file.
 func (m Model) createEnvCmd(configPath, envName string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.AddEnvironment(configPath, envName)
+		err := m.journal.Record(configPath, "Add environment "+envName, func() error {
+			return wcfg.AddEnvironment(configPath, envName)
+		})
 		return envpopup.CreateEnvDoneMsg{
 			EnvName: envName,
 			Err:     err,
@@ -42,7 +44,9 @@ func (m Model) createEnvCmd(configPath, envName string) tea.Cmd {
 // deleteEnvCmd removes an environment section from the wrangler config file.
 func (m Model) deleteEnvCmd(configPath, envName string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.DeleteEnvironment(configPath, envName)
+		err := m.journal.Record(configPath, "Delete environment "+envName, func() error {
+			return wcfg.DeleteEnvironment(configPath, envName)
+		})
 		return envpopup.DeleteEnvDoneMsg{
 			EnvName: envName,
 			Err:     err,
@@ -101,11 +105,26 @@ func (m Model) deleteResourceCmd(serviceName, resourceID string) tea.Cmd {
 // removeBindingCmd removes a binding from the local wrangler config file.
 func (m Model) removeBindingCmd(configPath, envName, bindingName, bindingType string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.RemoveBinding(configPath, envName, bindingName, bindingType)
+		err := m.removeBinding(configPath, envName, bindingName, bindingType)
 		return deletepopup.DeleteBindingDoneMsg{Err: err}
 	}
 }
 
+// removeBinding removes a binding from the config, recording it in the edit journal.
+func (m Model) removeBinding(configPath, envName, bindingName, bindingType string) error {
+	return m.journal.Record(configPath, fmt.Sprintf("Remove %s binding %s%s", bindingType, bindingName, envSuffix(envName)), func() error {
+		return wcfg.RemoveBinding(configPath, envName, bindingName, bindingType)
+	})
+}
+
+// envSuffix describes a named environment for journal entries ("" for default).
+func envSuffix(envName string) string {
+	if envName == "" || envName == "default" {
+		return ""
+	}
+	return " (env " + envName + ")"
+}
+
 // --- Environment variables / Triggers navigation helpers ---
 
 // navigateToEnvVars opens the Configuration tab with Env Variables category selected
@@ -131,7 +150,9 @@ func (m *Model) navigateToTriggers(configPath string) tea.Cmd {
 // setVarCmd writes an env var into the wrangler config file.
 func (m Model) setVarCmd(configPath, envName, varName, value string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.SetVar(configPath, envName, varName, value)
+		err := m.journal.Record(configPath, fmt.Sprintf("Set var %s%s", varName, envSuffix(envName)), func() error {
+			return wcfg.SetVar(configPath, envName, varName, value)
+		})
 		return uiconfig.SetVarDoneMsg{Err: err}
 	}
 }
@@ -139,7 +160,9 @@ func (m Model) setVarCmd(configPath, envName, varName, value string) tea.Cmd {
 // removeVarCmd removes an env var from the wrangler config file.
 func (m Model) removeVarCmd(configPath, envName, varName string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.RemoveVar(configPath, envName, varName)
+		err := m.journal.Record(configPath, fmt.Sprintf("Remove var %s%s", varName, envSuffix(envName)), func() error {
+			return wcfg.RemoveVar(configPath, envName, varName)
+		})
 		return uiconfig.DeleteVarDoneMsg{Err: err}
 	}
 }
@@ -147,7 +170,9 @@ func (m Model) removeVarCmd(configPath, envName, varName string) tea.Cmd {
 // addCronCmd adds a cron trigger to the wrangler config file.
 func (m Model) addCronCmd(configPath, cron string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.AddCron(configPath, cron)
+		err := m.journal.Record(configPath, fmt.Sprintf("Add cron %q", cron), func() error {
+			return wcfg.AddCron(configPath, cron)
+		})
 		return uiconfig.AddCronDoneMsg{Err: err}
 	}
 }
@@ -155,7 +180,9 @@ func (m Model) addCronCmd(configPath, cron string) tea.Cmd {
 // removeCronCmd removes a cron trigger from the wrangler config file.
 func (m Model) removeCronCmd(configPath, cron string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.RemoveCron(configPath, cron)
+		err := m.journal.Record(configPath, fmt.Sprintf("Remove cron %q", cron), func() error {
+			return wcfg.RemoveCron(configPath, cron)
+		})
 		return uiconfig.DeleteCronDoneMsg{Err: err}
 	}
 }
@@ -558,7 +585,7 @@ func (m *Model) handleTriggersMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 // removeBindingForConfigCmd removes a binding and returns uiconfig.DeleteBindingDoneMsg.
 func (m Model) removeBindingForConfigCmd(configPath, envName, bindingName, bindingType string) tea.Cmd {
 	return func() tea.Msg {
-		err := wcfg.RemoveBinding(configPath, envName, bindingName, bindingType)
+		err := m.removeBinding(configPath, envName, bindingName, bindingType)
 		return uiconfig.DeleteBindingDoneMsg{Err: err}
 	}
 }
@@ -643,7 +670,9 @@ func (m Model) writeDirectBindingCmd(configPath, envName string, bindingDef inte
 		}
 	}
 	return func() tea.Msg {
-		err := wcfg.AddBinding(configPath, envName, def)
+		err := m.journal.Record(configPath, fmt.Sprintf("Add %s binding %s%s", def.Type, def.BindingName, envSuffix(envName)), func() error {
+			return wcfg.AddBinding(configPath, envName, def)
+		})
 		return uiconfig.WriteDirectBindingDoneMsg{Err: err}
 	}
 }
@@ -0,0 +1,506 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	svc "github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/d1dumppopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// d1SnapshotCheckInterval is how often scheduled D1 snapshots are checked.
+const d1SnapshotCheckInterval = time.Minute
+
+// d1SnapshotRetryDelay holds off a database whose scheduled snapshot failed.
+const d1SnapshotRetryDelay = 15 * time.Minute
+
+// d1DumpTarget is the database the D1 backup popup works on.
+type d1DumpTarget struct {
+	name  string
+	id    string              // remote database UUID
+	local *wcfg.LocalResource // set for a local dev session database
+}
+
+// d1DumpEventMsg carries one progress or done message of a running export
+// or import, and the channel the rest arrive on.
+type d1DumpEventMsg struct {
+	gen int
+	ch  <-chan tea.Msg
+	msg tea.Msg
+}
+
+// d1SnapshotTickMsg triggers a check for due scheduled snapshots.
+type d1SnapshotTickMsg struct{}
+
+// d1SnapshotsDoneMsg reports a round of scheduled snapshots.
+type d1SnapshotsDoneMsg struct {
+	taken  []string
+	failed map[string]error // snapshot key → error
+}
+
+// handleD1DumpMsg handles the D1 backup popup and scheduled snapshots.
+// Returns (model, cmd, handled).
+func (m *Model) handleD1DumpMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case d1dumppopup.CloseMsg:
+		m.closeD1DumpPopup()
+		return *m, nil, true
+
+	case d1dumppopup.CancelMsg:
+		if m.d1DumpCancel != nil {
+			m.d1DumpCancel()
+			m.d1DumpCancel = nil
+		}
+		m.d1DumpGen++
+		var cmd tea.Cmd
+		m.d1DumpPopup, cmd = m.d1DumpPopup.Update(d1dumppopup.DoneMsg{Err: errors.New("Cancelled")})
+		return *m, cmd, true
+
+	case d1dumppopup.ExportMsg:
+		return *m, m.startD1DumpJob(m.exportD1Job(msg.Mode, m.resolveD1DumpPath(msg.Path))), true
+
+	case d1dumppopup.ImportMsg:
+		return *m, m.startD1DumpJob(m.importD1Job(m.resolveD1DumpPath(msg.Path))), true
+
+	case d1dumppopup.SnapshotMsg:
+		return *m, m.startD1DumpJob(m.snapshotD1Job()), true
+
+	case d1dumppopup.ScheduleMsg:
+		t := m.d1DumpTarget
+		var cmd tea.Cmd
+		done := d1dumppopup.DoneMsg{Status: fmt.Sprintf("Scheduled snapshots of %s turned off", t.name)}
+		if msg.Enabled {
+			done.Status = fmt.Sprintf("Scheduled snapshots of %s turned on", t.name)
+		}
+		if err := wcfg.SetD1Snapshot(m.settingsRoot(), t.name, t.local != nil, msg.Enabled); err != nil {
+			done = d1dumppopup.DoneMsg{Err: err}
+		}
+		m.d1DumpPopup, cmd = m.d1DumpPopup.Update(done)
+		m.d1DumpPopup, _ = m.d1DumpPopup.Update(d1dumppopup.InfoMsg{Info: m.d1DumpInfo(t)})
+		return *m, cmd, true
+
+	case d1DumpEventMsg:
+		if msg.gen != m.d1DumpGen || !m.showD1DumpPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.d1DumpPopup, cmd = m.d1DumpPopup.Update(msg.msg)
+		if _, done := msg.msg.(d1dumppopup.DoneMsg); done {
+			m.d1DumpCancel = nil
+			m.d1DumpPopup, _ = m.d1DumpPopup.Update(d1dumppopup.InfoMsg{Info: m.d1DumpInfo(m.d1DumpTarget)})
+			return *m, cmd, true
+		}
+		return *m, tea.Batch(cmd, readD1DumpEvent(msg.gen, msg.ch)), true
+
+	case d1SnapshotTickMsg:
+		return *m, tea.Batch(m.scheduledD1SnapshotsCmd(), d1SnapshotTick()), true
+
+	case d1SnapshotsDoneMsg:
+		m.d1SnapshotBusy = false
+		if m.d1SnapshotFailed == nil {
+			m.d1SnapshotFailed = make(map[string]time.Time)
+		}
+		for key := range msg.failed {
+			m.d1SnapshotFailed[key] = time.Now()
+		}
+		if m.showD1DumpPopup {
+			m.d1DumpPopup, _ = m.d1DumpPopup.Update(d1dumppopup.InfoMsg{Info: m.d1DumpInfo(m.d1DumpTarget)})
+		}
+		switch {
+		case len(msg.failed) > 0:
+			for key, err := range msg.failed {
+				m.setToast(fmt.Sprintf("D1 snapshot of %s failed: %v", key, err))
+				break
+			}
+		case len(msg.taken) > 0:
+			m.setToast(fmt.Sprintf("D1 snapshot written: %s", strings.Join(msg.taken, ", ")))
+		default:
+			return *m, nil, true
+		}
+		return *m, toastTick(), true
+	}
+	return *m, nil, false
+}
+
+// openD1DumpPopup shows the backup popup for the D1 database in the detail view.
+func (m *Model) openD1DumpPopup() tea.Cmd {
+	var t d1DumpTarget
+	if lr := m.detail.ActiveLocalResource(); m.detail.IsLocalResource() && lr != nil {
+		local := *lr
+		t = d1DumpTarget{name: lr.BindingName, local: &local}
+	} else if rd := m.detail.ResourceDetail(); rd != nil {
+		t = d1DumpTarget{name: rd.Name, id: rd.ID}
+	} else {
+		return nil
+	}
+	m.d1DumpGen++
+	m.d1DumpTarget = t
+	m.d1DumpPopup = d1dumppopup.New(m.d1DumpInfo(t))
+	m.showD1DumpPopup = true
+	return m.d1DumpPopup.SpinnerInit()
+}
+
+// closeD1DumpPopup hides the backup popup and stops its running job.
+func (m *Model) closeD1DumpPopup() {
+	if m.d1DumpCancel != nil {
+		m.d1DumpCancel()
+		m.d1DumpCancel = nil
+	}
+	m.showD1DumpPopup = false
+	m.d1DumpGen++
+}
+
+// settingsRoot returns the directory whose .orangeshell.toml applies: the
+// discovery root, else the open project's directory.
+func (m Model) settingsRoot() string {
+	if root := m.wrangler.RootDir(); root != "" {
+		return root
+	}
+	if path := m.wrangler.ConfigPath(); path != "" {
+		return filepath.Dir(path)
+	}
+	return ""
+}
+
+// d1DumpInfo collects a database's snapshot state for the popup.
+func (m Model) d1DumpInfo(t d1DumpTarget) d1dumppopup.Info {
+	root := m.settingsRoot()
+	info := d1dumppopup.Info{Name: t.name, Local: t.local != nil, ExportDir: root, NoSettings: root == ""}
+	if root == "" {
+		info.ExportDir, _ = os.Getwd()
+	}
+	settings, _ := wcfg.LoadRepoSettings(root)
+	s := settings.Snapshots
+	info.Every = s.Every()
+	info.Keep = s.KeepCount()
+	if root != "" {
+		info.Scheduled = s.Enabled(t.name, t.local != nil)
+		info.Snapshots = s.ListSnapshots(root, t.name, t.local != nil)
+	}
+	return info
+}
+
+// resolveD1DumpPath expands ~ and makes a relative path relative to the
+// project root.
+func (m Model) resolveD1DumpPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.d1DumpPopup.Info().ExportDir, path)
+	}
+	return path
+}
+
+// d1DumpJob runs in the background, reporting progress until it's done.
+type d1DumpJob func(ctx context.Context, progress func(d1dumppopup.ProgressMsg)) d1dumppopup.DoneMsg
+
+// startD1DumpJob runs a job of the backup popup, streaming its progress.
+func (m *Model) startD1DumpJob(job d1DumpJob) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.d1DumpCancel = cancel
+	gen := m.d1DumpGen
+	ch := make(chan tea.Msg, 8)
+
+	go func() {
+		defer close(ch)
+		defer cancel()
+		send := func(msg tea.Msg) {
+			select {
+			case ch <- msg:
+			case <-ctx.Done():
+			}
+		}
+		done := job(ctx, func(p d1dumppopup.ProgressMsg) { send(p) })
+		send(done)
+	}()
+	return readD1DumpEvent(gen, ch)
+}
+
+// readD1DumpEvent reads the next message of a running job.
+func readD1DumpEvent(gen int, ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return d1DumpEventMsg{gen: gen, ch: ch, msg: msg}
+	}
+}
+
+func (m Model) exportD1Job(mode svc.D1DumpMode, path string) d1DumpJob {
+	t := m.d1DumpTarget
+	d1Svc := m.getD1Service()
+	return func(ctx context.Context, progress func(d1dumppopup.ProgressMsg)) d1dumppopup.DoneMsg {
+		err := exportD1(ctx, d1Svc, t, mode, path, func(s string) {
+			progress(d1dumppopup.ProgressMsg{Text: s})
+		})
+		if err != nil {
+			return d1dumppopup.DoneMsg{Err: err}
+		}
+		return d1dumppopup.DoneMsg{Status: fmt.Sprintf("Exported %s (%s) to %s%s", t.name, mode, path, fileSizeSuffix(path))}
+	}
+}
+
+func (m Model) importD1Job(path string) d1DumpJob {
+	t := m.d1DumpTarget
+	d1Svc := m.getD1Service()
+	return func(ctx context.Context, progress func(d1dumppopup.ProgressMsg)) d1dumppopup.DoneMsg {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return d1dumppopup.DoneMsg{Err: err}
+		}
+		stmts := svc.SplitSQLStatements(string(data))
+		if len(stmts) == 0 {
+			return d1dumppopup.DoneMsg{Err: fmt.Errorf("%s has no SQL statements", filepath.Base(path))}
+		}
+
+		// Remote batches are atomic, so a failure can be narrowed down to its
+		// statement; wrangler's local execute gives no such guarantee.
+		var exec func(ctx context.Context, sql string) error
+		opts := svc.ImportOptions{}
+		if t.local != nil {
+			lr := *t.local
+			exec = func(ctx context.Context, sql string) error { return wcfg.ExecLocalD1Batch(ctx, lr, sql) }
+			opts.BatchSize, opts.BatchBytes = 50, 60*1024
+		} else {
+			if d1Svc == nil {
+				return d1dumppopup.DoneMsg{Err: fmt.Errorf("D1 service not available")}
+			}
+			exec = func(ctx context.Context, sql string) error { return d1Svc.ExecBatch(ctx, t.id, sql) }
+			opts.Atomic = true
+		}
+		applied := 0
+		opts.Progress = func(done, total int) {
+			applied = done
+			progress(d1dumppopup.ProgressMsg{Text: fmt.Sprintf("Applied %d of %d statements", done, total), Done: done, Total: total})
+		}
+		progress(d1dumppopup.ProgressMsg{Text: fmt.Sprintf("Applying %d statements...", len(stmts)), Total: len(stmts)})
+
+		err = svc.ImportStatements(ctx, stmts, exec, opts)
+		var ie *svc.ImportError
+		switch {
+		case errors.As(err, &ie) && ie.EndLine > 0:
+			return d1dumppopup.DoneMsg{
+				Err:  fmt.Errorf("Import failed in the statements at lines %d–%d (%d applied before them): %v", ie.Line, ie.EndLine, applied, ie.Err),
+				Line: ie.Line,
+			}
+		case errors.As(err, &ie):
+			return d1dumppopup.DoneMsg{
+				Err:  fmt.Errorf("Import failed at line %d (%d statements applied before it): %v", ie.Line, applied, ie.Err),
+				Line: ie.Line,
+				SQL:  ie.SQL,
+			}
+		case err != nil:
+			return d1dumppopup.DoneMsg{Err: err}
+		}
+		return d1dumppopup.DoneMsg{Status: fmt.Sprintf("Imported %d statements from %s into %s", len(stmts), filepath.Base(path), t.name)}
+	}
+}
+
+func (m Model) snapshotD1Job() d1DumpJob {
+	t := m.d1DumpTarget
+	d1Svc := m.getD1Service()
+	root := m.settingsRoot()
+	settings, _ := wcfg.LoadRepoSettings(root)
+	return func(ctx context.Context, progress func(d1dumppopup.ProgressMsg)) d1dumppopup.DoneMsg {
+		if root == "" {
+			return d1dumppopup.DoneMsg{Err: fmt.Errorf("Open a project first — snapshots are written under its directory")}
+		}
+		path, err := snapshotD1(ctx, d1Svc, t, root, settings.Snapshots, func(s string) {
+			progress(d1dumppopup.ProgressMsg{Text: s})
+		})
+		if err != nil {
+			return d1dumppopup.DoneMsg{Err: err}
+		}
+		return d1dumppopup.DoneMsg{Status: fmt.Sprintf("Snapshot written to %s%s", path, fileSizeSuffix(path))}
+	}
+}
+
+// exportD1 writes a dump of a remote or local database to path. A remote
+// dump is downloaded next to path first, so a failed export leaves no
+// partial file behind.
+func exportD1(ctx context.Context, d1Svc *svc.D1Service, t d1DumpTarget, mode svc.D1DumpMode, path string, progress func(string)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if t.local != nil {
+		progress("Exporting with wrangler...")
+		return wcfg.ExportLocalD1(ctx, *t.local, mode.NoSchema(), mode.NoData(), path)
+	}
+	if d1Svc == nil {
+		return fmt.Errorf("D1 service not available")
+	}
+
+	progress("Starting export...")
+	tmp := path + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = d1Svc.Export(ctx, t.id, mode, f, progress)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// snapshotD1 writes a full dump of a database to the snapshot directory and
+// prunes its oldest snapshots.
+func snapshotD1(ctx context.Context, d1Svc *svc.D1Service, t d1DumpTarget, root string, s wcfg.SnapshotSettings, progress func(string)) (string, error) {
+	path := s.SnapshotPath(root, t.name, t.local != nil, time.Now())
+	if err := exportD1(ctx, d1Svc, t, svc.D1DumpFull, path, progress); err != nil {
+		return "", err
+	}
+	if err := s.PruneSnapshots(root, t.name, t.local != nil); err != nil {
+		return path, fmt.Errorf("snapshot written, but pruning old ones failed: %w", err)
+	}
+	return path, nil
+}
+
+// --- Scheduled snapshots ---
+
+// startD1SnapshotTicks starts the scheduled snapshot loop unless it's
+// running. The loop outlives setup, so finishing setup mid-session must not
+// start a second one.
+func (m *Model) startD1SnapshotTicks() tea.Cmd {
+	if m.d1SnapshotTicking {
+		return nil
+	}
+	m.d1SnapshotTicking = true
+	return d1SnapshotTick()
+}
+
+func d1SnapshotTick() tea.Cmd {
+	return tea.Tick(d1SnapshotCheckInterval, func(time.Time) tea.Msg { return d1SnapshotTickMsg{} })
+}
+
+// scheduledD1SnapshotsCmd snapshots the databases listed in [d1_snapshots]
+// whose newest snapshot is older than the interval, one at a time.
+func (m *Model) scheduledD1SnapshotsCmd() tea.Cmd {
+	root := m.settingsRoot()
+	if m.d1SnapshotBusy || root == "" {
+		return nil
+	}
+	settings, err := wcfg.LoadRepoSettings(root)
+	if err != nil {
+		return nil
+	}
+	s := settings.Snapshots
+	now := time.Now()
+	due := func(name string, local bool) bool {
+		if failed, ok := m.d1SnapshotFailed[snapshotKey(name, local)]; ok && now.Sub(failed) < d1SnapshotRetryDelay {
+			return false
+		}
+		return s.SnapshotDue(root, name, local, now)
+	}
+
+	var targets []d1DumpTarget
+	d1Svc := m.getD1Service()
+	if d1Svc != nil {
+		for _, name := range s.Databases {
+			if due(name, false) {
+				targets = append(targets, d1DumpTarget{name: name})
+			}
+		}
+	}
+	for _, name := range s.Local {
+		if !due(name, true) {
+			continue
+		}
+		if lr := m.findLocalD1(name); lr != nil {
+			targets = append(targets, d1DumpTarget{name: name, local: lr})
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	m.d1SnapshotBusy = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		var done d1SnapshotsDoneMsg
+		fail := func(t d1DumpTarget, err error) {
+			if done.failed == nil {
+				done.failed = make(map[string]error)
+			}
+			done.failed[snapshotKey(t.name, t.local != nil)] = err
+		}
+		for _, t := range targets {
+			if t.local == nil {
+				id, err := d1Svc.DatabaseID(t.name)
+				if err != nil {
+					fail(t, err)
+					continue
+				}
+				t.id = id
+			}
+			if _, err := snapshotD1(ctx, d1Svc, t, root, s, func(string) {}); err != nil {
+				fail(t, err)
+				continue
+			}
+			done.taken = append(done.taken, snapshotKey(t.name, t.local != nil))
+		}
+		return done
+	}
+}
+
+// findLocalD1 finds a local D1 database by database_name among the open
+// projects' wrangler configs.
+func (m Model) findLocalD1(name string) *wcfg.LocalResource {
+	var configs []*wcfg.WranglerConfig
+	for _, pc := range m.wrangler.ProjectConfigs() {
+		configs = append(configs, pc.Config)
+	}
+	if cfg := m.wrangler.Config(); cfg != nil {
+		configs = append(configs, cfg)
+	}
+	for _, cfg := range configs {
+		for _, lr := range wcfg.DiscoverLocalResources(cfg, "") {
+			if lr.ResourceType == "D1" && lr.BindingName == name {
+				return &lr
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotKey names a scheduled database in toasts and the retry map.
+func snapshotKey(name string, local bool) string {
+	if local {
+		return name + " (local)"
+	}
+	return name
+}
+
+// fileSizeSuffix returns " (1.2 MB)" for a written file, or "".
+func fileSizeSuffix(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	size := float64(info.Size())
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf(" (%.1f MB)", size/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf(" (%.1f KB)", size/(1<<10))
+	}
+	return fmt.Sprintf(" (%d B)", info.Size())
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	svc "github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/d1gridpopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// d1GridResultMsg carries a page or apply result to the table browser;
+// gen drops results for a closed popup.
+type d1GridResultMsg struct {
+	gen int
+	msg tea.Msg
+}
+
+// handleD1GridMsg handles the D1 table browser popup. Returns (model, cmd, handled).
+func (m *Model) handleD1GridMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case d1gridpopup.CloseMsg:
+		m.showD1GridPopup = false
+		m.d1GridGen++
+		return *m, nil, true
+
+	case d1gridpopup.LoadPageMsg:
+		t := m.d1GridTarget
+		return *m, m.d1GridCmd(func(d1Svc *svc.D1Service) tea.Msg {
+			result, err := runD1Statements(d1Svc, t, []svc.D1Statement{msg.Query})
+			if err != nil {
+				return d1gridpopup.PageMsg{Err: err}
+			}
+			var rows [][]interface{}
+			if len(result.Sets) > 0 {
+				rows = result.Sets[0].Rows
+			}
+			return d1gridpopup.PageMsg{Rows: rows}
+		}), true
+
+	case d1gridpopup.ApplyMsg:
+		t := m.d1GridTarget
+		return *m, m.d1GridCmd(func(d1Svc *svc.D1Service) tea.Msg {
+			result, err := runD1Statements(d1Svc, t, msg.Statements)
+			if err != nil {
+				return d1gridpopup.AppliedMsg{Err: err}
+			}
+			return d1gridpopup.AppliedMsg{Unmatched: svc.UnmatchedRowChanges(msg.Statements, result.Sets)}
+		}), true
+
+	case d1GridResultMsg:
+		if msg.gen != m.d1GridGen || !m.showD1GridPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.d1GridPopup, cmd = m.d1GridPopup.Update(msg.msg)
+		return *m, cmd, true
+	}
+	return *m, nil, false
+}
+
+// openD1GridPopup shows the table browser for the D1 database in the detail
+// view, listing the tables of its loaded schema.
+func (m *Model) openD1GridPopup() tea.Cmd {
+	var t d1DumpTarget
+	if lr := m.detail.ActiveLocalResource(); m.detail.IsLocalResource() && lr != nil {
+		local := *lr
+		t = d1DumpTarget{name: lr.BindingName, local: &local}
+	} else if rd := m.detail.ResourceDetail(); rd != nil {
+		t = d1DumpTarget{name: rd.Name, id: rd.ID}
+	} else {
+		return nil
+	}
+	m.d1GridGen++
+	m.d1GridTarget = t
+	m.d1GridPopup = d1gridpopup.New(t.name, m.detail.D1SchemaTables())
+	m.d1GridPopup.SetSize(m.width, m.height)
+	m.showD1GridPopup = true
+	return m.d1GridPopup.SpinnerInit()
+}
+
+// d1GridCmd runs fn in the background and tags its result with the popup's
+// generation.
+func (m Model) d1GridCmd(fn func(d1Svc *svc.D1Service) tea.Msg) tea.Cmd {
+	gen := m.d1GridGen
+	d1Svc := m.getD1Service()
+	return func() tea.Msg {
+		return d1GridResultMsg{gen: gen, msg: fn(d1Svc)}
+	}
+}
+
+// runD1Statements runs statements against a database as one transaction.
+// Remote statements go as a D1 batch with their parameters bound; wrangler
+// can't bind parameters, so local ones are inlined into a single command,
+// which it runs as one batch too.
+func runD1Statements(d1Svc *svc.D1Service, t d1DumpTarget, stmts []svc.D1Statement) (*svc.D1QueryResult, error) {
+	if t.local != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		sql := make([]string, len(stmts))
+		for i, st := range stmts {
+			sql[i] = st.Inline()
+		}
+		result, err := wcfg.ExecuteLocalD1Query(ctx, *t.local, strings.Join(sql, "\n"))
+		if err != nil {
+			return nil, err
+		}
+		return localD1QueryResult(result), nil
+	}
+	if d1Svc == nil {
+		return nil, fmt.Errorf("D1 service not available")
+	}
+	return d1Svc.ExecuteBatch(t.id, stmts)
+}
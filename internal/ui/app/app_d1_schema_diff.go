@@ -0,0 +1,302 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	svc "github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/schemadiffpopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// d1SchemaSide is one database of a schema comparison.
+type d1SchemaSide struct {
+	label         string
+	name          string              // database name, used to resolve a missing ID
+	id            string              // remote database UUID
+	local         *wcfg.LocalResource // set for a local dev session database
+	configPath    string              // wrangler config binding the database, if known
+	migrationsDir string              // the binding's migrations_dir
+}
+
+// d1SchemasLoadedMsg carries both schemas of a comparison.
+type d1SchemasLoadedMsg struct {
+	gen int
+	msg schemadiffpopup.SchemasMsg
+}
+
+// handleD1SchemaDiffMsg handles the D1 schema diff popup. Returns (model, cmd, handled).
+func (m *Model) handleD1SchemaDiffMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case schemadiffpopup.CloseMsg:
+		m.showSchemaDiffPopup = false
+		m.schemaDiffGen++
+		return *m, nil, true
+
+	case schemadiffpopup.CompareMsg:
+		if msg.Index >= len(m.schemaDiffSides) {
+			return *m, nil, true
+		}
+		m.schemaDiffGen++
+		m.schemaDiffOther = m.schemaDiffSides[msg.Index]
+		return *m, tea.Batch(m.schemaDiffPopup.SpinnerInit(), m.loadD1SchemasCmd(m.schemaDiffCurrent, m.schemaDiffOther)), true
+
+	case d1SchemasLoadedMsg:
+		if msg.gen != m.schemaDiffGen || !m.showSchemaDiffPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.schemaDiffPopup, cmd = m.schemaDiffPopup.Update(msg.msg)
+		return *m, cmd, true
+
+	case schemadiffpopup.CopyMsg:
+		status := schemadiffpopup.StatusMsg{Text: "Migration copied to clipboard"}
+		if err := clipboard.WriteAll(msg.SQL); err != nil {
+			status = schemadiffpopup.StatusMsg{Err: err}
+		}
+		m.schemaDiffPopup, _ = m.schemaDiffPopup.Update(status)
+		return *m, nil, true
+
+	case schemadiffpopup.WriteMsg:
+		side := m.schemaDiffOther
+		if msg.ForCurrent {
+			side = m.schemaDiffCurrent
+		}
+		status := schemadiffpopup.StatusMsg{}
+		if path, err := m.writeD1Migration(side, msg.SQL); err != nil {
+			status.Err = err
+		} else {
+			status.Text = fmt.Sprintf("Draft migration written to %s — review it, then apply with wrangler d1 migrations apply", path)
+		}
+		m.schemaDiffPopup, _ = m.schemaDiffPopup.Update(status)
+		return *m, nil, true
+	}
+	return *m, nil, false
+}
+
+// openSchemaDiffPopup shows the schema diff popup for the D1 database in the
+// detail view.
+func (m *Model) openSchemaDiffPopup() tea.Cmd {
+	var current d1SchemaSide
+	if lr := m.detail.ActiveLocalResource(); m.detail.IsLocalResource() && lr != nil {
+		local := *lr
+		current = d1SchemaSide{label: "local · " + lr.BindingName, name: lr.BindingName, local: &local, configPath: lr.ConfigPath}
+	} else if rd := m.detail.ResourceDetail(); rd != nil {
+		current = d1SchemaSide{label: rd.Name, name: rd.Name, id: rd.ID}
+	} else {
+		return nil
+	}
+
+	sides := m.d1SchemaCandidates(&current)
+	candidates := make([]schemadiffpopup.Candidate, len(sides))
+	for i, s := range sides {
+		candidates[i] = schemadiffpopup.Candidate{Label: s.label, Detail: d1SideDetail(s), Local: s.local != nil}
+	}
+
+	m.schemaDiffGen++
+	m.schemaDiffCurrent = current
+	m.schemaDiffSides = sides
+	m.schemaDiffPopup = schemadiffpopup.New(current.label, candidates)
+	m.schemaDiffPopup.SetSize(m.height)
+	m.showSchemaDiffPopup = true
+	return m.schemaDiffPopup.SpinnerInit()
+}
+
+// d1SchemaCandidates lists the databases current can be compared with: its
+// local dev copy, the databases bound under the same binding in the other
+// environments of each project, then every other remote D1 database. It
+// fills in where current is bound when a project binds it.
+func (m Model) d1SchemaCandidates(current *d1SchemaSide) []d1SchemaSide {
+	var sides []d1SchemaSide
+	seen := map[string]bool{d1SideKey(*current): true}
+	add := func(s d1SchemaSide) {
+		if key := d1SideKey(s); !seen[key] {
+			seen[key] = true
+			sides = append(sides, s)
+		}
+	}
+
+	// Local dev session databases, the same database first
+	var locals []wcfg.LocalResource
+	for _, lr := range m.detail.LocalResources() {
+		if lr.ResourceType == "D1" {
+			locals = append(locals, lr)
+		}
+	}
+	sort.SliceStable(locals, func(i, j int) bool {
+		return locals[i].BindingName == current.name && locals[j].BindingName != current.name
+	})
+	for _, lr := range locals {
+		local := lr
+		add(d1SchemaSide{
+			label:      "local · " + lr.BindingName,
+			name:       lr.BindingName,
+			local:      &local,
+			configPath: lr.ConfigPath,
+		})
+	}
+
+	// The same binding in other environments
+	for _, cfg := range m.allWranglerConfigs() {
+		envs := cfg.EnvNames()
+		sort.Strings(envs)
+		for _, env := range envs {
+			for _, b := range cfg.EnvBindings(env) {
+				if b.Type != "d1" || !d1BindingMatches(b, *current) {
+					continue
+				}
+				if current.configPath == "" {
+					current.configPath, current.migrationsDir = cfg.Path, b.MigrationsDir
+				}
+				for _, other := range envs {
+					for _, ob := range cfg.EnvBindings(other) {
+						if other == env || ob.Type != "d1" || ob.Name != b.Name {
+							continue
+						}
+						add(d1SchemaSide{
+							label:         fmt.Sprintf("env %s · %s", other, d1BindingDBName(ob)),
+							name:          d1BindingDBName(ob),
+							id:            ob.ResourceID,
+							configPath:    cfg.Path,
+							migrationsDir: ob.MigrationsDir,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	// Every other remote database
+	if d1Svc := m.getD1Service(); d1Svc != nil {
+		for _, r := range d1Svc.SearchItems() {
+			add(d1SchemaSide{label: r.Name, name: r.Name, id: r.ID})
+		}
+	}
+	return sides
+}
+
+// allWranglerConfigs returns the loaded config of each project.
+func (m Model) allWranglerConfigs() []*wcfg.WranglerConfig {
+	if !m.wrangler.IsMonorepo() {
+		if cfg := m.wrangler.Config(); cfg != nil {
+			return []*wcfg.WranglerConfig{cfg}
+		}
+		return nil
+	}
+	var cfgs []*wcfg.WranglerConfig
+	for _, p := range m.wrangler.ProjectConfigs() {
+		if p.Config != nil {
+			cfgs = append(cfgs, p.Config)
+		}
+	}
+	return cfgs
+}
+
+// d1BindingMatches reports whether a D1 binding points at side's database.
+func d1BindingMatches(b wcfg.Binding, side d1SchemaSide) bool {
+	if side.local != nil {
+		return side.local.ConfigPath != "" && b.DisplayName == side.local.BindingName
+	}
+	return b.ResourceID == side.id || (b.DisplayName != "" && b.DisplayName == side.name)
+}
+
+func d1BindingDBName(b wcfg.Binding) string {
+	if b.DisplayName != "" {
+		return b.DisplayName
+	}
+	return b.ResourceID
+}
+
+func d1SideKey(s d1SchemaSide) string {
+	if s.local != nil {
+		return "local:" + s.local.BindingName + ":" + s.local.ConfigPath
+	}
+	if s.id != "" {
+		return "remote:" + s.id
+	}
+	return "remote-name:" + s.name
+}
+
+func d1SideDetail(s d1SchemaSide) string {
+	switch {
+	case s.local != nil:
+		return "dev session in " + filepath.Base(s.local.ProjectDir)
+	case s.configPath != "":
+		return "bound in " + filepath.Base(filepath.Dir(s.configPath))
+	}
+	return "remote"
+}
+
+// loadD1SchemasCmd loads both schemas of a comparison.
+func (m Model) loadD1SchemasCmd(a, b d1SchemaSide) tea.Cmd {
+	gen := m.schemaDiffGen
+	d1Svc := m.getD1Service()
+	return func() tea.Msg {
+		result := schemadiffpopup.SchemasMsg{ALabel: a.label, BLabel: b.label}
+		var err error
+		if result.A, err = loadD1SideSchema(d1Svc, a); err == nil {
+			result.B, err = loadD1SideSchema(d1Svc, b)
+		}
+		result.Err = err
+		return d1SchemasLoadedMsg{gen: gen, msg: result}
+	}
+}
+
+func loadD1SideSchema(d1Svc *svc.D1Service, s d1SchemaSide) ([]svc.SchemaTable, error) {
+	if s.local != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		tables, err := wcfg.QueryLocalD1Schema(ctx, *s.local)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.label, err)
+		}
+		return localSchemaTables(tables), nil
+	}
+	if d1Svc == nil {
+		return nil, fmt.Errorf("D1 service not available")
+	}
+	id := s.id
+	if id == "" || id == s.name {
+		// Configs may name a database without its ID
+		var err error
+		if id, err = d1Svc.DatabaseID(s.name); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.label, err)
+		}
+	}
+	tables, err := d1Svc.QuerySchema(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.label, err)
+	}
+	return tables, nil
+}
+
+// writeD1Migration saves a draft migration in the migrations directory of
+// the database it changes, or of the project when the database isn't bound.
+func (m Model) writeD1Migration(side d1SchemaSide, sql string) (string, error) {
+	var dir string
+	switch {
+	case side.configPath != "":
+		dir = wcfg.MigrationsDir(side.configPath, side.migrationsDir)
+	case m.schemaDiffCurrent.configPath != "":
+		dir = wcfg.MigrationsDir(m.schemaDiffCurrent.configPath, m.schemaDiffCurrent.migrationsDir)
+	case m.settingsRoot() != "":
+		dir = filepath.Join(m.settingsRoot(), "migrations")
+	default:
+		return "", fmt.Errorf("open a project first — migrations are written to its migrations directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := wcfg.NextMigrationPath(dir, "sync_schema")
+	if err := os.WriteFile(path, []byte(sql), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/config"
+	"github.com/oarafat/orangeshell/internal/ui/timetravelpopup"
+)
+
+// timeTravelResultMsg carries an API result for the Time Travel popup that
+// was open when the call started.
+type timeTravelResultMsg struct {
+	gen int
+	msg tea.Msg
+}
+
+// handleD1TimeTravelMsg handles the D1 Time Travel popup. Returns (model, cmd, handled).
+func (m *Model) handleD1TimeTravelMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case timetravelpopup.CloseMsg:
+		m.showTimeTravelPopup = false
+		m.timeTravelGen++
+		return *m, nil, true
+
+	case timetravelpopup.RefreshMsg:
+		return *m, m.timeTravelBookmarkCmd(), true
+
+	case timetravelpopup.ResolveMsg:
+		return *m, m.timeTravelResolveCmd(msg.At), true
+
+	case timetravelpopup.RestoreMsg:
+		return *m, m.timeTravelRestoreCmd(msg), true
+
+	case timetravelpopup.CopyMsg:
+		_ = clipboard.WriteAll(msg.Text)
+		m.timeTravelPopup, _ = m.timeTravelPopup.Update(timetravelpopup.StatusMsg{Text: "Bookmark copied to clipboard"})
+		return *m, nil, true
+
+	case timeTravelResultMsg:
+		if msg.gen != m.timeTravelGen || !m.showTimeTravelPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.timeTravelPopup, cmd = m.timeTravelPopup.Update(msg.msg)
+		if restored, ok := msg.msg.(timetravelpopup.RestoredMsg); ok && restored.Result != nil {
+			info := m.timeTravelPopup.Info()
+			info.Restores = config.D1Restores(info.ID)
+			m.timeTravelPopup, _ = m.timeTravelPopup.Update(timetravelpopup.InfoMsg{Info: info})
+			// The schema and rows in the detail view are now stale
+			if m.detail.D1DatabaseID() == info.ID {
+				m.detail.SetD1SchemaLoading()
+				cmd = tea.Batch(cmd, m.loadD1Schema(info.ID), m.detail.SpinnerInit())
+			}
+		}
+		return *m, cmd, true
+	}
+	return *m, nil, false
+}
+
+// openTimeTravelPopup shows the Time Travel popup for the remote D1 database
+// in the detail view.
+func (m *Model) openTimeTravelPopup() tea.Cmd {
+	rd := m.detail.ResourceDetail()
+	if rd == nil || m.detail.IsLocalResource() {
+		return nil
+	}
+	m.timeTravelGen++
+	m.timeTravelPopup = timetravelpopup.New(timetravelpopup.Info{
+		Name:     rd.Name,
+		ID:       rd.ID,
+		Restores: config.D1Restores(rd.ID),
+	})
+	m.showTimeTravelPopup = true
+	return tea.Batch(m.timeTravelPopup.SpinnerInit(), m.timeTravelBookmarkCmd())
+}
+
+// timeTravelCmd runs fn against the popup's database and tags its result
+// with the current generation.
+func (m Model) timeTravelCmd(fn func(ctx context.Context, id string) tea.Msg) tea.Cmd {
+	gen := m.timeTravelGen
+	id := m.timeTravelPopup.Info().ID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		return timeTravelResultMsg{gen: gen, msg: fn(ctx, id)}
+	}
+}
+
+// timeTravelBookmarkCmd fetches the database's current bookmark.
+func (m Model) timeTravelBookmarkCmd() tea.Cmd {
+	d1Svc := m.getD1Service()
+	return m.timeTravelCmd(func(ctx context.Context, id string) tea.Msg {
+		if d1Svc == nil {
+			return timetravelpopup.BookmarkMsg{Err: fmt.Errorf("D1 service not available")}
+		}
+		bookmark, err := d1Svc.Bookmark(ctx, id, time.Time{})
+		return timetravelpopup.BookmarkMsg{Bookmark: bookmark, Err: err}
+	})
+}
+
+// timeTravelResolveCmd fetches the bookmark at a point in time.
+func (m Model) timeTravelResolveCmd(at time.Time) tea.Cmd {
+	d1Svc := m.getD1Service()
+	return m.timeTravelCmd(func(ctx context.Context, id string) tea.Msg {
+		if d1Svc == nil {
+			return timetravelpopup.ResolvedMsg{Err: fmt.Errorf("D1 service not available")}
+		}
+		bookmark, err := d1Svc.Bookmark(ctx, id, at)
+		return timetravelpopup.ResolvedMsg{At: at, Bookmark: bookmark, Err: err}
+	})
+}
+
+// timeTravelRestoreCmd restores the database and records the bookmark it
+// had before, so the restore can be undone.
+func (m Model) timeTravelRestoreCmd(r timetravelpopup.RestoreMsg) tea.Cmd {
+	d1Svc := m.getD1Service()
+	name := m.timeTravelPopup.Info().Name
+	return m.timeTravelCmd(func(ctx context.Context, id string) tea.Msg {
+		if d1Svc == nil {
+			return timetravelpopup.RestoredMsg{Err: fmt.Errorf("D1 service not available")}
+		}
+		bookmark := r.Bookmark
+		if bookmark == "" {
+			var err error
+			if bookmark, err = d1Svc.Bookmark(ctx, id, r.At); err != nil {
+				return timetravelpopup.RestoredMsg{Err: err}
+			}
+		}
+		result, err := d1Svc.Restore(ctx, id, bookmark)
+		if err != nil {
+			return timetravelpopup.RestoredMsg{Err: err}
+		}
+		logErr := config.RecordD1Restore(config.D1Restore{
+			Time:             time.Now(),
+			DatabaseID:       id,
+			DatabaseName:     name,
+			Target:           r.Target,
+			Bookmark:         result.Bookmark,
+			PreviousBookmark: result.PreviousBookmark,
+		})
+		return timetravelpopup.RestoredMsg{Result: result, LogErr: logErr}
+	})
+}
@@ -17,7 +17,7 @@ import (
 // and spawns parallel deploy commands for all matching projects.
 func (m *Model) startDeployAll(envName string) tea.Cmd {
 	var items []deployallpopup.DeployItem
-	for _, pc := range m.wrangler.ProjectConfigs() {
+	for _, pc := range m.wrangler.ScopedProjectConfigs() {
 		if pc.Config == nil {
 			continue
 		}
@@ -15,14 +15,15 @@ import (
 // If 1+ found: sends ProjectsDiscoveredMsg for project list view
 func (m Model) discoverProjectsCmd() tea.Cmd {
 	return func() tea.Msg {
-		projects := wcfg.DiscoverProjects(".")
+		projects, settingsErr := wcfg.Discover(".")
 		cwd, _ := filepath.Abs(".")
 		rootName := filepath.Base(cwd)
 
 		if len(projects) == 0 {
 			return uiwrangler.ConfigLoadedMsg{Config: nil, Path: "", Err: nil}
 		}
-		return uiwrangler.ProjectsDiscoveredMsg{Projects: projects, RootName: rootName, RootDir: cwd}
+		settings, _ := wcfg.LoadRepoSettings(cwd)
+		return uiwrangler.ProjectsDiscoveredMsg{Projects: projects, RootName: rootName, RootDir: cwd, SettingsErr: settingsErr, Profile: settings.Profile}
 	}
 }
 
@@ -36,12 +37,13 @@ func (m Model) discoverProjectsFromDir(dir string) tea.Cmd {
 		}
 		rootName := filepath.Base(absDir)
 
-		projects := wcfg.DiscoverProjects(absDir)
+		projects, settingsErr := wcfg.Discover(absDir)
 
 		if len(projects) == 0 {
 			return uiwrangler.ConfigLoadedMsg{Config: nil, Path: "", Err: nil}
 		}
-		return uiwrangler.ProjectsDiscoveredMsg{Projects: projects, RootName: rootName, RootDir: absDir}
+		settings, _ := wcfg.LoadRepoSettings(absDir)
+		return uiwrangler.ProjectsDiscoveredMsg{Projects: projects, RootName: rootName, RootDir: absDir, SettingsErr: settingsErr, Profile: settings.Profile}
 	}
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/oarafat/orangeshell/internal/ui/deletepopup"
 	"github.com/oarafat/orangeshell/internal/ui/detail"
 	"github.com/oarafat/orangeshell/internal/ui/tabbar"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
 )
 
 // accessIndexBuiltMsg is sent when the background Access index build completes.
@@ -238,6 +239,7 @@ func (m *Model) handleDetailMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 				// Local D1: init console and load schema from local emulator
 				if !m.detail.D1Active() || m.detail.D1DatabaseID() != msg.ResourceID {
 					inputCmd := m.detail.InitD1Console(msg.ResourceID)
+					m.detail.SetD1SavedQueries(m.d1SavedQueries(m.detail.D1DatabaseName()))
 					lr := *msg.LocalResource
 					schemaCmd := m.loadLocalD1Schema(lr, msg.ResourceID)
 					return *m, tea.Batch(inputCmd, schemaCmd, m.detail.SpinnerInit()), true
@@ -246,6 +248,7 @@ func (m *Model) handleDetailMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 				// Remote D1: init console with schema fetch
 				if !m.detail.D1Active() || m.detail.D1DatabaseID() != msg.ResourceID {
 					inputCmd := m.detail.InitD1Console(msg.ResourceID)
+					m.detail.SetD1SavedQueries(m.d1SavedQueries(m.detail.D1DatabaseName()))
 					cmds := []tea.Cmd{inputCmd}
 					if m.detail.IsLoading() {
 						cmds = append(cmds, m.loadD1Schema(msg.ResourceID), m.detail.SpinnerInit())
@@ -317,6 +320,37 @@ func (m *Model) handleDetailMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		m.detail.SetD1Schema(msg.Tables, msg.Err)
 		return *m, nil, true
 
+	case detail.D1SaveQueryMsg:
+		root := m.settingsRoot()
+		if root == "" {
+			m.detail.D1Notice("", fmt.Errorf("open a project first — saved queries are stored in its .orangeshell.toml"))
+			return *m, nil, true
+		}
+		var err error
+		if msg.Delete {
+			err = wcfg.DeleteD1Query(root, msg.Database, msg.Name)
+		} else {
+			err = wcfg.SaveD1Query(root, msg.Database, msg.Name, msg.SQL)
+		}
+		if err != nil {
+			m.detail.D1Notice("", err)
+			return *m, nil, true
+		}
+		m.detail.SetD1SavedQueries(m.d1SavedQueries(msg.Database))
+		if msg.Delete {
+			m.detail.D1Notice(fmt.Sprintf("Forgot %s", msg.Name), nil)
+		} else {
+			m.detail.D1Notice(fmt.Sprintf("Saved %s — .run %s", msg.Name, msg.Name), nil)
+		}
+		return *m, nil, true
+
+	case detail.D1ExportMsg:
+		return *m, m.exportD1Result(msg), true
+
+	case detail.D1ExportDoneMsg:
+		m.detail.D1Notice(fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Path), msg.Err)
+		return *m, nil, true
+
 	// --- Queue Message Inspector messages ---
 
 	case detail.QueuePullMsg:
@@ -451,9 +485,10 @@ func (m *Model) handleDetailMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		m.monitoring.AppendTailLines(msg.Lines)
 		if m.monitoring.ScriptName() != "" {
 			m.logExporter.WriteLines(m.monitoring.ScriptName(), msg.Lines)
+			m.captureRequestLogs(m.monitoring.ScriptName(), msg.Lines)
 		}
 		// Continue polling for more lines
-		return *m, m.waitForTailLines(), true
+		return *m, tea.Batch(m.waitForTailLines(), m.observeTailAlerts(m.monitoring.ScriptName(), msg.Lines)), true
 
 	case detail.TailErrorMsg:
 		m.monitoring.SetTailError(msg.Err)
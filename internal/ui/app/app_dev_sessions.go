@@ -0,0 +1,279 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/ui/devsessionspopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// devHealthInterval is how often running dev servers are probed.
+const devHealthInterval = 15 * time.Second
+
+// devShutdownTimeout bounds how long Shutdown waits for dev servers to exit.
+const devShutdownTimeout = 5 * time.Second
+
+// devHealthTickMsg triggers a round of dev server health probes.
+type devHealthTickMsg struct{}
+
+// devHealthMsg carries the result of probing one dev server. runner drops
+// results for a server that has since been restarted or stopped.
+type devHealthMsg struct {
+	key    string
+	runner *wcfg.Runner
+	health string
+	detail string
+}
+
+// handleDevSessionsMsg handles the dev sessions popup and health probes.
+// Returns (model, cmd, handled).
+func (m *Model) handleDevSessionsMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case devsessionspopup.CloseMsg:
+		m.showDevSessionsPopup = false
+		return *m, nil, true
+
+	case devsessionspopup.StartMsg:
+		action := "dev"
+		if msg.Remote {
+			action = "dev --remote"
+		}
+		var cmds []tea.Cmd
+		for _, t := range msg.Targets {
+			cmds = append(cmds, m.startDevServer(action, t.Project, t.Env, t.ConfigPath, t.ScriptName))
+		}
+		return *m, tea.Batch(cmds...), true
+
+	case devsessionspopup.RestartMsg:
+		return *m, m.restartDevServer(msg.Key), true
+
+	case devsessionspopup.StopMsg:
+		m.cleanupDevSessionByKey(msg.Key)
+		return *m, nil, true
+
+	case devsessionspopup.StopAllMsg:
+		m.cleanupAllDevSessions()
+		m.syncDevBadges()
+		return *m, nil, true
+
+	case devHealthTickMsg:
+		if len(m.devRunners) == 0 {
+			m.devHealthTicking = false
+			return *m, nil, true
+		}
+		cmds := []tea.Cmd{devHealthTick()}
+		for key, dr := range m.devRunners {
+			if dr.runner == nil || dr.status != "running" || dr.port == "" {
+				continue
+			}
+			cmds = append(cmds, probeDevHealthCmd(key, dr.runner, dr.port))
+		}
+		return *m, tea.Batch(cmds...), true
+
+	case devHealthMsg:
+		dr, ok := m.devRunners[msg.key]
+		if !ok || dr.runner != msg.runner {
+			return *m, nil, true
+		}
+		changed := dr.health != msg.health
+		dr.health = msg.health
+		dr.healthDetail = msg.detail
+		if changed {
+			m.syncDevBadges()
+		} else {
+			m.syncDevSessionsPopup()
+		}
+		return *m, nil, true
+	}
+	return *m, nil, false
+}
+
+// openDevSessionsPopup shows the dev sessions manager for the scoped
+// monorepo projects.
+func (m *Model) openDevSessionsPopup() {
+	var projects []devsessionspopup.Project
+	names := m.wrangler.ScopedProjectNames()
+	for i, pc := range m.wrangler.ScopedProjectConfigs() {
+		if pc.Config == nil || i >= len(names) {
+			continue
+		}
+		p := devsessionspopup.Project{Name: names[i], ConfigPath: pc.ConfigPath}
+		for _, env := range pc.Config.EnvNames() {
+			script := pc.Config.ResolvedEnvName(env)
+			if script == "" {
+				continue
+			}
+			p.Envs = append(p.Envs, env)
+			p.Scripts = append(p.Scripts, script)
+		}
+		if len(p.Envs) > 0 {
+			projects = append(projects, p)
+		}
+	}
+	m.devSessionsPopup = devsessionspopup.New(m.devSessionsList(), projects)
+	m.showDevSessionsPopup = true
+}
+
+// devSessionsList returns the dev sessions for the popup, in start order.
+func (m Model) devSessionsList() []devsessionspopup.Session {
+	var sessions []devsessionspopup.Session
+	for _, ds := range m.devSessions {
+		dr, ok := m.devRunners[ds.RunnerKey]
+		if !ok {
+			continue
+		}
+		port := dr.wantPort
+		if p, err := strconv.Atoi(dr.port); err == nil {
+			port = p
+		}
+		sessions = append(sessions, devsessionspopup.Session{
+			Key:           ds.RunnerKey,
+			Project:       ds.ProjectName,
+			Env:           ds.EnvName,
+			Kind:          dr.devKind,
+			Port:          port,
+			InspectorPort: dr.inspectorPort,
+			Status:        dr.status,
+			Health:        dr.health,
+			HealthDetail:  dr.healthDetail,
+			Err:           dr.errMsg,
+			Started:       dr.startedAt,
+			Restarts:      dr.restarts,
+			LogPath:       dr.logPath,
+		})
+	}
+	return sessions
+}
+
+// syncDevSessionsPopup refreshes the open dev sessions popup.
+func (m *Model) syncDevSessionsPopup() {
+	if m.showDevSessionsPopup {
+		m.devSessionsPopup.SetSessions(m.devSessionsList())
+	}
+}
+
+// allocDevPorts picks an HTTP and an inspector port for a new dev server
+// that are free now and not held by another session, which may still be
+// starting and not yet listening. Returns zeros (wrangler's defaults) if
+// none is found.
+func (m Model) allocDevPorts() (port, inspectorPort int) {
+	taken := make(map[int]bool)
+	for _, dr := range m.devRunners {
+		taken[dr.wantPort] = true
+		taken[dr.inspectorPort] = true
+	}
+	port, err := wcfg.FreePort(wcfg.DefaultDevPort, taken)
+	if err != nil {
+		return 0, 0
+	}
+	taken[port] = true
+	inspectorPort, err = wcfg.FreePort(wcfg.DefaultInspectorPort, taken)
+	if err != nil {
+		return 0, 0
+	}
+	return port, inspectorPort
+}
+
+// restartDevServer relaunches a dev server on the same ports. A running
+// server is stopped first and relaunched by handleDevDone once its process
+// tree has exited; a failed one is relaunched right away.
+func (m *Model) restartDevServer(key string) tea.Cmd {
+	dr, ok := m.devRunners[key]
+	if !ok || dr.restarting {
+		return nil
+	}
+	if dr.runner == nil {
+		dr.restarts++
+		return m.launchDevRunner(dr)
+	}
+	dr.restarting = true
+	dr.status = "starting"
+	dr.health = ""
+	dr.runner.Stop()
+	m.syncDevBadges()
+	return nil
+}
+
+// restartDevServersFor restarts the dev servers running from configPath,
+// e.g. after the config changed on disk.
+func (m *Model) restartDevServersFor(configPath string) tea.Cmd {
+	var keys []string
+	for key, dr := range m.devRunners {
+		if filepath.Clean(dr.configPath) == filepath.Clean(configPath) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var cmds []tea.Cmd
+	for _, key := range keys {
+		cmds = append(cmds, m.restartDevServer(key))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startDevHealthTicks starts the health probe loop unless it's running.
+func (m *Model) startDevHealthTicks() tea.Cmd {
+	if m.devHealthTicking {
+		return nil
+	}
+	m.devHealthTicking = true
+	return devHealthTick()
+}
+
+func devHealthTick() tea.Cmd {
+	return tea.Tick(devHealthInterval, func(time.Time) tea.Msg { return devHealthTickMsg{} })
+}
+
+// probeDevHealthCmd checks that a dev server accepts connections on its port.
+// It doesn't send a request: that would run the worker and put a request
+// line in the dev tail, where it feeds tail alert rules and the request
+// composer's log capture.
+func probeDevHealthCmd(key string, runner *wcfg.Runner, port string) tea.Cmd {
+	return func() tea.Msg {
+		msg := devHealthMsg{key: key, runner: runner}
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", port), 2*time.Second)
+		if err != nil {
+			msg.health, msg.detail = "down", "not listening"
+			return msg
+		}
+		conn.Close()
+		msg.health = "up"
+		msg.detail = fmt.Sprintf("listening · %dms", time.Since(start).Milliseconds())
+		return msg
+	}
+}
+
+// Shutdown stops every dev server and wrangler command, and waits a bounded
+// time for their process trees to exit. Call it on the final model once the
+// program has quit, so no wrangler or workerd processes outlive orangeshell.
+func (m Model) Shutdown() {
+	var runners []*wcfg.Runner
+	for _, dr := range m.devRunners {
+		if dr.runner != nil {
+			runners = append(runners, dr.runner)
+		}
+		if dr.logFile != nil {
+			dr.logFile.Close()
+		}
+	}
+	for _, cr := range m.cmdRunners {
+		if cr.runner != nil {
+			runners = append(runners, cr.runner)
+		}
+	}
+	for _, r := range runners {
+		r.Stop()
+	}
+	deadline := time.Now().Add(devShutdownTimeout)
+	for _, r := range runners {
+		r.Wait(time.Until(deadline))
+	}
+}
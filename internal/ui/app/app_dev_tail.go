@@ -32,6 +32,22 @@ type devRunner struct {
 	errMsg  string // short error message on failure
 	logPath string // path to log file (~/.orangeshell/logs/)
 	logFile *os.File
+
+	// What was started, so the server can be relaunched as it was
+	action      string // "dev" or "dev --remote"
+	projectName string
+	envName     string
+	configPath  string
+	scriptName  string
+
+	wantPort      int // --port allocated at start, kept across restarts (0 = wrangler's default)
+	inspectorPort int // --inspector-port, likewise
+	startedAt     time.Time
+	restarts      int
+	restarting    bool // stopped to be relaunched once it exits
+
+	health       string // "", "up", "error", "down" — last HTTP probe
+	healthDetail string // e.g. "HTTP 200 · 12ms"
 }
 
 // cmdRunner tracks a short-lived wrangler command process (deploy, delete, etc.)
@@ -268,6 +284,7 @@ func (m *Model) syncDevBadges() {
 		Port   string
 		Status string // "starting", "running", "failed"
 		ErrMsg string // error text for failed state
+		Health string // last HTTP probe of a running server
 	}
 	// Map: projectName -> envName -> badgeInfo
 	badges := make(map[string]map[string]badgeInfo)
@@ -278,18 +295,21 @@ func (m *Model) syncDevBadges() {
 		status := "running"
 		port := ds.Port
 		errMsg := ""
+		health := ""
 		if dr, ok := m.devRunners[ds.RunnerKey]; ok {
 			status = dr.status
 			if dr.port != "" {
 				port = dr.port
 			}
 			errMsg = dr.errMsg
+			health = dr.health
 		}
 		badges[ds.ProjectName][ds.EnvName] = badgeInfo{
 			Kind:   ds.DevKind,
 			Port:   port,
 			Status: status,
 			ErrMsg: errMsg,
+			Health: health,
 		}
 	}
 
@@ -305,11 +325,13 @@ func (m *Model) syncDevBadges() {
 			eb.DevKind = info.Kind
 			eb.DevPort = info.Port
 			eb.DevError = info.ErrMsg
+			eb.DevHealth = info.Health
 		} else {
 			eb.DevStatus = ""
 			eb.DevKind = ""
 			eb.DevPort = ""
 			eb.DevError = ""
+			eb.DevHealth = ""
 		}
 	}
 
@@ -323,8 +345,10 @@ func (m *Model) syncDevBadges() {
 			Kind:   info.Kind,
 			Port:   info.Port,
 			Status: info.Status,
+			Health: info.Health,
 		}
 	})
+	m.syncDevSessionsPopup()
 }
 
 // syncLocalResources discovers all local D1/KV resources from active dev sessions
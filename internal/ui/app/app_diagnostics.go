@@ -0,0 +1,155 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/ui/diagpopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// pendingDeploy is a deploy held back by the diagnostics gate until the user
+// cancels or chooses to deploy anyway.
+type pendingDeploy struct {
+	all         bool // Deploy All for envName
+	projectName string
+	configPath  string
+	envName     string
+}
+
+// lintedServices are the dashboard services whose cached resource lists are
+// used to check that bound resources exist.
+var lintedServices = []string{"Workers", "KV", "R2", "D1", "Queues", "Vectorize", "Hyperdrive"}
+
+// handleDiagnosticsMsg handles the diagnostics popup.
+// Returns (model, cmd, handled).
+func (m *Model) handleDiagnosticsMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg.(type) {
+	case diagpopup.CloseMsg:
+		m.showDiagPopup = false
+		m.diagPending = nil
+		return *m, nil, true
+
+	case diagpopup.ProceedMsg:
+		m.showDiagPopup = false
+		p := m.diagPending
+		m.diagPending = nil
+		if p == nil {
+			return *m, nil, true
+		}
+		if p.all {
+			return *m, m.startDeployAll(p.envName), true
+		}
+		return *m, m.startWranglerCmdWithArgs("deploy", p.projectName, p.envName, p.configPath, nil), true
+	}
+	return *m, nil, false
+}
+
+// accountResources collects the cached resource lists of the active account.
+// Services that haven't been loaded yet are left out, so their bindings
+// aren't reported as missing.
+func (m Model) accountResources() wcfg.AccountResources {
+	res := make(wcfg.AccountResources)
+	for _, name := range lintedServices {
+		entry := m.registry.GetCache(name)
+		if entry == nil {
+			continue
+		}
+		known := make(map[string]bool, len(entry.Resources)*2)
+		for _, r := range entry.Resources {
+			known[r.ID] = true
+			known[r.Name] = true
+		}
+		res[name] = known
+	}
+	return res
+}
+
+// lintConfig runs the config linter against the active account's caches.
+func (m Model) lintConfig(cfg *wcfg.WranglerConfig) []wcfg.Diagnostic {
+	return wcfg.Lint(cfg, wcfg.LintOptions{Now: time.Now(), Account: m.accountResources()})
+}
+
+// openDiagnosticsPopup lints the focused project, or every project when the
+// monorepo list is showing.
+func (m *Model) openDiagnosticsPopup() {
+	var reports []diagpopup.Report
+	title := "Config Diagnostics"
+	if m.wrangler.IsOnProjectList() {
+		for _, pc := range m.wrangler.ProjectConfigs() {
+			if pc.Config == nil {
+				continue
+			}
+			reports = append(reports, diagpopup.Report{
+				Project:     pc.Config.Name,
+				ConfigPath:  pc.ConfigPath,
+				Diagnostics: m.lintConfig(pc.Config),
+			})
+		}
+	} else if cfg := m.wrangler.Config(); cfg != nil {
+		title = fmt.Sprintf("Config Diagnostics — %s", cfg.Name)
+		reports = append(reports, diagpopup.Report{
+			Project:     cfg.Name,
+			ConfigPath:  m.wrangler.ConfigPath(),
+			Diagnostics: m.lintConfig(cfg),
+		})
+	}
+	m.diagPopup = diagpopup.New(title, reports)
+	m.showDiagPopup = true
+}
+
+// gateDeploy lints the focused config before deploying envName. Errors hold
+// the deploy behind the diagnostics popup; warnings only raise a toast.
+func (m *Model) gateDeploy(envName string) tea.Cmd {
+	projectName := m.wrangler.FocusedProjectName()
+	configPath := m.wrangler.ConfigPath()
+	cfg := m.wrangler.Config()
+	if cfg == nil {
+		return m.startWranglerCmd("deploy", envName)
+	}
+
+	diags := wcfg.DiagnosticsFor(m.lintConfig(cfg), envName)
+	if wcfg.HasErrors(diags) {
+		m.diagPending = &pendingDeploy{projectName: projectName, configPath: configPath, envName: envName}
+		m.diagPopup = diagpopup.NewGate(fmt.Sprintf("Deploy %s blocked", cfg.ResolvedEnvName(envName)), []diagpopup.Report{
+			{Project: cfg.Name, ConfigPath: configPath, Diagnostics: diags},
+		})
+		m.showDiagPopup = true
+		return nil
+	}
+
+	cmd := m.startWranglerCmdWithArgs("deploy", projectName, envName, configPath, nil)
+	if len(diags) > 0 {
+		m.setToast(fmt.Sprintf("⚠ Deploying with %d config warnings (ctrl+p › Config Diagnostics)", len(diags)))
+		return tea.Batch(cmd, toastTick())
+	}
+	return cmd
+}
+
+// gateDeployAll lints every project that will be deployed for envName and
+// holds Deploy All behind the diagnostics popup if any has errors.
+func (m *Model) gateDeployAll(envName string) tea.Cmd {
+	var reports []diagpopup.Report
+	blocked := false
+	for _, pc := range m.wrangler.ScopedProjectConfigs() {
+		if pc.Config == nil || !pc.Config.HasEnv(envName) {
+			continue
+		}
+		diags := wcfg.DiagnosticsFor(m.lintConfig(pc.Config), envName)
+		if wcfg.HasErrors(diags) {
+			blocked = true
+		}
+		if len(diags) > 0 {
+			reports = append(reports, diagpopup.Report{Project: pc.Config.Name, ConfigPath: pc.ConfigPath, Diagnostics: diags})
+		}
+	}
+	if !blocked {
+		return m.startDeployAll(envName)
+	}
+	m.diagPending = &pendingDeploy{all: true, envName: envName}
+	m.diagPopup = diagpopup.NewGate(fmt.Sprintf("Deploy All (%s) blocked", envName), reports)
+	m.showDiagPopup = true
+	return nil
+}
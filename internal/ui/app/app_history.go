@@ -0,0 +1,86 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/ui/historypopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// historyStepDoneMsg reports the result of an undo or redo.
+type historyStepDoneMsg struct {
+	configPath string
+	redo       bool
+	entry      wcfg.JournalEntry
+	err        error
+}
+
+// handleHistoryMsg handles the edit history popup and undo/redo.
+// Returns (model, cmd, handled).
+func (m *Model) handleHistoryMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case historypopup.CloseMsg:
+		m.showHistoryPopup = false
+		return *m, nil, true
+
+	case historypopup.UndoMsg:
+		return *m, m.historyStepCmd(msg.ConfigPath, false), true
+
+	case historypopup.RedoMsg:
+		return *m, m.historyStepCmd(msg.ConfigPath, true), true
+
+	case historyStepDoneMsg:
+		verb := "undo"
+		if msg.redo {
+			verb = "redo"
+		}
+		if msg.err != nil {
+			if errors.Is(msg.err, wcfg.ErrConfigChanged) {
+				m.historyPopup.SetError(fmt.Sprintf("Cannot %s: the config was edited outside orangeshell since", verb))
+			} else {
+				m.historyPopup.SetError(fmt.Sprintf("Cannot %s: %v", verb, msg.err))
+			}
+			return *m, nil, true
+		}
+
+		toast := "Undone: " + msg.entry.Op
+		if msg.redo {
+			toast = "Redone: " + msg.entry.Op
+		}
+		m.reloadWranglerConfig(msg.configPath, toast)
+		if m.configView.ConfigPath() == msg.configPath {
+			m.configView.ReloadConfig()
+		}
+		m.historyPopup.SetHistory(m.journal.History(msg.configPath))
+		return *m, toastTick(), true
+	}
+	return *m, nil, false
+}
+
+// openHistoryPopup shows the edit history of the active project's config.
+func (m *Model) openHistoryPopup() {
+	configPath, _ := m.resolveActiveProjectConfig()
+	if configPath == "" || m.journal == nil {
+		return
+	}
+	m.historyPopup = historypopup.New(m.journal.History(configPath))
+	m.showHistoryPopup = true
+}
+
+// historyStepCmd undoes or redoes the last edit of a config in the background.
+func (m Model) historyStepCmd(configPath string, redo bool) tea.Cmd {
+	journal := m.journal
+	return func() tea.Msg {
+		var entry wcfg.JournalEntry
+		var err error
+		if redo {
+			entry, err = journal.Redo(configPath)
+		} else {
+			entry, err = journal.Undo(configPath)
+		}
+		return historyStepDoneMsg{configPath: configPath, redo: redo, entry: entry, err: err}
+	}
+}
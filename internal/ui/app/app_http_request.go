@@ -0,0 +1,259 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	svc "github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/requestpopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// requestLogWindow is how long after its response a dev server's output is
+// still attributed to a request. Deployed Workers' tail events name their
+// request, so they're matched for requestTailWindow instead.
+const (
+	requestLogWindow  = time.Second
+	requestTailWindow = time.Minute
+)
+
+// requestResultMsg carries a response to the request composer; gen drops
+// results for a closed popup.
+type requestResultMsg struct {
+	gen int
+	msg tea.Msg
+}
+
+// requestCapture correlates tail lines with the composer's last request.
+type requestCapture struct {
+	script string // tail key: "dev:<name>" or the worker name
+	dev    bool
+	method string
+	url    string
+	sent   time.Time
+	done   time.Time // when the response arrived; zero while in flight
+}
+
+// handleRequestMsg handles the HTTP request composer. Returns (model, cmd, handled).
+func (m *Model) handleRequestMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case requestpopup.CloseMsg:
+		m.showRequestPopup = false
+		m.requestGen++
+		m.requestCapture = nil
+		return *m, nil, true
+
+	case requestpopup.SendMsg:
+		m.requestCapture = &requestCapture{
+			script: msg.Target.Script,
+			dev:    msg.Target.Dev,
+			method: msg.Request.Method,
+			url:    msg.Request.URL,
+			sent:   time.Now(),
+		}
+		gen := m.requestGen
+		req := msg.Request
+		return *m, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			resp, err := svc.SendHTTPRequest(ctx, req)
+			return requestResultMsg{gen: gen, msg: requestpopup.ResponseMsg{Resp: resp, Err: err}}
+		}, true
+
+	case requestpopup.SaveMsg, requestpopup.DeleteMsg:
+		root := m.settingsRoot()
+		if root == "" {
+			m.requestPopup, _ = m.requestPopup.Update(requestpopup.SavedMsg{
+				Err: fmt.Errorf("open a project first — saved requests are stored in its .orangeshell.toml"),
+			})
+			return *m, nil, true
+		}
+		var err error
+		var notice string
+		if save, ok := msg.(requestpopup.SaveMsg); ok {
+			var redacted []string
+			redacted, err = wcfg.SaveHTTPRequest(root, m.requestProject, save.Name, save.Request)
+			notice = "Saved " + save.Name
+			if len(redacted) > 0 {
+				notice += " without the values of " + strings.Join(redacted, ", ")
+			}
+		} else {
+			name := msg.(requestpopup.DeleteMsg).Name
+			err = wcfg.DeleteHTTPRequest(root, m.requestProject, name)
+			notice = "Deleted " + name
+		}
+		m.requestPopup, _ = m.requestPopup.Update(requestpopup.SavedMsg{
+			Requests: m.savedRequests(m.requestProject),
+			Notice:   notice,
+			Err:      err,
+		})
+		return *m, nil, true
+
+	case requestpopup.TailMsg:
+		if m.client == nil {
+			return *m, nil, true
+		}
+		if m.tailSession != nil {
+			m.stopTail()
+		}
+		m.tailSource = "monitoring"
+		m.monitoring.StartSingleTail(msg.Script)
+		m.requestPopup.SetTailing(msg.Script)
+		return *m, m.startTailCmd(m.registry.ActiveAccountID(), msg.Script), true
+
+	case requestResultMsg:
+		if msg.gen != m.requestGen || !m.showRequestPopup {
+			return *m, nil, true
+		}
+		if m.requestCapture != nil {
+			m.requestCapture.done = time.Now()
+		}
+		var cmd tea.Cmd
+		m.requestPopup, cmd = m.requestPopup.Update(msg.msg)
+		return *m, cmd, true
+	}
+	return *m, nil, false
+}
+
+// openRequestPopup shows the request composer for the focused project, with
+// its dev servers and deployed environments as targets.
+func (m *Model) openRequestPopup() tea.Cmd {
+	project := m.wrangler.FocusedProjectName()
+	m.requestGen++
+	m.requestProject = project
+	m.requestCapture = nil
+	m.requestPopup = requestpopup.New(project, m.requestTargets(project), m.savedRequests(project))
+	m.requestPopup.SetSize(m.width, m.height)
+	m.showRequestPopup = true
+	return m.requestPopup.SpinnerInit()
+}
+
+// requestTargets lists where the composer can send requests: the project's
+// running dev servers, then each environment's workers.dev URL and routes.
+// The focused environment comes first in each group.
+func (m Model) requestTargets(project string) []requestpopup.Target {
+	focusedEnv := m.wrangler.FocusedEnvName()
+	var targets []requestpopup.Target
+	for _, ds := range m.devSessions {
+		dr, ok := m.devRunners[ds.RunnerKey]
+		if ds.ProjectName != project || !ok || dr.port == "" {
+			continue
+		}
+		t := requestpopup.Target{
+			Label:   "dev · " + ds.EnvName,
+			BaseURL: "http://localhost:" + dr.port,
+			Script:  ds.ScriptName,
+			Dev:     true,
+		}
+		if ds.EnvName == focusedEnv {
+			targets = append([]requestpopup.Target{t}, targets...)
+		} else {
+			targets = append(targets, t)
+		}
+	}
+
+	cfg := m.wrangler.Config()
+	if cfg == nil {
+		return targets
+	}
+	caches := m.registry.GetAllDeploymentCaches()
+	var deployed []requestpopup.Target
+	for _, env := range cfg.EnvNames() {
+		script := cfg.ResolvedEnvName(env)
+		if script == "" {
+			continue
+		}
+		tailing := m.isTailing(script)
+		var envTargets []requestpopup.Target
+		if entry, ok := caches[script]; ok && entry.Deployment != nil && entry.Subdomain != "" {
+			envTargets = append(envTargets, requestpopup.Target{
+				Label:   env + " · workers.dev",
+				BaseURL: fmt.Sprintf("https://%s.%s.workers.dev", script, entry.Subdomain),
+				Script:  script,
+				Tailing: tailing,
+			})
+		}
+		for _, r := range cfg.EnvRoutes(env) {
+			if base := routeBaseURL(r.Pattern); base != "" {
+				envTargets = append(envTargets, requestpopup.Target{
+					Label:   env + " · route",
+					BaseURL: base,
+					Script:  script,
+					Tailing: tailing,
+				})
+			}
+		}
+		if env == focusedEnv {
+			deployed = append(envTargets, deployed...)
+		} else {
+			deployed = append(deployed, envTargets...)
+		}
+	}
+	return append(targets, deployed...)
+}
+
+// routeBaseURL returns the URL a route pattern serves, e.g. "https://example.com/api"
+// for "example.com/api/*". Patterns with a wildcard elsewhere don't name a
+// single URL and yield "".
+func routeBaseURL(pattern string) string {
+	p := strings.TrimPrefix(strings.TrimPrefix(pattern, "https://"), "http://")
+	p = strings.TrimSuffix(p, "*")
+	if p == "" || strings.Contains(p, "*") {
+		return ""
+	}
+	return "https://" + strings.TrimSuffix(p, "/")
+}
+
+// isTailing reports whether a deployed worker's logs are streaming, from the
+// single tail or a parallel tail.
+func (m Model) isTailing(script string) bool {
+	if m.tailSession != nil && m.tailSession.ScriptName == script {
+		return true
+	}
+	for _, s := range m.parallelTailSessions {
+		if s.ScriptName == script {
+			return true
+		}
+	}
+	return false
+}
+
+// savedRequests returns a project's saved requests.
+func (m Model) savedRequests(project string) map[string]wcfg.SavedRequest {
+	root := m.settingsRoot()
+	if root == "" {
+		return nil
+	}
+	settings, _ := wcfg.LoadRepoSettings(root)
+	return settings.Requests[project]
+}
+
+// captureRequestLogs forwards the tail lines of script that belong to the
+// composer's last request: a dev server's output while the request is in
+// flight and just after, or a deployed Worker's tail events for the request.
+func (m *Model) captureRequestLogs(script string, lines []svc.TailLine) {
+	c := m.requestCapture
+	if !m.showRequestPopup || c == nil || c.script != script {
+		return
+	}
+	now := time.Now()
+	var matched []svc.TailLine
+	if c.dev {
+		if !c.done.IsZero() && now.Sub(c.done) > requestLogWindow {
+			return
+		}
+		matched = lines
+	} else {
+		if now.Sub(c.sent) > requestTailWindow {
+			return
+		}
+		matched = svc.MatchRequestLines(lines, c.method, c.url)
+	}
+	if len(matched) > 0 {
+		m.requestPopup, _ = m.requestPopup.Update(requestpopup.LogsMsg{Lines: matched})
+	}
+}
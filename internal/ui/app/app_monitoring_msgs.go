@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,12 +17,12 @@ import (
 // analyticsTokenProvisionedMsg is sent when a fallback token has been
 // re-provisioned with analytics scope. On success the analytics fetch is retried.
 type analyticsTokenProvisionedMsg struct {
-	token          string
-	tokenID        string // Cloudflare token UUID
-	accountID      string
-	err            error
-	scriptName     string // worker to retry analytics for
-	timeRangeIndex int
+	token      string
+	tokenID    string // Cloudflare token UUID
+	accountID  string
+	err        error
+	scriptName string // worker to retry analytics for
+	timeRange  api.TimeRange
 }
 
 // handleMonitoringMsg handles all monitoring-related messages.
@@ -150,9 +151,53 @@ func (m *Model) handleMonitoringMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 	case monitoring.AnalyticsRequestMsg:
 		// User pressed 'a' on a worker or changed time range — fetch analytics
 		if !m.monitoring.ShowAnalytics() {
-			m.monitoring.OpenAnalytics(msg.ScriptName)
+			m.monitoring.OpenAnalytics(msg.ScriptName, msg.TimeRangeIndex)
 		}
-		return *m, m.fetchAnalyticsCmd(msg.ScriptName, msg.TimeRangeIndex), true
+		tr := resolveTimeRange(msg.TimeRangeIndex, msg.Custom)
+		cmds := []tea.Cmd{m.fetchAnalyticsCmd(msg.ScriptName, tr)}
+		if msg.ByVersion {
+			cmds = append(cmds, m.fetchVersionAnalyticsCmd(msg.ScriptName, tr))
+		}
+		if msg.Compare {
+			cmds = append(cmds, m.fetchCompareAnalyticsCmd(msg.ScriptName, tr))
+		}
+		return *m, tea.Batch(cmds...), true
+
+	case monitoring.AnalyticsVersionsRequestMsg:
+		return *m, m.fetchVersionAnalyticsCmd(msg.ScriptName, resolveTimeRange(msg.TimeRangeIndex, msg.Custom)), true
+
+	case monitoring.AnalyticsCompareRequestMsg:
+		return *m, m.fetchCompareAnalyticsCmd(msg.ScriptName, resolveTimeRange(msg.TimeRangeIndex, msg.Custom)), true
+
+	case monitoring.AnalyticsCompareDataMsg:
+		if msg.ScriptName != m.monitoring.AnalyticsScript() {
+			return *m, nil, true
+		}
+		if msg.Err != nil {
+			m.monitoring.SetAnalyticsCompareError(msg.Err)
+		} else {
+			m.monitoring.SetAnalyticsCompareMetrics(msg.Data)
+		}
+		return *m, nil, true
+
+	case monitoring.AnalyticsExportedMsg:
+		if msg.Err != nil {
+			m.setToast(fmt.Sprintf("Export error: %v", msg.Err))
+		} else {
+			m.setToast(fmt.Sprintf("Analytics exported to %s", strings.Join(msg.Paths, ", ")))
+		}
+		return *m, toastTick(), true
+
+	case monitoring.AnalyticsVersionsDataMsg:
+		if msg.ScriptName != m.monitoring.AnalyticsScript() {
+			return *m, nil, true // view closed or switched worker meanwhile
+		}
+		if msg.Err != nil {
+			m.monitoring.SetAnalyticsVersionError(msg.Err)
+		} else {
+			m.monitoring.SetAnalyticsVersionMetrics(msg.Data)
+		}
+		return *m, nil, true
 
 	case monitoring.AnalyticsDataMsg:
 		if msg.Err != nil {
@@ -172,6 +217,32 @@ func (m *Model) handleMonitoringMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 	case monitoring.AnalyticsCloseMsg:
 		m.monitoring.CloseAnalytics()
 		return *m, nil, true
+
+	case monitoring.DashboardRequestMsg:
+		// User pressed 'A' on a worker or changed the dashboard time range
+		if m.monitoring.DashboardEnv() != msg.EnvName {
+			m.monitoring.OpenDashboard(msg.EnvName, msg.ScriptNames, msg.TimeRangeIndex)
+		}
+		return *m, m.fetchDashboardCmd(msg.EnvName, msg.ScriptNames, msg.TimeRangeIndex), true
+
+	case monitoring.DashboardDataMsg:
+		if msg.EnvName != m.monitoring.DashboardEnv() {
+			return *m, nil, true // dashboard closed or switched env meanwhile
+		}
+		if msg.Err != nil {
+			if api.IsAuthError(msg.Err) {
+				msg.Err = fmt.Errorf("analytics requires Account Analytics Read permission. " +
+					"Open a single worker's analytics (a) to provision a token, or set CLOUDFLARE_API_KEY and CLOUDFLARE_EMAIL")
+			}
+			m.monitoring.SetDashboardError(msg.Err)
+		} else {
+			m.monitoring.SetDashboardMetrics(msg.Data)
+		}
+		return *m, nil, true
+
+	case monitoring.DashboardCloseMsg:
+		m.monitoring.CloseDashboard()
+		return *m, nil, true
 	}
 
 	return *m, nil, false
@@ -189,15 +260,10 @@ func (m *Model) handleAnalyticsAuthError(msg monitoring.AnalyticsDataMsg) (Model
 		apiKey := m.cfg.APIKey
 		accountID := m.registry.ActiveAccountID()
 		scriptName := msg.ScriptName
-		trIdx := 2 // default 24h
+		tr := api.TimeRanges[2] // default 24h
 		if m.monitoring.ShowAnalytics() {
 			// Preserve current time range from the analytics view
-			for i, tr := range api.TimeRanges {
-				if tr.Label == m.monitoring.AnalyticsTimeRangeLabel() {
-					trIdx = i
-					break
-				}
-			}
+			tr = m.monitoring.AnalyticsTimeRange()
 		}
 
 		m.setToast("Upgrading API token for analytics access...")
@@ -206,12 +272,12 @@ func (m *Model) handleAnalyticsAuthError(msg monitoring.AnalyticsDataMsg) (Model
 			func() tea.Msg {
 				result, err := api.CreateScopedToken(context.Background(), email, apiKey, accountID)
 				return analyticsTokenProvisionedMsg{
-					token:          result.Value,
-					tokenID:        result.ID,
-					accountID:      accountID,
-					err:            err,
-					scriptName:     scriptName,
-					timeRangeIndex: trIdx,
+					token:      result.Value,
+					tokenID:    result.ID,
+					accountID:  accountID,
+					err:        err,
+					scriptName: scriptName,
+					timeRange:  tr,
 				}
 			},
 		), true
@@ -256,12 +322,24 @@ func (m *Model) handleAnalyticsTokenProvisioned(msg analyticsTokenProvisionedMsg
 	// Retry the analytics fetch with the new token
 	return *m, tea.Batch(
 		toastTick(),
-		m.fetchAnalyticsCmd(msg.scriptName, msg.timeRangeIndex),
+		m.fetchAnalyticsCmd(msg.scriptName, msg.timeRange),
 	), true
 }
 
+// resolveTimeRange returns the custom range when set, otherwise the preset at
+// the given index into api.TimeRanges (falling back to the first preset).
+func resolveTimeRange(timeRangeIndex int, custom *api.TimeRange) api.TimeRange {
+	if custom != nil {
+		return *custom
+	}
+	if timeRangeIndex >= 0 && timeRangeIndex < len(api.TimeRanges) {
+		return api.TimeRanges[timeRangeIndex]
+	}
+	return api.TimeRanges[0]
+}
+
 // fetchAnalyticsCmd creates a tea.Cmd that fetches worker analytics via GraphQL.
-func (m *Model) fetchAnalyticsCmd(scriptName string, timeRangeIndex int) tea.Cmd {
+func (m *Model) fetchAnalyticsCmd(scriptName string, tr api.TimeRange) tea.Cmd {
 	client := m.getAnalyticsClient()
 	if client == nil {
 		return func() tea.Msg {
@@ -272,11 +350,6 @@ func (m *Model) fetchAnalyticsCmd(scriptName string, timeRangeIndex int) tea.Cmd
 		}
 	}
 
-	tr := api.TimeRanges[0]
-	if timeRangeIndex >= 0 && timeRangeIndex < len(api.TimeRanges) {
-		tr = api.TimeRanges[timeRangeIndex]
-	}
-
 	return func() tea.Msg {
 		ctx := context.Background()
 		metrics, err := client.FetchWorkerMetrics(ctx, scriptName, tr)
@@ -288,6 +361,78 @@ func (m *Model) fetchAnalyticsCmd(scriptName string, timeRangeIndex int) tea.Cmd
 	}
 }
 
+// fetchVersionAnalyticsCmd creates a tea.Cmd that fetches a worker's
+// analytics grouped by version.
+func (m *Model) fetchVersionAnalyticsCmd(scriptName string, tr api.TimeRange) tea.Cmd {
+	client := m.getAnalyticsClient()
+	if client == nil {
+		return func() tea.Msg {
+			return monitoring.AnalyticsVersionsDataMsg{
+				ScriptName: scriptName,
+				Err:        fmt.Errorf("no API credentials available"),
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		data, err := client.FetchWorkerVersionMetrics(context.Background(), scriptName, tr)
+		return monitoring.AnalyticsVersionsDataMsg{
+			ScriptName: scriptName,
+			Data:       data,
+			Err:        err,
+		}
+	}
+}
+
+// fetchCompareAnalyticsCmd creates a tea.Cmd that fetches the period of equal
+// length immediately preceding tr, for previous-period deltas.
+func (m *Model) fetchCompareAnalyticsCmd(scriptName string, tr api.TimeRange) tea.Cmd {
+	client := m.getAnalyticsClient()
+	if client == nil {
+		return func() tea.Msg {
+			return monitoring.AnalyticsCompareDataMsg{
+				ScriptName: scriptName,
+				Err:        fmt.Errorf("no API credentials available"),
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		prev := tr.Previous(time.Now())
+		metrics, err := client.FetchWorkerMetrics(context.Background(), scriptName, prev)
+		return monitoring.AnalyticsCompareDataMsg{
+			ScriptName: scriptName,
+			Data:       metrics,
+			Err:        err,
+		}
+	}
+}
+
+// fetchDashboardCmd creates a tea.Cmd that fetches analytics for all workers
+// of an environment in batched GraphQL queries.
+func (m *Model) fetchDashboardCmd(envName string, scriptNames []string, timeRangeIndex int) tea.Cmd {
+	client := m.getAnalyticsClient()
+	if client == nil {
+		return func() tea.Msg {
+			return monitoring.DashboardDataMsg{
+				EnvName: envName,
+				Err:     fmt.Errorf("no API credentials available"),
+			}
+		}
+	}
+
+	tr := resolveTimeRange(timeRangeIndex, nil)
+
+	return func() tea.Msg {
+		data, err := client.FetchMultiWorkerMetrics(context.Background(), scriptNames, tr)
+		return monitoring.DashboardDataMsg{
+			EnvName: envName,
+			Data:    data,
+			Err:     err,
+		}
+	}
+}
+
 // getAnalyticsClient creates the GraphQL Analytics API client.
 // Uses the same credential priority as getBuildsClient: fallback token first,
 // then primary credentials based on auth method.
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/ui/header"
+	"github.com/oarafat/orangeshell/internal/ui/profilepopup"
+	"github.com/oarafat/orangeshell/internal/ui/setup"
+)
+
+// handleProfileMsg handles the profile switcher. Returns (model, cmd, handled).
+func (m *Model) handleProfileMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case profilepopup.CloseMsg:
+		m.showProfilePopup = false
+		return *m, nil, true
+
+	case profilepopup.SwitchMsg:
+		m.showProfilePopup = false
+		if msg.Name == m.cfg.ActiveProfile() {
+			return *m, nil, true
+		}
+		return *m, m.switchProfile(msg.Name), true
+
+	case profilepopup.NewProfileMsg:
+		m.showProfilePopup = false
+		if err := m.cfg.NewProfile(msg.Name); err != nil {
+			m.setToast(fmt.Sprintf("Cannot create profile: %v", err))
+			return *m, toastTick(), true
+		}
+		// Setup fills in and saves the new profile's credentials
+		m.resetForProfile()
+		return *m, nil, true
+	}
+	return *m, nil, false
+}
+
+// openProfilePopup shows the profile switcher.
+func (m *Model) openProfilePopup() {
+	var entries []profilepopup.Entry
+	for _, name := range m.cfg.ProfileNames() {
+		p, _ := m.cfg.ProfileByName(name)
+		entries = append(entries, profilepopup.Entry{
+			Name:       name,
+			AuthMethod: p.AuthMethod,
+			AccountID:  p.AccountID,
+			Active:     name == m.cfg.ActiveProfile(),
+			Bound:      name == m.boundProfile,
+		})
+	}
+	m.profilePopup = profilepopup.New(entries, m.cfg.SecretStoreName())
+	m.showProfilePopup = true
+}
+
+// switchProfile makes another credential profile active and re-authenticates
+// with it. A profile without usable credentials opens the setup wizard.
+func (m *Model) switchProfile(name string) tea.Cmd {
+	if err := m.cfg.UseProfile(name); err != nil {
+		m.setToast(fmt.Sprintf("Cannot switch profile: %v", err))
+		return toastTick()
+	}
+	_ = m.cfg.Save()
+	m.resetForProfile()
+	if m.phase == PhaseSetup {
+		return nil
+	}
+	m.setToast(fmt.Sprintf("Switched to profile %q", name))
+	return tea.Batch(m.initDashboardCmd(), toastTick())
+}
+
+// resetForProfile drops everything tied to the previous profile's
+// credentials. The dashboard re-initializes once the new client is ready;
+// an unconfigured profile goes through setup first.
+func (m *Model) resetForProfile() {
+	m.stopAccountSessions()
+	m.client = nil
+	m.err = nil
+	m.header = m.newHeader()
+	m.wrangler.ClearDeployments()
+	m.restrictedToastShown = make(map[string]bool)
+	if !m.cfg.IsConfigured() {
+		m.phase = PhaseSetup
+		m.setup = setup.New(m.cfg)
+		m.setup.SetSize(m.width, m.height)
+	}
+}
+
+// newHeader creates the header for the active profile's auth method.
+func (m Model) newHeader() header.Model {
+	h := header.New(m.cfg.AuthMethod)
+	h.SetWidth(m.width)
+	h.SetProfile(m.cfg.ActiveProfile(), len(m.cfg.ProfileNames()))
+	if m.alerts != nil {
+		h.SetAlertCount(m.alerts.Unread())
+	}
+	return h
+}
+
+// applyBoundProfile switches to the profile a repo's .orangeshell.toml binds
+// it to, if it isn't active already.
+func (m *Model) applyBoundProfile(name string) tea.Cmd {
+	m.boundProfile = name
+	if name == "" || name == m.cfg.ActiveProfile() {
+		return nil
+	}
+	if !m.cfg.HasProfile(name) {
+		m.setToast(fmt.Sprintf("⚠ .orangeshell.toml: no profile named %q — keeping %q", name, m.cfg.ActiveProfile()))
+		return toastTick()
+	}
+	return m.switchProfile(name)
+}
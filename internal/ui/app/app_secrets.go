@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PromptPassphrase reads the passphrase of the encrypted secrets file from
+// the terminal; main sets it. It runs with the terminal released by tea.Exec.
+var PromptPassphrase func(create bool) (string, error)
+
+// secretsUnlockedMsg carries the result of the passphrase prompt.
+type secretsUnlockedMsg struct {
+	passphrase string
+	err        error
+}
+
+// passphrasePrompt runs PromptPassphrase as a tea.ExecCommand, so the
+// program pauses rendering and hands over the terminal while it reads.
+type passphrasePrompt struct {
+	passphrase string
+}
+
+func (p *passphrasePrompt) Run() error {
+	if PromptPassphrase == nil {
+		return fmt.Errorf("no terminal to ask for the passphrase: set ORANGESHELL_PASSPHRASE")
+	}
+	// Inside the TUI only a new file can be locked: an existing one was
+	// unlocked when the config loaded
+	var err error
+	p.passphrase, err = PromptPassphrase(true)
+	return err
+}
+
+// The prompt reads and writes the terminal itself.
+func (p *passphrasePrompt) SetStdin(io.Reader)  {}
+func (p *passphrasePrompt) SetStdout(io.Writer) {}
+func (p *passphrasePrompt) SetStderr(io.Writer) {}
+
+// unlockSecretsCmd asks for the passphrase of a new secrets file.
+func (m *Model) unlockSecretsCmd() tea.Cmd {
+	m.secretsPrompting = true
+	prompt := &passphrasePrompt{}
+	return tea.Exec(prompt, func(err error) tea.Msg {
+		return secretsUnlockedMsg{passphrase: prompt.passphrase, err: err}
+	})
+}
+
+// handleSecretsMsg stores the waiting credentials once the passphrase is set.
+// Returns (model, cmd, handled).
+func (m *Model) handleSecretsMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	done, ok := msg.(secretsUnlockedMsg)
+	if !ok {
+		return *m, nil, false
+	}
+	m.secretsPrompting = false
+	err := done.err
+	if err == nil && done.passphrase == "" {
+		err = fmt.Errorf("empty passphrase")
+	}
+	if err != nil {
+		// Drop the waiting save rather than ask again right away; the
+		// credentials stay in memory and the next save asks again
+		m.cfg.UnlockSecrets("")
+		m.setToast(fmt.Sprintf("Credentials not saved: %v", err))
+		return *m, toastTick(), true
+	}
+	m.cfg.UnlockSecrets(done.passphrase)
+	if err := m.cfg.Save(); err != nil {
+		m.setToast(fmt.Sprintf("Failed to save credentials: %v", err))
+		return *m, toastTick(), true
+	}
+	m.setToast("Credentials saved to " + m.cfg.SecretStoreName())
+	return *m, toastTick(), true
+}
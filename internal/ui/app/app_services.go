@@ -309,9 +309,9 @@ func (m *Model) registerServices(accountID string) {
 	})
 }
 
-// switchAccount handles switching to a different account. Re-registers services with the
-// new accountID. If currently viewing a service, reloads it with the new account's data.
-func (m *Model) switchAccount(accountID, accountName string) tea.Cmd {
+// stopAccountSessions stops everything tied to the active account before a
+// switch: tails, dev servers, wrangler commands and per-account caches.
+func (m *Model) stopAccountSessions() {
 	// Stop any active tail session and wrangler command
 	m.stopTail()
 	m.stopAllParallelTails()
@@ -327,6 +327,28 @@ func (m *Model) switchAccount(accountID, accountName string) tea.Cmd {
 	m.detail.ClearQueueCache()
 	m.wrangler.ClearVersionCache()
 	m.wrangler.CloseVersionPicker()
+	m.showBuildsPopup = false
+	m.buildsGen++
+	m.buildsTags = nil
+	m.showBuildTriggersPopup = false
+	m.buildTriggersGen++
+	m.closeD1DumpPopup()
+	m.showTimeTravelPopup = false
+	m.timeTravelGen++
+	m.showSchemaDiffPopup = false
+	m.schemaDiffGen++
+	m.showD1GridPopup = false
+	m.d1GridGen++
+	m.showDevSessionsPopup = false
+	m.showRequestPopup = false
+	m.requestGen++
+	m.requestCapture = nil
+}
+
+// switchAccount handles switching to a different account. Re-registers services with the
+// new accountID. If currently viewing a service, reloads it with the new account's data.
+func (m *Model) switchAccount(accountID, accountName string) tea.Cmd {
+	m.stopAccountSessions()
 
 	m.cfg.AccountID = accountID
 	m.registerServices(accountID)
@@ -767,6 +789,55 @@ func (m Model) provisionFallbackTokenCmd() tea.Cmd {
 	}
 }
 
+// d1SavedQueries returns the saved console queries of a D1 database.
+func (m Model) d1SavedQueries(database string) map[string]string {
+	root := m.settingsRoot()
+	if root == "" {
+		return nil
+	}
+	settings, _ := wcfg.LoadRepoSettings(root)
+	return settings.D1Queries[database]
+}
+
+// exportD1Result returns a command that runs a query in full and writes the
+// last result set with columns to msg.Path.
+func (m Model) exportD1Result(msg detail.D1ExportMsg) tea.Cmd {
+	d1Svc := m.getD1Service()
+	return func() tea.Msg {
+		var (
+			result *svc.D1QueryResult
+			err    error
+		)
+		if msg.LocalResource != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			var local *wcfg.LocalD1QueryResult
+			if local, err = wcfg.ExecuteLocalD1Query(ctx, *msg.LocalResource, msg.SQL); err == nil {
+				result = localD1QueryResult(local)
+			}
+		} else if d1Svc == nil {
+			err = fmt.Errorf("D1 service not available")
+		} else {
+			result, err = d1Svc.ExecuteQuery(msg.DatabaseID, msg.SQL)
+		}
+		if err != nil {
+			return detail.D1ExportDoneMsg{Path: msg.Path, Err: err}
+		}
+
+		var set *svc.D1ResultSet
+		for i := range result.Sets {
+			if len(result.Sets[i].Columns) > 0 {
+				set = &result.Sets[i]
+			}
+		}
+		if set == nil {
+			return detail.D1ExportDoneMsg{Path: msg.Path, Err: fmt.Errorf("the query returned no rows")}
+		}
+		err = detail.WriteD1Export(msg.Path, msg.Format, *set)
+		return detail.D1ExportDoneMsg{Path: msg.Path, Rows: len(set.Rows), Err: err}
+	}
+}
+
 // --- Local emulator helpers ---
 
 // executeLocalD1Query returns a command that runs a SQL query against a local D1 database
@@ -781,24 +852,22 @@ func (m Model) executeLocalD1Query(lr wcfg.LocalResource, sql string) tea.Cmd {
 			return detail.LocalD1QueryResultMsg{Err: err}
 		}
 
-		// Convert wrangler.LocalD1QueryResult → service.D1QueryResult
-		var output string
-		if len(localResult.Columns) > 0 {
-			output = svc.FormatASCIITable(localResult.Columns, localResult.Rows)
-		} else if localResult.ChangedDB {
-			output = "Query OK"
-		} else {
-			output = "(empty result)"
-		}
+		return detail.LocalD1QueryResultMsg{Result: localD1QueryResult(localResult)}
+	}
+}
 
-		return detail.LocalD1QueryResultMsg{
-			Result: &svc.D1QueryResult{
-				Output:    output,
-				Meta:      localResult.Meta,
-				ChangedDB: localResult.ChangedDB,
-			},
-		}
+// localD1QueryResult converts a wrangler.LocalD1QueryResult to a service.D1QueryResult.
+func localD1QueryResult(r *wcfg.LocalD1QueryResult) *svc.D1QueryResult {
+	sets := make([]svc.D1ResultSet, len(r.Sets))
+	for i, s := range r.Sets {
+		sets[i] = svc.D1ResultSet{Columns: s.Columns, Rows: s.Rows, Meta: s.Meta, Changes: s.Changes}
 	}
+	result := svc.NewD1QueryResult(sets, r.ChangedDB)
+	// wrangler reports no columns for a SELECT without rows
+	if len(sets) == 0 || (len(sets) == 1 && len(sets[0].Columns) == 0 && !r.ChangedDB) {
+		result.Output = "(empty result)"
+	}
+	return result
 }
 
 // loadLocalD1Schema returns a command that introspects a local D1 database schema
@@ -813,32 +882,40 @@ func (m Model) loadLocalD1Schema(lr wcfg.LocalResource, databaseID string) tea.C
 			return detail.D1SchemaLoadedMsg{DatabaseID: databaseID, Err: err}
 		}
 
-		// Convert wrangler.LocalSchemaTable → service.SchemaTable
-		tables := make([]svc.SchemaTable, 0, len(localTables))
-		for _, lt := range localTables {
-			st := svc.SchemaTable{Name: lt.Name}
-			for _, lc := range lt.Columns {
-				st.Columns = append(st.Columns, svc.SchemaColumn{
-					Name:    lc.Name,
-					Type:    lc.Type,
-					NotNull: lc.NotNull,
-					PK:      lc.PK,
-				})
-			}
-			for _, lf := range lt.FKs {
-				st.FKs = append(st.FKs, svc.SchemaFK{
-					FromCol: lf.FromCol,
-					ToTable: lf.ToTable,
-					ToCol:   lf.ToCol,
-				})
-			}
-			tables = append(tables, st)
-		}
-
+		tables := localSchemaTables(localTables)
 		return detail.D1SchemaLoadedMsg{DatabaseID: databaseID, Tables: tables}
 	}
 }
 
+// localSchemaTables converts wrangler.LocalSchemaTable → service.SchemaTable.
+func localSchemaTables(localTables []wcfg.LocalSchemaTable) []svc.SchemaTable {
+	tables := make([]svc.SchemaTable, 0, len(localTables))
+	for _, lt := range localTables {
+		st := svc.SchemaTable{Name: lt.Name, SQL: lt.SQL}
+		for _, lc := range lt.Columns {
+			st.Columns = append(st.Columns, svc.SchemaColumn{
+				Name:    lc.Name,
+				Type:    lc.Type,
+				NotNull: lc.NotNull,
+				PK:      lc.PK,
+				Default: lc.Default,
+			})
+		}
+		for _, lf := range lt.FKs {
+			st.FKs = append(st.FKs, svc.SchemaFK{
+				FromCol: lf.FromCol,
+				ToTable: lf.ToTable,
+				ToCol:   lf.ToCol,
+			})
+		}
+		for _, li := range lt.Indexes {
+			st.Indexes = append(st.Indexes, svc.SchemaIndex{Name: li.Name, SQL: li.SQL})
+		}
+		tables = append(tables, st)
+	}
+	return tables
+}
+
 // loadLocalKVKeys returns a command that fetches keys (with values) from a local KV namespace
 // via the wrangler CLI (npx wrangler kv key list/get --local).
 func (m Model) loadLocalKVKeys(lr wcfg.LocalResource, prefix string) tea.Cmd {
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/triggerspopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// buildTriggersLoadedMsg delivers the triggers of the popup's Workers.
+type buildTriggersLoadedMsg struct {
+	gen      int
+	triggers []triggerspopup.Trigger
+	err      error
+}
+
+// handleBuildTriggersMsg handles the build triggers popup. Returns (model, cmd, handled).
+func (m *Model) handleBuildTriggersMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case triggerspopup.CloseMsg:
+		m.showBuildTriggersPopup = false
+		m.buildTriggersGen++
+		return *m, nil, true
+
+	case triggerspopup.RefreshMsg:
+		return *m, m.loadBuildTriggersCmd(), true
+
+	case buildTriggersLoadedMsg:
+		if msg.gen != m.buildTriggersGen {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.buildTriggersPopup, cmd = m.buildTriggersPopup.Update(triggerspopup.LoadedMsg{
+			Triggers:   msg.triggers,
+			Err:        msg.err,
+			Restricted: api.IsAuthError(msg.err),
+		})
+		return *m, cmd, true
+
+	case triggerspopup.ActionDoneMsg:
+		if !m.showBuildTriggersPopup {
+			return *m, nil, true
+		}
+		var cmd tea.Cmd
+		m.buildTriggersPopup, cmd = m.buildTriggersPopup.Update(msg)
+		if msg.Err == nil {
+			cmd = tea.Batch(cmd, m.loadBuildTriggersCmd())
+		}
+		return *m, cmd, true
+
+	case triggerspopup.SaveMsg:
+		return *m, m.saveBuildTriggerCmd(msg), true
+
+	case triggerspopup.DeleteMsg:
+		return *m, m.deleteBuildTriggerCmd(msg.Trigger), true
+	}
+	return *m, nil, false
+}
+
+// openBuildTriggersPopup shows the build triggers of a project's environments.
+func (m *Model) openBuildTriggersPopup(title string, envs []triggerspopup.Env) tea.Cmd {
+	if len(envs) == 0 {
+		m.setToast("No Workers to show build triggers for")
+		return toastTick()
+	}
+	m.buildTriggersGen++
+	m.buildTriggersPopup = triggerspopup.New(title, envs)
+	m.buildTriggersPopup.SetSize(m.height)
+	m.showBuildTriggersPopup = true
+	return tea.Batch(m.buildTriggersPopup.SpinnerInit(), m.loadBuildTriggersCmd())
+}
+
+// triggerEnvs returns a config's environments and the Worker each deploys,
+// the default environment first and the named ones sorted.
+func triggerEnvs(cfg *wcfg.WranglerConfig) []triggerspopup.Env {
+	if cfg == nil {
+		return nil
+	}
+	names := cfg.EnvNames()
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "default" || names[j] == "default" {
+			return names[i] == "default"
+		}
+		return names[i] < names[j]
+	})
+	var envs []triggerspopup.Env
+	for _, name := range names {
+		if script := cfg.ResolvedEnvName(name); script != "" {
+			envs = append(envs, triggerspopup.Env{Name: name, Script: script})
+		}
+	}
+	return envs
+}
+
+// loadBuildTriggersCmd lists the triggers of every environment's Worker along
+// with their build variables. Workers that were never deployed have no
+// script tag and are skipped.
+func (m Model) loadBuildTriggersCmd() tea.Cmd {
+	client := m.getBuildsClient()
+	envs := m.buildTriggersPopup.Envs()
+	tags := m.buildsTagsCopy()
+	gen := m.buildTriggersGen
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			byEnv    = make([][]triggerspopup.Trigger, len(envs))
+			authErr  error
+			lastErr  error
+			failures int
+		)
+		for i, env := range envs {
+			wg.Add(1)
+			go func(i int, env triggerspopup.Env) {
+				defer wg.Done()
+				tag, err := resolveScriptTag(ctx, client, tags, env.Script)
+				var triggers []api.Trigger
+				if err == nil {
+					triggers, err = client.GetWorkerTriggers(ctx, tag)
+				}
+				if err != nil {
+					mu.Lock()
+					if api.IsAuthError(err) {
+						authErr = err
+					}
+					lastErr = err
+					failures++
+					mu.Unlock()
+					return
+				}
+				list := make([]triggerspopup.Trigger, len(triggers))
+				for j, t := range triggers {
+					// Variables are best effort: a trigger is still editable without them
+					vars, _ := client.GetTriggerEnvVars(ctx, t.UUID)
+					list[j] = triggerspopup.Trigger{Env: env, Trigger: t, Vars: vars}
+				}
+				byEnv[i] = list
+			}(i, env)
+		}
+		wg.Wait()
+
+		if authErr != nil {
+			return buildTriggersLoadedMsg{gen: gen, err: authErr}
+		}
+		if failures == len(envs) && lastErr != nil {
+			return buildTriggersLoadedMsg{gen: gen, err: lastErr}
+		}
+		var all []triggerspopup.Trigger
+		for _, list := range byEnv {
+			all = append(all, list...)
+		}
+		return buildTriggersLoadedMsg{gen: gen, triggers: all}
+	}
+}
+
+// saveBuildTriggerCmd applies an edit to a trigger, or creates its copy for
+// another environment's Worker, then syncs the build variables.
+func (m Model) saveBuildTriggerCmd(msg triggerspopup.SaveMsg) tea.Cmd {
+	client := m.getBuildsClient()
+	tags := m.buildsTagsCopy()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		req := msg.Req
+		triggerUUID := msg.Trigger.UUID
+		if msg.Copy {
+			// The new trigger deploys the target Worker, identified by its script tag
+			tag, err := resolveScriptTag(ctx, client, tags, msg.Target.Script)
+			if err != nil {
+				return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("resolving %s: %w — deploy it once first", msg.Target.Script, err)}
+			}
+			req.ScriptID = tag
+			created, err := client.CreateTrigger(ctx, req)
+			if err != nil {
+				if api.IsConflictError(err) {
+					return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("%s already has a trigger for this repository — edit it instead", msg.Target.Script)}
+				}
+				return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("copying trigger: %w", err)}
+			}
+			triggerUUID = created.UUID
+		} else if _, err := client.UpdateTrigger(ctx, triggerUUID, req); err != nil {
+			return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("updating trigger: %w", err)}
+		}
+
+		if len(msg.SetVars) > 0 {
+			if err := client.SetTriggerEnvVars(ctx, triggerUUID, msg.SetVars); err != nil {
+				return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("saving build variables: %w", err)}
+			}
+		}
+		for _, key := range msg.DeleteVars {
+			if err := client.DeleteTriggerEnvVar(ctx, triggerUUID, key); err != nil {
+				return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("deleting build variable %s: %w", key, err)}
+			}
+		}
+
+		status := fmt.Sprintf("Saved trigger %s", req.TriggerName)
+		if msg.Copy {
+			status = fmt.Sprintf("Copied trigger to %s (env %s)", msg.Target.Script, msg.Target.Name)
+		}
+		if len(msg.SkippedSecrets) > 0 {
+			status += fmt.Sprintf(" — secrets not copied, set them again: %s", strings.Join(msg.SkippedSecrets, ", "))
+		}
+		return triggerspopup.ActionDoneMsg{Status: status}
+	}
+}
+
+// deleteBuildTriggerCmd deletes a build trigger.
+func (m Model) deleteBuildTriggerCmd(t triggerspopup.Trigger) tea.Cmd {
+	client := m.getBuildsClient()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := client.DeleteTrigger(ctx, t.UUID); err != nil {
+			return triggerspopup.ActionDoneMsg{Err: fmt.Errorf("deleting trigger: %w", err)}
+		}
+		return triggerspopup.ActionDoneMsg{Status: fmt.Sprintf("Deleted trigger %s of %s", t.Name, t.Env.Script)}
+	}
+}
@@ -48,7 +48,21 @@ func (m Model) viewDashboard() string {
 		{m.showProjectPopup, func() string { return m.projectPopup.View(w, h) }},
 		{m.showRemoveProjectPopup, func() string { return m.removeProjectPopup.View(w, h) }},
 		{m.showHelpPopup, func() string { return m.helpPopup.View(w, h) }},
+		{m.showAlertsPopup, func() string { return m.alertsPopup.View(w, h) }},
+		{m.showDiagPopup, func() string { return m.diagPopup.View(w, h) }},
+		{m.showHistoryPopup, func() string { return m.historyPopup.View(w, h) }},
+		{m.showTagPopup, func() string { return m.tagPopup.View(w, h) }},
+		{m.showWorkspacePopup, func() string { return m.workspacePopup.View(w, h) }},
+		{m.showProfilePopup, func() string { return m.profilePopup.View(w, h) }},
 		{m.showCICDPopup, func() string { return m.cicdPopup.View(w, h) }},
+		{m.showBuildsPopup, func() string { return m.buildsPopup.View(w, h) }},
+		{m.showBuildTriggersPopup, func() string { return m.buildTriggersPopup.View(w, h) }},
+		{m.showD1DumpPopup, func() string { return m.d1DumpPopup.View(w, h) }},
+		{m.showTimeTravelPopup, func() string { return m.timeTravelPopup.View(w, h) }},
+		{m.showSchemaDiffPopup, func() string { return m.schemaDiffPopup.View(w, h) }},
+		{m.showD1GridPopup, func() string { return m.d1GridPopup.View(w, h) }},
+		{m.showDevSessionsPopup, func() string { return m.devSessionsPopup.View(w, h) }},
+		{m.showRequestPopup, func() string { return m.requestPopup.View(w, h) }},
 		{m.showActions, func() string { return m.actionsPopup.View(w, h) }},
 	}
 
@@ -343,7 +357,7 @@ func (m Model) renderOperationsHelp() []helpEntry {
 			{"[/]", "accounts"},
 		}
 		if m.wrangler.HasConfig() && !m.wrangler.IsOnProjectList() {
-			entries = append(entries, helpEntry{"t", "tail"})
+			entries = append(entries, helpEntry{"t", "tail"}, helpEntry{"v", "version stats"})
 			if m.wrangler.InsideBox() {
 				entries = append(entries, helpEntry{"d", "del binding"})
 			} else {
@@ -436,11 +450,34 @@ func (m Model) renderMonitoringHelp() []helpEntry {
 
 	// Analytics view active — show analytics-specific help
 	if m.monitoring.ShowAnalytics() {
+		if m.monitoring.AnalyticsPickerActive() {
+			return []helpEntry{
+				{"tab", "start/end"},
+				{"enter", "apply"},
+				{"esc", "cancel"},
+			}
+		}
 		return []helpEntry{
 			{",/.", "time range"},
+			{"t", "custom range"},
+			{"c", "vs previous"},
+			{"v", "by version"},
+			{"e/E", "export csv/json"},
 			{"r", "refresh"},
 			{"R", "auto-refresh"},
 			{"j/k", "scroll"},
+			{"esc", "back"},
+		}
+	}
+
+	// Environment dashboard active
+	if m.monitoring.ShowDashboard() {
+		return []helpEntry{
+			{"j/k", "navigate"},
+			{"enter", "drill down"},
+			{"s/S", "sort/reverse"},
+			{",/.", "time range"},
+			{"r", "refresh"},
 			{"esc", "back to grid"},
 		}
 	}
@@ -452,10 +489,14 @@ func (m Model) renderMonitoringHelp() []helpEntry {
 			{"space", "toggle"},
 			{"a", "analytics"},
 		}
+		if !m.monitoring.CursorOnDev() {
+			entries = append(entries, helpEntry{"A", "env dashboard"})
+		}
 		if m.monitoring.CursorOnDev() {
 			entries = append(entries, helpEntry{"c", "cron trigger"})
 		}
 		entries = append(entries,
+			helpEntry{"!", "alerts"},
 			helpEntry{"tab", "grid"},
 			helpEntry{"esc", "back"},
 			helpEntry{"ctrl+h", "home"},
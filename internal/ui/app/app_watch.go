@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// configWatchMsg carries a settled batch of config changes from the watcher.
+type configWatchMsg struct {
+	watcher *wcfg.Watcher
+	event   wcfg.WatchEvent
+}
+
+// startConfigWatch replaces the config watcher with one for the given
+// projects. rootDir is watched for added and removed projects; pass "" to
+// watch only the projects' configs. Watching is best-effort: if the watcher
+// can't start (e.g. the inotify limit is reached) configs just aren't
+// reloaded live.
+func (m *Model) startConfigWatch(rootDir string, projects []wcfg.ProjectInfo) tea.Cmd {
+	m.stopConfigWatch()
+	w, err := wcfg.NewWatcher(rootDir, projects)
+	if err != nil {
+		return nil
+	}
+	m.configWatcher = w
+	return waitForConfigWatch(w)
+}
+
+// stopConfigWatch closes the active config watcher, if any.
+func (m *Model) stopConfigWatch() {
+	if m.configWatcher != nil {
+		m.configWatcher.Close()
+		m.configWatcher = nil
+	}
+}
+
+// waitForConfigWatch blocks until the watcher reports a change. Returns nil
+// (ending the loop) once the watcher is closed.
+func waitForConfigWatch(w *wcfg.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-w.Events()
+		if !ok {
+			return nil
+		}
+		return configWatchMsg{watcher: w, event: ev}
+	}
+}
+
+// handleConfigWatchMsg applies config changes made on disk: rediscovered
+// projects are merged into the project list and changed configs re-parsed,
+// restarting the dev servers running from them. A config that fails to parse
+// keeps its last good version (and its dev servers) and raises a toast.
+// Returns (model, cmd, handled).
+func (m *Model) handleConfigWatchMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	wm, ok := msg.(configWatchMsg)
+	if !ok {
+		return *m, nil, false
+	}
+	if wm.watcher != m.configWatcher {
+		// From a watcher that has since been replaced
+		return *m, nil, true
+	}
+	cmds := []tea.Cmd{waitForConfigWatch(wm.watcher)}
+
+	if wm.event.Rediscovered {
+		if len(wm.event.Projects) == 0 {
+			m.wrangler.UpdateProjects(nil)
+			m.wrangler.SetConfig(nil, "", nil)
+		} else {
+			m.wrangler.UpdateProjects(wm.event.Projects)
+			cmds = append(cmds, m.fetchAllProjectDeployments())
+		}
+		m.refreshMonitoringWorkerTree()
+		m.refreshAIFileSources()
+	}
+
+	var failed []string
+	var lastErr error
+	for _, path := range wm.event.Configs {
+		cfg, err := wcfg.Parse(path)
+		if err != nil {
+			failed = append(failed, path)
+			lastErr = err
+			continue
+		}
+		m.wrangler.ReloadConfig(path, cfg)
+		cmds = append(cmds, m.restartDevServersFor(path))
+		if m.configView.ConfigPath() == path {
+			m.configView.ReloadConfig()
+		}
+	}
+
+	if wm.event.Rediscovered || len(failed) < len(wm.event.Configs) {
+		// Rebuilt env and project boxes lose their badges
+		m.syncAccessBadges()
+		m.syncCICDBadges()
+		m.syncDevBadges()
+	}
+
+	switch {
+	case wm.event.SettingsErr != nil:
+		m.setToast(fmt.Sprintf("⚠ %v — using default discovery", wm.event.SettingsErr))
+		cmds = append(cmds, toastTick())
+	case len(failed) == 1:
+		m.setToast(fmt.Sprintf("⚠ %s: %v — keeping the last valid config", relConfigName(failed[0]), lastErr))
+		cmds = append(cmds, toastTick())
+	case len(failed) > 1:
+		m.setToast(fmt.Sprintf("⚠ %d configs failed to parse — keeping their last valid versions", len(failed)))
+		cmds = append(cmds, toastTick())
+	}
+	return *m, tea.Batch(cmds...), true
+}
+
+// relConfigName names a config by its project directory, e.g. "api/wrangler.toml".
+func relConfigName(path string) string {
+	return filepath.Join(filepath.Base(filepath.Dir(path)), filepath.Base(path))
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/config"
+	"github.com/oarafat/orangeshell/internal/ui/actions"
+	"github.com/oarafat/orangeshell/internal/ui/launcher"
+	"github.com/oarafat/orangeshell/internal/ui/tabbar"
+	"github.com/oarafat/orangeshell/internal/ui/tagpopup"
+	"github.com/oarafat/orangeshell/internal/ui/workspacepopup"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// projectTagDoneMsg reports the result of tagging or untagging a project,
+// with the projects rediscovered afterwards.
+type projectTagDoneMsg struct {
+	tag      string
+	add      bool
+	projects []wcfg.ProjectInfo
+	err      error
+}
+
+// handleWorkspaceMsg handles project tagging, tag filters and saved
+// workspaces. Returns (model, cmd, handled).
+func (m *Model) handleWorkspaceMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tagpopup.CloseMsg:
+		m.showTagPopup = false
+		return *m, nil, true
+
+	case tagpopup.ToggleTagMsg:
+		return *m, m.projectTagCmd(msg.RelPath, msg.Tag, msg.Add), true
+
+	case projectTagDoneMsg:
+		if msg.err != nil {
+			m.tagPopup.SetTagged(msg.tag, !msg.add)
+			m.tagPopup.SetError(msg.err.Error())
+			return *m, nil, true
+		}
+		if len(msg.projects) > 0 {
+			m.wrangler.UpdateProjects(msg.projects)
+		}
+		return *m, nil, true
+
+	case workspacepopup.CloseMsg:
+		m.showWorkspacePopup = false
+		return *m, nil, true
+
+	case workspacepopup.SaveMsg:
+		m.showWorkspacePopup = false
+		if m.cfg == nil {
+			return *m, nil, true
+		}
+		m.cfg.SaveWorkspace(config.Workspace{
+			Name: msg.Name,
+			Root: m.wrangler.RootDir(),
+			Tags: m.wrangler.TagFilter(),
+			Env:  msg.Env,
+		})
+		if err := m.cfg.Save(); err != nil && !errors.Is(err, config.ErrSecretsLocked) {
+			m.setToast(fmt.Sprintf("Failed to save workspace: %v", err))
+			return *m, toastTick(), true
+		}
+		m.wrangler.SetWorkspace(msg.Name, m.wrangler.TagFilter(), msg.Env)
+		m.setToast(fmt.Sprintf("Workspace %q saved — reopen it from ctrl+l", msg.Name))
+		return *m, toastTick(), true
+
+	case launcher.OpenWorkspaceMsg:
+		m.showLauncher = false
+		if m.cfg == nil {
+			return *m, nil, true
+		}
+		ws := m.cfg.Workspace(msg.Name)
+		if ws == nil {
+			return *m, nil, true
+		}
+		open := *ws
+		m.pendingWorkspace = &open
+		m.activeTab = tabbar.TabOperations
+		m.viewState = ViewWrangler
+		if ws.Root == m.wrangler.RootDir() && m.wrangler.IsMonorepo() {
+			// Already open — just apply the filter and env
+			m.applyPendingWorkspace()
+			return *m, nil, true
+		}
+		m.wrangler.SetConfigLoading()
+		return *m, tea.Batch(m.discoverProjectsFromDir(ws.Root), m.wrangler.SpinnerInit()), true
+	}
+	return *m, nil, false
+}
+
+// applyPendingWorkspace applies the workspace being opened to the freshly
+// discovered project list.
+func (m *Model) applyPendingWorkspace() {
+	ws := m.pendingWorkspace
+	if ws == nil {
+		return
+	}
+	m.pendingWorkspace = nil
+	m.wrangler.BackToProjectList()
+	m.wrangler.SetWorkspace(ws.Name, ws.Tags, ws.Env)
+}
+
+// projectTagCmd tags or untags a project in the repo's .orangeshell.toml and
+// rediscovers the projects so the change shows up right away.
+func (m Model) projectTagCmd(relPath, tag string, add bool) tea.Cmd {
+	root := m.wrangler.RootDir()
+	return func() tea.Msg {
+		var err error
+		if add {
+			err = wcfg.AddProjectTag(root, relPath, tag)
+		} else {
+			err = wcfg.RemoveProjectTag(root, relPath, tag)
+		}
+		if err != nil {
+			return projectTagDoneMsg{tag: tag, add: add, err: err}
+		}
+		projects, _ := wcfg.Discover(root)
+		return projectTagDoneMsg{tag: tag, add: add, projects: projects}
+	}
+}
+
+// openTagPopup shows the tag checklist for the selected project.
+func (m *Model) openTagPopup() {
+	relPath := m.wrangler.SelectedProjectRelPath()
+	if relPath == "" {
+		return
+	}
+	m.tagPopup = tagpopup.New(m.wrangler.SelectedProjectName(), relPath,
+		m.wrangler.AllTags(), m.wrangler.SelectedProjectTags())
+	m.showTagPopup = true
+}
+
+// openWorkspacePopup offers to save the current root and tag filter as a
+// named workspace.
+func (m *Model) openWorkspacePopup() {
+	exists := func(name string) bool {
+		return m.cfg != nil && m.cfg.Workspace(name) != nil
+	}
+	m.workspacePopup = workspacepopup.New(m.wrangler.RootDir(), m.wrangler.TagFilter(),
+		m.wrangler.WorkspaceName(), m.wrangler.AllEnvNames(), m.wrangler.DefaultEnv(), exists)
+	m.showWorkspacePopup = true
+}
+
+// workspaceNames returns the names of the saved workspaces.
+func (m Model) workspaceNames() []string {
+	if m.cfg == nil {
+		return nil
+	}
+	names := make([]string, len(m.cfg.Workspaces))
+	for i, ws := range m.cfg.Workspaces {
+		names[i] = ws.Name
+	}
+	return names
+}
+
+// buildTagFilterPopup creates a sub-popup toggling tags in the project list filter.
+func (m Model) buildTagFilterPopup() actions.Model {
+	active := make(map[string]bool)
+	for _, t := range m.wrangler.TagFilter() {
+		active[t] = true
+	}
+	var items []actions.Item
+	for _, tag := range m.wrangler.AllTags() {
+		desc := "Show projects tagged #" + tag
+		if active[tag] {
+			desc = "✓ Filtering — select to remove"
+		}
+		items = append(items, actions.Item{
+			Label:       "#" + tag,
+			Description: desc,
+			Section:     "Tags",
+			Action:      "tag_filter:" + tag,
+		})
+	}
+	if len(active) > 0 {
+		items = append(items, actions.Item{
+			Label:       "Clear Filter",
+			Description: "Show all projects",
+			Section:     "Tags",
+			Action:      "tag_filter_clear",
+		})
+	}
+	return actions.New("Filter by Tag", items)
+}
+
+// scopedLabel appends the scope of project-wide commands to an action label,
+// e.g. "Deploy All (edge)...".
+func (m Model) scopedLabel(label string) string {
+	if scope := m.wrangler.ScopeLabel(); scope != "" {
+		return fmt.Sprintf("%s (%s)...", label, scope)
+	}
+	return label + "..."
+}
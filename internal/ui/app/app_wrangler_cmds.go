@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/oarafat/orangeshell/internal/api"
@@ -43,22 +44,20 @@ func (m *Model) startDevServer(action, projectName, envName, configPath, scriptN
 		m.cleanupDevSessionByKey(key)
 	}
 
-	// Open log file
-	logFile, logPath := openDevLogFile(scriptName)
-
-	// Create runner
-	runner := wcfg.NewRunner()
 	dr := &devRunner{
-		runner:  runner,
-		key:     key,
-		devKind: "local",
-		status:  "starting",
-		logPath: logPath,
-		logFile: logFile,
+		key:         key,
+		devKind:     "local",
+		action:      action,
+		projectName: projectName,
+		envName:     envName,
+		configPath:  configPath,
+		scriptName:  scriptName,
 	}
 	if action == "dev --remote" {
 		dr.devKind = "remote"
 	}
+	// Concurrent sessions would all default to 8787/9229: give each its own
+	dr.wantPort, dr.inspectorPort = m.allocDevPorts()
 	m.devRunners[key] = dr
 
 	// Create dev session for monitoring integration
@@ -73,18 +72,44 @@ func (m *Model) startDevServer(action, projectName, envName, configPath, scriptN
 	})
 	m.monitoring.AddDevToGrid(dsName, dr.devKind)
 	m.refreshMonitoringWorkerTree()
+
+	return m.launchDevRunner(dr)
+}
+
+// launchDevRunner starts (or restarts) the wrangler dev process of dr on its
+// allocated ports, resetting its status to "starting".
+func (m *Model) launchDevRunner(dr *devRunner) tea.Cmd {
+	if dr.logFile == nil {
+		dr.logFile, dr.logPath = openDevLogFile(dr.scriptName)
+	}
+	runner := wcfg.NewRunner()
+	dr.runner = runner
+	dr.status = "starting"
+	dr.port = ""
+	dr.errMsg = ""
+	dr.health = ""
+	dr.healthDetail = ""
+	dr.startedAt = time.Time{}
+	if ds := m.findDevSessionByKey(dr.key); ds != nil {
+		ds.Port = ""
+	}
 	m.syncDevBadges()
 	m.syncLocalResources()
 
+	extraArgs := []string{"--show-interactive-dev-session=false"}
+	if dr.wantPort > 0 && dr.inspectorPort > 0 {
+		extraArgs = append(extraArgs, wcfg.DevPortArgs(dr.wantPort, dr.inspectorPort)...)
+	}
 	cmd := wcfg.Command{
-		Action:     action,
-		ConfigPath: configPath,
-		EnvName:    envName,
-		ExtraArgs:  []string{"--show-interactive-dev-session=false"},
+		Action:     dr.action,
+		ConfigPath: dr.configPath,
+		EnvName:    dr.envName,
+		ExtraArgs:  extraArgs,
 		AccountID:  m.registry.ActiveAccountID(),
 		FilterEnv:  m.wranglerFilterEnv(),
 	}
 
+	key := dr.key
 	return tea.Batch(
 		func() tea.Msg {
 			ctx := context.Background()
@@ -98,6 +123,7 @@ func (m *Model) startDevServer(action, projectName, envName, configPath, scriptN
 			return readRunnerOutput(runner, key, true)
 		},
 		m.wrangler.SpinnerInit(),
+		m.startDevHealthTicks(),
 	)
 }
 
@@ -209,11 +235,12 @@ func (m Model) waitForRunnerOutput(key string, isDevCmd bool) tea.Cmd {
 
 // --- Output handlers ---
 
-// handleDevOutput processes a line of dev server output.
-func (m *Model) handleDevOutput(key string, line wcfg.OutputLine) {
+// handleDevOutput processes a line of dev server output. The returned command
+// raises any alert rules the line triggered.
+func (m *Model) handleDevOutput(key string, line wcfg.OutputLine) tea.Cmd {
 	dr, ok := m.devRunners[key]
 	if !ok {
-		return
+		return nil
 	}
 
 	// Write to log file
@@ -222,20 +249,24 @@ func (m *Model) handleDevOutput(key string, line wcfg.OutputLine) {
 	// Pipe to monitoring grid
 	ds := m.findDevSessionByKey(key)
 	if ds == nil {
-		return
+		return nil
 	}
 	tailLine := parseDevOutputLine(line)
 	m.monitoring.GridAppendLines(ds.ScriptName, []svc.TailLine{tailLine})
 	m.logExporter.WriteLines(ds.ScriptName, []svc.TailLine{tailLine})
+	m.captureRequestLogs(ds.ScriptName, []svc.TailLine{tailLine})
 
 	// Check for port announcement
 	if port := extractDevPort(line.Text); port != "" && dr.port == "" {
 		dr.port = port
 		dr.status = "running"
+		dr.startedAt = time.Now()
 		ds.Port = port
 		m.refreshMonitoringWorkerTree()
 		m.syncDevBadges()
 	}
+
+	return m.observeTailAlerts(ds.ScriptName, []svc.TailLine{tailLine})
 }
 
 // handleCmdOutput processes a line of command output (deploy, delete, etc.).
@@ -269,6 +300,14 @@ func (m *Model) handleDevDone(key string, result wcfg.RunResult) tea.Cmd {
 		}
 	}
 
+	// Stopped for a restart: the old process tree is gone, so its ports are
+	// free again
+	if dr.restarting {
+		dr.restarting = false
+		dr.restarts++
+		return m.launchDevRunner(dr)
+	}
+
 	// On failure: keep the session with "failed" status so the badge persists.
 	// The user can retry via the action menu (which calls cleanupDevSessionByKey first).
 	if result.ExitCode != 0 || result.Err != nil {
@@ -3,9 +3,11 @@ package app
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/oarafat/orangeshell/internal/api"
 	svc "github.com/oarafat/orangeshell/internal/service"
 	uiconfig "github.com/oarafat/orangeshell/internal/ui/config"
 	"github.com/oarafat/orangeshell/internal/ui/deletepopup"
@@ -13,6 +15,7 @@ import (
 	"github.com/oarafat/orangeshell/internal/ui/projectpopup"
 	"github.com/oarafat/orangeshell/internal/ui/tabbar"
 	uiwrangler "github.com/oarafat/orangeshell/internal/ui/wrangler"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
 )
 
 // handleWranglerMsg handles all wrangler-related messages.
@@ -30,11 +33,24 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		// Sync badges on newly created env boxes
 		m.syncAccessBadges()
 		m.syncCICDBadges()
+		// Reload the config live when it changes on disk
+		var watchCmd tea.Cmd
+		if msg.Path != "" {
+			watchCmd = m.startConfigWatch("", []wcfg.ProjectInfo{{ConfigPath: msg.Path, Dir: filepath.Dir(msg.Path)}})
+		} else {
+			m.stopConfigWatch()
+		}
+		if ws := m.pendingWorkspace; ws != nil {
+			// The workspace root no longer holds any projects
+			m.pendingWorkspace = nil
+			m.setToast(fmt.Sprintf("Workspace %q: no projects found in %s", ws.Name, ws.Root))
+			watchCmd = tea.Batch(watchCmd, toastTick())
+		}
 		// Trigger deployment fetching for single-project environments
 		if msg.Err == nil && msg.Config != nil {
-			return *m, m.fetchSingleProjectDeployments(msg.Config), true
+			return *m, tea.Batch(m.fetchSingleProjectDeployments(msg.Config), watchCmd), true
 		}
-		return *m, nil, true
+		return *m, watchCmd, true
 
 	case uiwrangler.EmptyMenuSelectMsg:
 		switch msg.Action {
@@ -47,6 +63,8 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 
 	case uiwrangler.ProjectsDiscoveredMsg:
 		m.wrangler.SetProjects(msg.Projects, msg.RootName, msg.RootDir)
+		// Opening a saved workspace: apply its tag filter and default env
+		m.applyPendingWorkspace()
 		// Refresh the monitoring worker tree
 		m.refreshMonitoringWorkerTree()
 		// Refresh AI file sources so the context panel picks up new projects
@@ -54,8 +72,16 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		// Sync badges on newly created project boxes
 		m.syncAccessBadges()
 		m.syncCICDBadges()
-		// Trigger deployment fetching for all projects
-		return *m, m.fetchAllProjectDeployments(), true
+		// Trigger deployment fetching for all projects, and pick up config
+		// edits and added/removed projects live
+		cmds := []tea.Cmd{m.fetchAllProjectDeployments(), m.startConfigWatch(msg.RootDir, msg.Projects)}
+		if msg.SettingsErr != nil {
+			m.setToast(fmt.Sprintf("⚠ %v — using default discovery", msg.SettingsErr))
+			cmds = append(cmds, toastTick())
+		}
+		// Switch to the credential profile the repo is bound to, if any
+		cmds = append(cmds, m.applyBoundProfile(msg.Profile))
+		return *m, tea.Batch(cmds...), true
 
 	case uiwrangler.LoadConfigPathMsg:
 		if m.wrangler.DirBrowserActiveMode() == uiwrangler.DirBrowserModeCreate {
@@ -114,6 +140,29 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		accountID := m.registry.ActiveAccountID()
 		return *m, m.startTailCmd(accountID, msg.ScriptName), true
 
+	case uiwrangler.VersionAnalyticsMsg:
+		// 'v' on a deployed env or 'a' in the version picker — open the
+		// per-version analytics breakdown on the Monitoring tab.
+		m.wrangler.CloseVersionPicker()
+		scriptName := msg.ScriptName
+		if scriptName == "" {
+			if cfg := m.wrangler.Config(); cfg != nil {
+				scriptName = cfg.ResolvedEnvName(msg.EnvName)
+			}
+		}
+		if scriptName == "" {
+			return *m, nil, true
+		}
+		const trIdx = 2 // default 24h
+		m.activeTab = tabbar.TabMonitoring
+		m.refreshMonitoringWorkerTree()
+		m.monitoring.OpenAnalytics(scriptName, trIdx)
+		m.monitoring.ShowAnalyticsVersions(msg.VersionIDs)
+		return *m, tea.Batch(
+			m.fetchAnalyticsCmd(scriptName, api.TimeRanges[trIdx]),
+			m.fetchVersionAnalyticsCmd(scriptName, api.TimeRanges[trIdx]),
+		), true
+
 	case uiwrangler.TailStoppedMsg:
 		// "t" key pressed while tail is active — stop it
 		m.stopTail()
@@ -172,13 +221,15 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		}
 		m.monitoring.ParallelTailAppendLines(msg.ScriptName, msg.Lines)
 		m.logExporter.WriteLines(msg.ScriptName, msg.Lines)
+		m.captureRequestLogs(msg.ScriptName, msg.Lines)
+		alertCmd := m.observeTailAlerts(msg.ScriptName, msg.Lines)
 		// Find the session to continue polling
 		for _, s := range m.parallelTailSessions {
 			if s.ScriptName == msg.ScriptName {
-				return *m, m.waitForParallelTailLines(msg.ScriptName, s), true
+				return *m, tea.Batch(m.waitForParallelTailLines(msg.ScriptName, s), alertCmd), true
 			}
 		}
-		return *m, nil, true
+		return *m, alertCmd, true
 
 	case parallelTailErrorMsg:
 		if !m.parallelTailActive {
@@ -197,6 +248,9 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 		return *m, m.navigateTo(msg.ServiceName, msg.ResourceID), true
 
 	case uiwrangler.ActionMsg:
+		if msg.Action == "deploy" {
+			return *m, m.gateDeploy(msg.EnvName), true
+		}
 		return *m, m.startWranglerCmd(msg.Action, msg.EnvName), true
 
 	case uiwrangler.OpenURLMsg:
@@ -272,11 +326,11 @@ func (m *Model) handleWranglerMsg(msg tea.Msg) (Model, tea.Cmd, bool) {
 	case uiwrangler.CmdOutputMsg:
 		if msg.IsDevCmd {
 			// Dev server output → monitoring grid + log file (no CmdPane)
-			m.handleDevOutput(msg.RunnerKey, msg.Line)
-		} else {
-			// Command output → CmdPane
-			m.handleCmdOutput(msg.RunnerKey, msg.Line)
+			alertCmd := m.handleDevOutput(msg.RunnerKey, msg.Line)
+			return *m, tea.Batch(m.waitForRunnerOutput(msg.RunnerKey, true), alertCmd), true
 		}
+		// Command output → CmdPane
+		m.handleCmdOutput(msg.RunnerKey, msg.Line)
 		// Continue reading from the runner
 		return *m, m.waitForRunnerOutput(msg.RunnerKey, msg.IsDevCmd), true
 
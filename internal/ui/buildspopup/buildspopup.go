@@ -0,0 +1,764 @@
+// Package buildspopup provides the Workers Builds dashboard overlay: recent
+// builds of one Worker or of every Worker in a monorepo, a live build log
+// for the selected build, and retry / cancel / manual build actions.
+package buildspopup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// Build is a build together with the Worker script it belongs to.
+type Build struct {
+	Script string
+	api.BuildResult
+}
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// RefreshMsg asks the app to reload the build list.
+type RefreshMsg struct{}
+
+// OpenLogMsg asks the app to fetch (and keep polling) a build's log.
+type OpenLogMsg struct {
+	Build Build
+}
+
+// RetryMsg asks the app to start a new build of the same branch and commit.
+type RetryMsg struct {
+	Build Build
+}
+
+// CancelBuildMsg asks the app to cancel a queued or running build.
+type CancelBuildMsg struct {
+	Build Build
+}
+
+// NewBuildMsg asks the app to start a manual build of a branch through the
+// Worker's trigger.
+type NewBuildMsg struct {
+	Script string
+	Branch string
+}
+
+// --- Messages received from app.go ---
+
+// LoadedMsg delivers the build list, newest first.
+type LoadedMsg struct {
+	Builds     []Build
+	Err        error
+	Restricted bool // the Builds API rejected the credentials
+}
+
+// LogMsg delivers the full log of a build so far and its current state.
+type LogMsg struct {
+	BuildUUID string
+	Lines     []string         // nil = no new log (e.g. the build is still queued)
+	Build     *api.BuildResult // fresh status; nil if it couldn't be fetched
+	Err       error
+}
+
+// ActionDoneMsg reports the result of a retry, cancel or manual build.
+type ActionDoneMsg struct {
+	Status string
+	Err    error
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeLog
+	modeBranch
+	modeConfirmCancel
+)
+
+// Model is the builds dashboard state.
+type Model struct {
+	title   string
+	scripts []string // Workers whose builds are listed
+	multi   bool     // more than one Worker: show the Worker column
+
+	builds  []Build
+	loading bool
+	err     string
+	cursor  int
+	scroll  int
+
+	mode       mode
+	returnMode mode // where confirm / branch input go back to
+
+	// Log view
+	logBuild   Build
+	logLines   []string
+	logLoading bool
+	logErr     string
+	logScroll  int
+	follow     bool // keep the view at the end as lines stream in
+
+	// Manual build
+	input       textinput.Model
+	inputScript string
+
+	status    string // feedback of the last action
+	statusErr bool
+	spinner   spinner.Model
+	height    int // list rows that fit
+	logHeight int // log lines that fit
+}
+
+// New creates the dashboard for the given Worker scripts.
+func New(title string, scripts []string) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	ti := textinput.New()
+	ti.Placeholder = "main"
+	ti.CharLimit = 200
+	ti.Width = 40
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+
+	return Model{
+		title:     title,
+		scripts:   scripts,
+		multi:     len(scripts) > 1,
+		loading:   true,
+		input:     ti,
+		spinner:   s,
+		height:    10,
+		logHeight: 10,
+	}
+}
+
+// bodyHeight returns the rows left for the list or log in a terminal of the
+// given height, after borders, padding, title, separators, status and help.
+func bodyHeight(termHeight int) int {
+	if h := termHeight - 14; h > 5 {
+		return h
+	}
+	return 5
+}
+
+// SetSize fits the list and log to the terminal height.
+func (m *Model) SetSize(termHeight int) {
+	h := bodyHeight(termHeight)
+	m.height = h - 2    // column header and range footer
+	m.logHeight = h - 6 // build metadata and streaming footer
+	if m.logHeight < 3 {
+		m.logHeight = 3
+	}
+	m.clampScroll()
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Scripts returns the Worker scripts whose builds are listed.
+func (m Model) Scripts() []string {
+	return m.scripts
+}
+
+// HasActiveBuilds returns true if any listed build is queued or running.
+func (m Model) HasActiveBuilds() bool {
+	for _, b := range m.builds {
+		if b.Active() {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamingBuild returns the build whose log is open, if it is still active
+// and its log should keep being polled.
+func (m Model) StreamingBuild() (Build, bool) {
+	if m.mode == modeList || m.logBuild.BuildUUID == "" || m.logErr != "" {
+		return Build{}, false
+	}
+	return m.logBuild, m.logLoading || m.logBuild.Active()
+}
+
+// selected returns the build under the cursor.
+func (m Model) selected() (Build, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.builds) {
+		return Build{}, false
+	}
+	return m.builds[m.cursor], true
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadedMsg:
+		m.loading = false
+		m.err = ""
+		if msg.Err != nil {
+			if msg.Restricted {
+				m.err = "Builds API unavailable — add a fallback token with Workers CI Read for this account"
+			} else {
+				m.err = msg.Err.Error()
+			}
+			return m, nil
+		}
+		// Keep the cursor on the same build across refreshes
+		var keep string
+		if b, ok := m.selected(); ok {
+			keep = b.BuildUUID
+		}
+		m.builds = msg.Builds
+		m.cursor = 0
+		for i, b := range m.builds {
+			if b.BuildUUID == keep {
+				m.cursor = i
+			}
+		}
+		m.clampScroll()
+		return m, nil
+
+	case LogMsg:
+		if msg.BuildUUID != m.logBuild.BuildUUID {
+			return m, nil
+		}
+		m.logLoading = false
+		if msg.Err != nil {
+			m.logErr = msg.Err.Error()
+			return m, nil
+		}
+		m.logErr = ""
+		if msg.Build != nil {
+			m.logBuild.BuildResult = *msg.Build
+			for i := range m.builds {
+				if m.builds[i].BuildUUID == msg.BuildUUID {
+					m.builds[i].BuildResult = *msg.Build
+				}
+			}
+		}
+		if msg.Lines != nil {
+			m.logLines = msg.Lines
+		}
+		if m.follow {
+			m.logScroll = m.maxLogScroll()
+		}
+		return m, nil
+
+	case ActionDoneMsg:
+		m.status = msg.Status
+		m.statusErr = msg.Err != nil
+		if msg.Err != nil {
+			m.status = msg.Err.Error()
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeBranch:
+			return m.updateBranch(msg)
+		case modeConfirmCancel:
+			return m.updateConfirmCancel(msg)
+		case modeLog:
+			return m.updateLog(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		m.clampScroll()
+	case "down", "j":
+		if m.cursor < len(m.builds)-1 {
+			m.cursor++
+		}
+		m.clampScroll()
+	case "R":
+		m.loading = true
+		return m, func() tea.Msg { return RefreshMsg{} }
+	case "enter":
+		if b, ok := m.selected(); ok {
+			return m.openLog(b)
+		}
+	case "r":
+		return m.retry(modeList)
+	case "x":
+		return m.confirmCancel(modeList)
+	case "n":
+		return m.startBranchInput(modeList)
+	}
+	return m, nil
+}
+
+func (m Model) updateLog(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace":
+		m.mode = modeList
+		m.logBuild = Build{}
+		m.logLines = nil
+		return m, nil
+	case "up", "k":
+		m.follow = false
+		if m.logScroll > 0 {
+			m.logScroll--
+		}
+	case "down", "j":
+		if m.logScroll < m.maxLogScroll() {
+			m.logScroll++
+		}
+		m.follow = m.logScroll == m.maxLogScroll()
+	case "pgup":
+		m.follow = false
+		m.logScroll -= m.logHeight
+		if m.logScroll < 0 {
+			m.logScroll = 0
+		}
+	case "pgdown":
+		m.logScroll += m.logHeight
+		if m.logScroll > m.maxLogScroll() {
+			m.logScroll = m.maxLogScroll()
+		}
+		m.follow = m.logScroll == m.maxLogScroll()
+	case "home", "g":
+		m.follow = false
+		m.logScroll = 0
+	case "end", "G", "f":
+		m.follow = true
+		m.logScroll = m.maxLogScroll()
+	case "r":
+		return m.retry(modeLog)
+	case "x":
+		return m.confirmCancel(modeLog)
+	}
+	return m, nil
+}
+
+func (m Model) updateBranch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = m.returnMode
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		branch := strings.TrimSpace(m.input.Value())
+		if branch == "" {
+			branch = m.input.Placeholder
+		}
+		script := m.inputScript
+		m.mode = m.returnMode
+		m.input.Blur()
+		m.setStatus(fmt.Sprintf("Starting a build of %s for %s...", branch, script))
+		return m, func() tea.Msg { return NewBuildMsg{Script: script, Branch: branch} }
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateConfirmCancel(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		b := m.target()
+		m.mode = m.returnMode
+		m.setStatus("Cancelling build " + shortID(b.BuildUUID) + "...")
+		return m, func() tea.Msg { return CancelBuildMsg{Build: b} }
+	case "n", "N", "esc":
+		m.mode = m.returnMode
+	}
+	return m, nil
+}
+
+// target returns the build actions apply to: the open log's, else the
+// selected one.
+func (m Model) target() Build {
+	if m.mode == modeLog || m.returnMode == modeLog {
+		return m.logBuild
+	}
+	b, _ := m.selected()
+	return b
+}
+
+func (m Model) openLog(b Build) (Model, tea.Cmd) {
+	m.mode = modeLog
+	m.logBuild = b
+	m.logLines = nil
+	m.logErr = ""
+	m.logLoading = true
+	m.logScroll = 0
+	m.follow = true
+	return m, func() tea.Msg { return OpenLogMsg{Build: b} }
+}
+
+func (m Model) retry(from mode) (Model, tea.Cmd) {
+	m.returnMode = from
+	b := m.target()
+	if b.BuildUUID == "" {
+		return m, nil
+	}
+	if b.Active() {
+		m.setError("Build is still running — cancel it first")
+		return m, nil
+	}
+	m.setStatus(fmt.Sprintf("Retrying %s on %s...", b.Script, branchOf(b)))
+	return m, func() tea.Msg { return RetryMsg{Build: b} }
+}
+
+func (m Model) confirmCancel(from mode) (Model, tea.Cmd) {
+	m.returnMode = from
+	b := m.target()
+	if b.BuildUUID == "" {
+		return m, nil
+	}
+	if !b.Active() {
+		m.setError("Only queued or running builds can be cancelled")
+		return m, nil
+	}
+	m.mode = modeConfirmCancel
+	return m, nil
+}
+
+func (m Model) startBranchInput(from mode) (Model, tea.Cmd) {
+	if len(m.scripts) == 0 {
+		return m, nil
+	}
+	m.returnMode = from
+	m.inputScript = m.scripts[0]
+	m.input.Placeholder = "main"
+	if b, ok := m.selected(); ok {
+		m.inputScript = b.Script
+		if branch := b.BuildTriggerMetadata.Branch; branch != "" {
+			m.input.Placeholder = branch
+		}
+	}
+	m.input.SetValue("")
+	m.input.Focus()
+	m.mode = modeBranch
+	return m, textinput.Blink
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+func (m *Model) clampScroll() {
+	if m.cursor < m.scroll {
+		m.scroll = m.cursor
+	}
+	if m.cursor >= m.scroll+m.height {
+		m.scroll = m.cursor - m.height + 1
+	}
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+}
+
+func (m Model) maxLogScroll() int {
+	if n := len(m.logLines) - m.logHeight; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 4 / 5
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 140 {
+		popupWidth = 140
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	var title string
+	var body []string
+	var help string
+	if m.mode == modeLog || (m.returnMode == modeLog && m.mode != modeList) {
+		title = theme.TitleStyle.Render("  Build Log — " + m.logBuild.Script)
+		body = m.viewLog(innerWidth)
+		help = "  esc back  |  ↑/↓ scroll  |  f follow  |  r retry  |  x cancel build"
+	} else {
+		title = theme.TitleStyle.Render("  Builds — " + m.title)
+		body = m.viewList(innerWidth)
+		help = "  esc close  |  enter log  |  r retry  |  x cancel build  |  n new build  |  R refresh"
+	}
+
+	switch m.mode {
+	case modeBranch:
+		body = append(body, "", fmt.Sprintf("  %s  %s", theme.SelectedItemStyle.Render("Branch to build for "+m.inputScript+":"), m.input.View()))
+		help = "  esc cancel  |  enter start build"
+	case modeConfirmCancel:
+		body = append(body, "", theme.ErrorStyle.Render(fmt.Sprintf("  Cancel build %s of %s? (y/n)", shortID(m.target().BuildUUID), m.target().Script)))
+		help = "  y cancel build  |  n keep it running"
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		parts = append(parts, lineStyle.Render(style.Render("  "+m.status)))
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewList(width int) []string {
+	if m.loading && len(m.builds) == 0 {
+		return []string{fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Loading builds..."))}
+	}
+	if m.err != "" {
+		return []string{theme.ErrorStyle.Render("  " + m.err)}
+	}
+	if len(m.builds) == 0 {
+		return []string{
+			theme.DimStyle.Render("  No builds yet."),
+			theme.DimStyle.Render("  Connect a repository with Setup CI/CD, or press n to start a manual build."),
+		}
+	}
+
+	// Fixed columns; the commit message takes what's left
+	scriptW := 0
+	if m.multi {
+		scriptW = 20
+	}
+	const branchW, commitW, ageW, durW = 18, 7, 9, 7
+	msgW := width - 4 - scriptW - branchW - commitW - ageW - durW - 12
+	if msgW < 10 {
+		msgW = 10
+	}
+
+	lines := []string{theme.DimStyle.Render(fmt.Sprintf("    %s%-*s  %-*s  %-*s  %*s",
+		pad("WORKER", scriptW), branchW, "BRANCH", commitW, "COMMIT", msgW, "MESSAGE", ageW+durW+2, fmt.Sprintf("%*s  %*s", ageW, "STARTED", durW, "TOOK")))}
+
+	end := m.scroll + m.height
+	if end > len(m.builds) {
+		end = len(m.builds)
+	}
+	for i := m.scroll; i < end; i++ {
+		b := m.builds[i]
+		meta := b.BuildTriggerMetadata
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		row := fmt.Sprintf("%s%-*s  %-*s  %-*s  %*s  %*s",
+			pad(truncate(b.Script, scriptW-2), scriptW),
+			branchW, truncate(meta.Branch, branchW),
+			commitW, truncate(meta.CommitHash, commitW),
+			msgW, truncate(firstLine(meta.CommitMessage), msgW),
+			ageW, timeAgo(b.CreatedOn),
+			durW, duration(b.BuildResult))
+		lines = append(lines, fmt.Sprintf("%s%s %s", cursor, m.stateIcon(b.BuildResult), style.Render(row)))
+	}
+	if len(m.builds) > m.height {
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("  %d–%d of %d builds", m.scroll+1, end, len(m.builds))))
+	}
+	return lines
+}
+
+func (m Model) viewLog(width int) []string {
+	b := m.logBuild
+	meta := b.BuildTriggerMetadata
+	label := func(k, v string) string {
+		return fmt.Sprintf("  %s %s", theme.DimStyle.Render(fmt.Sprintf("%-8s", k)), theme.ValueStyle.Render(v))
+	}
+
+	lines := []string{
+		fmt.Sprintf("  %s %s  %s", m.stateIcon(b.BuildResult), stateStyle(b.State()).Render(b.State()),
+			theme.DimStyle.Render(fmt.Sprintf("%s · started %s · took %s", shortID(b.BuildUUID), timeAgo(b.CreatedOn), duration(b.BuildResult)))),
+		label("Branch", meta.Branch),
+		label("Commit", truncate(meta.CommitHash, 10)+"  "+truncate(firstLine(meta.CommitMessage), width-24)),
+	}
+	if meta.Author != "" {
+		lines = append(lines, label("Author", meta.Author))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case m.logErr != "":
+		return append(lines, theme.ErrorStyle.Render("  "+m.logErr))
+	case m.logLoading && len(m.logLines) == 0:
+		return append(lines, fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Loading build log...")))
+	case len(m.logLines) == 0:
+		return append(lines, theme.DimStyle.Render("  No log output yet"))
+	}
+
+	start := m.logScroll
+	if start > m.maxLogScroll() {
+		start = m.maxLogScroll()
+	}
+	end := start + m.logHeight
+	if end > len(m.logLines) {
+		end = len(m.logLines)
+	}
+	for _, l := range m.logLines[start:end] {
+		lines = append(lines, "  "+theme.NormalItemStyle.Render(truncate(l, width-2)))
+	}
+	if b.Active() {
+		tail := "streaming"
+		if !m.follow {
+			tail = "streaming — press f to follow"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render(tail)))
+	}
+	return lines
+}
+
+// stateIcon renders the status glyph of a build.
+func (m Model) stateIcon(r api.BuildResult) string {
+	switch r.State() {
+	case "success":
+		return theme.SuccessStyle.Render("✓")
+	case "failure", "failed":
+		return theme.ErrorStyle.Render("✗")
+	case "canceled", "cancelled":
+		return theme.DimStyle.Render("–")
+	case "running", "initializing":
+		return m.spinner.View()
+	}
+	return theme.DimStyle.Render("○")
+}
+
+// stateStyle colors a build state.
+func stateStyle(state string) lipgloss.Style {
+	switch state {
+	case "success":
+		return lipgloss.NewStyle().Foreground(theme.ColorGreen)
+	case "failure", "failed":
+		return lipgloss.NewStyle().Foreground(theme.ColorRed)
+	case "running", "initializing":
+		return lipgloss.NewStyle().Foreground(theme.ColorYellow)
+	}
+	return theme.DimStyle
+}
+
+// --- Helpers ---
+
+func branchOf(b Build) string {
+	if b.BuildTriggerMetadata.Branch == "" {
+		return "its branch"
+	}
+	return b.BuildTriggerMetadata.Branch
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// duration formats how long a build ran (so far).
+func duration(r api.BuildResult) string {
+	if r.RunningOn == nil {
+		return "—"
+	}
+	end := time.Now()
+	if r.StoppedOn != nil {
+		end = *r.StoppedOn
+	}
+	d := end.Sub(*r.RunningOn).Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// timeAgo formats a time relative to now (e.g. "5m ago").
+func timeAgo(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+func pad(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-*s", width, s)
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 1 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -20,7 +20,7 @@ type step int
 const (
 	stepDetecting    step = iota // Auto-detecting git repo
 	stepNoGit                    // No git repo found — error state
-	stepNoInstall                // GitHub/GitLab installation not found
+	stepNoInstall                // GitHub/GitLab/Bitbucket installation not found
 	stepCheckInstall             // Checking if installation exists (via config_autofill)
 	stepConfigure                // Form: branch, build cmd, deploy cmd, root dir, watch paths
 	stepReview                   // Summary before applying
@@ -46,9 +46,10 @@ const (
 // --- Messages emitted by this component ---
 
 // CheckInstallMsg requests the app to call GetConfigAutofill to verify the
-// GitHub/GitLab installation and fetch auto-detected config.
+// GitHub/GitLab/Bitbucket installation and fetch auto-detected config.
 type CheckInstallMsg struct {
-	Provider          string // "github" or "gitlab"
+	Provider          string // "github", "gitlab" or "bitbucket"
+	Host              string // remote host (self-hosted instances resolve IDs there)
 	ProviderAccountID string // owner
 	RepoID            string // repo name
 	Branch            string
@@ -114,7 +115,7 @@ type Model struct {
 	scriptName string // worker script name
 	envName    string // wrangler environment name
 
-	// Resolved numeric IDs from GitHub/GitLab API (set by CheckInstallDoneMsg)
+	// Resolved IDs from the GitHub/GitLab/Bitbucket API (set by CheckInstallDoneMsg)
 	ownerID string // numeric provider account/user ID
 	repoID  string // numeric provider repo ID
 
@@ -184,7 +185,10 @@ func New(gitInfo *wcfg.GitInfo, scriptName, envName, projectDir string) Model {
 
 	if gitInfo.ProviderType == "" || gitInfo.Owner == "" || gitInfo.RepoName == "" {
 		m.step = stepNoGit
-		m.resultMsg = "Could not detect GitHub or GitLab remote from git config"
+		m.resultMsg = "Could not detect a GitHub, GitLab or Bitbucket remote from git config.\n" +
+			"For a self-hosted instance, map its host in ~/.orangeshell/config.toml:\n\n" +
+			"  [git_hosts]\n" +
+			"  \"git.example.com\" = \"gitlab\""
 		return m
 	}
 
@@ -210,6 +214,7 @@ func (m Model) Init() tea.Cmd {
 			func() tea.Msg {
 				return CheckInstallMsg{
 					Provider:          m.gitInfo.ProviderType,
+					Host:              m.gitInfo.Host,
 					ProviderAccountID: m.gitInfo.Owner,
 					RepoID:            m.gitInfo.RepoName,
 					Branch:            m.gitInfo.Branch,
@@ -303,18 +308,18 @@ func (m Model) handleSetupDone(msg SetupCICDDoneMsg) (Model, tea.Cmd) {
 		// Non-auth error from repo connection.
 		if strings.Contains(errStr, "creating repo connection:") {
 			if strings.Contains(errStr, "disconnected") || strings.Contains(errStr, "8000008") {
-				// The GitHub/GitLab App installation is in a broken state.
+				// The GitHub/GitLab/Bitbucket App installation is in a broken state.
 				// This is a known Cloudflare issue that requires reinstallation.
 				m.step = stepResult
 				m.resultIsErr = true
 				m.resultMsg = "Git integration is disconnected.\n\n" +
-					"Cloudflare's link to your GitHub/GitLab account is broken.\n" +
+					fmt.Sprintf("Cloudflare's link to your %s account is broken.\n", m.gitInfo.ProviderLabel()) +
 					"This is a known issue that requires reinstalling the integration:\n\n" +
-					"  1. Go to github.com/settings/installations\n" +
+					fmt.Sprintf("  1. Go to %s\n", installPage(m.gitInfo)) +
 					"  2. Find 'Cloudflare Workers' → Configure → Uninstall\n" +
 					"  3. In the Cloudflare dashboard, go to any Worker →\n" +
 					"     Settings → Builds → Connect to Git\n" +
-					"  4. Re-authorize the Cloudflare GitHub App\n" +
+					fmt.Sprintf("  4. Re-authorize the Cloudflare %s App\n", m.gitInfo.ProviderLabel()) +
 					"  5. Return here and run this wizard again"
 				return m, nil
 			}
@@ -590,12 +595,13 @@ func (m Model) viewNoGit(w int) string {
 
 	explanation := theme.SubtitleStyle.Render(
 		"Workers Builds CI/CD requires a Git repository with a\n" +
-			"GitHub or GitLab remote to trigger automated deployments.\n\n" +
+			"GitHub, GitLab or Bitbucket remote to trigger automated deployments.\n" +
+			"Self-hosted hosts can be mapped under [git_hosts] in config.toml.\n\n" +
 			"To get started:\n\n" +
 			"  1. Initialize a git repository:  " + theme.ValueStyle.Render("git init") + "\n" +
 			"  2. Add a remote:                 " + theme.ValueStyle.Render("git remote add origin <url>") + "\n" +
 			"  3. Push your code:               " + theme.ValueStyle.Render("git push -u origin main") + "\n" +
-			"  4. Connect your GitHub/GitLab account to Cloudflare\n" +
+			"  4. Connect your Git provider account to Cloudflare\n" +
 			"     via the web dashboard (one-time setup)\n" +
 			"  5. Run this wizard again")
 
@@ -618,12 +624,7 @@ func (m Model) viewNoInstall(w int) string {
 	title := theme.TitleStyle.Render("Setup CI/CD")
 	sep := theme.DimStyle.Render(strings.Repeat("─", w))
 
-	providerLabel := "GitHub"
-	installPage := "github.com/settings/installations"
-	if m.gitInfo.ProviderType == "gitlab" {
-		providerLabel = "GitLab"
-		installPage = "gitlab.com/-/profile/applications"
-	}
+	providerLabel := m.gitInfo.ProviderLabel()
 
 	header := theme.ErrorStyle.Render(
 		fmt.Sprintf("%s integration not found for this Cloudflare account.", providerLabel))
@@ -645,7 +646,7 @@ func (m Model) viewNoInstall(w int) string {
 			providerLabel,
 			theme.LabelStyle.Render(m.dashboardURL),
 			providerLabel,
-			theme.DimStyle.Render(installPage)))
+			theme.DimStyle.Render(installPage(m.gitInfo))))
 
 	help := theme.DimStyle.Render("esc close")
 
@@ -718,10 +719,7 @@ func (m Model) viewReview(w int) string {
 	title := theme.TitleStyle.Render("Review CI/CD Configuration")
 	sep := theme.DimStyle.Render(strings.Repeat("─", w))
 
-	providerLabel := "GitHub"
-	if m.gitInfo.ProviderType == "gitlab" {
-		providerLabel = "GitLab"
-	}
+	providerLabel := m.gitInfo.ProviderLabel()
 
 	lines := []string{
 		fmt.Sprintf("  Repository    %s %s/%s", providerLabel, m.gitInfo.Owner, m.gitInfo.RepoName),
@@ -851,3 +849,23 @@ func splitCommaSeparated(s string) []string {
 	}
 	return result
 }
+
+// installPage returns where the user can review the Cloudflare app's access
+// on their Git provider (the instance itself for self-hosted hosts).
+func installPage(g *wcfg.GitInfo) string {
+	host := g.Host
+	switch g.ProviderType {
+	case "gitlab":
+		if host == "" {
+			host = "gitlab.com"
+		}
+		return host + "/-/profile/applications"
+	case "bitbucket":
+		return "bitbucket.org/account/settings/app-authorizations/"
+	default:
+		if host == "" {
+			host = "github.com"
+		}
+		return host + "/settings/installations"
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -203,10 +204,14 @@ func (m Model) viewBindingsList() []string {
 			arrow = " " + theme.ActionNavArrowStyle.Render("->")
 		}
 
-		// Resource ID
-		resourceID := theme.DimStyle.Render(b.Binding.ResourceID)
-		if len(b.Binding.ResourceID) > boxWidth-40 && boxWidth > 43 {
-			resourceID = theme.DimStyle.Render(b.Binding.ResourceID[:boxWidth-43] + "...")
+		// Resource ID, with a rate limiter's limit
+		id := b.Binding.ResourceID
+		if b.Binding.Type == "ratelimit" && b.Binding.DisplayName != "" {
+			id += " (" + b.Binding.DisplayName + ")"
+		}
+		resourceID := theme.DimStyle.Render(id)
+		if len(id) > boxWidth-40 && boxWidth > 43 {
+			resourceID = theme.DimStyle.Render(id[:boxWidth-43] + "...")
 		}
 
 		line := fmt.Sprintf("%s%s %s %s %s%s",
@@ -306,6 +311,12 @@ var allBindingTypes = []bindingTypeEntry{
 	{"images", "Images", "form", "Cloudflare Images (singleton)"},
 	{"mtls_certificate", "mTLS", "picker", "mTLS certificate"},
 	{"workflow", "Workflow", "picker", "Workflow binding (scans source for classes)"},
+	{"secrets_store_secret", "Secrets Store", "form", "Secrets Store secret"},
+	{"ratelimit", "Rate Limit", "form", "Rate limiter (unsafe binding)"},
+	{"dispatch_namespace", "Dispatch", "form", "Workers for Platforms dispatch namespace"},
+	{"send_email", "Email", "form", "Send Email binding"},
+	{"pipeline", "Pipeline", "form", "Pipelines binding"},
+	{"container", "Container", "form", "Container (backed by a Durable Object class)"},
 }
 
 // bindingFormField describes a single field in the inline binding form.
@@ -381,10 +392,70 @@ func bindingFormFields(writerType string) []bindingFormField {
 			{"class_name", "MyWorkflow (must match exported class)", true},
 			{"script_name", "other-worker (optional, if external)", false},
 		}
+	case "secrets_store_secret":
+		return []bindingFormField{
+			{"binding", "MY_SECRET (binding name)", true},
+			{"store_id", "store-id (Secrets Store ID)", true},
+			{"secret_name", "my-secret (secret name in the store)", true},
+		}
+	case "ratelimit":
+		return []bindingFormField{
+			{"binding", "MY_RATE_LIMITER (binding name)", true},
+			{"namespace_id", "1001 (unique namespace ID)", true},
+			{"limit", "100 (requests per period)", true},
+			{"period", "60 (seconds: 10 or 60)", true},
+		}
+	case "dispatch_namespace":
+		return []bindingFormField{
+			{"binding", "DISPATCHER (binding name)", true},
+			{"namespace", "my-namespace (dispatch namespace)", true},
+		}
+	case "send_email":
+		return []bindingFormField{
+			{"binding", "EMAIL (binding name)", true},
+			{"destination_address", "user@example.com (optional, single recipient)", false},
+			{"allowed_destination_addresses", "a@example.com, b@example.com (optional)", false},
+		}
+	case "pipeline":
+		return []bindingFormField{
+			{"binding", "MY_PIPELINE (binding name)", true},
+			{"pipeline", "my-pipeline (pipeline name)", true},
+		}
+	case "container":
+		return []bindingFormField{
+			{"class_name", "MyContainer (Durable Object class)", true},
+			{"image", "./Dockerfile (path or registry image)", true},
+			{"max_instances", "5 (optional)", false},
+		}
 	}
 	return nil
 }
 
+// validateBindingForm checks type-specific field formats after the required
+// fields are present. Returns an error message, or "" if the values are valid.
+func validateBindingForm(writerType string, values map[string]string) string {
+	switch writerType {
+	case "ratelimit":
+		if n, err := strconv.Atoi(values["limit"]); err != nil || n <= 0 {
+			return "limit must be a positive number"
+		}
+		if p := values["period"]; p != "10" && p != "60" {
+			return "period must be 10 or 60"
+		}
+	case "send_email":
+		if values["destination_address"] != "" && values["allowed_destination_addresses"] != "" {
+			return "set destination_address or allowed_destination_addresses, not both"
+		}
+	case "container":
+		if v := values["max_instances"]; v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+				return "max_instances must be a positive number"
+			}
+		}
+	}
+	return ""
+}
+
 // pickerResourceType maps a writer type to the resource type string used in
 // ListBindingResourcesMsg. Returns "" if the type doesn't use a picker.
 func pickerResourceType(writerType string) string {
@@ -567,6 +638,10 @@ func (m Model) submitBindingForm() (Model, tea.Cmd) {
 		}
 		values[f.Name] = val
 	}
+	if errMsg := validateBindingForm(m.addBindingType, values); errMsg != "" {
+		m.errMsg = errMsg
+		return m, nil
+	}
 
 	// Build BindingDef
 	def := m.buildBindingDef(m.addBindingType, values)
@@ -627,6 +702,28 @@ func (m Model) buildBindingDef(writerType string, values map[string]string) wcfg
 		if sn := values["script_name"]; sn != "" {
 			def.ExtraFields = map[string]string{"script_name": sn}
 		}
+	case "secrets_store_secret":
+		def.ResourceID = values["store_id"]
+		def.ResourceName = values["secret_name"]
+	case "ratelimit":
+		def.ResourceID = values["namespace_id"]
+		def.ExtraFields = map[string]string{"limit": values["limit"], "period": values["period"]}
+	case "dispatch_namespace":
+		def.ResourceID = values["namespace"]
+	case "send_email":
+		def.ResourceID = values["destination_address"]
+		if list := values["allowed_destination_addresses"]; list != "" {
+			def.ExtraFields = map[string]string{"allowed_destination_addresses": list}
+		}
+	case "pipeline":
+		def.ResourceID = values["pipeline"]
+	case "container":
+		// Containers have no JS binding — they're keyed by their DO class
+		def.BindingName = values["class_name"]
+		def.ResourceID = values["image"]
+		if n := values["max_instances"]; n != "" {
+			def.ExtraFields = map[string]string{"max_instances": n}
+		}
 	}
 
 	return def
@@ -874,6 +971,18 @@ func typeColor(label string) lipgloss.Color {
 		return lipgloss.Color("#F87171") // light red
 	case "Workflow":
 		return lipgloss.Color("#2DD4BF") // teal
+	case "Secrets Store":
+		return lipgloss.Color("#E879F9") // fuchsia
+	case "Rate Limit":
+		return lipgloss.Color("#FCA5A5") // rose
+	case "Dispatch":
+		return lipgloss.Color("#93C5FD") // light blue
+	case "Email":
+		return lipgloss.Color("#A3E635") // lime
+	case "Pipeline":
+		return lipgloss.Color("#22D3EE") // cyan
+	case "Container":
+		return lipgloss.Color("#94A3B8") // slate
 	default:
 		return theme.ColorGray
 	}
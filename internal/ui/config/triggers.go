@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/oarafat/orangeshell/internal/ui/theme"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
 )
 
 // --- Cron presets (same as triggers package) ---
@@ -124,9 +125,8 @@ func (m Model) updateTriggersCustom(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.errMsg = "Cron expression cannot be empty"
 			return m, nil
 		}
-		parts := strings.Fields(cron)
-		if len(parts) != 5 {
-			m.errMsg = "Cron must have 5 fields: min hour day month weekday"
+		if err := wcfg.ValidateCron(cron); err != nil {
+			m.errMsg = "Invalid cron: " + err.Error()
 			return m, nil
 		}
 		for _, existing := range m.triggersCrons {
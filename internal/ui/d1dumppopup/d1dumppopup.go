@@ -0,0 +1,509 @@
+// Package d1dumppopup provides the D1 backup overlay: export a database to a
+// local .sql file, import a .sql file into it, take a snapshot now and toggle
+// scheduled snapshots. It works for remote and local (dev session) databases.
+package d1dumppopup
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// Info describes the database and its snapshot state.
+type Info struct {
+	Name       string
+	Local      bool
+	ExportDir  string        // default directory for exports
+	Scheduled  bool          // included in scheduled snapshots
+	Every      time.Duration // snapshot interval
+	Keep       int           // snapshots kept per database
+	Snapshots  []string      // existing snapshot files, newest first
+	NoSettings bool          // no project open to hold .orangeshell.toml
+}
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// ExportMsg asks the app to export the database to Path.
+type ExportMsg struct {
+	Mode service.D1DumpMode
+	Path string
+}
+
+// ImportMsg asks the app to run the statements of a .sql file.
+type ImportMsg struct {
+	Path string
+}
+
+// SnapshotMsg asks the app to take a snapshot now.
+type SnapshotMsg struct{}
+
+// ScheduleMsg asks the app to add or remove the database from scheduled
+// snapshots.
+type ScheduleMsg struct {
+	Enabled bool
+}
+
+// CancelMsg asks the app to stop the running export or import.
+type CancelMsg struct{}
+
+// --- Messages received from app.go ---
+
+// ProgressMsg reports progress of the running job. Total is 0 when the job
+// can't be measured.
+type ProgressMsg struct {
+	Text        string
+	Done, Total int
+}
+
+// DoneMsg reports the end of a job. Line and SQL locate a failed import's
+// statement when known.
+type DoneMsg struct {
+	Status string
+	Err    error
+	Line   int
+	SQL    string
+}
+
+// InfoMsg refreshes the snapshot state after a snapshot or schedule change.
+type InfoMsg struct {
+	Info Info
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modeMenu mode = iota
+	modeExport
+	modeImport
+	modeConfirmImport
+	modeRunning
+)
+
+const (
+	itemExport = iota
+	itemImport
+	itemSnapshot
+	itemSchedule
+	itemCount
+)
+
+var dumpModes = []service.D1DumpMode{service.D1DumpFull, service.D1DumpSchema, service.D1DumpData}
+
+// Model is the D1 backup popup state.
+type Model struct {
+	info Info
+
+	mode     mode
+	cursor   int
+	dumpMode int // index into dumpModes
+	path     textinput.Model
+	formErr  string
+
+	progress  ProgressMsg
+	status    string
+	statusErr bool
+	errSQL    string // failing statement of the last import
+	spinner   spinner.Model
+}
+
+// New creates the popup for a database.
+func New(info Info) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	ti := textinput.New()
+	ti.CharLimit = 500
+	ti.Width = 60
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+
+	return Model{info: info, path: ti, spinner: s}
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Info returns the database the popup works on.
+func (m Model) Info() Info {
+	return m.info
+}
+
+// Running reports whether an export or import is in progress.
+func (m Model) Running() bool {
+	return m.mode == modeRunning
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ProgressMsg:
+		m.progress = msg
+		return m, nil
+
+	case DoneMsg:
+		m.mode = modeMenu
+		m.errSQL = ""
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+			if msg.SQL != "" {
+				m.errSQL = msg.SQL
+			}
+			return m, nil
+		}
+		m.setStatus(msg.Status)
+		return m, nil
+
+	case InfoMsg:
+		m.info = msg.Info
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeExport, modeImport:
+			return m.updateForm(msg)
+		case modeConfirmImport:
+			return m.updateConfirmImport(msg)
+		case modeRunning:
+			if msg.String() == "esc" {
+				m.setStatus("Cancelling...")
+				return m, func() tea.Msg { return CancelMsg{} }
+			}
+			return m, nil
+		}
+		return m.updateMenu(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < itemCount-1 {
+			m.cursor++
+		}
+	case "enter":
+		m.errSQL = ""
+		switch m.cursor {
+		case itemExport:
+			m.mode = modeExport
+			m.formErr = ""
+			m.path.SetValue(m.defaultExportPath())
+			m.path.CursorEnd()
+			return m, m.path.Focus()
+		case itemImport:
+			m.mode = modeImport
+			m.formErr = ""
+			m.path.SetValue("")
+			m.path.Placeholder = "path/to/dump.sql"
+			return m, m.path.Focus()
+		case itemSnapshot:
+			m.startJob("Taking snapshot...")
+			return m, func() tea.Msg { return SnapshotMsg{} }
+		case itemSchedule:
+			if m.info.NoSettings {
+				m.setError("Open a project first — scheduled snapshots are kept in its .orangeshell.toml")
+				return m, nil
+			}
+			enabled := !m.info.Scheduled
+			return m, func() tea.Msg { return ScheduleMsg{Enabled: enabled} }
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateForm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeMenu
+		m.path.Blur()
+		return m, nil
+	case "tab", "shift+tab":
+		if m.mode != modeExport {
+			return m, nil
+		}
+		// Cycle the dump mode, keeping a custom file name untouched
+		wasDefault := m.path.Value() == m.defaultExportPath()
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(dumpModes) - 1
+		}
+		m.dumpMode = (m.dumpMode + step) % len(dumpModes)
+		if wasDefault {
+			m.path.SetValue(m.defaultExportPath())
+			m.path.CursorEnd()
+		}
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.path.Value())
+		if path == "" {
+			m.formErr = "A file path is required"
+			return m, nil
+		}
+		m.path.Blur()
+		if m.mode == modeImport {
+			m.mode = modeConfirmImport
+			return m, nil
+		}
+		mode := dumpModes[m.dumpMode]
+		m.startJob(fmt.Sprintf("Exporting %s (%s)...", m.info.Name, mode))
+		return m, func() tea.Msg { return ExportMsg{Mode: mode, Path: path} }
+	}
+	var cmd tea.Cmd
+	m.path, cmd = m.path.Update(msg)
+	m.formErr = ""
+	return m, cmd
+}
+
+func (m Model) updateConfirmImport(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		path := strings.TrimSpace(m.path.Value())
+		m.startJob(fmt.Sprintf("Importing %s...", filepath.Base(path)))
+		return m, func() tea.Msg { return ImportMsg{Path: path} }
+	case "n", "N", "esc":
+		m.mode = modeMenu
+	}
+	return m, nil
+}
+
+func (m *Model) startJob(text string) {
+	m.mode = modeRunning
+	m.progress = ProgressMsg{Text: text}
+	m.status = ""
+	m.statusErr = false
+	m.errSQL = ""
+}
+
+// defaultExportPath names an export after the database, mode and time.
+func (m Model) defaultExportPath() string {
+	suffix := ""
+	switch dumpModes[m.dumpMode] {
+	case service.D1DumpSchema:
+		suffix = "-schema"
+	case service.D1DumpData:
+		suffix = "-data"
+	}
+	if m.info.Local {
+		suffix += "-local"
+	}
+	file := fmt.Sprintf("%s%s-%s.sql", m.info.Name, suffix, time.Now().Format("20060102-150405"))
+	return filepath.Join(m.info.ExportDir, file)
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 3 / 4
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 100 {
+		popupWidth = 100
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	where := "remote"
+	if m.info.Local {
+		where = "local"
+	}
+	title := theme.TitleStyle.Render(fmt.Sprintf("  D1 Backup — %s [%s]", m.info.Name, where))
+
+	var body []string
+	var help string
+	switch m.mode {
+	case modeExport:
+		body = m.viewExport()
+		help = "  tab change contents  |  enter export  |  esc back"
+	case modeImport:
+		body = m.viewImport()
+		help = "  enter continue  |  esc back"
+	case modeConfirmImport:
+		body = []string{
+			theme.ErrorStyle.Render(fmt.Sprintf("  Run every statement of %s against %s (%s)?", filepath.Base(strings.TrimSpace(m.path.Value())), m.info.Name, where)),
+			theme.DimStyle.Render("  Statements applied before an error are not rolled back."),
+		}
+		help = "  y import  |  n cancel"
+	case modeRunning:
+		body = m.viewRunning(innerWidth)
+		help = "  esc cancel"
+	default:
+		body = m.viewMenu()
+		help = "  ↑/↓ select  |  enter run  |  esc close"
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		parts = append(parts, lineStyle.Render(style.Render("  "+m.status)))
+		if m.errSQL != "" {
+			parts = append(parts, lineStyle.Render(theme.DimStyle.Render("  "+firstLine(m.errSQL))))
+		}
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewMenu() []string {
+	schedule := "Scheduled snapshots: off"
+	if m.info.Scheduled {
+		schedule = "Scheduled snapshots: on"
+	}
+	items := [itemCount][2]string{
+		itemExport:   {"Export...", "schema, data or both to a .sql file"},
+		itemImport:   {"Import...", "run a .sql file against this database"},
+		itemSnapshot: {"Snapshot now", "write a full dump to the snapshot directory"},
+		itemSchedule: {schedule, fmt.Sprintf("every %s, keeping %d", formatEvery(m.info.Every), m.info.Keep)},
+	}
+	var lines []string
+	for i, it := range items {
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		lines = append(lines, "  "+cursor+style.Render(fmt.Sprintf("%-26s", it[0]))+theme.DimStyle.Render(it[1]))
+	}
+
+	lines = append(lines, "")
+	if len(m.info.Snapshots) == 0 {
+		lines = append(lines, theme.DimStyle.Render("  No snapshots yet."))
+		return lines
+	}
+	lines = append(lines, theme.SubtitleStyle.Render(fmt.Sprintf("  Snapshots in %s", filepath.Dir(m.info.Snapshots[0]))))
+	for i, f := range m.info.Snapshots {
+		if i == 5 {
+			lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("    … %d more", len(m.info.Snapshots)-i)))
+			break
+		}
+		lines = append(lines, theme.DimStyle.Render("    "+filepath.Base(f)))
+	}
+	return lines
+}
+
+func (m Model) viewExport() []string {
+	var modes []string
+	for i, d := range dumpModes {
+		if i == m.dumpMode {
+			modes = append(modes, theme.SelectedItemStyle.Render("["+d.String()+"]"))
+		} else {
+			modes = append(modes, theme.DimStyle.Render(" "+d.String()+" "))
+		}
+	}
+	lines := []string{
+		theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "Contents")) + strings.Join(modes, " "),
+		theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "File")) + m.path.View(),
+	}
+	if m.formErr != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.formErr))
+	}
+	return lines
+}
+
+func (m Model) viewImport() []string {
+	lines := []string{
+		theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "File")) + m.path.View(),
+		"",
+		theme.DimStyle.Render("  Statements run in order; BEGIN/COMMIT lines are skipped."),
+	}
+	if m.formErr != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.formErr))
+	}
+	return lines
+}
+
+func (m Model) viewRunning(width int) []string {
+	lines := []string{fmt.Sprintf("  %s %s", m.spinner.View(), theme.ValueStyle.Render(m.progress.Text))}
+	if m.progress.Total > 0 {
+		barW := width - 20
+		if barW > 50 {
+			barW = 50
+		}
+		filled := barW * m.progress.Done / m.progress.Total
+		bar := lipgloss.NewStyle().Foreground(theme.ColorOrange).Render(strings.Repeat("█", filled)) +
+			theme.DimStyle.Render(strings.Repeat("░", barW-filled))
+		lines = append(lines, "", fmt.Sprintf("  %s %s", bar,
+			theme.DimStyle.Render(fmt.Sprintf("%d/%d", m.progress.Done, m.progress.Total))))
+	}
+	return lines
+}
+
+// --- Helpers ---
+
+func formatEvery(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return d.String()
+	}
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
@@ -0,0 +1,861 @@
+// Package d1gridpopup provides the D1 table browser overlay: page through a
+// table's rows by its primary key, edit cells, insert and delete rows, then
+// review the generated parameterized SQL before it runs as one transaction.
+package d1gridpopup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// pageSize is the number of rows shown per page.
+const pageSize = 50
+
+// maxCellWidth caps the width of a grid column.
+const maxCellWidth = 24
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// LoadPageMsg asks the app to run a page query.
+type LoadPageMsg struct {
+	Query service.D1Statement
+}
+
+// ApplyMsg asks the app to run the statements as a single transaction.
+type ApplyMsg struct {
+	Statements []service.D1Statement
+}
+
+// --- Messages received from app.go ---
+
+// PageMsg carries the rows of a page query.
+type PageMsg struct {
+	Rows [][]interface{}
+	Err  error
+}
+
+// AppliedMsg reports the outcome of an ApplyMsg. Unmatched lists the
+// updates and deletes that were applied but changed no row, or several.
+type AppliedMsg struct {
+	Unmatched []service.D1Statement
+	Err       error
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modeTables mode = iota
+	modeLoading
+	modeGrid
+	modeEdit
+	modeReview
+	modeRunning
+)
+
+// rowEdit is the pending change of a loaded row.
+type rowEdit struct {
+	key     []interface{}
+	values  map[string]*string // column → new value, nil for NULL
+	deleted bool
+}
+
+// Model is the table browser state.
+type Model struct {
+	name   string
+	tables []service.SchemaTable
+
+	mode   mode
+	cursor int // table list cursor
+	width  int
+	height int
+
+	// The open table
+	table   service.SchemaTable
+	key     []string
+	columns []string // rows carry the key values as text first, then these
+
+	starts [][]interface{} // key after which each page up to the current one starts
+	rows   [][]interface{}
+	more   bool
+
+	edits   map[string]*rowEdit // keyed by rowKey
+	order   []string            // edits in the order they were made
+	inserts []map[string]*string
+
+	row, col     int // grid cursor; rows past the page are inserts
+	rowOff       int
+	colOff       int
+	input        textinput.Model
+	review       []service.D1Statement
+	discardArmed bool
+
+	status    string
+	statusErr bool
+	spinner   spinner.Model
+}
+
+// New creates the table browser for the database name with its tables.
+func New(name string, tables []service.SchemaTable) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	ti := textinput.New()
+	ti.CharLimit = 0
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+	ti.Placeholder = "value"
+	return Model{name: name, tables: tables, input: ti, spinner: s}
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// SetSize sets the terminal size used to fit the grid.
+func (m *Model) SetSize(width, height int) {
+	m.width, m.height = width, height
+}
+
+// Table returns the name of the open table.
+func (m Model) Table() string {
+	return m.table.Name
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case PageMsg:
+		if m.mode != modeLoading {
+			return m, nil
+		}
+		if msg.Err != nil {
+			if len(m.starts) > 1 {
+				m.starts = m.starts[:len(m.starts)-1]
+				m.mode = modeGrid
+			} else {
+				m.mode = modeTables
+			}
+			m.setError(msg.Err.Error())
+			return m, nil
+		}
+		m.mode = modeGrid
+		m.rows, m.more = msg.Rows, len(msg.Rows) > pageSize
+		if m.more {
+			m.rows = m.rows[:pageSize]
+		}
+		m.row, m.rowOff = 0, 0
+		m.clampCursor()
+		return m, nil
+
+	case AppliedMsg:
+		if m.mode != modeRunning {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.mode = modeReview
+			m.setError("Nothing was applied: " + msg.Err.Error())
+			return m, nil
+		}
+		n := len(m.review)
+		m.clearChanges()
+		if len(msg.Unmatched) > 0 {
+			m.setError(fmt.Sprintf("Applied %d statement%s, but %d matched no single row (changed since loaded?): %s",
+				n, plural(n), len(msg.Unmatched), msg.Unmatched[0].String()))
+		} else {
+			m.setStatus(fmt.Sprintf("Applied %d statement%s", n, plural(n)))
+		}
+		return m.loadPage()
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeTables:
+			return m.updateTables(msg)
+		case modeGrid:
+			return m.updateGrid(msg)
+		case modeEdit:
+			return m.updateEdit(msg)
+		case modeReview:
+			return m.updateReview(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateTables(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.tables)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if m.cursor >= len(m.tables) {
+			return m, nil
+		}
+		m.openTable(m.tables[m.cursor])
+		return m.loadPage()
+	}
+	return m, nil
+}
+
+func (m Model) updateGrid(msg tea.KeyMsg) (Model, tea.Cmd) {
+	key := msg.String()
+	if key != "esc" {
+		m.discardArmed = false
+	}
+	if len(m.rows)+len(m.inserts) == 0 {
+		switch key {
+		case "enter", "e", "d", "u":
+			return m, nil
+		}
+	}
+	switch key {
+	case "esc", "q":
+		if m.hasChanges() && !m.discardArmed {
+			m.discardArmed = true
+			m.setError("Unsaved changes — press esc again to discard them")
+			return m, nil
+		}
+		m.discardArmed = false
+		m.mode = modeTables
+		m.status = ""
+	case "up", "k":
+		m.row--
+	case "down", "j":
+		m.row++
+	case "left", "h":
+		m.col--
+	case "right", "l":
+		m.col++
+	case "home":
+		m.col = 0
+	case "end":
+		m.col = len(m.columns) - 1
+	case "pgdown", "n":
+		if !m.more || len(m.rows) == 0 {
+			return m, nil
+		}
+		m.starts = append(m.starts, m.keyOf(m.rows[len(m.rows)-1]))
+		return m.loadPage()
+	case "pgup", "p":
+		if len(m.starts) < 2 {
+			return m, nil
+		}
+		m.starts = m.starts[:len(m.starts)-1]
+		return m.loadPage()
+	case "enter", "e":
+		if m.rowDeleted(m.row) || len(m.columns) == 0 {
+			return m, nil
+		}
+		v := m.cellValue(m.row, m.col)
+		m.input.SetValue("")
+		if v != nil {
+			m.input.SetValue(*v)
+		}
+		m.input.CursorEnd()
+		m.mode = modeEdit
+		m.status = ""
+		return m, m.input.Focus()
+	case "a":
+		m.inserts = append(m.inserts, map[string]*string{})
+		m.row = len(m.rows) + len(m.inserts) - 1
+	case "d":
+		if m.row >= len(m.rows) {
+			i := m.row - len(m.rows)
+			m.inserts = append(m.inserts[:i], m.inserts[i+1:]...)
+		} else if len(m.rows) > 0 {
+			e := m.editFor(m.rows[m.row])
+			e.deleted = !e.deleted
+			if !e.deleted && len(e.values) == 0 {
+				m.dropEdit(rowKey(e.key))
+			}
+		}
+	case "u":
+		if m.row >= len(m.rows) {
+			m.inserts[m.row-len(m.rows)] = map[string]*string{}
+		} else if len(m.rows) > 0 {
+			m.dropEdit(rowKey(m.keyOf(m.rows[m.row])))
+		}
+	case "r":
+		m.review = m.statements()
+		if len(m.review) == 0 {
+			m.setStatus("No changes to review")
+			return m, nil
+		}
+		m.mode = modeReview
+		m.rowOff = 0
+		m.status = ""
+		return m, nil
+	}
+	m.clampCursor()
+	return m, nil
+}
+
+func (m Model) updateEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeGrid
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		v := m.input.Value()
+		m.setCell(&v)
+		m.mode = modeGrid
+		m.input.Blur()
+		return m, nil
+	case "ctrl+n":
+		m.setCell(nil)
+		m.mode = modeGrid
+		m.input.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateReview(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeGrid
+		m.status = ""
+		m.rowOff = 0
+		m.clampCursor()
+	case "up", "k":
+		if m.rowOff > 0 {
+			m.rowOff--
+		}
+	case "down", "j":
+		if m.rowOff < len(m.review)-1 {
+			m.rowOff++
+		}
+	case "enter", "y":
+		m.mode = modeRunning
+		m.setStatus("")
+		stmts := m.review
+		return m, tea.Batch(m.spinner.Tick, func() tea.Msg { return ApplyMsg{Statements: stmts} })
+	}
+	return m, nil
+}
+
+// openTable resets the grid for t.
+func (m *Model) openTable(t service.SchemaTable) {
+	m.table = t
+	m.key = service.RowKey(t)
+	m.columns = make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		m.columns[i] = c.Name
+	}
+	m.starts = [][]interface{}{nil}
+	m.rows, m.more = nil, false
+	m.row, m.col, m.rowOff, m.colOff = 0, 0, 0, 0
+	m.clearChanges()
+	m.status = ""
+}
+
+// loadPage asks for the current page.
+func (m Model) loadPage() (Model, tea.Cmd) {
+	m.mode = modeLoading
+	q := service.RowPageQuery(m.table.Name, m.key, m.starts[len(m.starts)-1], pageSize+1)
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg { return LoadPageMsg{Query: q} })
+}
+
+func (m *Model) clearChanges() {
+	m.edits = make(map[string]*rowEdit)
+	m.order = nil
+	m.inserts = nil
+	m.review = nil
+	m.discardArmed = false
+}
+
+func (m Model) hasChanges() bool {
+	return len(m.order) > 0 || len(m.inserts) > 0
+}
+
+// keyOf returns the key column values of a loaded row, as text.
+func (m Model) keyOf(row []interface{}) []interface{} {
+	key := make([]interface{}, len(m.key))
+	for i := range m.key {
+		key[i] = cell(row, i)
+	}
+	return key
+}
+
+// rowKey identifies a row by its key values.
+func rowKey(key []interface{}) string {
+	parts := make([]string, len(key))
+	for i, v := range key {
+		if v == nil {
+			parts[i] = "\x00"
+		} else {
+			parts[i] = service.ParamValue(v)
+		}
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+func (m *Model) editFor(row []interface{}) *rowEdit {
+	key := m.keyOf(row)
+	k := rowKey(key)
+	if e, ok := m.edits[k]; ok {
+		return e
+	}
+	e := &rowEdit{key: key, values: make(map[string]*string)}
+	m.edits[k] = e
+	m.order = append(m.order, k)
+	return e
+}
+
+func (m *Model) dropEdit(k string) {
+	delete(m.edits, k)
+	for i, o := range m.order {
+		if o == k {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m Model) rowDeleted(r int) bool {
+	if r >= len(m.rows) {
+		return false
+	}
+	e, ok := m.edits[rowKey(m.keyOf(m.rows[r]))]
+	return ok && e.deleted
+}
+
+// loadedValue returns a loaded cell as text, nil for NULL.
+func (m Model) loadedValue(r, c int) *string {
+	v := cell(m.rows[r], c+len(m.key))
+	if v == nil {
+		return nil
+	}
+	s := service.ParamValue(v)
+	return &s
+}
+
+// cellValue returns a cell with its pending edit applied, and whether it
+// was edited.
+func (m Model) cellValue(r, c int) *string {
+	v, _ := m.cellState(r, c)
+	return v
+}
+
+func (m Model) cellState(r, c int) (*string, bool) {
+	col := m.columns[c]
+	if r >= len(m.rows) {
+		v, ok := m.inserts[r-len(m.rows)][col]
+		return v, ok
+	}
+	if e, ok := m.edits[rowKey(m.keyOf(m.rows[r]))]; ok {
+		if v, ok := e.values[col]; ok {
+			return v, true
+		}
+	}
+	return m.loadedValue(r, c), false
+}
+
+// setCell records an edit of the cell under the cursor.
+func (m *Model) setCell(v *string) {
+	col := m.columns[m.col]
+	if m.row >= len(m.rows) {
+		m.inserts[m.row-len(m.rows)][col] = v
+		return
+	}
+	e := m.editFor(m.rows[m.row])
+	if sameValue(v, m.loadedValue(m.row, m.col)) {
+		delete(e.values, col)
+		if len(e.values) == 0 && !e.deleted {
+			m.dropEdit(rowKey(e.key))
+		}
+		return
+	}
+	e.values[col] = v
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// statements generates the SQL for the pending changes: updates and
+// deletes in the order they were made, then inserts.
+func (m Model) statements() []service.D1Statement {
+	var changes []service.RowChange
+	for _, k := range m.order {
+		e := m.edits[k]
+		if e.deleted {
+			changes = append(changes, service.RowChange{Kind: service.RowDelete, Key: e.key})
+			continue
+		}
+		changes = append(changes, service.RowChange{Kind: service.RowUpdate, Key: e.key, Values: e.values, Columns: m.ordered(e.values)})
+	}
+	for _, ins := range m.inserts {
+		changes = append(changes, service.RowChange{Kind: service.RowInsert, Values: ins, Columns: m.ordered(ins)})
+	}
+	return service.RowChangeStatements(m.table.Name, m.key, changes)
+}
+
+// ordered returns the columns set in values, in table order.
+func (m Model) ordered(values map[string]*string) []string {
+	var cols []string
+	for _, c := range m.columns {
+		if _, ok := values[c]; ok {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// clampCursor keeps the cursor on a cell and in view.
+func (m *Model) clampCursor() {
+	total := len(m.rows) + len(m.inserts)
+	if m.row >= total {
+		m.row = total - 1
+	}
+	if m.row < 0 {
+		m.row = 0
+	}
+	if m.col >= len(m.columns) {
+		m.col = len(m.columns) - 1
+	}
+	if m.col < 0 {
+		m.col = 0
+	}
+
+	visible := m.gridHeight()
+	if m.row < m.rowOff {
+		m.rowOff = m.row
+	}
+	if m.row >= m.rowOff+visible {
+		m.rowOff = m.row - visible + 1
+	}
+
+	if m.col < m.colOff {
+		m.colOff = m.col
+	}
+	for m.colOff < m.col && m.lastVisibleCol() < m.col {
+		m.colOff++
+	}
+}
+
+// lastVisibleCol returns the last column that fits from colOff.
+func (m Model) lastVisibleCol() int {
+	widths := m.colWidths()
+	used := 0
+	last := m.colOff
+	for c := m.colOff; c < len(widths); c++ {
+		used += widths[c] + 3
+		if used > m.innerWidth() && c > m.colOff {
+			break
+		}
+		last = c
+	}
+	return last
+}
+
+func (m Model) gridHeight() int {
+	h := m.height - 16
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+func (m Model) popupWidth() int {
+	w := m.width - 8
+	if w < 60 {
+		w = 60
+	}
+	return w
+}
+
+func (m Model) innerWidth() int {
+	return m.popupWidth() - 6 // border (2) + padding (4)
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+func cell(row []interface{}, i int) interface{} {
+	if i < len(row) {
+		return row[i]
+	}
+	return nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	m.width = termWidth
+	innerWidth := m.innerWidth()
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	titleText := fmt.Sprintf("  D1 Table Browser — %s", m.name)
+	if m.mode != modeTables {
+		titleText += " · " + m.table.Name
+	}
+	title := theme.TitleStyle.Render(titleText)
+
+	var body []string
+	var help string
+	switch m.mode {
+	case modeLoading:
+		body = []string{fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Loading rows..."))}
+	case modeGrid, modeEdit:
+		body = m.viewGrid()
+		help = "  ←↑↓→ move  |  enter edit  |  a add row  |  d delete  |  u undo row  |  pgup/pgdn page  |  r review  |  esc back"
+		if m.mode == modeEdit {
+			help = "  enter set  |  ctrl+n set NULL  |  esc cancel"
+		}
+	case modeReview, modeRunning:
+		body = m.viewReview()
+		help = "  ↑/↓ scroll  |  enter apply as one transaction  |  esc back to grid"
+	default:
+		body = m.viewTables()
+		help = "  ↑/↓ select  |  enter browse  |  esc close"
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		parts = append(parts, lineStyle.Render(style.Render("  "+m.status)))
+	}
+	if help != "" {
+		parts = append(parts, lineStyle.Render(theme.DimStyle.Render(help)))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(m.popupWidth()).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewTables() []string {
+	if len(m.tables) == 0 {
+		return []string{theme.DimStyle.Render("  No tables found. Wait for the schema to load, or create a table in the SQL console.")}
+	}
+	lines := []string{theme.DimStyle.Render("  Browse table:"), ""}
+	for i, t := range m.tables {
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		key := service.RowKey(t)
+		lines = append(lines, "  "+cursor+style.Render(fmt.Sprintf("%-32s", t.Name))+
+			theme.DimStyle.Render(fmt.Sprintf("%d columns · key %s", len(t.Columns), strings.Join(key, ", "))))
+	}
+	return lines
+}
+
+// colWidths sizes each column to its header and visible values.
+func (m Model) colWidths() []int {
+	widths := make([]int, len(m.columns))
+	for c, name := range m.columns {
+		widths[c] = lipgloss.Width(name)
+		for r := 0; r < len(m.rows)+len(m.inserts); r++ {
+			if w := lipgloss.Width(displayValue(m.cellValue(r, c))); w > widths[c] {
+				widths[c] = w
+			}
+		}
+		if widths[c] > maxCellWidth {
+			widths[c] = maxCellWidth
+		}
+		if widths[c] < 4 {
+			widths[c] = 4
+		}
+	}
+	return widths
+}
+
+func (m Model) viewGrid() []string {
+	page := len(m.starts)
+	info := fmt.Sprintf("  Page %d · %d rows", page, len(m.rows))
+	if m.more {
+		info += " · more"
+	}
+	if n := len(m.order) + len(m.inserts); n > 0 {
+		info += fmt.Sprintf(" · %d pending change%s", n, plural(n))
+	}
+	lines := []string{theme.DimStyle.Render(info), ""}
+	if len(m.columns) == 0 {
+		return append(lines, theme.DimStyle.Render("  The table has no columns."))
+	}
+
+	widths := m.colWidths()
+	last := m.lastVisibleCol()
+
+	var header []string
+	for c := m.colOff; c <= last; c++ {
+		name := pad(m.columns[c], widths[c])
+		if m.isKey(m.columns[c]) {
+			header = append(header, theme.D1SchemaPKTagStyle.Render(name))
+		} else {
+			header = append(header, theme.LabelStyle.Render(name))
+		}
+	}
+	lines = append(lines, "  "+strings.Join(header, " │ "))
+
+	total := len(m.rows) + len(m.inserts)
+	if total == 0 {
+		return append(lines, theme.DimStyle.Render("  No rows — press a to add one."))
+	}
+	end := m.rowOff + m.gridHeight()
+	if end > total {
+		end = total
+	}
+	for r := m.rowOff; r < end; r++ {
+		marker := "  "
+		deleted := m.rowDeleted(r)
+		switch {
+		case r >= len(m.rows):
+			marker = lipgloss.NewStyle().Foreground(theme.ColorGreen).Render("+ ")
+		case deleted:
+			marker = theme.ErrorStyle.Render("- ")
+		}
+		var cells []string
+		for c := m.colOff; c <= last; c++ {
+			v, edited := m.cellState(r, c)
+			text := pad(displayValue(v), widths[c])
+			if r == m.row && c == m.col && m.mode == modeEdit {
+				text = m.input.View()
+			}
+			style := lipgloss.NewStyle()
+			switch {
+			case deleted:
+				style = theme.ErrorStyle.Strikethrough(true)
+			case r >= len(m.rows):
+				style = style.Foreground(theme.ColorGreen)
+			case edited:
+				style = style.Foreground(theme.ColorYellow)
+			case v == nil:
+				style = theme.DimStyle
+			}
+			if r == m.row && c == m.col && m.mode != modeEdit {
+				style = style.Reverse(true)
+			}
+			cells = append(cells, style.Render(text))
+		}
+		lines = append(lines, marker+strings.Join(cells, " │ "))
+	}
+	if end < total {
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("  … %d more rows", total-end)))
+	}
+	return lines
+}
+
+func (m Model) viewReview() []string {
+	lines := []string{
+		theme.SubtitleStyle.Render(fmt.Sprintf("  %d statement%s, run as one transaction:", len(m.review), plural(len(m.review)))),
+		"",
+	}
+	end := m.rowOff + m.gridHeight()
+	if end > len(m.review) {
+		end = len(m.review)
+	}
+	for _, st := range m.review[m.rowOff:end] {
+		lines = append(lines, "  "+theme.ValueStyle.Render(st.SQL))
+		if len(st.Params) > 0 {
+			quoted := make([]string, len(st.Params))
+			for i, p := range st.Params {
+				quoted[i] = fmt.Sprintf("%q", p)
+			}
+			lines = append(lines, theme.DimStyle.Render("    params: "+strings.Join(quoted, ", ")))
+		}
+	}
+	if end < len(m.review) {
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("  … %d more statements", len(m.review)-end)))
+	}
+	if m.mode == modeRunning {
+		lines = append(lines, "", fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Applying...")))
+	}
+	return lines
+}
+
+func (m Model) isKey(col string) bool {
+	for _, k := range m.key {
+		if k == col {
+			return true
+		}
+	}
+	return false
+}
+
+func displayValue(v *string) string {
+	if v == nil {
+		return "NULL"
+	}
+	return strings.ReplaceAll(*v, "\n", "↵")
+}
+
+// pad truncates or pads s to exactly width cells.
+func pad(s string, width int) string {
+	if lipgloss.Width(s) > width {
+		runes := []rune(s)
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[:len(runes)-1]
+		}
+		return string(runes) + "…"
+	}
+	return s + strings.Repeat(" ", width-lipgloss.Width(s))
+}
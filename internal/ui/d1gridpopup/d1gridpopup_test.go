@@ -0,0 +1,75 @@
+package d1gridpopup
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/service"
+)
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	case "ctrl+n":
+		return tea.KeyMsg{Type: tea.KeyCtrlN}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestPendingChanges(t *testing.T) {
+	users := service.SchemaTable{Name: "users", Columns: []service.SchemaColumn{
+		{Name: "id", Type: "INTEGER", PK: true},
+		{Name: "email", Type: "TEXT"},
+	}}
+	m := New("db", []service.SchemaTable{users})
+	m.SetSize(120, 40)
+
+	m, _ = m.Update(key("enter"))
+	// Rows lead with their key as text: row 2's id is past float64 precision
+	m, _ = m.Update(PageMsg{Rows: [][]interface{}{
+		{"1", float64(1), "a@x.y"},
+		{"9007199254740993", float64(9007199254740992), "b@x.y"},
+	}})
+
+	// Edit row 1's email, then set it back: no change is left
+	m, _ = m.Update(key("right"))
+	m, _ = m.Update(key("enter"))
+	m.input.SetValue("new@x.y")
+	m, _ = m.Update(key("enter"))
+	m, _ = m.Update(key("enter"))
+	m.input.SetValue("a@x.y")
+	m, _ = m.Update(key("enter"))
+	if m.hasChanges() {
+		t.Fatal("restoring the original value should drop the edit")
+	}
+
+	// NULL row 1's email, delete row 2, add a row
+	m, _ = m.Update(key("enter"))
+	m, _ = m.Update(key("ctrl+n"))
+	m, _ = m.Update(key("down"))
+	m, _ = m.Update(key("d"))
+	m, _ = m.Update(key("a"))
+	m, _ = m.Update(key("enter"))
+	m.input.SetValue("c@x.y")
+	m, _ = m.Update(key("enter"))
+
+	var got []string
+	for _, st := range m.statements() {
+		got = append(got, st.String())
+	}
+	want := []string{
+		`UPDATE "users" SET "email" = NULL WHERE "id" = ?;  -- "1"`,
+		`DELETE FROM "users" WHERE "id" = ?;  -- "9007199254740993"`,
+		`INSERT INTO "users" ("email") VALUES (?);  -- "c@x.y"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("statements:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -123,9 +123,25 @@ type Model struct {
 	// D1 SQL console state
 	d1Input      textinput.Model // SQL text input
 	d1Active     bool            // true when D1 console is initialized
-	d1Output     []string        // accumulated output lines (query results)
+	d1Output     []d1Line        // accumulated output lines (query results)
 	d1Querying   bool            // true while a query is in flight
 	d1DatabaseID string          // current database UUID
+	d1XOff       int             // output columns scrolled off to the left
+	d1YOff       int             // output lines scrolled up from the bottom
+
+	// D1 console history, saved queries and paging
+	d1HistoryKey string            // history file name for this database
+	d1History    []string          // past queries, oldest first
+	d1HistIdx    int               // recalled entry while browsing with ↑/↓, else len(d1History)
+	d1HistDraft  string            // input typed before browsing history
+	d1Searching  bool              // ctrl+r history search is open
+	d1Search     string            // history search query
+	d1SearchIdx  int               // selected search match
+	d1Saved      map[string]string // saved queries of this database, by name
+	d1LastSQL    string            // last query run, for .save
+	d1Pending    d1Page            // how to read the result of the query in flight
+	d1Page       d1Page            // paging of the last result
+	d1LastSet    *service.D1ResultSet
 
 	// D1 Schema pane state
 	d1SchemaTables  []service.SchemaTable // structured schema data (nil = not loaded)
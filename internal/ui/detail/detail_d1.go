@@ -32,6 +32,10 @@ func (m *Model) InitD1Console(databaseID string) tea.Cmd {
 	m.d1DatabaseID = databaseID
 	m.d1Output = nil
 	m.d1Querying = false
+	m.resetD1Console()
+	m.d1HistoryKey = d1HistoryKeyFor(databaseID)
+	m.d1History = loadD1History(m.d1HistoryKey)
+	m.d1HistIdx = len(m.d1History)
 	if !preserveSchema {
 		m.d1SchemaTables = nil
 		m.d1SchemaErr = ""
@@ -59,6 +63,12 @@ func (m Model) D1Active() bool {
 	return m.d1Active
 }
 
+// D1SchemaTables returns the loaded schema of the D1 database, nil while it
+// loads.
+func (m Model) D1SchemaTables() []service.SchemaTable {
+	return m.d1SchemaTables
+}
+
 // SetD1Schema sets the schema data for the D1 detail view.
 func (m *Model) SetD1Schema(tables []service.SchemaTable, err error) {
 	m.d1SchemaLoading = false
@@ -80,17 +90,12 @@ func (m *Model) SetD1SchemaLoading() {
 func (m *Model) SetD1QueryResult(result *service.D1QueryResult, err error) {
 	m.d1Querying = false
 	if err != nil {
-		m.d1Output = append(m.d1Output, theme.ErrorStyle.Render(fmt.Sprintf("Error: %s", err)))
-		m.d1Output = append(m.d1Output, "")
+		m.d1Print(d1LineError, fmt.Sprintf("Error: %s", err))
+		m.d1Print(d1LineText, "")
 		return
 	}
-	// Append the output lines
-	outputLines := strings.Split(result.Output, "\n")
-	m.d1Output = append(m.d1Output, outputLines...)
-	if result.Meta != "" {
-		m.d1Output = append(m.d1Output, theme.D1MetaStyle.Render(result.Meta))
-	}
-	m.d1Output = append(m.d1Output, "") // blank separator between queries
+	m.printD1Result(result)
+	m.d1Print(d1LineText, "") // blank separator between queries
 }
 
 // ClearD1 resets all D1 console state (used on navigation away).
@@ -102,38 +107,89 @@ func (m *Model) ClearD1() {
 	m.d1SchemaTables = nil
 	m.d1SchemaErr = ""
 	m.d1SchemaLoading = false
+	m.resetD1Console()
+	m.d1HistoryKey = ""
+	m.d1History = nil
+	m.d1HistIdx = 0
+	m.d1Saved = nil
 	m.d1Input.Blur()
 }
 
+// resetD1Console clears the per-session console state.
+func (m *Model) resetD1Console() {
+	m.d1XOff, m.d1YOff = 0, 0
+	m.d1HistDraft = ""
+	m.d1Searching = false
+	m.d1Search = ""
+	m.d1SearchIdx = 0
+	m.d1LastSQL = ""
+	m.d1Pending = d1Page{}
+	m.d1Page = d1Page{}
+	m.d1LastSet = nil
+}
+
 // updateD1 handles key events when the D1 SQL console is active.
 func (m Model) updateD1(msg tea.KeyMsg) (Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEsc:
+	if m.d1Searching {
+		return m.updateD1Search(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
 		// Exit interactive mode, switch focus to list pane
 		m.interacting = false
 		m.focus = FocusList
 		return m, nil
-	case tea.KeyEnter:
-		// Submit the SQL query
+	case "enter":
+		// Submit the SQL query or console command
 		sql := strings.TrimSpace(m.d1Input.Value())
 		if sql == "" || m.d1Querying {
 			return m, nil
 		}
-		m.d1Querying = true
-		m.d1Output = append(m.d1Output, theme.D1PromptStyle.Render("sql> ")+theme.ValueStyle.Render(sql))
 		m.d1Input.Reset()
-
-		// Route to local or remote handler
-		if m.isLocalResource && m.activeLocalResource != nil {
-			lr := *m.activeLocalResource
-			return m, func() tea.Msg {
-				return LocalD1QueryMsg{LocalResource: lr, SQL: sql}
-			}
+		m.d1HistDraft = ""
+		saveCmd := m.addD1History(sql)
+		m.d1Output = append(m.d1Output, d1Line{kind: d1LinePrompt, text: sql})
+		var cmd tea.Cmd
+		if strings.HasPrefix(sql, ".") {
+			m, cmd = m.runD1Command(sql)
+		} else {
+			m.d1LastSQL = sql
+			m, cmd = m.runD1Query(sql, 0)
 		}
-		dbID := m.d1DatabaseID
-		return m, func() tea.Msg {
-			return D1QueryMsg{DatabaseID: dbID, SQL: sql}
+		return m, tea.Batch(saveCmd, cmd)
+	case "up":
+		m.recallD1History(-1)
+		return m, nil
+	case "down":
+		m.recallD1History(1)
+		return m, nil
+	case "ctrl+r":
+		m.d1Searching = true
+		m.d1Search = ""
+		m.d1SearchIdx = 0
+		return m, nil
+	case "pgdown":
+		return m.turnD1Page(1)
+	case "pgup":
+		return m.turnD1Page(-1)
+	case "shift+right":
+		m.d1XOff += 8
+		return m, nil
+	case "shift+left":
+		m.d1XOff -= 8
+		if m.d1XOff < 0 {
+			m.d1XOff = 0
+		}
+		return m, nil
+	case "shift+up":
+		m.d1YOff++
+		return m, nil
+	case "shift+down":
+		if m.d1YOff > 0 {
+			m.d1YOff--
 		}
+		return m, nil
 	}
 
 	// Forward all other keys to the textinput
@@ -263,7 +319,10 @@ func (m Model) renderD1SQLConsole(width, height int) []string {
 	header := theme.D1SchemaTitleStyle.Render("SQL Console")
 
 	// Help at the bottom
-	help := theme.DimStyle.Render("esc back | enter query")
+	help := theme.DimStyle.Render("esc back | enter run | ↑↓ history | ctrl+r search | .help")
+	if m.d1XOff > 0 || m.d1YOff > 0 {
+		help = theme.DimStyle.Render(fmt.Sprintf("scrolled ←%d ↑%d | shift+arrows scroll", m.d1XOff, m.d1YOff))
+	}
 
 	// Input line
 	inputLine := m.d1Input.View()
@@ -277,20 +336,22 @@ func (m Model) renderD1SQLConsole(width, height int) []string {
 		outputHeight = 1
 	}
 
-	// Build output lines, wrapped/truncated to width
+	// Build output lines, scrolled and truncated to width
 	var outputLines []string
-	for _, line := range m.d1Output {
-		// Truncate long lines to fit the pane width
-		if utf8.RuneCountInString(line) > width-1 {
-			runes := []rune(line)
-			line = string(runes[:width-2]) + "…"
+	if m.d1Searching {
+		outputLines = m.renderD1Search(width, outputHeight)
+	} else {
+		end := len(m.d1Output) - m.d1YOff
+		if end < 0 {
+			end = 0
+		}
+		start := end - outputHeight
+		if start < 0 {
+			start = 0
+		}
+		for _, line := range m.d1Output[start:end] {
+			outputLines = append(outputLines, m.renderD1Line(line, width))
 		}
-		outputLines = append(outputLines, line)
-	}
-
-	// Show most recent output that fits (scroll to bottom)
-	if len(outputLines) > outputHeight {
-		outputLines = outputLines[len(outputLines)-outputHeight:]
 	}
 
 	// Build the pane
@@ -315,6 +376,32 @@ func (m Model) renderD1SQLConsole(width, height int) []string {
 	return lines
 }
 
+// renderD1Line styles an output line after applying the horizontal scroll.
+func (m Model) renderD1Line(line d1Line, width int) string {
+	if line.kind == d1LinePrompt {
+		return theme.D1PromptStyle.Render("sql> ") + theme.ValueStyle.Render(truncateRunes(line.text, width-6))
+	}
+	runes := []rune(line.text)
+	if m.d1XOff < len(runes) {
+		runes = runes[m.d1XOff:]
+	} else {
+		runes = nil
+	}
+	text := string(runes)
+	if utf8.RuneCountInString(text) > width-1 {
+		text = string(runes[:width-2]) + "…"
+	}
+	switch line.kind {
+	case d1LineMeta:
+		return theme.D1MetaStyle.Render(text)
+	case d1LineInfo:
+		return theme.DimStyle.Render(text)
+	case d1LineError:
+		return theme.ErrorStyle.Render(text)
+	}
+	return text
+}
+
 // renderD1SchemaPane renders the schema diagram right pane with syntax coloring.
 func (m Model) renderD1SchemaPane(width, height int) []string {
 	header := theme.D1SchemaTitleStyle.Render("Schema")
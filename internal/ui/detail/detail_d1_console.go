@@ -0,0 +1,638 @@
+package detail
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// --- D1 console: output lines, history, saved queries, paging, export ---
+
+// d1PageSize is the number of rows fetched per page of a paged query.
+const d1PageSize = 100
+
+// d1HistoryMax caps the persisted history of one database.
+const d1HistoryMax = 500
+
+// d1ResultColWidth caps the width of a result grid column.
+const d1ResultColWidth = 40
+
+// d1LineKind selects how an output line is styled.
+type d1LineKind int
+
+const (
+	d1LineText   d1LineKind = iota // result rows
+	d1LinePrompt                   // an echoed query
+	d1LineMeta                     // query stats
+	d1LineInfo                     // console notices
+	d1LineError
+)
+
+// d1Line is one line of console output, kept unstyled so it can be
+// scrolled horizontally.
+type d1Line struct {
+	kind d1LineKind
+	text string
+}
+
+// d1Page tracks a query fetched a page at a time.
+type d1Page struct {
+	sql    string // query as typed
+	paged  bool   // wrapped in LIMIT/OFFSET
+	offset int
+	more   bool // rows exist past this page
+}
+
+func (m *Model) d1Print(kind d1LineKind, text string) {
+	for _, l := range strings.Split(text, "\n") {
+		m.d1Output = append(m.d1Output, d1Line{kind: kind, text: l})
+	}
+	m.d1YOff = 0
+}
+
+// D1Notice appends a console notice, e.g. the outcome of a saved query or
+// export handled by the app.
+func (m *Model) D1Notice(text string, err error) {
+	if !m.d1Active {
+		return
+	}
+	if err != nil {
+		m.d1Print(d1LineError, fmt.Sprintf("Error: %s", err))
+	} else {
+		m.d1Print(d1LineInfo, text)
+	}
+	m.d1Print(d1LineText, "")
+}
+
+// SetD1SavedQueries sets the saved queries of the console's database.
+func (m *Model) SetD1SavedQueries(queries map[string]string) {
+	m.d1Saved = queries
+}
+
+// D1DatabaseName returns the name of the database the console runs against.
+func (m Model) D1DatabaseName() string {
+	if m.detail != nil {
+		return m.detail.Name
+	}
+	return ""
+}
+
+// --- Running queries ---
+
+// runD1Query sends a query, paging it when it's a single read query.
+func (m Model) runD1Query(sql string, offset int) (Model, tea.Cmd) {
+	query, paged := service.PagedQuery(sql, d1PageSize+1, offset)
+	m.d1Pending = d1Page{sql: sql, paged: paged, offset: offset}
+	m.d1Querying = true
+
+	// Route to local or remote handler
+	if m.isLocalResource && m.activeLocalResource != nil {
+		lr := *m.activeLocalResource
+		return m, func() tea.Msg {
+			return LocalD1QueryMsg{LocalResource: lr, SQL: query}
+		}
+	}
+	dbID := m.d1DatabaseID
+	return m, func() tea.Msg {
+		return D1QueryMsg{DatabaseID: dbID, SQL: query}
+	}
+}
+
+// turnD1Page fetches the next (dir 1) or previous (dir -1) page of the last
+// paged result.
+func (m Model) turnD1Page(dir int) (Model, tea.Cmd) {
+	p := m.d1Page
+	if !p.paged || m.d1Querying || (dir > 0 && !p.more) || (dir < 0 && p.offset == 0) {
+		return m, nil
+	}
+	offset := p.offset + dir*d1PageSize
+	if offset < 0 {
+		offset = 0
+	}
+	m.d1Print(d1LineInfo, fmt.Sprintf("-- rows %d–%d", offset+1, offset+d1PageSize))
+	return m.runD1Query(p.sql, offset)
+}
+
+// printD1Result renders a query result into the output, trimming a paged
+// result to its page.
+func (m *Model) printD1Result(result *service.D1QueryResult) {
+	p := m.d1Pending
+	p.more = false
+	if len(result.Sets) == 0 {
+		m.d1Print(d1LineText, result.Output)
+		if result.Meta != "" {
+			m.d1Print(d1LineMeta, result.Meta)
+		}
+		m.d1Page = d1Page{}
+		return
+	}
+
+	for i, set := range result.Sets {
+		if i > 0 {
+			m.d1Print(d1LineText, "")
+		}
+		if len(set.Columns) == 0 {
+			m.d1Print(d1LineText, set.Summary())
+		} else {
+			if p.paged && len(set.Rows) > d1PageSize {
+				set.Rows = set.Rows[:d1PageSize]
+				p.more = true
+			}
+			table := service.FormatASCIITableWidth(set.Columns, set.Rows, d1ResultColWidth)
+			if p.paged {
+				// Replace the row count with the page's position
+				table = table[:strings.LastIndex(table, "\n")]
+			}
+			m.d1Print(d1LineText, table)
+			if p.paged {
+				m.d1Print(d1LineMeta, d1PageFooter(p, len(set.Rows)))
+			}
+			last := set
+			m.d1LastSet = &last
+		}
+		if set.Meta != "" {
+			m.d1Print(d1LineMeta, set.Meta)
+		}
+	}
+	if p.paged {
+		m.d1Page = p
+	} else {
+		m.d1Page = d1Page{}
+	}
+}
+
+func d1PageFooter(p d1Page, rows int) string {
+	if rows == 0 {
+		return fmt.Sprintf("(no rows past %d)  pgup previous page", p.offset)
+	}
+	footer := fmt.Sprintf("(rows %d–%d", p.offset+1, p.offset+rows)
+	if !p.more {
+		footer += fmt.Sprintf(" of %d", p.offset+rows)
+	}
+	footer += ")"
+	var keys []string
+	if p.offset > 0 {
+		keys = append(keys, "pgup previous")
+	}
+	if p.more {
+		keys = append(keys, "pgdn next page")
+	}
+	if len(keys) > 0 {
+		footer += "  " + strings.Join(keys, " · ")
+	}
+	return footer
+}
+
+// --- Dot commands ---
+
+const d1ConsoleHelp = `.save NAME [SQL]        save the last query (or SQL) as NAME
+.run NAME               run a saved query
+.queries                list saved queries
+.forget NAME            delete a saved query
+.read FILE              run the statements of a .sql file
+.export FORMAT [FILE]   export the last result (csv, json, md)
+.clear                  clear the output
+↑/↓ history  ctrl+r search  pgup/pgdn page  shift+arrows scroll`
+
+// runD1Command handles a console dot command.
+func (m Model) runD1Command(line string) (Model, tea.Cmd) {
+	fields := strings.Fields(line)
+	cmd, args := fields[0], fields[1:]
+	rest := strings.TrimSpace(strings.TrimPrefix(line, cmd))
+	fail := func(format string, a ...interface{}) (Model, tea.Cmd) {
+		m.d1Print(d1LineError, fmt.Sprintf(format, a...))
+		m.d1Print(d1LineText, "")
+		return m, nil
+	}
+
+	switch cmd {
+	case ".help":
+		m.d1Print(d1LineInfo, d1ConsoleHelp)
+		m.d1Print(d1LineText, "")
+
+	case ".clear":
+		m.d1Output = nil
+		m.d1YOff, m.d1XOff = 0, 0
+
+	case ".queries":
+		if len(m.d1Saved) == 0 {
+			m.d1Print(d1LineInfo, "No saved queries — save one with .save NAME")
+		}
+		names := make([]string, 0, len(m.d1Saved))
+		for name := range m.d1Saved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m.d1Print(d1LineInfo, fmt.Sprintf("%-16s %s", name, oneLine(m.d1Saved[name])))
+		}
+		m.d1Print(d1LineText, "")
+
+	case ".save":
+		if len(args) == 0 {
+			return fail("Usage: .save NAME [SQL]")
+		}
+		name := args[0]
+		sql := strings.TrimSpace(strings.TrimPrefix(rest, name))
+		if sql == "" {
+			sql = m.d1LastSQL
+		}
+		if sql == "" {
+			return fail("Nothing to save — run a query first or give the SQL")
+		}
+		db := m.D1DatabaseName()
+		return m, func() tea.Msg { return D1SaveQueryMsg{Database: db, Name: name, SQL: sql} }
+
+	case ".forget":
+		if len(args) != 1 {
+			return fail("Usage: .forget NAME")
+		}
+		db, name := m.D1DatabaseName(), args[0]
+		return m, func() tea.Msg { return D1SaveQueryMsg{Database: db, Name: name, Delete: true} }
+
+	case ".run":
+		if len(args) != 1 {
+			return fail("Usage: .run NAME")
+		}
+		sql, ok := m.d1Saved[args[0]]
+		if !ok {
+			return fail("No saved query %q — .queries lists them", args[0])
+		}
+		m.d1Print(d1LinePrompt, sql)
+		m.d1LastSQL = sql
+		return m.runD1Query(sql, 0)
+
+	case ".read":
+		if rest == "" {
+			return fail("Usage: .read FILE")
+		}
+		data, err := os.ReadFile(expandHome(rest))
+		if err != nil {
+			return fail("Error: %s", err)
+		}
+		script := strings.TrimSpace(string(data))
+		if len(service.SplitSQLStatements(script)) == 0 {
+			return fail("%s has no SQL statements", rest)
+		}
+		m.d1Print(d1LineInfo, fmt.Sprintf("-- running %s", rest))
+		m.d1LastSQL = script
+		return m.runD1Query(script, 0)
+
+	case ".export":
+		if len(args) == 0 {
+			return fail("Usage: .export FORMAT [FILE] — FORMAT is %s", strings.Join(service.ResultFormats, ", "))
+		}
+		format := strings.ToLower(args[0])
+		if format == "markdown" {
+			format = service.ResultFormatMarkdown
+		}
+		path := strings.TrimSpace(strings.TrimPrefix(rest, args[0]))
+		return m.exportD1Result(format, path)
+
+	default:
+		return fail("Unknown command %s — .help lists commands", cmd)
+	}
+	return m, nil
+}
+
+// exportD1Result exports the last result. A paged result is fetched again
+// in full by the app; any other result is written as shown.
+func (m Model) exportD1Result(format, path string) (Model, tea.Cmd) {
+	valid := false
+	for _, f := range service.ResultFormats {
+		valid = valid || f == format
+	}
+	if !valid {
+		m.d1Print(d1LineError, fmt.Sprintf("Unknown format %q — use %s", format, strings.Join(service.ResultFormats, ", ")))
+		return m, nil
+	}
+	if m.d1LastSet == nil {
+		m.d1Print(d1LineError, "No result to export — run a query that returns rows first")
+		return m, nil
+	}
+	path = D1ExportPath(m.D1DatabaseName(), format, path)
+	m.d1Print(d1LineInfo, fmt.Sprintf("Exporting to %s...", path))
+
+	if m.d1Page.paged {
+		msg := D1ExportMsg{DatabaseID: m.d1DatabaseID, SQL: m.d1Page.sql, Format: format, Path: path}
+		if m.isLocalResource && m.activeLocalResource != nil {
+			lr := *m.activeLocalResource
+			msg.LocalResource = &lr
+		}
+		return m, func() tea.Msg { return msg }
+	}
+	set := *m.d1LastSet
+	return m, func() tea.Msg {
+		err := WriteD1Export(path, format, set)
+		return D1ExportDoneMsg{Path: path, Rows: len(set.Rows), Err: err}
+	}
+}
+
+// D1ExportPath resolves an export file name; an empty one defaults to
+// ~/.orangeshell/exports/d1-<database>-<time>.<format>.
+func D1ExportPath(database, format, path string) string {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		name := fmt.Sprintf("d1-%s-%s.%s", database, time.Now().Format("20060102-150405"), format)
+		return filepath.Join(home, ".orangeshell", "exports", name)
+	}
+	path = expandHome(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path
+}
+
+// WriteD1Export writes a result set to path in the given format.
+func WriteD1Export(path, format string, set service.D1ResultSet) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := service.WriteResultSet(f, format, set); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// --- History ---
+
+// d1HistoryKeyFor names the history file of a database: its UUID, or for a
+// local database its name and a hash of its config path.
+func d1HistoryKeyFor(databaseID string) string {
+	if !isLocalResourceID(databaseID) {
+		return databaseID
+	}
+	h := fnv.New32a()
+	h.Write([]byte(databaseID))
+	name := strings.SplitN(strings.TrimPrefix(databaseID, localResourceIDPrefix), ":", 2)[0]
+	return fmt.Sprintf("local-%s-%08x", sanitizeFileName(name), h.Sum32())
+}
+
+func d1HistoryPath(key string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || key == "" {
+		return ""
+	}
+	return filepath.Join(home, ".orangeshell", "d1-history", key+".jsonl")
+}
+
+// loadD1History reads a database's history, one JSON string per line.
+func loadD1History(key string) []string {
+	path := d1HistoryPath(key)
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var history []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var sql string
+		if json.Unmarshal(sc.Bytes(), &sql) == nil && sql != "" {
+			history = append(history, sql)
+		}
+	}
+	return history
+}
+
+// saveD1HistoryCmd writes a database's history in the background.
+func saveD1HistoryCmd(key string, history []string) tea.Cmd {
+	path := d1HistoryPath(key)
+	if path == "" {
+		return nil
+	}
+	history = append([]string(nil), history...)
+	return func() tea.Msg {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil
+		}
+		var b strings.Builder
+		for _, sql := range history {
+			line, _ := json.Marshal(sql)
+			b.Write(line)
+			b.WriteByte('\n')
+		}
+		_ = os.WriteFile(path, []byte(b.String()), 0600)
+		return nil
+	}
+}
+
+// addD1History records a query, moving a repeat to the end.
+func (m *Model) addD1History(sql string) tea.Cmd {
+	for i, h := range m.d1History {
+		if h == sql {
+			m.d1History = append(m.d1History[:i], m.d1History[i+1:]...)
+			break
+		}
+	}
+	m.d1History = append(m.d1History, sql)
+	if len(m.d1History) > d1HistoryMax {
+		m.d1History = m.d1History[len(m.d1History)-d1HistoryMax:]
+	}
+	m.d1HistIdx = len(m.d1History)
+	return saveD1HistoryCmd(m.d1HistoryKey, m.d1History)
+}
+
+// recallD1History steps through the history with ↑ (dir -1) and ↓ (dir 1).
+func (m *Model) recallD1History(dir int) {
+	if len(m.d1History) == 0 {
+		return
+	}
+	if m.d1HistIdx == len(m.d1History) {
+		m.d1HistDraft = m.d1Input.Value()
+	}
+	idx := m.d1HistIdx + dir
+	if idx < 0 || idx > len(m.d1History) {
+		return
+	}
+	m.d1HistIdx = idx
+	if idx == len(m.d1History) {
+		m.d1Input.SetValue(m.d1HistDraft)
+	} else {
+		m.d1Input.SetValue(m.d1History[idx])
+	}
+	m.d1Input.CursorEnd()
+}
+
+// --- History search (ctrl+r) ---
+
+// d1SearchMatches returns the history entries and saved queries matching
+// the search query, best match first; ties go to the most recent query.
+func (m Model) d1SearchMatches() []string {
+	type match struct {
+		sql   string
+		score int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for i := len(m.d1History) - 1; i >= 0; i-- {
+		if score, ok := fuzzyScore(m.d1Search, m.d1History[i]); ok {
+			matches = append(matches, match{m.d1History[i], score})
+		}
+		seen[m.d1History[i]] = true
+	}
+	names := make([]string, 0, len(m.d1Saved))
+	for name := range m.d1Saved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sql := m.d1Saved[name]
+		if seen[sql] {
+			continue
+		}
+		if score, ok := fuzzyScore(m.d1Search, name+" "+sql); ok {
+			matches = append(matches, match{sql, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	out := make([]string, len(matches))
+	for i, mt := range matches {
+		out[i] = mt.sql
+	}
+	return out
+}
+
+// updateD1Search handles keys while the history search is open.
+func (m Model) updateD1Search(msg tea.KeyMsg) (Model, tea.Cmd) {
+	matches := m.d1SearchMatches()
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.d1Searching = false
+	case "enter", "tab":
+		m.d1Searching = false
+		if m.d1SearchIdx < len(matches) {
+			m.d1Input.SetValue(matches[m.d1SearchIdx])
+			m.d1Input.CursorEnd()
+			m.d1HistIdx = len(m.d1History)
+		}
+	case "up", "ctrl+r":
+		if m.d1SearchIdx < len(matches)-1 {
+			m.d1SearchIdx++
+		}
+	case "down":
+		if m.d1SearchIdx > 0 {
+			m.d1SearchIdx--
+		}
+	case "backspace":
+		if r := []rune(m.d1Search); len(r) > 0 {
+			m.d1Search = string(r[:len(r)-1])
+			m.d1SearchIdx = 0
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.d1Search += string(msg.Runes)
+			m.d1SearchIdx = 0
+		}
+	}
+	return m, nil
+}
+
+// renderD1Search renders the history search in place of the output.
+func (m Model) renderD1Search(width, height int) []string {
+	matches := m.d1SearchMatches()
+	lines := []string{
+		theme.D1PromptStyle.Render("history> ") + theme.ValueStyle.Render(m.d1Search) + theme.SelectedItemStyle.Render("_"),
+	}
+	if len(matches) == 0 {
+		lines = append(lines, theme.DimStyle.Render("  no matches"))
+	}
+	// Best match at the bottom, next to the input, like a shell's reverse search
+	n := height - 1
+	if n > len(matches) {
+		n = len(matches)
+	}
+	start := 0
+	if m.d1SearchIdx >= n {
+		start = m.d1SearchIdx - n + 1
+	}
+	var list []string
+	for i := start; i < start+n && i < len(matches); i++ {
+		text := truncateRunes(oneLine(matches[i]), width-4)
+		if i == m.d1SearchIdx {
+			list = append([]string{theme.SelectedItemStyle.Render("> " + text)}, list...)
+		} else {
+			list = append([]string{theme.NormalItemStyle.Render("  " + text)}, list...)
+		}
+	}
+	return append(list, lines...)
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of s. Runs of
+// consecutive characters and matches at word starts score higher.
+func fuzzyScore(query, s string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	t := []rune(strings.ToLower(s))
+	score, qi, run := 0, 0, 0
+	for i := 0; i < len(t) && qi < len(q); i++ {
+		if t[i] != q[qi] {
+			run = 0
+			continue
+		}
+		run++
+		score += 1 + 2*(run-1)
+		if i == 0 || !isWordRune(t[i-1]) {
+			score += 3
+		}
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9'
+}
+
+// --- Helpers ---
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -89,6 +89,29 @@ type (
 		Tables     []service.SchemaTable
 		Err        error
 	}
+	// D1SaveQueryMsg requests the app to save (or delete) a named query in the
+	// project's .orangeshell.toml.
+	D1SaveQueryMsg struct {
+		Database string
+		Name     string
+		SQL      string
+		Delete   bool
+	}
+	// D1ExportMsg requests the app to run a paged query in full and export the
+	// result to Path. LocalResource is set for a local database.
+	D1ExportMsg struct {
+		DatabaseID    string
+		LocalResource *wrangler.LocalResource
+		SQL           string
+		Format        string
+		Path          string
+	}
+	// D1ExportDoneMsg reports the outcome of a result export.
+	D1ExportDoneMsg struct {
+		Path string
+		Rows int
+		Err  error
+	}
 
 	// EnterInteractiveMsg is emitted when the user enters interactive mode on a
 	// ReadWrite service's detail view. The app layer handles this to initialize
@@ -0,0 +1,438 @@
+// Package devsessionspopup provides the "Dev Sessions" overlay: the running
+// wrangler dev servers with their ports, health and uptime, and a picker for
+// starting several projects at once.
+package devsessionspopup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// StartMsg asks the app to start a dev server for each target.
+type StartMsg struct {
+	Targets []Target
+	Remote  bool // wrangler dev --remote
+}
+
+// RestartMsg asks the app to restart one session, keeping its ports.
+type RestartMsg struct {
+	Key string
+}
+
+// StopMsg asks the app to stop one session.
+type StopMsg struct {
+	Key string
+}
+
+// StopAllMsg asks the app to stop every session.
+type StopAllMsg struct{}
+
+// --- Data model ---
+
+// Session is a dev server as the app tracks it.
+type Session struct {
+	Key           string // runner key ("project:env")
+	Project       string
+	Env           string
+	Kind          string // "local" or "remote"
+	Port          int
+	InspectorPort int
+	Status        string // "starting", "running", "failed"
+	Health        string // "", "up", "down"
+	HealthDetail  string // e.g. "listening · 1ms"
+	Err           string
+	Started       time.Time
+	Restarts      int
+	LogPath       string
+}
+
+// Project is a monorepo project a dev server can be started for.
+type Project struct {
+	Name       string
+	ConfigPath string
+	Envs       []string // env names, default first
+	Scripts    []string // worker name per env
+}
+
+// Target is one project/env to start.
+type Target struct {
+	Project    string
+	Env        string
+	ConfigPath string
+	ScriptName string
+}
+
+type mode int
+
+const (
+	modeSessions mode = iota
+	modePicker
+)
+
+// Model is the dev sessions popup state.
+type Model struct {
+	mode     mode
+	sessions []Session
+	cursor   int
+	stopAll  bool // "stop all" armed, waiting for confirmation
+
+	projects []Project
+	checked  map[int]bool
+	envIdx   map[int]int // project index -> index into its Envs
+	pick     int
+	remote   bool
+}
+
+// New creates the popup. It opens on the picker when no session is running.
+func New(sessions []Session, projects []Project) Model {
+	m := Model{
+		sessions: sessions,
+		projects: projects,
+		checked:  make(map[int]bool),
+		envIdx:   make(map[int]int),
+	}
+	if len(sessions) == 0 && len(projects) > 0 {
+		m.mode = modePicker
+	}
+	return m
+}
+
+// SetSessions replaces the sessions shown, e.g. after a status change.
+func (m *Model) SetSessions(sessions []Session) {
+	m.sessions = sessions
+	m.cursor = max(0, min(m.cursor, len(sessions)-1))
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.mode == modePicker {
+		return m.updatePicker(keyMsg)
+	}
+	return m.updateSessions(keyMsg)
+}
+
+func (m Model) updateSessions(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
+	armed := m.stopAll
+	m.stopAll = false
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		m.cursor = max(0, m.cursor-1)
+	case "down", "j":
+		m.cursor = max(0, min(m.cursor+1, len(m.sessions)-1))
+	case "n":
+		if len(m.projects) > 0 {
+			m.mode = modePicker
+		}
+	case "r":
+		if s, ok := m.selected(); ok {
+			key := s.Key
+			return m, func() tea.Msg { return RestartMsg{Key: key} }
+		}
+	case "x":
+		if s, ok := m.selected(); ok {
+			key := s.Key
+			return m, func() tea.Msg { return StopMsg{Key: key} }
+		}
+	case "X":
+		if len(m.sessions) == 0 {
+			return m, nil
+		}
+		if !armed {
+			m.stopAll = true
+			return m, nil
+		}
+		return m, func() tea.Msg { return StopAllMsg{} }
+	}
+	return m, nil
+}
+
+func (m Model) updatePicker(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		if len(m.sessions) == 0 {
+			return m, func() tea.Msg { return CloseMsg{} }
+		}
+		m.mode = modeSessions
+	case "up", "k":
+		m.pick = max(0, m.pick-1)
+	case "down", "j":
+		m.pick = max(0, min(m.pick+1, len(m.projects)-1))
+	case " ":
+		m.checked[m.pick] = !m.checked[m.pick]
+	case "a":
+		all := len(m.checked) > 0
+		for i := range m.projects {
+			all = all && m.checked[i]
+		}
+		for i := range m.projects {
+			m.checked[i] = !all
+		}
+	case "left", "h":
+		m.cycleEnv(-1)
+	case "right", "l":
+		m.cycleEnv(1)
+	case "m":
+		m.remote = !m.remote
+	case "enter":
+		targets := m.targets()
+		if len(targets) == 0 {
+			return m, nil
+		}
+		remote := m.remote
+		m.mode = modeSessions
+		m.checked = make(map[int]bool)
+		return m, func() tea.Msg { return StartMsg{Targets: targets, Remote: remote} }
+	}
+	return m, nil
+}
+
+func (m *Model) cycleEnv(delta int) {
+	if m.pick >= len(m.projects) {
+		return
+	}
+	n := len(m.projects[m.pick].Envs)
+	if n == 0 {
+		return
+	}
+	m.envIdx[m.pick] = (m.envIdx[m.pick] + delta + n) % n
+}
+
+// targets returns the checked projects, or the one under the cursor when
+// none is checked, with their selected env.
+func (m Model) targets() []Target {
+	var targets []Target
+	add := func(i int) {
+		p := m.projects[i]
+		if len(p.Envs) == 0 {
+			return
+		}
+		e := m.envIdx[i]
+		targets = append(targets, Target{Project: p.Name, Env: p.Envs[e], ConfigPath: p.ConfigPath, ScriptName: p.Scripts[e]})
+	}
+	for i := range m.projects {
+		if m.checked[i] {
+			add(i)
+		}
+	}
+	if len(targets) == 0 && m.pick < len(m.projects) {
+		add(m.pick)
+	}
+	return targets
+}
+
+func (m Model) selected() (Session, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.sessions) {
+		return Session{}, false
+	}
+	return m.sessions[m.cursor], true
+}
+
+// running reports whether a session exists for project/env.
+func (m Model) running(project, env string) bool {
+	for _, s := range m.sessions {
+		if s.Project == project && s.Env == env {
+			return true
+		}
+	}
+	return false
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 3 / 4
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+
+	var title string
+	var body []string
+	var status, help string
+	if m.mode == modePicker {
+		title = theme.TitleStyle.Render("  Dev Sessions — Start")
+		body = m.viewPicker()
+		kind := "local"
+		if m.remote {
+			kind = "remote"
+		}
+		status = theme.DimStyle.Render("  Mode: ") + theme.ValueStyle.Render(kind) +
+			theme.DimStyle.Render("  ·  ports are allocated from 8787 / inspector 9229")
+		help = theme.DimStyle.Render("  space select  |  a all  |  ←/→ env  |  m local/remote  |  enter start  |  esc back")
+	} else {
+		title = theme.TitleStyle.Render(fmt.Sprintf("  Dev Sessions (%d)", len(m.sessions)))
+		body = m.viewSessions()
+		if m.stopAll {
+			status = theme.ErrorStyle.Render("  Press X again to stop all dev servers")
+		} else if s, ok := m.selected(); ok {
+			status = m.sessionDetail(s)
+		}
+		help = theme.DimStyle.Render("  r restart  |  x stop  |  X stop all  |  n start more  |  esc close")
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if status != "" {
+		parts = append(parts, status)
+	}
+	parts = append(parts, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewSessions() []string {
+	if len(m.sessions) == 0 {
+		return []string{theme.DimStyle.Render("  No dev servers running. Press n to start some.")}
+	}
+	nameW := 12
+	for _, s := range m.sessions {
+		nameW = max(nameW, min(len(s.Project)+1+len(s.Env), 32))
+	}
+
+	header := fmt.Sprintf("  %-*s  %-6s  %-5s  %-5s  %-9s  %-8s  %-7s  %s",
+		nameW, "PROJECT", "KIND", "PORT", "INSP", "STATUS", "HEALTH", "UPTIME", "RESTARTS")
+	lines := []string{theme.DimStyle.Render(header)}
+	for i, s := range m.sessions {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+		}
+		name := truncate(s.Project+"/"+s.Env, nameW)
+		row := fmt.Sprintf("%-*s  %-6s  %-5s  %-5s  ",
+			nameW, name, s.Kind, portText(s.Port), portText(s.InspectorPort))
+		style := theme.ActionItemStyle
+		if i == m.cursor {
+			style = theme.SelectedItemStyle
+		}
+		restarts := ""
+		if s.Restarts > 0 {
+			restarts = fmt.Sprint(s.Restarts)
+		}
+		lines = append(lines, cursor+style.Render(row)+
+			statusCell(s.Status)+"  "+healthCell(s)+"  "+
+			theme.DimStyle.Render(fmt.Sprintf("%-7s  %s", uptime(s), restarts)))
+	}
+	return lines
+}
+
+func (m Model) sessionDetail(s Session) string {
+	switch {
+	case s.Status == "failed" && s.Err != "":
+		return theme.ErrorStyle.Render("  " + s.Err)
+	case s.Port > 0:
+		detail := fmt.Sprintf("  http://localhost:%d", s.Port)
+		if s.HealthDetail != "" {
+			detail += "  ·  " + s.HealthDetail
+		}
+		if s.LogPath != "" {
+			detail += "  ·  " + s.LogPath
+		}
+		return theme.DimStyle.Render(detail)
+	}
+	return ""
+}
+
+func (m Model) viewPicker() []string {
+	if len(m.projects) == 0 {
+		return []string{theme.DimStyle.Render("  No projects with a wrangler config.")}
+	}
+	var lines []string
+	for i, p := range m.projects {
+		cursor := "  "
+		style := theme.ActionItemStyle
+		if i == m.pick {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		box := "[ ]"
+		if m.checked[i] {
+			box = theme.SuccessStyle.Render("[x]")
+		}
+		env := ""
+		if len(p.Envs) > 0 {
+			env = p.Envs[m.envIdx[i]]
+		}
+		line := fmt.Sprintf("%s%s %s %s", cursor, box, style.Render(p.Name), theme.LabelStyle.Render("‹"+env+"›"))
+		if m.running(p.Name, env) {
+			line += theme.DimStyle.Render("  running")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func statusCell(status string) string {
+	text := fmt.Sprintf("%-9s", status)
+	switch status {
+	case "running":
+		return theme.SuccessStyle.Render(text)
+	case "failed":
+		return theme.ErrorStyle.Render(text)
+	}
+	return lipgloss.NewStyle().Foreground(theme.ColorYellow).Render(text)
+}
+
+func healthCell(s Session) string {
+	if s.Status != "running" || s.Health == "" {
+		return theme.DimStyle.Render(fmt.Sprintf("%-8s", "—"))
+	}
+	text := fmt.Sprintf("%-8s", s.Health)
+	if s.Health == "up" {
+		return theme.SuccessStyle.Render(text)
+	}
+	return theme.DimStyle.Render(text)
+}
+
+func portText(port int) string {
+	if port == 0 {
+		return "—"
+	}
+	return fmt.Sprint(port)
+}
+
+// uptime formats how long a running session has been up.
+func uptime(s Session) string {
+	if s.Status != "running" || s.Started.IsZero() {
+		return "—"
+	}
+	d := time.Since(s.Started).Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return d.String()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
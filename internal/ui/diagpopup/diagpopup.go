@@ -0,0 +1,203 @@
+// Package diagpopup provides the config diagnostics overlay: the problems the
+// wrangler config linter found, grouped by project and environment. Opened
+// on demand, or as a pre-deploy gate when a deploy would ship a config with
+// problems.
+package diagpopup
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// CloseMsg signals that the diagnostics popup should be dismissed (and, in
+// gate mode, that the deploy is cancelled).
+type CloseMsg struct{}
+
+// ProceedMsg signals that the user chose to deploy despite the diagnostics.
+type ProceedMsg struct{}
+
+// Report holds the diagnostics of one project's config.
+type Report struct {
+	Project     string
+	ConfigPath  string
+	Diagnostics []wcfg.Diagnostic
+}
+
+// Model represents the diagnostics overlay.
+type Model struct {
+	title   string
+	reports []Report
+	gate    bool // pre-deploy gate: offer to deploy anyway
+	errors  int
+	warns   int
+	scroll  int
+}
+
+// New creates the popup for on-demand viewing.
+func New(title string, reports []Report) Model {
+	m := Model{title: title, reports: reports}
+	for _, r := range reports {
+		for _, d := range r.Diagnostics {
+			if d.Severity == wcfg.SeverityError {
+				m.errors++
+			} else {
+				m.warns++
+			}
+		}
+	}
+	return m
+}
+
+// NewGate creates the popup as a pre-deploy gate for a config with errors:
+// esc cancels the deploy, D deploys anyway.
+func NewGate(title string, reports []Report) Model {
+	m := New(title, reports)
+	m.gate = true
+	return m
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, func() tea.Msg { return CloseMsg{} }
+		case "D":
+			if m.gate {
+				return m, func() tea.Msg { return ProceedMsg{} }
+			}
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		case "down", "j":
+			if m.scroll < len(m.lines())-1 {
+				m.scroll++
+			}
+		}
+	}
+	return m, nil
+}
+
+// lines renders the content lines: a heading per project, then its
+// diagnostics grouped by environment.
+func (m Model) lines() []string {
+	projectStyle := lipgloss.NewStyle().Foreground(theme.ColorOrange).Bold(true)
+	envStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue)
+	warnStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+
+	if m.errors+m.warns == 0 {
+		return []string{theme.SuccessStyle.Render("✓ No problems found.")}
+	}
+
+	var lines []string
+	for i, r := range m.reports {
+		if len(r.Diagnostics) == 0 {
+			continue
+		}
+		if i > 0 && len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		if len(m.reports) > 1 {
+			lines = append(lines, projectStyle.Render(r.Project))
+		}
+
+		// Keep the linter's order (errors first) within each env group
+		var envs []string
+		byEnv := make(map[string][]wcfg.Diagnostic)
+		for _, d := range r.Diagnostics {
+			if _, ok := byEnv[d.Env]; !ok {
+				envs = append(envs, d.Env)
+			}
+			byEnv[d.Env] = append(byEnv[d.Env], d)
+		}
+		for _, env := range envs {
+			label := "env " + env
+			switch env {
+			case "":
+				label = "all environments"
+			case "default":
+				label = "top level"
+			}
+			lines = append(lines, envStyle.Render(label))
+			for _, d := range byEnv[env] {
+				icon := warnStyle.Render("▲")
+				if d.Severity == wcfg.SeverityError {
+					icon = theme.ErrorStyle.Render("✗")
+				}
+				lines = append(lines, fmt.Sprintf("  %s %s %s", icon, d.Message, theme.DimStyle.Render(d.Rule)))
+			}
+		}
+	}
+	return lines
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 2 / 3
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render("  " + m.title)
+	summary := theme.DimStyle.Render(fmt.Sprintf("  %d errors, %d warnings", m.errors, m.warns))
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+
+	maxVisible := termHeight/2 - 6
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	all := m.lines()
+	scroll := m.scroll
+	if maxScroll := len(all) - maxVisible; scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + maxVisible
+	if end > len(all) {
+		end = len(all)
+	}
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+	var visible []string
+	for _, l := range all[scroll:end] {
+		visible = append(visible, lineStyle.Render(l))
+	}
+
+	var help string
+	if m.gate {
+		help = theme.DimStyle.Render("  esc cancel deploy  |  D deploy anyway  |  j/k scroll")
+	} else {
+		help = theme.DimStyle.Render("  esc close  |  j/k scroll")
+	}
+
+	var parts []string
+	parts = append(parts, title, summary, sep)
+	parts = append(parts, visible...)
+	parts = append(parts, sep, help)
+
+	border := theme.ColorOrange
+	if m.gate {
+		border = theme.ColorRed
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
@@ -22,9 +22,15 @@ type Model struct {
 	hoverIdx   int // -1 means no hover
 	authMethod config.AuthMethod
 	restricted bool // true when OAuth auth lacks fallback credentials for restricted APIs
+	alerts     int  // unread fired alerts (0 hides the badge)
+	profile    string
+	showProf   bool // show the profile switcher (several profiles, or a named one)
 	width      int
 }
 
+// ProfileZoneID is the bubblezone marker ID for the header profile switcher.
+const ProfileZoneID = "hdr-profile"
+
 // AccountZoneID returns the bubblezone marker ID for a header account tab.
 func AccountZoneID(idx int) string {
 	return fmt.Sprintf("hdr-acct-%d", idx)
@@ -118,6 +124,18 @@ func (m Model) IsRestricted() bool {
 	return m.restricted
 }
 
+// SetAlertCount sets the number of unread alerts shown in the header badge.
+func (m *Model) SetAlertCount(n int) {
+	m.alerts = n
+}
+
+// SetProfile sets the active credential profile. The switcher is shown when
+// there are several profiles or the active one isn't the default.
+func (m *Model) SetProfile(name string, count int) {
+	m.profile = name
+	m.showProf = count > 1 || name != config.DefaultProfile
+}
+
 // SetHoverIdx sets which account tab the mouse is hovering over (-1 for none).
 func (m *Model) SetHoverIdx(idx int) {
 	m.hoverIdx = idx
@@ -140,6 +158,14 @@ func (m Model) View() string {
 	}
 
 	left := theme.HeaderStyle.Render(" orangeshell ")
+	if m.showProf {
+		// Profile switcher (ctrl+o or click)
+		left += zone.Mark(ProfileZoneID, lipgloss.NewStyle().
+			Foreground(theme.ColorWhite).
+			Background(theme.ColorOrangeDim).
+			Padding(0, 1).
+			Render("◆ "+m.profile+" ▾"))
+	}
 
 	// Build account tabs
 	tabs := m.renderTabs()
@@ -161,6 +187,20 @@ func (m Model) View() string {
 			Render(badge)
 	}
 
+	// Unread alerts badge (press ! to open the history)
+	if m.alerts > 0 {
+		label := fmt.Sprintf("⚠ %d alert", m.alerts)
+		if m.alerts > 1 {
+			label += "s"
+		}
+		right = lipgloss.NewStyle().
+			Foreground(theme.ColorWhite).
+			Background(theme.ColorRed).
+			Bold(true).
+			Padding(0, 1).
+			Render(label) + right
+	}
+
 	// Fill the gap between left+tabs and right
 	leftWidth := lipgloss.Width(left) + lipgloss.Width(tabs)
 	rightWidth := lipgloss.Width(right)
@@ -0,0 +1,245 @@
+// Package historypopup provides the config edit history overlay: the journal
+// of edits orangeshell made to one wrangler config, with a unified diff per
+// edit and undo/redo.
+package historypopup
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// CloseMsg signals that the history popup should be dismissed.
+type CloseMsg struct{}
+
+// UndoMsg asks the app to undo the last applied edit of ConfigPath.
+type UndoMsg struct{ ConfigPath string }
+
+// RedoMsg asks the app to redo the last undone edit of ConfigPath.
+type RedoMsg struct{ ConfigPath string }
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// Model represents the edit history overlay.
+type Model struct {
+	history  *wcfg.ConfigHistory
+	cursor   int  // selected row; row 0 is the newest entry
+	showDiff bool // diff view of the selected entry
+	scroll   int  // list or diff scroll offset
+	errMsg   string
+}
+
+// New creates the popup for a config's history.
+func New(history *wcfg.ConfigHistory) Model {
+	return Model{history: history}
+}
+
+// SetHistory replaces the history after an undo/redo, keeping the selection
+// on the entry that changed state.
+func (m *Model) SetHistory(history *wcfg.ConfigHistory) {
+	m.history = history
+	m.errMsg = ""
+	m.showDiff = false
+	if n := len(history.Entries); m.cursor >= n {
+		m.cursor = max(n-1, 0)
+	}
+}
+
+// SetError shows an undo/redo failure.
+func (m *Model) SetError(msg string) {
+	m.errMsg = msg
+}
+
+// entryAt returns the journal index of a row (rows are newest first).
+func (m Model) entryAt(row int) int {
+	return len(m.history.Entries) - 1 - row
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	configPath := m.history.ConfigPath
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		if m.showDiff {
+			m.showDiff = false
+			m.scroll = 0
+			return m, nil
+		}
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "u":
+		if m.history.CanUndo() {
+			return m, func() tea.Msg { return UndoMsg{ConfigPath: configPath} }
+		}
+		m.errMsg = "Nothing to undo"
+	case "r":
+		if m.history.CanRedo() {
+			return m, func() tea.Msg { return RedoMsg{ConfigPath: configPath} }
+		}
+		m.errMsg = "Nothing to redo"
+	case "enter", "d":
+		if len(m.history.Entries) > 0 {
+			m.showDiff = !m.showDiff
+			m.scroll = 0
+		}
+	case "up", "k":
+		if m.showDiff {
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		} else if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.showDiff {
+			if m.scroll < len(m.diffLines())-1 {
+				m.scroll++
+			}
+		} else if m.cursor < len(m.history.Entries)-1 {
+			m.cursor++
+		}
+	}
+	return m, nil
+}
+
+// listLines renders one row per journal entry, newest first. Undone entries
+// are dimmed; the next edit to undo is marked.
+func (m Model) listLines(width int) []string {
+	if len(m.history.Entries) == 0 {
+		return []string{theme.DimStyle.Render("No edits recorded for this config yet.")}
+	}
+	timeStyle := lipgloss.NewStyle().Foreground(theme.ColorGray)
+	var lines []string
+	for row := 0; row < len(m.history.Entries); row++ {
+		idx := m.entryAt(row)
+		e := m.history.Entries[idx]
+		undone := idx >= m.history.Cursor
+
+		marker := "  "
+		if idx == m.history.Cursor-1 {
+			marker = theme.SuccessStyle.Render("● ")
+		}
+		op := e.Op
+		if undone {
+			op = theme.DimStyle.Render(op + " (undone)")
+		}
+		line := fmt.Sprintf("%s%s  %s", marker, timeStyle.Render(e.Time.Format("Jan 02 15:04:05")), op)
+		if row == m.cursor {
+			line = theme.SelectedItemStyle.Width(width).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// diffLines renders the selected entry's unified diff.
+func (m Model) diffLines() []string {
+	if len(m.history.Entries) == 0 {
+		return nil
+	}
+	e := m.history.Entries[m.entryAt(m.cursor)]
+	addStyle := lipgloss.NewStyle().Foreground(theme.ColorGreen)
+	delStyle := lipgloss.NewStyle().Foreground(theme.ColorRed)
+	hunkStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue)
+
+	name := filepath.Base(m.history.ConfigPath)
+	lines := []string{
+		theme.LabelStyle.Render(e.Op),
+		delStyle.Render("--- a/" + name),
+		addStyle.Render("+++ b/" + name),
+	}
+	for _, l := range wcfg.UnifiedDiff(e.Before, e.After, diffContext) {
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			l = hunkStyle.Render(l)
+		case strings.HasPrefix(l, "+"):
+			l = addStyle.Render(l)
+		case strings.HasPrefix(l, "-"):
+			l = delStyle.Render(l)
+		default:
+			l = theme.DimStyle.Render(l)
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 2 / 3
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render(fmt.Sprintf("  Edit History — %s", filepath.Base(filepath.Dir(m.history.ConfigPath))))
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+
+	maxVisible := termHeight/2 - 6
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var all []string
+	scroll := m.scroll
+	if m.showDiff {
+		all = m.diffLines()
+	} else {
+		all = m.listLines(innerWidth)
+		// Keep the selected row visible
+		scroll = 0
+		if m.cursor >= maxVisible {
+			scroll = m.cursor - maxVisible + 1
+		}
+	}
+	if maxScroll := len(all) - maxVisible; scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := min(scroll+maxVisible, len(all))
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+	var visible []string
+	for _, l := range all[scroll:end] {
+		visible = append(visible, lineStyle.Render(l))
+	}
+
+	var help string
+	if m.showDiff {
+		help = theme.DimStyle.Render("  esc back  |  j/k scroll  |  u undo  |  r redo")
+	} else {
+		help = theme.DimStyle.Render("  esc close  |  enter diff  |  u undo  |  r redo")
+	}
+
+	var parts []string
+	parts = append(parts, title, sep)
+	parts = append(parts, visible...)
+	parts = append(parts, sep)
+	if m.errMsg != "" {
+		parts = append(parts, theme.ErrorStyle.Render("  "+m.errMsg))
+	}
+	parts = append(parts, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
@@ -14,12 +14,18 @@ type LaunchServiceMsg struct {
 	ServiceName string // "Workers", "KV", "R2", etc., or "" for wrangler home
 }
 
+// OpenWorkspaceMsg is sent when the user selects a saved workspace.
+type OpenWorkspaceMsg struct {
+	Name string
+}
+
 // CloseMsg is sent when the launcher should be closed.
 type CloseMsg struct{}
 
 type serviceEntry struct {
-	Name string
-	Icon string
+	Name      string
+	Icon      string
+	Workspace bool // a saved workspace rather than a service
 }
 
 // defaultServices are the Cloudflare developer platform services (excluding Wrangler).
@@ -43,6 +49,8 @@ type Model struct {
 	width     int
 	height    int
 	homeLabel string // e.g. "Wrangler Home" or "Home: express-d1-app"
+
+	workspaces []string // saved workspace names, listed after the services
 }
 
 // New creates a new service launcher.
@@ -60,6 +68,12 @@ func New(projectName string) Model {
 	return m
 }
 
+// SetWorkspaces lists saved workspaces below the services.
+func (m *Model) SetWorkspaces(names []string) {
+	m.workspaces = names
+	m.filter()
+}
+
 // filter rebuilds the results list based on the current query.
 func (m *Model) filter() {
 	q := strings.ToLower(m.query)
@@ -77,6 +91,12 @@ func (m *Model) filter() {
 		}
 	}
 
+	for _, name := range m.workspaces {
+		if q == "" || strings.Contains(strings.ToLower(name), q) || strings.Contains("workspace", q) {
+			m.results = append(m.results, serviceEntry{Name: name, Icon: "◇", Workspace: true})
+		}
+	}
+
 	// Clamp cursor
 	if m.cursor >= len(m.results) {
 		m.cursor = len(m.results) - 1
@@ -97,6 +117,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case "enter":
 			if len(m.results) > 0 && m.cursor < len(m.results) {
 				entry := m.results[m.cursor]
+				if entry.Workspace {
+					return m, func() tea.Msg { return OpenWorkspaceMsg{Name: entry.Name} }
+				}
 				return m, func() tea.Msg {
 					return LaunchServiceMsg{ServiceName: entry.Name}
 				}
@@ -152,6 +175,9 @@ func (m Model) View(termWidth, termHeight int) string {
 	var bodyLines []string
 
 	for i, entry := range m.results {
+		if entry.Workspace && (i == 0 || !m.results[i-1].Workspace) {
+			bodyLines = append(bodyLines, "", theme.ActionSectionStyle.Render("  Workspaces"))
+		}
 		cursor := "  "
 		if i == m.cursor {
 			cursor = theme.SelectedItemStyle.Render("> ")
@@ -0,0 +1,305 @@
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/config"
+	svc "github.com/oarafat/orangeshell/internal/service"
+)
+
+const (
+	defaultTailAlertWindow      = time.Minute
+	defaultAnalyticsAlertWindow = 15 * time.Minute
+	defaultAlertCooldown        = 5 * time.Minute
+	alertHistoryMax             = 200
+)
+
+// AlertEvent is a single firing of an alert rule.
+type AlertEvent struct {
+	Time      time.Time
+	Rule      string
+	Worker    string
+	Value     float64
+	Threshold float64
+	Message   string
+}
+
+// AlertPoll describes an analytics query needed to evaluate a rule.
+type AlertPoll struct {
+	RuleIndex int
+	Worker    string
+	Window    time.Duration
+}
+
+// alertRule is a config.AlertRule with its durations parsed.
+type alertRule struct {
+	config.AlertRule
+	window   time.Duration
+	cooldown time.Duration
+}
+
+// AlertEngine evaluates locally defined alert rules against tail line batches
+// and analytics polls, and keeps the history of fired alerts. It is owned by
+// the app model and only touched from the Update loop (not goroutine-safe).
+type AlertEngine struct {
+	rules     []alertRule
+	hits      map[string][]time.Time // rule#worker → timestamps of matching tail lines
+	lastFired map[string]time.Time   // rule#worker → last firing
+	history   []AlertEvent           // oldest first
+	unread    int
+}
+
+// NewAlertEngine creates an engine for the given rules. Rules with an unknown
+// source or an unparseable window are skipped; the returned errors describe them.
+func NewAlertEngine(rules []config.AlertRule) (*AlertEngine, []error) {
+	e := &AlertEngine{
+		hits:      make(map[string][]time.Time),
+		lastFired: make(map[string]time.Time),
+	}
+	var errs []error
+	for i, r := range rules {
+		parsed, err := parseAlertRule(r)
+		if err != nil {
+			name := r.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			errs = append(errs, fmt.Errorf("alert %s: %w", name, err))
+			continue
+		}
+		e.rules = append(e.rules, parsed)
+	}
+	return e, errs
+}
+
+func parseAlertRule(r config.AlertRule) (alertRule, error) {
+	ar := alertRule{AlertRule: r, cooldown: defaultAlertCooldown}
+	switch r.Source {
+	case config.AlertSourceTail:
+		ar.window = defaultTailAlertWindow
+		switch r.Metric {
+		case "exceptions", "errors":
+		case "matches":
+			if r.Match == "" {
+				return ar, fmt.Errorf(`metric "matches" requires match`)
+			}
+		default:
+			return ar, fmt.Errorf("unknown tail metric %q", r.Metric)
+		}
+	case config.AlertSourceAnalytics:
+		ar.window = defaultAnalyticsAlertWindow
+		switch r.Metric {
+		case "error_rate", "errors", "requests", "cpu_p99":
+		default:
+			return ar, fmt.Errorf("unknown analytics metric %q", r.Metric)
+		}
+		if r.Worker == "" || r.Worker == "*" {
+			return ar, fmt.Errorf("analytics rules need a worker")
+		}
+	default:
+		return ar, fmt.Errorf("unknown source %q", r.Source)
+	}
+
+	if r.Window != "" {
+		d, err := time.ParseDuration(r.Window)
+		if err != nil || d <= 0 {
+			return ar, fmt.Errorf("invalid window %q", r.Window)
+		}
+		ar.window = d
+	}
+	if r.Cooldown != "" {
+		d, err := time.ParseDuration(r.Cooldown)
+		if err != nil || d < 0 {
+			return ar, fmt.Errorf("invalid cooldown %q", r.Cooldown)
+		}
+		ar.cooldown = d
+	}
+	if ar.Name == "" {
+		ar.Name = fmt.Sprintf("%s %s > %g", r.Source, r.Metric, r.Threshold)
+	}
+	return ar, nil
+}
+
+// HasRules returns whether any valid rule is configured.
+func (e *AlertEngine) HasRules() bool {
+	return len(e.rules) > 0
+}
+
+// ObserveTail feeds a batch of tail lines for a worker into all tail rules
+// and returns the alerts that fired.
+func (e *AlertEngine) ObserveTail(worker string, lines []svc.TailLine, now time.Time) []AlertEvent {
+	var fired []AlertEvent
+	for i, r := range e.rules {
+		if r.Source != config.AlertSourceTail || !r.matchesWorker(worker) {
+			continue
+		}
+		key := alertKey(i, worker)
+		hits := e.hits[key]
+		for _, line := range lines {
+			if r.matchesLine(line) {
+				ts := line.Timestamp
+				if ts.IsZero() {
+					ts = now
+				}
+				hits = append(hits, ts)
+			}
+		}
+
+		// Drop hits that slid out of the window
+		cutoff := now.Add(-r.window)
+		n := 0
+		for _, ts := range hits {
+			if ts.After(cutoff) {
+				hits[n] = ts
+				n++
+			}
+		}
+		hits = hits[:n]
+		e.hits[key] = hits
+
+		count := float64(len(hits))
+		if count > r.Threshold {
+			msg := fmt.Sprintf("%s: %d %s in %s (> %g)", worker, len(hits), r.Metric, formatAlertWindow(r.window), r.Threshold)
+			if ev, ok := e.fire(i, worker, count, msg, now); ok {
+				fired = append(fired, ev)
+			}
+		}
+	}
+	return fired
+}
+
+// AnalyticsPolls returns the analytics queries needed to evaluate the
+// analytics rules.
+func (e *AlertEngine) AnalyticsPolls() []AlertPoll {
+	var polls []AlertPoll
+	for i, r := range e.rules {
+		if r.Source == config.AlertSourceAnalytics {
+			polls = append(polls, AlertPoll{RuleIndex: i, Worker: r.Worker, Window: r.window})
+		}
+	}
+	return polls
+}
+
+// ObserveMetrics evaluates an analytics rule against a poll result.
+func (e *AlertEngine) ObserveMetrics(ruleIndex int, m *api.WorkerMetrics, now time.Time) []AlertEvent {
+	if ruleIndex < 0 || ruleIndex >= len(e.rules) || m == nil {
+		return nil
+	}
+	r := e.rules[ruleIndex]
+
+	var value float64
+	var display string
+	switch r.Metric {
+	case "error_rate":
+		if m.TotalRequests > 0 {
+			value = float64(m.TotalErrors) / float64(m.TotalRequests) * 100
+		}
+		display = fmt.Sprintf("error rate %.2f%%", value)
+	case "errors":
+		value = float64(m.TotalErrors)
+		display = fmt.Sprintf("%d errors", m.TotalErrors)
+	case "requests":
+		value = float64(m.TotalRequests)
+		display = fmt.Sprintf("%d requests", m.TotalRequests)
+	case "cpu_p99":
+		value = m.CPUTimeP99 / 1000 // µs → ms
+		display = fmt.Sprintf("CPU p99 %.1fms", value)
+	}
+
+	if value <= r.Threshold {
+		return nil
+	}
+	msg := fmt.Sprintf("%s: %s over %s (> %g)", r.Worker, display, formatAlertWindow(r.window), r.Threshold)
+	if ev, ok := e.fire(ruleIndex, r.Worker, value, msg, now); ok {
+		return []AlertEvent{ev}
+	}
+	return nil
+}
+
+// fire records an alert unless the rule is still cooling down for the worker.
+func (e *AlertEngine) fire(ruleIndex int, worker string, value float64, msg string, now time.Time) (AlertEvent, bool) {
+	r := e.rules[ruleIndex]
+	key := alertKey(ruleIndex, worker)
+	if last, ok := e.lastFired[key]; ok && now.Sub(last) < r.cooldown {
+		return AlertEvent{}, false
+	}
+	e.lastFired[key] = now
+
+	ev := AlertEvent{
+		Time:      now,
+		Rule:      r.Name,
+		Worker:    worker,
+		Value:     value,
+		Threshold: r.Threshold,
+		Message:   msg,
+	}
+	e.history = append(e.history, ev)
+	if len(e.history) > alertHistoryMax {
+		e.history = e.history[len(e.history)-alertHistoryMax:]
+	}
+	e.unread++
+	return ev, true
+}
+
+// History returns the fired alerts, newest first.
+func (e *AlertEngine) History() []AlertEvent {
+	out := make([]AlertEvent, len(e.history))
+	for i, ev := range e.history {
+		out[len(e.history)-1-i] = ev
+	}
+	return out
+}
+
+// Unread returns the number of alerts fired since the history was last viewed.
+func (e *AlertEngine) Unread() int {
+	return e.unread
+}
+
+// MarkRead clears the unread counter.
+func (e *AlertEngine) MarkRead() {
+	e.unread = 0
+}
+
+// ClearHistory removes all recorded alerts.
+func (e *AlertEngine) ClearHistory() {
+	e.history = nil
+	e.unread = 0
+}
+
+func (r alertRule) matchesWorker(worker string) bool {
+	if r.Worker == "" || r.Worker == "*" {
+		return true
+	}
+	return r.Worker == worker || r.Worker == strings.TrimPrefix(worker, "dev:")
+}
+
+func (r alertRule) matchesLine(line svc.TailLine) bool {
+	switch r.Metric {
+	case "exceptions":
+		return line.Level == "exception"
+	case "errors":
+		return line.Level == "exception" || line.Level == "error"
+	case "matches":
+		return strings.Contains(line.Text, r.Match)
+	}
+	return false
+}
+
+func alertKey(ruleIndex int, worker string) string {
+	return fmt.Sprintf("%d#%s", ruleIndex, worker)
+}
+
+// formatAlertWindow renders a window as "1m", "15m", "2h" rather than "1m0s".
+func formatAlertWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
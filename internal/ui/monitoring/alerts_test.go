@@ -0,0 +1,297 @@
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/config"
+	svc "github.com/oarafat/orangeshell/internal/service"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         config.AlertRule
+		err          string // substring of the error; "" for a valid rule
+		window       time.Duration
+		cooldown     time.Duration
+		wantRuleName string
+	}{
+		{
+			name:         "tail defaults",
+			rule:         config.AlertRule{Source: config.AlertSourceTail, Metric: "exceptions", Threshold: 5},
+			window:       time.Minute,
+			cooldown:     5 * time.Minute,
+			wantRuleName: "tail exceptions > 5",
+		},
+		{
+			name:         "analytics defaults",
+			rule:         config.AlertRule{Name: "api errors", Source: config.AlertSourceAnalytics, Worker: "api", Metric: "error_rate", Threshold: 1.5},
+			window:       15 * time.Minute,
+			cooldown:     5 * time.Minute,
+			wantRuleName: "api errors",
+		},
+		{
+			name:         "window and cooldown",
+			rule:         config.AlertRule{Source: config.AlertSourceTail, Metric: "errors", Window: "30s", Cooldown: "0s"},
+			window:       30 * time.Second,
+			cooldown:     0,
+			wantRuleName: "tail errors > 0",
+		},
+		{
+			name: "unknown source",
+			rule: config.AlertRule{Source: "logs", Metric: "errors"},
+			err:  `unknown source "logs"`,
+		},
+		{
+			name: "unknown tail metric",
+			rule: config.AlertRule{Source: config.AlertSourceTail, Metric: "cpu_p99"},
+			err:  `unknown tail metric "cpu_p99"`,
+		},
+		{
+			name: "matches without match",
+			rule: config.AlertRule{Source: config.AlertSourceTail, Metric: "matches"},
+			err:  "requires match",
+		},
+		{
+			name: "unknown analytics metric",
+			rule: config.AlertRule{Source: config.AlertSourceAnalytics, Worker: "api", Metric: "exceptions"},
+			err:  `unknown analytics metric "exceptions"`,
+		},
+		{
+			name: "analytics wildcard worker",
+			rule: config.AlertRule{Source: config.AlertSourceAnalytics, Worker: "*", Metric: "errors"},
+			err:  "need a worker",
+		},
+		{
+			name: "bad window",
+			rule: config.AlertRule{Source: config.AlertSourceTail, Metric: "errors", Window: "5"},
+			err:  `invalid window "5"`,
+		},
+		{
+			name: "zero window",
+			rule: config.AlertRule{Source: config.AlertSourceTail, Metric: "errors", Window: "0s"},
+			err:  `invalid window "0s"`,
+		},
+		{
+			name: "negative cooldown",
+			rule: config.AlertRule{Source: config.AlertSourceTail, Metric: "errors", Cooldown: "-1m"},
+			err:  `invalid cooldown "-1m"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAlertRule(tt.rule)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.window != tt.window || got.cooldown != tt.cooldown || got.Name != tt.wantRuleName {
+				t.Errorf("got window %v, cooldown %v, name %q; want %v, %v, %q",
+					got.window, got.cooldown, got.Name, tt.window, tt.cooldown, tt.wantRuleName)
+			}
+		})
+	}
+}
+
+func TestNewAlertEngineSkipsInvalidRules(t *testing.T) {
+	e, errs := NewAlertEngine([]config.AlertRule{
+		{Source: config.AlertSourceTail, Metric: "errors"},
+		{Source: "logs", Metric: "errors"},
+		{Name: "cpu", Source: config.AlertSourceAnalytics, Metric: "cpu_p99"},
+	})
+	if len(e.rules) != 1 || len(errs) != 2 {
+		t.Fatalf("got %d rules and %d errors, want 1 and 2", len(e.rules), len(errs))
+	}
+	// Errors name the rule, or its position when it has no name
+	if !strings.HasPrefix(errs[0].Error(), "alert #2:") || !strings.HasPrefix(errs[1].Error(), "alert cpu:") {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestObserveTailWindow(t *testing.T) {
+	e, _ := NewAlertEngine([]config.AlertRule{
+		{Source: config.AlertSourceTail, Worker: "api", Metric: "exceptions", Threshold: 2, Window: "1m", Cooldown: "0s"},
+	})
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	exc := func(at time.Duration) svc.TailLine {
+		return svc.TailLine{Timestamp: start.Add(at), Level: "exception", Text: "boom"}
+	}
+
+	// Two hits don't exceed the threshold
+	if fired := e.ObserveTail("api", []svc.TailLine{exc(0), exc(10 * time.Second)}, start.Add(10*time.Second)); len(fired) != 0 {
+		t.Fatalf("fired at 2 hits: %+v", fired)
+	}
+	// Other levels and other workers don't count
+	if fired := e.ObserveTail("api", []svc.TailLine{{Timestamp: start.Add(20 * time.Second), Level: "error"}}, start.Add(20*time.Second)); len(fired) != 0 {
+		t.Fatalf("fired on an error line: %+v", fired)
+	}
+	if fired := e.ObserveTail("web", []svc.TailLine{exc(20 * time.Second), exc(21 * time.Second), exc(22 * time.Second)}, start.Add(22*time.Second)); len(fired) != 0 {
+		t.Fatalf("fired for another worker: %+v", fired)
+	}
+	// A dev session matches the rule by script name but counts its own hits
+	e.ObserveTail("dev:api", []svc.TailLine{exc(30 * time.Second)}, start.Add(30*time.Second))
+	if n := len(e.hits[alertKey(0, "dev:api")]); n != 1 {
+		t.Fatalf("dev:api has %d hits, want 1", n)
+	}
+	// A third hit within the minute fires
+	fired := e.ObserveTail("api", []svc.TailLine{exc(30 * time.Second)}, start.Add(30*time.Second))
+	if len(fired) != 1 || fired[0].Value != 3 || fired[0].Message != "api: 3 exceptions in 1m (> 2)" {
+		t.Fatalf("fired = %+v, want one alert at 3 hits", fired)
+	}
+	// At 71s the hits at 0s and 10s have slid out of the window
+	fired = e.ObserveTail("api", []svc.TailLine{exc(71 * time.Second)}, start.Add(71*time.Second))
+	if len(fired) != 0 {
+		t.Fatalf("fired after hits left the window: %+v", fired)
+	}
+	if n := len(e.hits[alertKey(0, "api")]); n != 2 {
+		t.Errorf("%d hits kept, want 2", n)
+	}
+	// Lines without a timestamp count at now
+	fired = e.ObserveTail("api", []svc.TailLine{{Level: "exception"}}, start.Add(72*time.Second))
+	if len(fired) != 1 || fired[0].Value != 3 {
+		t.Errorf("fired = %+v, want one alert at 3 hits", fired)
+	}
+}
+
+func TestObserveTailMatches(t *testing.T) {
+	e, _ := NewAlertEngine([]config.AlertRule{
+		{Source: config.AlertSourceTail, Metric: "matches", Match: "timeout", Threshold: 0},
+	})
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	lines := []svc.TailLine{{Level: "log", Text: "ok"}, {Level: "log", Text: "upstream timeout"}}
+	fired := e.ObserveTail("any-worker", lines, now)
+	if len(fired) != 1 || fired[0].Worker != "any-worker" || fired[0].Value != 1 {
+		t.Errorf("fired = %+v, want one alert for any-worker", fired)
+	}
+}
+
+func TestAlertCooldown(t *testing.T) {
+	e, _ := NewAlertEngine([]config.AlertRule{
+		{Source: config.AlertSourceTail, Metric: "errors", Threshold: 0, Window: "1h", Cooldown: "5m"},
+	})
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	errLine := []svc.TailLine{{Level: "error"}}
+
+	steps := []struct {
+		worker string
+		at     time.Duration
+		fires  bool
+	}{
+		{"api", 0, true},
+		{"api", time.Minute, false},       // cooling down
+		{"web", time.Minute, true},        // cooldown is per worker
+		{"api", 5*time.Minute - 1, false}, // still cooling down
+		{"api", 5 * time.Minute, true},    // cooldown over
+		{"api", 5*time.Minute + time.Second, false},
+	}
+	for _, s := range steps {
+		fired := e.ObserveTail(s.worker, errLine, start.Add(s.at))
+		if (len(fired) == 1) != s.fires {
+			t.Errorf("%s at %v: fired %d alerts, want fires=%v", s.worker, s.at, len(fired), s.fires)
+		}
+	}
+	if e.Unread() != 3 || len(e.History()) != 3 {
+		t.Errorf("unread %d, history %d; want 3, 3", e.Unread(), len(e.History()))
+	}
+}
+
+func TestObserveMetrics(t *testing.T) {
+	tests := []struct {
+		metric    string
+		threshold float64
+		metrics   api.WorkerMetrics
+		value     float64 // 0 when the rule must not fire
+		message   string
+	}{
+		{"error_rate", 5, api.WorkerMetrics{TotalRequests: 200, TotalErrors: 20}, 10, "api: error rate 10.00% over 15m (> 5)"},
+		{"error_rate", 10, api.WorkerMetrics{TotalRequests: 200, TotalErrors: 20}, 0, ""}, // equal isn't over
+		{"error_rate", 0, api.WorkerMetrics{}, 0, ""},                                     // no requests, no rate
+		{"errors", 3, api.WorkerMetrics{TotalErrors: 4}, 4, "api: 4 errors over 15m (> 3)"},
+		{"requests", 1000, api.WorkerMetrics{TotalRequests: 999}, 0, ""},
+		{"requests", 1000, api.WorkerMetrics{TotalRequests: 1001}, 1001, "api: 1001 requests over 15m (> 1000)"},
+		{"cpu_p99", 50, api.WorkerMetrics{CPUTimeP99: 62500}, 62.5, "api: CPU p99 62.5ms over 15m (> 50)"},
+		{"cpu_p99", 50, api.WorkerMetrics{CPUTimeP99: 50000}, 0, ""},
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		e, errs := NewAlertEngine([]config.AlertRule{
+			{Source: config.AlertSourceAnalytics, Worker: "api", Metric: tt.metric, Threshold: tt.threshold},
+		})
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		fired := e.ObserveMetrics(0, &tt.metrics, now)
+		if tt.value == 0 {
+			if len(fired) != 0 {
+				t.Errorf("%s > %g: fired %+v", tt.metric, tt.threshold, fired)
+			}
+			continue
+		}
+		if len(fired) != 1 || fired[0].Value != tt.value || fired[0].Message != tt.message {
+			t.Errorf("%s > %g: fired %+v, want value %g, %q", tt.metric, tt.threshold, fired, tt.value, tt.message)
+		}
+	}
+
+	// Out-of-range indexes and missing results are ignored
+	e, _ := NewAlertEngine([]config.AlertRule{{Source: config.AlertSourceAnalytics, Worker: "api", Metric: "errors"}})
+	if e.ObserveMetrics(1, &api.WorkerMetrics{TotalErrors: 9}, now) != nil || e.ObserveMetrics(0, nil, now) != nil {
+		t.Error("fired for an unknown rule or a nil result")
+	}
+}
+
+func TestAlertHistory(t *testing.T) {
+	e, _ := NewAlertEngine([]config.AlertRule{
+		{Source: config.AlertSourceTail, Metric: "errors", Cooldown: "0s"},
+	})
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < alertHistoryMax+10; i++ {
+		e.ObserveTail("api", []svc.TailLine{{Level: "error"}}, start.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	h := e.History()
+	if len(h) != alertHistoryMax {
+		t.Fatalf("history holds %d alerts, want %d", len(h), alertHistoryMax)
+	}
+	// Newest first; the oldest 10 were dropped
+	if want := start.Add(time.Duration(alertHistoryMax+9) * time.Millisecond); !h[0].Time.Equal(want) {
+		t.Errorf("newest alert at %v, want %v", h[0].Time, want)
+	}
+	if want := start.Add(10 * time.Millisecond); !h[len(h)-1].Time.Equal(want) {
+		t.Errorf("oldest alert at %v, want %v", h[len(h)-1].Time, want)
+	}
+	if e.Unread() != alertHistoryMax+10 {
+		t.Errorf("unread = %d, want %d", e.Unread(), alertHistoryMax+10)
+	}
+
+	e.MarkRead()
+	if e.Unread() != 0 || len(e.History()) != alertHistoryMax {
+		t.Error("MarkRead changed the history or kept the unread count")
+	}
+	e.ClearHistory()
+	if len(e.History()) != 0 {
+		t.Error("ClearHistory kept alerts")
+	}
+}
+
+func TestFormatAlertWindow(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Minute:                "1m",
+		15 * time.Minute:           "15m",
+		2 * time.Hour:              "2h",
+		90 * time.Second:           "1m30s",
+		30 * time.Second:           "30s",
+		time.Hour + 30*time.Minute: "1h30m",
+	} {
+		if got := formatAlertWindow(d); got != want {
+			t.Errorf("formatAlertWindow(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,7 +13,9 @@ import (
 // Displayed in the monitoring right pane when the user presses 'a' on a worker.
 type AnalyticsModel struct {
 	scriptName     string
-	timeRangeIndex int // index into api.TimeRanges
+	timeRangeIndex int            // index into api.TimeRanges
+	customRange    *api.TimeRange // absolute range from the 't' picker; overrides timeRangeIndex
+	rangePicker    rangePicker
 	metrics        *api.WorkerMetrics
 	loading        bool
 	err            error
@@ -22,6 +25,22 @@ type AnalyticsModel struct {
 	width          int
 	height         int
 	lastFetch      time.Time
+
+	// Per-version breakdown (toggled with 'v'). focusVersions holds version
+	// IDs (or 8-char prefixes) to show first, e.g. the versions of the active
+	// gradual deployment when opened from the wrangler view.
+	byVersion      bool
+	versionMetrics []*api.WorkerMetrics
+	versionLoading bool
+	versionErr     error
+	versionOffset  int // first version column shown (h/l to page)
+	focusVersions  []string
+
+	// Previous-period comparison (toggled with 'c')
+	compare     bool
+	prevMetrics *api.WorkerMetrics
+	prevLoading bool
+	prevErr     error
 }
 
 // NewAnalytics creates a new analytics model for the given worker.
@@ -51,12 +70,32 @@ func (a AnalyticsModel) IsLoading() bool {
 
 // TimeRangeLabel returns the current time range display label.
 func (a AnalyticsModel) TimeRangeLabel() string {
+	if a.customRange != nil {
+		return a.customRange.Label
+	}
 	if a.timeRangeIndex >= 0 && a.timeRangeIndex < len(api.TimeRanges) {
 		return api.TimeRanges[a.timeRangeIndex].Label
 	}
 	return "?"
 }
 
+// TimeRange returns the active time range: the custom range when one is set,
+// otherwise the selected preset.
+func (a AnalyticsModel) TimeRange() api.TimeRange {
+	if a.customRange != nil {
+		return *a.customRange
+	}
+	if a.timeRangeIndex >= 0 && a.timeRangeIndex < len(api.TimeRanges) {
+		return api.TimeRanges[a.timeRangeIndex]
+	}
+	return api.TimeRanges[0]
+}
+
+// PickerActive returns whether the custom range picker is capturing input.
+func (a AnalyticsModel) PickerActive() bool {
+	return a.rangePicker.active
+}
+
 // SetMetrics stores the fetched analytics data.
 func (a *AnalyticsModel) SetMetrics(m *api.WorkerMetrics) {
 	a.metrics = m
@@ -77,6 +116,87 @@ func (a *AnalyticsModel) SetError(err error) {
 func (a *AnalyticsModel) SetLoading() {
 	a.loading = true
 	a.err = nil
+	if a.byVersion {
+		a.versionLoading = true
+	}
+	if a.compare {
+		a.prevLoading = true
+	}
+}
+
+// SetCompareMetrics stores the previous-period metrics.
+func (a *AnalyticsModel) SetCompareMetrics(m *api.WorkerMetrics) {
+	a.prevMetrics = m
+	a.prevLoading = false
+	a.prevErr = nil
+}
+
+// SetCompareError records a previous-period fetch error.
+func (a *AnalyticsModel) SetCompareError(err error) {
+	a.prevErr = err
+	a.prevLoading = false
+}
+
+// ByVersion returns whether the per-version breakdown is shown.
+func (a AnalyticsModel) ByVersion() bool {
+	return a.byVersion
+}
+
+// ShowVersions enables the per-version breakdown, listing the given versions
+// (full IDs or short prefixes) first.
+func (a *AnalyticsModel) ShowVersions(focus []string) {
+	a.byVersion = true
+	a.versionLoading = true
+	a.versionOffset = 0
+	a.focusVersions = focus
+}
+
+// SetVersionMetrics stores the per-version analytics data, ordering focused
+// versions first.
+func (a *AnalyticsModel) SetVersionMetrics(data []*api.WorkerMetrics) {
+	var focused, rest []*api.WorkerMetrics
+	for _, vm := range data {
+		if a.isFocusVersion(vm.VersionID) {
+			focused = append(focused, vm)
+		} else {
+			rest = append(rest, vm)
+		}
+	}
+	a.versionMetrics = append(focused, rest...)
+	a.versionLoading = false
+	a.versionErr = nil
+	if a.versionOffset >= len(a.versionMetrics) {
+		a.versionOffset = 0
+	}
+}
+
+// SetVersionError records a per-version fetch error.
+func (a *AnalyticsModel) SetVersionError(err error) {
+	a.versionErr = err
+	a.versionLoading = false
+}
+
+// isFocusVersion reports whether the version ID matches one of the focus IDs.
+func (a AnalyticsModel) isFocusVersion(versionID string) bool {
+	for _, f := range a.focusVersions {
+		if f != "" && strings.HasPrefix(versionID, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestCmd returns a command asking the app to (re)fetch analytics for the
+// current time range, including the per-version breakdown when it is shown.
+func (a AnalyticsModel) requestCmd() tea.Cmd {
+	msg := AnalyticsRequestMsg{
+		ScriptName:     a.scriptName,
+		TimeRangeIndex: a.timeRangeIndex,
+		Custom:         a.customRange,
+		ByVersion:      a.byVersion,
+		Compare:        a.compare,
+	}
+	return func() tea.Msg { return msg }
 }
 
 // autoRefreshTickMsg fires every 30 seconds when auto-refresh is enabled.
@@ -88,42 +208,101 @@ type autoRefreshTickMsg struct {
 func (a AnalyticsModel) Update(msg tea.Msg) (AnalyticsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if a.rangePicker.active {
+			tr, cmd := a.rangePicker.update(msg)
+			if tr == nil {
+				return a, cmd
+			}
+			a.customRange = tr
+			a.SetLoading()
+			return a, a.requestCmd()
+		}
+
 		switch msg.String() {
 		case "esc":
 			// Close analytics view, return to grid
 			return a, func() tea.Msg { return AnalyticsCloseMsg{} }
 
 		case ",":
-			// Previous time range
-			if a.timeRangeIndex > 0 {
-				a.timeRangeIndex--
-				a.loading = true
-				idx := a.timeRangeIndex
-				name := a.scriptName
-				return a, func() tea.Msg {
-					return AnalyticsRequestMsg{ScriptName: name, TimeRangeIndex: idx}
+			// Previous time range (leaving a custom range returns to its preset)
+			if a.customRange != nil || a.timeRangeIndex > 0 {
+				if a.customRange != nil {
+					a.customRange = nil
+				} else {
+					a.timeRangeIndex--
 				}
+				a.SetLoading()
+				return a, a.requestCmd()
 			}
 
 		case ".":
 			// Next time range
-			if a.timeRangeIndex < len(api.TimeRanges)-1 {
-				a.timeRangeIndex++
-				a.loading = true
-				idx := a.timeRangeIndex
-				name := a.scriptName
-				return a, func() tea.Msg {
-					return AnalyticsRequestMsg{ScriptName: name, TimeRangeIndex: idx}
+			if a.customRange != nil || a.timeRangeIndex < len(api.TimeRanges)-1 {
+				if a.customRange != nil {
+					a.customRange = nil
+				} else {
+					a.timeRangeIndex++
 				}
+				a.SetLoading()
+				return a, a.requestCmd()
 			}
 
+		case "t":
+			// Custom absolute range, pre-filled with the current window
+			since, until := a.TimeRange().Window(time.Now())
+			a.rangePicker.open(since, until)
+
+		case "c":
+			// Toggle previous-period comparison; fetch when enabled
+			a.compare = !a.compare
+			if !a.compare {
+				a.prevMetrics = nil
+				a.prevErr = nil
+				a.prevLoading = false
+				return a, nil
+			}
+			a.prevLoading = true
+			msg := AnalyticsCompareRequestMsg{ScriptName: a.scriptName, TimeRangeIndex: a.timeRangeIndex, Custom: a.customRange}
+			return a, func() tea.Msg { return msg }
+
+		case "e", "E":
+			// Export the fetched series: e → CSV, E → JSON
+			if a.metrics == nil {
+				return a, nil
+			}
+			format := ExportFormatCSV
+			if msg.String() == "E" {
+				format = ExportFormatJSON
+			}
+			var prev *api.WorkerMetrics
+			if a.compare {
+				prev = a.prevMetrics
+			}
+			return a, exportAnalyticsCmd(a.metrics, prev, format)
+
 		case "r":
 			// Manual refresh
-			a.loading = true
-			idx := a.timeRangeIndex
-			name := a.scriptName
-			return a, func() tea.Msg {
-				return AnalyticsRequestMsg{ScriptName: name, TimeRangeIndex: idx}
+			a.SetLoading()
+			return a, a.requestCmd()
+
+		case "v":
+			// Toggle per-version breakdown; fetch on first use
+			a.byVersion = !a.byVersion
+			a.scrollY = 0
+			if a.byVersion && a.versionMetrics == nil && !a.versionLoading {
+				a.versionLoading = true
+				msg := AnalyticsVersionsRequestMsg{ScriptName: a.scriptName, TimeRangeIndex: a.timeRangeIndex, Custom: a.customRange}
+				return a, func() tea.Msg { return msg }
+			}
+
+		case "h", "left":
+			if a.byVersion && a.versionOffset > 0 {
+				a.versionOffset--
+			}
+
+		case "l", "right":
+			if a.byVersion && a.versionOffset < len(a.versionMetrics)-1 {
+				a.versionOffset++
 			}
 
 		case "R":
@@ -150,15 +329,8 @@ func (a AnalyticsModel) Update(msg tea.Msg) (AnalyticsModel, tea.Cmd) {
 		if !a.autoRefresh {
 			return a, nil
 		}
-		a.loading = true
-		idx := a.timeRangeIndex
-		name := a.scriptName
-		return a, tea.Batch(
-			func() tea.Msg {
-				return AnalyticsRequestMsg{ScriptName: name, TimeRangeIndex: idx}
-			},
-			a.autoRefreshCmd(),
-		)
+		a.SetLoading()
+		return a, tea.Batch(a.requestCmd(), a.autoRefreshCmd())
 	}
 
 	return a, nil
@@ -0,0 +1,183 @@
+package monitoring
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/api"
+)
+
+// Analytics export formats.
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// analyticsExport is the JSON document written by exportAnalytics.
+type analyticsExport struct {
+	ScriptName   string                  `json:"scriptName"`
+	Range        analyticsExportRange    `json:"range"`
+	Totals       analyticsExportTotals   `json:"totals"`
+	Previous     *analyticsExportTotals  `json:"previous,omitempty"`
+	StatusCounts map[string]int64        `json:"statusCounts"`
+	Buckets      []analyticsExportBucket `json:"buckets"`
+}
+
+type analyticsExportRange struct {
+	Label   string    `json:"label"`
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	GroupBy string    `json:"groupBy"`
+}
+
+type analyticsExportTotals struct {
+	Requests    int64   `json:"requests"`
+	Errors      int64   `json:"errors"`
+	Subrequests int64   `json:"subrequests"`
+	CPUTimeP50  float64 `json:"cpuTimeP50"`
+	CPUTimeP99  float64 `json:"cpuTimeP99"`
+}
+
+type analyticsExportBucket struct {
+	Datetime    time.Time `json:"datetime"`
+	Status      string    `json:"status"`
+	Requests    int64     `json:"requests"`
+	Errors      int64     `json:"errors"`
+	Subrequests int64     `json:"subrequests"`
+	CPUTimeP50  float64   `json:"cpuTimeP50"`
+	CPUTimeP99  float64   `json:"cpuTimeP99"`
+}
+
+func exportTotals(m *api.WorkerMetrics) analyticsExportTotals {
+	return analyticsExportTotals{
+		Requests:    m.TotalRequests,
+		Errors:      m.TotalErrors,
+		Subrequests: m.TotalSubrequests,
+		CPUTimeP50:  m.CPUTimeP50,
+		CPUTimeP99:  m.CPUTimeP99,
+	}
+}
+
+// exportAnalyticsCmd writes the fetched metrics to ~/.orangeshell/exports/ in
+// the background and reports the written files via AnalyticsExportedMsg.
+func exportAnalyticsCmd(m, prev *api.WorkerMetrics, format string) tea.Cmd {
+	return func() tea.Msg {
+		paths, err := exportAnalytics(m, prev, format, time.Now())
+		return AnalyticsExportedMsg{Paths: paths, Err: err}
+	}
+}
+
+// exportAnalytics writes the metrics in the given format. JSON produces a
+// single document; CSV produces a bucket series file and a status counts file.
+func exportAnalytics(m, prev *api.WorkerMetrics, format string, now time.Time) ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".orangeshell", "exports")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	base := filepath.Join(dir, fmt.Sprintf("analytics-%s-%s", sanitizeFilename(m.ScriptName), now.Format("20060102-150405")))
+
+	switch format {
+	case ExportFormatJSON:
+		doc := analyticsExport{
+			ScriptName: m.ScriptName,
+			Range: analyticsExportRange{
+				Label:   m.TimeRange.Label,
+				Since:   m.Since,
+				Until:   m.Until,
+				GroupBy: m.TimeRange.GroupBy,
+			},
+			Totals:       exportTotals(m),
+			StatusCounts: m.StatusCounts,
+			Buckets:      make([]analyticsExportBucket, 0, len(m.Buckets)),
+		}
+		if prev != nil {
+			t := exportTotals(prev)
+			doc.Previous = &t
+		}
+		for _, b := range m.Buckets {
+			doc.Buckets = append(doc.Buckets, analyticsExportBucket{
+				Datetime:    b.Datetime,
+				Status:      b.Status,
+				Requests:    b.Requests,
+				Errors:      b.Errors,
+				Subrequests: b.Subrequests,
+				CPUTimeP50:  b.CPUTimeP50,
+				CPUTimeP99:  b.CPUTimeP99,
+			})
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding analytics: %w", err)
+		}
+		path := base + ".json"
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+		return []string{path}, nil
+
+	case ExportFormatCSV:
+		seriesPath := base + "-series.csv"
+		series := [][]string{{"datetime", "status", "requests", "errors", "subrequests", "cpu_time_p50_us", "cpu_time_p99_us"}}
+		for _, b := range m.Buckets {
+			series = append(series, []string{
+				b.Datetime.UTC().Format(time.RFC3339),
+				b.Status,
+				strconv.FormatInt(b.Requests, 10),
+				strconv.FormatInt(b.Errors, 10),
+				strconv.FormatInt(b.Subrequests, 10),
+				strconv.FormatFloat(b.CPUTimeP50, 'f', -1, 64),
+				strconv.FormatFloat(b.CPUTimeP99, 'f', -1, 64),
+			})
+		}
+		if err := writeCSV(seriesPath, series); err != nil {
+			return nil, err
+		}
+
+		statusPath := base + "-status.csv"
+		statuses := make([]string, 0, len(m.StatusCounts))
+		for s := range m.StatusCounts {
+			statuses = append(statuses, s)
+		}
+		sort.Strings(statuses)
+		rows := [][]string{{"status", "requests"}}
+		for _, s := range statuses {
+			rows = append(rows, []string{s, strconv.FormatInt(m.StatusCounts[s], 10)})
+		}
+		if err := writeCSV(statusPath, rows); err != nil {
+			return nil, err
+		}
+		return []string{seriesPath, statusPath}, nil
+	}
+
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+func writeCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	// Close reports write errors the kernel deferred, e.g. a full disk
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package monitoring
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oarafat/orangeshell/internal/api"
+)
+
+func testExportMetrics() *api.WorkerMetrics {
+	at := time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)
+	return &api.WorkerMetrics{
+		ScriptName:    "dev:my-api",
+		TimeRange:     api.TimeRanges[0],
+		Since:         at.Add(-time.Hour),
+		Until:         at,
+		TotalRequests: 12,
+		TotalErrors:   2,
+		CPUTimeP99:    1500,
+		StatusCounts:  map[string]int64{"success": 10, "clientDisconnected": 2},
+		Buckets: []api.MetricsBucket{
+			{Datetime: at.Add(-time.Minute), Status: "success", Requests: 10, CPUTimeP50: 200.5},
+			{Datetime: at, Status: "clientDisconnected", Requests: 2, Errors: 2},
+		},
+	}
+}
+
+func TestExportAnalyticsCSV(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	now := time.Date(2026, 10, 18, 12, 30, 0, 0, time.Local)
+
+	paths, err := exportAnalytics(testExportMetrics(), nil, ExportFormatCSV, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(home, ".orangeshell", "exports")
+	want := []string{
+		filepath.Join(dir, "analytics-my-api-20261018-123000-series.csv"),
+		filepath.Join(dir, "analytics-my-api-20261018-123000-status.csv"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	series := readCSV(t, paths[0])
+	if len(series) != 3 || series[0][0] != "datetime" {
+		t.Fatalf("series = %v", series)
+	}
+	if got := series[1]; !reflect.DeepEqual(got, []string{"2026-10-18T10:59:00Z", "success", "10", "0", "0", "200.5", "0"}) {
+		t.Errorf("series row = %v", got)
+	}
+	status := readCSV(t, paths[1])
+	wantStatus := [][]string{{"status", "requests"}, {"clientDisconnected", "2"}, {"success", "10"}}
+	if !reflect.DeepEqual(status, wantStatus) {
+		t.Errorf("status = %v, want %v", status, wantStatus)
+	}
+}
+
+func TestExportAnalyticsJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := testExportMetrics()
+	prev := &api.WorkerMetrics{TotalRequests: 8}
+
+	paths, err := exportAnalytics(m, prev, ExportFormatJSON, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc analyticsExport
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.ScriptName != "dev:my-api" || doc.Range.Label != "1h" || !doc.Range.Until.Equal(m.Until) {
+		t.Errorf("header = %+v", doc)
+	}
+	if doc.Totals.Requests != 12 || doc.Totals.CPUTimeP99 != 1500 || len(doc.Buckets) != 2 {
+		t.Errorf("totals %+v, %d buckets", doc.Totals, len(doc.Buckets))
+	}
+	if doc.Previous == nil || doc.Previous.Requests != 8 {
+		t.Errorf("previous = %+v", doc.Previous)
+	}
+}
+
+func TestExportAnalyticsErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := exportAnalytics(testExportMetrics(), nil, "xml", time.Now()); err == nil {
+		t.Error("unknown format: no error")
+	}
+
+	// The export directory is a file: nothing can be written
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, ".orangeshell"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exportAnalytics(testExportMetrics(), nil, ExportFormatCSV, time.Now()); err == nil {
+		t.Error("unwritable directory: no error")
+	}
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
@@ -0,0 +1,125 @@
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// rangeInputLayout is the accepted format for custom range bounds (local time).
+const rangeInputLayout = "2006-01-02 15:04"
+
+// minCustomRange is the shortest custom window accepted by the picker.
+const minCustomRange = 5 * time.Minute
+
+// rangePicker is the inline start/end editor opened with 't' in the
+// analytics view. Times are entered in local time.
+type rangePicker struct {
+	active bool
+	inputs [2]textinput.Model // 0 = start, 1 = end
+	focus  int
+	err    string
+}
+
+// open activates the picker, pre-filled with the given window.
+func (p *rangePicker) open(since, until time.Time) {
+	for i := range p.inputs {
+		ti := textinput.New()
+		ti.Placeholder = rangeInputLayout
+		ti.CharLimit = len(rangeInputLayout)
+		ti.Width = len(rangeInputLayout) + 1
+		p.inputs[i] = ti
+	}
+	p.inputs[0].SetValue(since.Local().Format(rangeInputLayout))
+	p.inputs[1].SetValue(until.Local().Format(rangeInputLayout))
+	p.focus = 0
+	p.inputs[0].Focus()
+	p.err = ""
+	p.active = true
+}
+
+func (p *rangePicker) close() {
+	p.active = false
+	p.err = ""
+}
+
+// update handles a key while the picker is open. It returns the parsed range
+// when the user confirms a valid window with enter.
+func (p *rangePicker) update(msg tea.KeyMsg) (*api.TimeRange, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.close()
+		return nil, nil
+	case "tab", "shift+tab", "up", "down":
+		p.inputs[p.focus].Blur()
+		p.focus = 1 - p.focus
+		p.inputs[p.focus].Focus()
+		return nil, nil
+	case "enter":
+		tr, err := p.parse(time.Now())
+		if err != nil {
+			p.err = err.Error()
+			return nil, nil
+		}
+		p.close()
+		return &tr, nil
+	}
+
+	var cmd tea.Cmd
+	p.inputs[p.focus], cmd = p.inputs[p.focus].Update(msg)
+	p.err = ""
+	return nil, cmd
+}
+
+// parse validates the inputs and builds an absolute time range. An end time
+// in the future is clamped to now.
+func (p rangePicker) parse(now time.Time) (api.TimeRange, error) {
+	start, err := time.ParseInLocation(rangeInputLayout, strings.TrimSpace(p.inputs[0].Value()), time.Local)
+	if err != nil {
+		return api.TimeRange{}, fmt.Errorf("start: expected %s", rangeInputLayout)
+	}
+	end, err := time.ParseInLocation(rangeInputLayout, strings.TrimSpace(p.inputs[1].Value()), time.Local)
+	if err != nil {
+		return api.TimeRange{}, fmt.Errorf("end: expected %s", rangeInputLayout)
+	}
+	if end.After(now) {
+		end = now
+	}
+	if !start.Before(end) {
+		return api.TimeRange{}, fmt.Errorf("start must be before end")
+	}
+	if end.Sub(start) < minCustomRange {
+		return api.TimeRange{}, fmt.Errorf("range must be at least %s", minCustomRange)
+	}
+	return api.CustomTimeRange(start, end), nil
+}
+
+// view renders the picker as a two-line block below the analytics title.
+func (p rangePicker) view() string {
+	labelStyle := lipgloss.NewStyle().Foreground(theme.ColorGray)
+	activeStyle := lipgloss.NewStyle().Foreground(theme.ColorOrange).Bold(true)
+
+	labels := [2]string{"From", "To"}
+	var fields []string
+	for i, ti := range p.inputs {
+		style := labelStyle
+		if i == p.focus {
+			style = activeStyle
+		}
+		fields = append(fields, style.Render(labels[i])+" "+ti.View())
+	}
+
+	line := " " + strings.Join(fields, "   ")
+	hint := " " + theme.DimStyle.Render("tab switch  |  enter apply  |  esc cancel  (local time, "+rangeInputLayout+")")
+	if p.err != "" {
+		hint = " " + theme.ErrorStyle.Render(p.err)
+	}
+	return line + "\n" + hint
+}
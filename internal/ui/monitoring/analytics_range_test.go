@@ -0,0 +1,67 @@
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRangePickerParse(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	local := func(s string) time.Time {
+		tm, err := time.ParseInLocation(rangeInputLayout, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		err        string // substring of the error; "" for a valid range
+		wantStart  time.Time
+		wantEnd    time.Time
+		wantGroup  string
+	}{
+		{
+			name: "hours", start: "2026-10-18 08:00", end: "2026-10-18 10:30",
+			wantStart: local("2026-10-18 08:00"), wantEnd: local("2026-10-18 10:30"), wantGroup: "datetimeFiveMinutes",
+		},
+		{
+			name: "days", start: "2026-10-10 00:00", end: "2026-10-15 00:00",
+			wantStart: local("2026-10-10 00:00"), wantEnd: local("2026-10-15 00:00"), wantGroup: "datetimeHour",
+		},
+		{
+			name: "future end clamped to now", start: "2026-10-18 11:00", end: "2026-10-19 00:00",
+			wantStart: local("2026-10-18 11:00"), wantEnd: now, wantGroup: "datetimeMinute",
+		},
+		{name: "bad start", start: "18/10/2026", end: "2026-10-18 10:00", err: "start: expected"},
+		{name: "bad end", start: "2026-10-18 08:00", end: "", err: "end: expected"},
+		{name: "reversed", start: "2026-10-18 10:00", end: "2026-10-18 08:00", err: "start must be before end"},
+		{name: "empty after clamping", start: "2026-10-18 13:00", end: "2026-10-18 14:00", err: "start must be before end"},
+		{name: "too short", start: "2026-10-18 10:00", end: "2026-10-18 10:04", err: "at least 5m0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p rangePicker
+			p.open(now, now)
+			p.inputs[0].SetValue(tt.start)
+			p.inputs[1].SetValue(tt.end)
+
+			tr, err := p.parse(now)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tr.IsAbsolute() || !tr.Start.Equal(tt.wantStart) || !tr.End.Equal(tt.wantEnd) || tr.GroupBy != tt.wantGroup {
+				t.Errorf("got %v–%v by %s, want %v–%v by %s", tr.Start, tr.End, tr.GroupBy, tt.wantStart, tt.wantEnd, tt.wantGroup)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/oarafat/orangeshell/internal/api"
 	"github.com/oarafat/orangeshell/internal/ui/theme"
 )
 
@@ -16,6 +17,7 @@ type chartBucket struct {
 	dt       time.Time
 	requests int64
 	errors   int64
+	cpuP50   float64 // mean p50 CPU time across the merged status rows
 }
 
 // View renders the analytics dashboard for a single worker.
@@ -28,6 +30,9 @@ func (a AnalyticsModel) View(width, height int) string {
 
 	// Title bar
 	sections = append(sections, a.viewTitle(width))
+	if a.rangePicker.active {
+		sections = append(sections, a.rangePicker.view())
+	}
 
 	if a.loading && a.metrics == nil {
 		sections = append(sections, a.viewLoading(width))
@@ -40,13 +45,19 @@ func (a AnalyticsModel) View(width, height int) string {
 		sections = append(sections, a.viewSummaryCards(width))
 		sections = append(sections, "")
 
-		// Requests bar chart
-		sections = append(sections, a.viewRequestsChart(width))
-		sections = append(sections, "")
+		if a.byVersion {
+			// Side-by-side series per version
+			sections = append(sections, a.viewVersionBreakdown(width))
+			sections = append(sections, "")
+		} else {
+			// Requests bar chart
+			sections = append(sections, a.viewRequestsChart(width))
+			sections = append(sections, "")
 
-		// Status breakdown table
-		sections = append(sections, a.viewStatusBreakdown(width))
-		sections = append(sections, "")
+			// Status breakdown table
+			sections = append(sections, a.viewStatusBreakdown(width))
+			sections = append(sections, "")
+		}
 
 		// Error log
 		if len(a.metrics.Errors) > 0 {
@@ -98,6 +109,19 @@ func (a AnalyticsModel) viewTitle(width int) string {
 	if a.autoRefresh {
 		title += " " + lipgloss.NewStyle().Foreground(theme.ColorGreen).Render("⟳")
 	}
+	if a.byVersion {
+		title += " " + lipgloss.NewStyle().Foreground(theme.ColorYellow).Render("by version")
+	}
+	if a.compare {
+		tag := "vs previous"
+		switch {
+		case a.prevLoading:
+			tag += " ..."
+		case a.prevErr != nil:
+			tag += " (failed)"
+		}
+		title += " " + lipgloss.NewStyle().Foreground(theme.ColorYellow).Render(tag)
+	}
 
 	return title
 }
@@ -131,19 +155,27 @@ func (a AnalyticsModel) viewSummaryCards(width int) string {
 	cpuP50 := formatCPUTime(m.CPUTimeP50)
 	cpuP99 := formatCPUTime(m.CPUTimeP99)
 
-	// Card definitions
+	// Card definitions. cur/prev feed the previous-period delta line;
+	// higherIsBad flips the delta coloring for errors and CPU time.
 	type card struct {
-		label string
-		value string
-		color lipgloss.Color
+		label       string
+		value       string
+		color       lipgloss.Color
+		cur, prev   float64
+		higherIsBad bool
+	}
+	p := a.prevMetrics
+	if !a.compare || p == nil {
+		p = &api.WorkerMetrics{}
 	}
 	cards := []card{
-		{"Requests", formatCount(m.TotalRequests), theme.ColorBlue},
-		{"Errors", fmt.Sprintf("%s (%.1f%%)", formatCount(m.TotalErrors), errorRate), theme.ColorRed},
-		{"CPU p50", cpuP50, theme.ColorGreen},
-		{"CPU p99", cpuP99, theme.ColorYellow},
-		{"Subreqs", formatCount(m.TotalSubrequests), theme.ColorGray},
+		{"Requests", formatCount(m.TotalRequests), theme.ColorBlue, float64(m.TotalRequests), float64(p.TotalRequests), false},
+		{"Errors", fmt.Sprintf("%s (%.1f%%)", formatCount(m.TotalErrors), errorRate), theme.ColorRed, float64(m.TotalErrors), float64(p.TotalErrors), true},
+		{"CPU p50", cpuP50, theme.ColorGreen, m.CPUTimeP50, p.CPUTimeP50, true},
+		{"CPU p99", cpuP99, theme.ColorYellow, m.CPUTimeP99, p.CPUTimeP99, true},
+		{"Subreqs", formatCount(m.TotalSubrequests), theme.ColorGray, float64(m.TotalSubrequests), float64(p.TotalSubrequests), false},
 	}
+	showDeltas := a.compare && a.prevMetrics != nil
 
 	// Calculate card width (distribute evenly, accounting for border characters)
 	cardCount := len(cards)
@@ -163,6 +195,9 @@ func (a AnalyticsModel) viewSummaryCards(width int) string {
 		valueStyle := lipgloss.NewStyle().Bold(true).Foreground(c.color).Width(innerWidth)
 
 		cardContent := labelStyle.Render(c.label) + "\n" + valueStyle.Render(c.value)
+		if showDeltas {
+			cardContent += "\n" + renderDelta(c.cur, c.prev, c.higherIsBad, innerWidth)
+		}
 
 		cardStyle := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -176,6 +211,37 @@ func (a AnalyticsModel) viewSummaryCards(width int) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, cardViews...)
 }
 
+// renderDelta formats the change from the previous period as "▲ 12.3%".
+// Increases are green unless higherIsBad (errors, CPU), in which case red.
+func renderDelta(cur, prev float64, higherIsBad bool, width int) string {
+	style := lipgloss.NewStyle().Width(width)
+	if prev == 0 {
+		if cur == 0 {
+			return style.Foreground(theme.ColorGray).Render("= prev")
+		}
+		return style.Foreground(theme.ColorGray).Render("new vs prev")
+	}
+
+	pct := (cur - prev) / prev * 100
+	if math.Abs(pct) < 0.05 {
+		return style.Foreground(theme.ColorGray).Render("= prev")
+	}
+	arrow := "▲"
+	color := theme.ColorGreen
+	if pct < 0 {
+		arrow = "▼"
+		color = theme.ColorRed
+	}
+	if higherIsBad {
+		if pct < 0 {
+			color = theme.ColorGreen
+		} else {
+			color = theme.ColorRed
+		}
+	}
+	return style.Foreground(color).Render(fmt.Sprintf("%s %.1f%%", arrow, math.Abs(pct)))
+}
+
 // --- Requests Bar Chart ---
 
 func (a AnalyticsModel) viewRequestsChart(width int) string {
@@ -187,24 +253,7 @@ func (a AnalyticsModel) viewRequestsChart(width int) string {
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorWhite)
 	header := " " + headerStyle.Render("Requests over Time")
 
-	// Aggregate buckets by time (merge status dimensions)
-	merged := make(map[string]*chartBucket)
-	var orderedKeys []string
-	for _, b := range m.Buckets {
-		key := b.Datetime.Format(time.RFC3339)
-		if _, ok := merged[key]; !ok {
-			merged[key] = &chartBucket{dt: b.Datetime}
-			orderedKeys = append(orderedKeys, key)
-		}
-		merged[key].requests += b.Requests
-		merged[key].errors += b.Errors
-	}
-
-	// Convert to slice
-	var buckets []chartBucket
-	for _, k := range orderedKeys {
-		buckets = append(buckets, *merged[k])
-	}
+	buckets := mergeChartBuckets(m.Buckets)
 
 	// Downsample if too many buckets for display width
 	chartWidth := width - 4 // padding
@@ -440,6 +489,169 @@ func errorStatusDescription(status string) string {
 	}
 }
 
+// --- Per-version breakdown ---
+
+// versionColumnMinWidth is the narrowest a per-version column may get before
+// fewer columns are shown side by side.
+const versionColumnMinWidth = 30
+
+func (a AnalyticsModel) viewVersionBreakdown(width int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorWhite)
+	header := " " + headerStyle.Render("Versions")
+
+	switch {
+	case a.versionErr != nil:
+		return header + "\n " + theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", a.versionErr))
+	case a.versionMetrics == nil && a.versionLoading:
+		return header + "\n " + theme.DimStyle.Render("Fetching per-version analytics...")
+	case len(a.versionMetrics) == 0:
+		return header + "\n " + theme.DimStyle.Render("No per-version data in this period.")
+	}
+
+	cols := (width - 1) / versionColumnMinWidth
+	if cols < 1 {
+		cols = 1
+	}
+	remaining := len(a.versionMetrics) - a.versionOffset
+	if cols > remaining {
+		cols = remaining
+	}
+	colWidth := (width - 1) / cols
+
+	header += theme.DimStyle.Render(fmt.Sprintf("  %d-%d of %d", a.versionOffset+1, a.versionOffset+cols, len(a.versionMetrics)))
+	if len(a.versionMetrics) > cols {
+		header += theme.DimStyle.Render("  (h/l to page)")
+	}
+
+	var columns []string
+	for i := a.versionOffset; i < a.versionOffset+cols; i++ {
+		columns = append(columns, a.renderVersionColumn(a.versionMetrics[i], colWidth))
+	}
+
+	return header + "\n" + lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// renderVersionColumn renders the request, error and CPU series of a single
+// version as a bordered column.
+func (a AnalyticsModel) renderVersionColumn(vm *api.WorkerMetrics, colWidth int) string {
+	inner := colWidth - 4 // border + padding
+	if inner < 10 {
+		inner = 10
+	}
+
+	borderColor := theme.ColorDarkGray
+	idStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorWhite)
+	title := idStyle.Render("v" + shortVersionID(vm.VersionID))
+	if a.isFocusVersion(vm.VersionID) {
+		borderColor = theme.ColorOrange
+		title += " " + lipgloss.NewStyle().Foreground(theme.ColorOrange).Render("deployed")
+	}
+
+	errorRate := float64(0)
+	if vm.TotalRequests > 0 {
+		errorRate = float64(vm.TotalErrors) / float64(vm.TotalRequests) * 100
+	}
+
+	buckets := mergeChartBuckets(vm.Buckets)
+	var reqs, errs, cpu []float64
+	for _, b := range buckets {
+		reqs = append(reqs, float64(b.requests))
+		errs = append(errs, float64(b.errors))
+		cpu = append(cpu, b.cpuP50)
+	}
+
+	label := func(name, value string, color lipgloss.Color) string {
+		return theme.DimStyle.Render(fmt.Sprintf("%-9s", name)) +
+			lipgloss.NewStyle().Bold(true).Foreground(color).Render(value)
+	}
+
+	lines := []string{
+		title,
+		label("Requests", formatCount(vm.TotalRequests), theme.ColorBlue),
+		renderSeriesSparkline(reqs, inner, theme.ColorBlue, false),
+		label("Errors", fmt.Sprintf("%s (%.2f%%)", formatCount(vm.TotalErrors), errorRate), theme.ColorRed),
+		renderSeriesSparkline(errs, inner, theme.ColorRed, false),
+		label("CPU", fmt.Sprintf("%s / %s", formatCPUTime(vm.CPUTimeP50), formatCPUTime(vm.CPUTimeP99)), theme.ColorGreen),
+		renderSeriesSparkline(cpu, inner, theme.ColorGreen, true),
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(inner).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderSeriesSparkline draws a one-line sparkline of a value series,
+// downsampling to width cells. Counts are summed when merging cells;
+// gauges (mean=true, e.g. CPU time) are averaged.
+func renderSeriesSparkline(values []float64, width int, color lipgloss.Color, mean bool) string {
+	if len(values) == 0 {
+		return theme.DimStyle.Render(strings.Repeat("·", width))
+	}
+	if len(values) > width {
+		values = downsampleValues(values, width, mean)
+	}
+
+	var maxVal float64
+	for _, v := range values {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+
+	levels := []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	style := lipgloss.NewStyle().Foreground(color)
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 || maxVal == 0 {
+			sb.WriteString(theme.DimStyle.Render("▁"))
+			continue
+		}
+		sb.WriteString(style.Render(levels[int(v/maxVal*float64(len(levels)-1))]))
+	}
+	return sb.String()
+}
+
+// downsampleValues reduces a series to target points by merging adjacent values.
+func downsampleValues(values []float64, target int, mean bool) []float64 {
+	if len(values) <= target || target <= 0 {
+		return values
+	}
+	step := float64(len(values)) / float64(target)
+	result := make([]float64, target)
+	for i := 0; i < target; i++ {
+		start := int(float64(i) * step)
+		end := int(float64(i+1) * step)
+		if end > len(values) {
+			end = len(values)
+		}
+		if start >= end {
+			continue
+		}
+		var sum float64
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		if mean {
+			sum /= float64(end - start)
+		}
+		result[i] = sum
+	}
+	return result
+}
+
+func shortVersionID(id string) string {
+	if id == "" {
+		return "unknown"
+	}
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 // --- Formatting helpers ---
 
 func formatCount(n int64) string {
@@ -476,6 +688,36 @@ func formatTimeLabel(t time.Time) string {
 	return t.Format("Jan 02 15:04")
 }
 
+// mergeChartBuckets aggregates metrics buckets by time, merging the status
+// dimension so each time point appears once.
+func mergeChartBuckets(raw []api.MetricsBucket) []chartBucket {
+	merged := make(map[string]*chartBucket)
+	cpuSamples := make(map[string]int)
+	var orderedKeys []string
+	for _, b := range raw {
+		key := b.Datetime.Format(time.RFC3339)
+		if _, ok := merged[key]; !ok {
+			merged[key] = &chartBucket{dt: b.Datetime}
+			orderedKeys = append(orderedKeys, key)
+		}
+		merged[key].requests += b.Requests
+		merged[key].errors += b.Errors
+		if b.CPUTimeP50 > 0 {
+			merged[key].cpuP50 += b.CPUTimeP50
+			cpuSamples[key]++
+		}
+	}
+
+	buckets := make([]chartBucket, 0, len(orderedKeys))
+	for _, k := range orderedKeys {
+		if n := cpuSamples[k]; n > 0 {
+			merged[k].cpuP50 /= float64(n)
+		}
+		buckets = append(buckets, *merged[k])
+	}
+	return buckets
+}
+
 // downsampleChartBuckets reduces the number of chart buckets by merging adjacent ones.
 func downsampleChartBuckets(buckets []chartBucket, target int) []chartBucket {
 	if len(buckets) <= target || target <= 0 {
@@ -0,0 +1,287 @@
+package monitoring
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/oarafat/orangeshell/internal/api"
+)
+
+// dashboardSortColumn identifies the column the dashboard table is sorted by.
+type dashboardSortColumn int
+
+const (
+	sortByName dashboardSortColumn = iota
+	sortByRequests
+	sortByErrors
+	sortByErrorRate
+	sortByCPUP50
+	sortByCPUP99
+	dashboardSortColumnCount // sentinel — number of sortable columns
+)
+
+// dashboardRow is a single worker row in the environment dashboard.
+type dashboardRow struct {
+	scriptName string
+	metrics    *api.WorkerMetrics // nil until data arrives
+}
+
+func (r dashboardRow) errorRate() float64 {
+	if r.metrics == nil || r.metrics.TotalRequests == 0 {
+		return 0
+	}
+	return float64(r.metrics.TotalErrors) / float64(r.metrics.TotalRequests) * 100
+}
+
+// DashboardModel holds the state for the environment-wide analytics dashboard.
+// Displayed in the monitoring right pane when the user presses 'A' on a worker:
+// every Worker of that environment is listed with its totals and a sparkline,
+// and enter drills down into the single-worker AnalyticsModel.
+type DashboardModel struct {
+	envName        string
+	rows           []dashboardRow
+	timeRangeIndex int // index into api.TimeRanges
+	sortCol        dashboardSortColumn
+	sortDesc       bool
+	cursor         int
+	scrollY        int
+	loading        bool
+	err            error
+	width          int
+	height         int
+	lastFetch      time.Time
+}
+
+// NewDashboard creates a dashboard for the given environment and scripts.
+func NewDashboard(envName string, scriptNames []string) DashboardModel {
+	rows := make([]dashboardRow, len(scriptNames))
+	for i, name := range scriptNames {
+		rows[i] = dashboardRow{scriptName: name}
+	}
+	return DashboardModel{
+		envName:        envName,
+		rows:           rows,
+		timeRangeIndex: 2, // default to 24h
+		sortCol:        sortByRequests,
+		sortDesc:       true,
+	}
+}
+
+// SetSize updates the available dimensions.
+func (d *DashboardModel) SetSize(w, h int) {
+	d.width = w
+	d.height = h
+}
+
+// EnvName returns the environment shown by the dashboard.
+func (d DashboardModel) EnvName() string {
+	return d.envName
+}
+
+// ScriptNames returns the workers listed in the dashboard.
+func (d DashboardModel) ScriptNames() []string {
+	names := make([]string, len(d.rows))
+	for i, r := range d.rows {
+		names[i] = r.scriptName
+	}
+	return names
+}
+
+// TimeRangeIndex returns the selected index into api.TimeRanges.
+func (d DashboardModel) TimeRangeIndex() int {
+	return d.timeRangeIndex
+}
+
+// TimeRangeLabel returns the current time range display label.
+func (d DashboardModel) TimeRangeLabel() string {
+	if d.timeRangeIndex >= 0 && d.timeRangeIndex < len(api.TimeRanges) {
+		return api.TimeRanges[d.timeRangeIndex].Label
+	}
+	return "?"
+}
+
+// SetLoading marks the dashboard as loading.
+func (d *DashboardModel) SetLoading() {
+	d.loading = true
+	d.err = nil
+}
+
+// SetError records a fetch error.
+func (d *DashboardModel) SetError(err error) {
+	d.err = err
+	d.loading = false
+}
+
+// SetMetrics stores fetched metrics for all rows and re-sorts the table,
+// keeping the cursor on the same worker.
+func (d *DashboardModel) SetMetrics(data map[string]*api.WorkerMetrics) {
+	selected := d.selectedScript()
+	for i := range d.rows {
+		d.rows[i].metrics = data[d.rows[i].scriptName]
+	}
+	d.loading = false
+	d.err = nil
+	d.lastFetch = time.Now()
+	d.sortRows(selected)
+}
+
+func (d DashboardModel) selectedScript() string {
+	if d.cursor >= 0 && d.cursor < len(d.rows) {
+		return d.rows[d.cursor].scriptName
+	}
+	return ""
+}
+
+// sortRows orders the rows by the active column and moves the cursor to keep
+// the given script selected.
+func (d *DashboardModel) sortRows(keep string) {
+	less := func(a, b dashboardRow) bool {
+		if d.sortCol == sortByName {
+			return strings.ToLower(a.scriptName) < strings.ToLower(b.scriptName)
+		}
+		av, bv := d.sortKey(a), d.sortKey(b)
+		if av == bv {
+			return a.scriptName < b.scriptName
+		}
+		return av < bv
+	}
+
+	sort.SliceStable(d.rows, func(i, j int) bool {
+		if d.sortDesc {
+			return less(d.rows[j], d.rows[i])
+		}
+		return less(d.rows[i], d.rows[j])
+	})
+
+	for i, r := range d.rows {
+		if r.scriptName == keep {
+			d.cursor = i
+			break
+		}
+	}
+	d.adjustScroll()
+}
+
+// sortKey returns the numeric value of the active sort column for a row.
+// Rows without data sort as zero.
+func (d DashboardModel) sortKey(r dashboardRow) float64 {
+	m := r.metrics
+	if m == nil {
+		return 0
+	}
+	switch d.sortCol {
+	case sortByRequests:
+		return float64(m.TotalRequests)
+	case sortByErrors:
+		return float64(m.TotalErrors)
+	case sortByErrorRate:
+		return r.errorRate()
+	case sortByCPUP50:
+		return m.CPUTimeP50
+	case sortByCPUP99:
+		return m.CPUTimeP99
+	}
+	return 0
+}
+
+// requestCmd returns a command asking the app to (re)fetch dashboard data.
+func (d DashboardModel) requestCmd() tea.Cmd {
+	env := d.envName
+	names := d.ScriptNames()
+	idx := d.timeRangeIndex
+	return func() tea.Msg {
+		return DashboardRequestMsg{EnvName: env, ScriptNames: names, TimeRangeIndex: idx}
+	}
+}
+
+// Update handles key events for the dashboard.
+func (d DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		return d, func() tea.Msg { return DashboardCloseMsg{} }
+
+	case "j", "down":
+		if d.cursor < len(d.rows)-1 {
+			d.cursor++
+			d.adjustScroll()
+		}
+
+	case "k", "up":
+		if d.cursor > 0 {
+			d.cursor--
+			d.adjustScroll()
+		}
+
+	case ",":
+		if d.timeRangeIndex > 0 {
+			d.timeRangeIndex--
+			d.loading = true
+			return d, d.requestCmd()
+		}
+
+	case ".":
+		if d.timeRangeIndex < len(api.TimeRanges)-1 {
+			d.timeRangeIndex++
+			d.loading = true
+			return d, d.requestCmd()
+		}
+
+	case "r":
+		d.loading = true
+		return d, d.requestCmd()
+
+	case "s":
+		// Cycle sort column; numeric columns default to descending
+		d.sortCol = (d.sortCol + 1) % dashboardSortColumnCount
+		d.sortDesc = d.sortCol != sortByName
+		d.sortRows(d.selectedScript())
+
+	case "S":
+		d.sortDesc = !d.sortDesc
+		d.sortRows(d.selectedScript())
+
+	case "enter":
+		// Drill down into the single-worker analytics view
+		name := d.selectedScript()
+		if name == "" {
+			return d, nil
+		}
+		idx := d.timeRangeIndex
+		return d, func() tea.Msg {
+			return AnalyticsRequestMsg{ScriptName: name, TimeRangeIndex: idx}
+		}
+	}
+
+	return d, nil
+}
+
+// visibleRows is the number of table rows that fit below the title, summary
+// line and column header.
+func (d DashboardModel) visibleRows() int {
+	n := d.height - 5
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (d *DashboardModel) adjustScroll() {
+	visible := d.visibleRows()
+	if d.cursor < d.scrollY {
+		d.scrollY = d.cursor
+	}
+	if d.cursor >= d.scrollY+visible {
+		d.scrollY = d.cursor - visible + 1
+	}
+	if d.scrollY < 0 {
+		d.scrollY = 0
+	}
+}
@@ -0,0 +1,246 @@
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// dashboardNumColWidth is the width of each numeric column in the table.
+const dashboardNumColWidth = 10
+
+// dashboardColumns defines the header labels in table order.
+var dashboardColumns = []struct {
+	col   dashboardSortColumn
+	label string
+}{
+	{sortByName, "Worker"},
+	{sortByRequests, "Requests"},
+	{sortByErrors, "Errors"},
+	{sortByErrorRate, "Err %"},
+	{sortByCPUP50, "CPU p50"},
+	{sortByCPUP99, "CPU p99"},
+}
+
+// View renders the environment dashboard.
+func (d DashboardModel) View(width, height int) string {
+	if width < 20 || height < 5 {
+		return ""
+	}
+
+	nameW, sparkW := d.columnWidths(width)
+
+	lines := []string{d.viewTitle()}
+
+	switch {
+	case d.err != nil:
+		lines = append(lines, " "+theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", d.err)))
+	case len(d.rows) == 0:
+		lines = append(lines, " "+theme.DimStyle.Render("No deployed workers in this environment."))
+	default:
+		lines = append(lines, d.viewSummary(), "")
+		lines = append(lines, d.viewHeader(nameW, sparkW))
+
+		end := d.scrollY + d.visibleRows()
+		if end > len(d.rows) {
+			end = len(d.rows)
+		}
+		for i := d.scrollY; i < end; i++ {
+			lines = append(lines, d.viewRow(d.rows[i], i == d.cursor, nameW, sparkW))
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	widthStyle := lipgloss.NewStyle().Width(width)
+	for i, line := range lines {
+		lines[i] = widthStyle.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// columnWidths splits the space left after the numeric columns between the
+// worker name and the sparkline.
+func (d DashboardModel) columnWidths(width int) (nameW, sparkW int) {
+	remaining := width - 2 - dashboardNumColWidth*(len(dashboardColumns)-1) - 2
+	sparkW = remaining / 3
+	if sparkW > 30 {
+		sparkW = 30
+	}
+	if sparkW < 8 {
+		sparkW = 8
+	}
+	nameW = remaining - sparkW
+	if nameW < 10 {
+		nameW = 10
+	}
+	return nameW, sparkW
+}
+
+func (d DashboardModel) viewTitle() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorWhite)
+	envStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorOrange)
+	rangeStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue).Bold(true)
+
+	title := fmt.Sprintf(" %s  %s  %s  %s",
+		titleStyle.Render("Environment Analytics"),
+		envStyle.Render(d.envName),
+		rangeStyle.Render("["+d.TimeRangeLabel()+"]"),
+		theme.DimStyle.Render(fmt.Sprintf("%d workers", len(d.rows))))
+
+	if d.loading {
+		title += " " + theme.DimStyle.Render("loading...")
+	}
+	return title
+}
+
+// viewSummary renders the environment totals across all rows.
+func (d DashboardModel) viewSummary() string {
+	var requests, errs int64
+	for _, r := range d.rows {
+		if r.metrics != nil {
+			requests += r.metrics.TotalRequests
+			errs += r.metrics.TotalErrors
+		}
+	}
+	rate := float64(0)
+	if requests > 0 {
+		rate = float64(errs) / float64(requests) * 100
+	}
+
+	reqStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(theme.ColorRed).Bold(true)
+	return fmt.Sprintf(" %s %s   %s %s",
+		theme.DimStyle.Render("Total requests"), reqStyle.Render(formatCount(requests)),
+		theme.DimStyle.Render("errors"), errStyle.Render(fmt.Sprintf("%s (%.2f%%)", formatCount(errs), rate)))
+}
+
+func (d DashboardModel) viewHeader(nameW, sparkW int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorGray)
+	activeStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.ColorOrange)
+
+	arrow := "▲"
+	if d.sortDesc {
+		arrow = "▼"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("  ")
+	for i, c := range dashboardColumns {
+		label := c.label
+		style := headerStyle
+		if c.col == d.sortCol {
+			label += arrow
+			style = activeStyle
+		}
+		if i == 0 {
+			sb.WriteString(style.Render(padRight(label, nameW)))
+		} else {
+			sb.WriteString(style.Render(padLeft(label, dashboardNumColWidth)))
+		}
+	}
+	sb.WriteString("  ")
+	sb.WriteString(headerStyle.Render(padRight("Trend", sparkW)))
+	return sb.String()
+}
+
+func (d DashboardModel) viewRow(r dashboardRow, selected bool, nameW, sparkW int) string {
+	cursor := "  "
+	nameStyle := lipgloss.NewStyle().Foreground(theme.ColorWhite)
+	if selected {
+		cursor = lipgloss.NewStyle().Foreground(theme.ColorOrange).Bold(true).Render("> ")
+		nameStyle = nameStyle.Bold(true).Foreground(theme.ColorOrange)
+	}
+
+	name := nameStyle.Render(padRight(truncateStr(r.scriptName, nameW-1), nameW))
+	if r.metrics == nil {
+		return cursor + name + theme.DimStyle.Render(padLeft("—", dashboardNumColWidth))
+	}
+
+	m := r.metrics
+	rate := r.errorRate()
+	rateStyle := theme.DimStyle
+	switch {
+	case rate >= 5:
+		rateStyle = lipgloss.NewStyle().Foreground(theme.ColorRed).Bold(true)
+	case rate >= 1:
+		rateStyle = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+	}
+	errStyle := theme.DimStyle
+	if m.TotalErrors > 0 {
+		errStyle = lipgloss.NewStyle().Foreground(theme.ColorRed)
+	}
+	valueStyle := lipgloss.NewStyle().Foreground(theme.ColorWhite)
+
+	return cursor + name +
+		valueStyle.Render(padLeft(formatCount(m.TotalRequests), dashboardNumColWidth)) +
+		errStyle.Render(padLeft(formatCount(m.TotalErrors), dashboardNumColWidth)) +
+		rateStyle.Render(padLeft(fmt.Sprintf("%.2f%%", rate), dashboardNumColWidth)) +
+		valueStyle.Render(padLeft(formatCPUTime(m.CPUTimeP50), dashboardNumColWidth)) +
+		valueStyle.Render(padLeft(formatCPUTime(m.CPUTimeP99), dashboardNumColWidth)) +
+		"  " + renderSparkline(m.Buckets, sparkW)
+}
+
+// renderSparkline draws a one-line request sparkline. Cells are colored like
+// the bar chart: blue normally, orange/red when the bucket had errors.
+func renderSparkline(raw []api.MetricsBucket, width int) string {
+	buckets := mergeChartBuckets(raw)
+	if len(buckets) == 0 {
+		return theme.DimStyle.Render(strings.Repeat("·", width))
+	}
+	if len(buckets) > width {
+		buckets = downsampleChartBuckets(buckets, width)
+	}
+
+	var maxReq int64
+	for _, b := range buckets {
+		if b.requests > maxReq {
+			maxReq = b.requests
+		}
+	}
+
+	levels := []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	var sb strings.Builder
+	for _, b := range buckets {
+		if b.requests == 0 || maxReq == 0 {
+			sb.WriteString(theme.DimStyle.Render("▁"))
+			continue
+		}
+		idx := int(float64(b.requests) / float64(maxReq) * float64(len(levels)-1))
+		ch := levels[idx]
+		color := theme.ColorBlue
+		if b.errors > 0 {
+			color = theme.ColorOrange
+			if float64(b.errors)/float64(b.requests) > 0.5 {
+				color = theme.ColorRed
+			}
+		}
+		sb.WriteString(lipgloss.NewStyle().Foreground(color).Render(ch))
+	}
+	return sb.String()
+}
+
+func padRight(s string, w int) string {
+	n := len([]rune(s))
+	if n >= w {
+		return s
+	}
+	return s + strings.Repeat(" ", w-n)
+}
+
+func padLeft(s string, w int) string {
+	n := len([]rune(s))
+	if n >= w {
+		return s
+	}
+	return strings.Repeat(" ", w-n) + s
+}
@@ -29,6 +29,11 @@ type Model struct {
 	showAnalytics bool
 	analyticsView AnalyticsModel
 
+	// Environment dashboard (replaces grid when active). Drilling down into a
+	// worker opens the analytics view on top; closing it returns here.
+	showDashboard bool
+	dashboardView DashboardModel
+
 	// Focus
 	focusPane FocusPane
 
@@ -56,6 +61,9 @@ func (m *Model) SetSize(w, h int) {
 	if m.showAnalytics {
 		m.analyticsView.SetSize(m.analyticsRightWidth(), h)
 	}
+	if m.showDashboard {
+		m.dashboardView.SetSize(m.analyticsRightWidth(), h)
+	}
 }
 
 // --- Worker tree API ---
@@ -119,19 +127,104 @@ func (m Model) AnalyticsScript() string {
 	return ""
 }
 
-// OpenAnalytics switches the right pane to the analytics view for the given script.
-func (m *Model) OpenAnalytics(scriptName string) {
+// OpenAnalytics switches the right pane to the analytics view for the given
+// script, starting at the given index into api.TimeRanges.
+func (m *Model) OpenAnalytics(scriptName string, timeRangeIndex int) {
 	m.showAnalytics = true
 	m.analyticsView = NewAnalytics(scriptName)
+	if timeRangeIndex >= 0 && timeRangeIndex < len(api.TimeRanges) {
+		m.analyticsView.timeRangeIndex = timeRangeIndex
+	}
 	m.analyticsView.SetSize(m.analyticsRightWidth(), m.height)
 	m.analyticsView.SetLoading()
 }
 
-// CloseAnalytics switches back to the grid view.
+// ShowAnalyticsVersions enables the per-version breakdown in the open
+// analytics view, listing the given version IDs (or short prefixes) first.
+func (m *Model) ShowAnalyticsVersions(focus []string) {
+	m.analyticsView.ShowVersions(focus)
+}
+
+// AnalyticsTimeRangeIndex returns the analytics view's index into api.TimeRanges.
+func (m Model) AnalyticsTimeRangeIndex() int {
+	return m.analyticsView.timeRangeIndex
+}
+
+// SetAnalyticsVersionMetrics stores the per-version breakdown.
+func (m *Model) SetAnalyticsVersionMetrics(data []*api.WorkerMetrics) {
+	m.analyticsView.SetVersionMetrics(data)
+}
+
+// SetAnalyticsVersionError records a per-version fetch error.
+func (m *Model) SetAnalyticsVersionError(err error) {
+	m.analyticsView.SetVersionError(err)
+}
+
+// CloseAnalytics switches back to the grid view, or to the environment
+// dashboard if the analytics view was opened from it.
 func (m *Model) CloseAnalytics() {
 	m.showAnalytics = false
 }
 
+// ShowDashboard returns whether the environment dashboard is active
+// (possibly underneath a drilled-down analytics view).
+func (m Model) ShowDashboard() bool {
+	return m.showDashboard
+}
+
+// DashboardEnv returns the environment shown by the dashboard (empty if not shown).
+func (m Model) DashboardEnv() string {
+	if m.showDashboard {
+		return m.dashboardView.EnvName()
+	}
+	return ""
+}
+
+// OpenDashboard switches the right pane to the environment dashboard.
+func (m *Model) OpenDashboard(envName string, scriptNames []string, timeRangeIndex int) {
+	m.showDashboard = true
+	m.showAnalytics = false
+	m.dashboardView = NewDashboard(envName, scriptNames)
+	if timeRangeIndex >= 0 && timeRangeIndex < len(api.TimeRanges) {
+		m.dashboardView.timeRangeIndex = timeRangeIndex
+	}
+	m.dashboardView.SetSize(m.analyticsRightWidth(), m.height)
+	m.dashboardView.SetLoading()
+}
+
+// CloseDashboard switches back to the grid view.
+func (m *Model) CloseDashboard() {
+	m.showDashboard = false
+	m.showAnalytics = false
+}
+
+// SetDashboardMetrics stores batched analytics data for the dashboard.
+func (m *Model) SetDashboardMetrics(data map[string]*api.WorkerMetrics) {
+	m.dashboardView.SetMetrics(data)
+}
+
+// SetDashboardError records a fetch error in the dashboard.
+func (m *Model) SetDashboardError(err error) {
+	m.dashboardView.SetError(err)
+}
+
+// envScripts returns the deployed (non-dev) workers of the given environment
+// across all projects in the worker tree, in tree order.
+func (m Model) envScripts(envName string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, e := range m.workerTree {
+		if e.IsHeader || e.IsDev || e.ScriptName == "" || e.EnvName != envName {
+			continue
+		}
+		if !seen[e.ScriptName] {
+			seen[e.ScriptName] = true
+			names = append(names, e.ScriptName)
+		}
+	}
+	return names
+}
+
 // SetAnalyticsMetrics stores fetched analytics data.
 func (m *Model) SetAnalyticsMetrics(metrics *api.WorkerMetrics) {
 	m.analyticsView.SetMetrics(metrics)
@@ -142,6 +235,27 @@ func (m *Model) SetAnalyticsError(err error) {
 	m.analyticsView.SetError(err)
 }
 
+// AnalyticsTimeRange returns the analytics view's active time range.
+func (m Model) AnalyticsTimeRange() api.TimeRange {
+	return m.analyticsView.TimeRange()
+}
+
+// AnalyticsPickerActive returns whether the analytics custom range picker is
+// open (it captures esc and text input).
+func (m Model) AnalyticsPickerActive() bool {
+	return m.showAnalytics && m.analyticsView.PickerActive()
+}
+
+// SetAnalyticsCompareMetrics stores previous-period metrics in the analytics view.
+func (m *Model) SetAnalyticsCompareMetrics(metrics *api.WorkerMetrics) {
+	m.analyticsView.SetCompareMetrics(metrics)
+}
+
+// SetAnalyticsCompareError records a previous-period fetch error.
+func (m *Model) SetAnalyticsCompareError(err error) {
+	m.analyticsView.SetCompareError(err)
+}
+
 // AnalyticsTimeRangeLabel returns the current time range label of the analytics view.
 func (m Model) AnalyticsTimeRangeLabel() string {
 	if m.showAnalytics {
@@ -519,6 +633,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.analyticsView, cmd = m.analyticsView.Update(msg)
 			return m, cmd
 		}
+		if m.showDashboard {
+			var cmd tea.Cmd
+			m.dashboardView, cmd = m.dashboardView.Update(msg)
+			return m, cmd
+		}
 
 		// Tab switches focus between panes
 		if msg.String() == "tab" {
@@ -573,6 +692,20 @@ func (m Model) updateLeftPane(msg tea.KeyMsg) (Model, tea.Cmd) {
 				}
 			}
 		}
+	case "A":
+		// Open the environment dashboard for the focused worker's environment
+		if m.treeCursor >= 0 && m.treeCursor < len(m.workerTree) {
+			entry := m.workerTree[m.treeCursor]
+			if !entry.IsHeader && !entry.IsDev {
+				envName := entry.EnvName
+				names := m.envScripts(envName)
+				if len(names) > 0 {
+					return m, func() tea.Msg {
+						return DashboardRequestMsg{EnvName: envName, ScriptNames: names, TimeRangeIndex: 2} // default 24h
+					}
+				}
+			}
+		}
 	case "c":
 		// Trigger cron handler on focused dev worker
 		if m.treeCursor >= 0 && m.treeCursor < len(m.workerTree) {
@@ -128,7 +128,10 @@ type GridPaneInfo struct {
 // AnalyticsRequestMsg requests the app to fetch analytics for a worker.
 type AnalyticsRequestMsg struct {
 	ScriptName     string
-	TimeRangeIndex int // index into api.TimeRanges
+	TimeRangeIndex int            // index into api.TimeRanges
+	Custom         *api.TimeRange // absolute range; overrides TimeRangeIndex when set
+	ByVersion      bool           // also refresh the per-version breakdown
+	Compare        bool           // also fetch the previous period for deltas
 }
 
 // AnalyticsDataMsg carries the analytics response back to the monitoring model.
@@ -138,5 +141,61 @@ type AnalyticsDataMsg struct {
 	Err        error
 }
 
+// AnalyticsVersionsRequestMsg requests the app to fetch the per-version
+// breakdown for a worker (grouped by scriptVersion).
+type AnalyticsVersionsRequestMsg struct {
+	ScriptName     string
+	TimeRangeIndex int            // index into api.TimeRanges
+	Custom         *api.TimeRange // absolute range; overrides TimeRangeIndex when set
+}
+
+// AnalyticsVersionsDataMsg carries the per-version analytics back to the model.
+type AnalyticsVersionsDataMsg struct {
+	ScriptName string
+	Data       []*api.WorkerMetrics // busiest version first
+	Err        error
+}
+
+// AnalyticsCompareRequestMsg requests the app to fetch the period preceding
+// the analytics view's current range, for the deltas on the summary cards.
+type AnalyticsCompareRequestMsg struct {
+	ScriptName     string
+	TimeRangeIndex int            // index into api.TimeRanges
+	Custom         *api.TimeRange // absolute range; overrides TimeRangeIndex when set
+}
+
+// AnalyticsCompareDataMsg carries the previous-period metrics back to the model.
+type AnalyticsCompareDataMsg struct {
+	ScriptName string
+	Data       *api.WorkerMetrics
+	Err        error
+}
+
+// AnalyticsExportedMsg reports the result of an analytics CSV/JSON export.
+type AnalyticsExportedMsg struct {
+	Paths []string
+	Err   error
+}
+
 // AnalyticsCloseMsg signals that the analytics view should be closed.
 type AnalyticsCloseMsg struct{}
+
+// --- Environment dashboard messages ---
+
+// DashboardRequestMsg requests the app to fetch analytics for every worker
+// shown in the environment dashboard (batched into as few queries as possible).
+type DashboardRequestMsg struct {
+	EnvName        string
+	ScriptNames    []string
+	TimeRangeIndex int // index into api.TimeRanges
+}
+
+// DashboardDataMsg carries the batched analytics response back to the dashboard.
+type DashboardDataMsg struct {
+	EnvName string
+	Data    map[string]*api.WorkerMetrics
+	Err     error
+}
+
+// DashboardCloseMsg signals that the environment dashboard should be closed.
+type DashboardCloseMsg struct{}
@@ -54,10 +54,12 @@ func (m Model) viewDualPane() string {
 
 	leftView := m.viewWorkerTree(leftWidth, contentHeight)
 
-	// Right pane: analytics view or tail grid
+	// Right pane: analytics view, environment dashboard, or tail grid
 	var rightView string
 	if m.showAnalytics {
 		rightView = m.analyticsView.View(rightWidth, contentHeight)
+	} else if m.showDashboard {
+		rightView = m.dashboardView.View(rightWidth, contentHeight)
 	} else {
 		rightView = m.viewTailGrid(rightWidth, contentHeight)
 	}
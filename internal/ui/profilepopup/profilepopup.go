@@ -0,0 +1,207 @@
+// Package profilepopup provides the credential profile switcher overlay:
+// the configured profiles, and an input for adding a new one.
+package profilepopup
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/config"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// SwitchMsg asks the app to make a profile active.
+type SwitchMsg struct {
+	Name string
+}
+
+// NewProfileMsg asks the app to create a profile and run setup for it.
+type NewProfileMsg struct {
+	Name string
+}
+
+// CloseMsg signals that the popup should be dismissed.
+type CloseMsg struct{}
+
+// Entry describes one profile in the list.
+type Entry struct {
+	Name       string
+	AuthMethod config.AuthMethod
+	AccountID  string
+	Active     bool
+	Bound      bool // bound to the open directory by .orangeshell.toml
+}
+
+// validName matches profile names: they become keys under [profiles] in
+// config.toml, so stick to bare-key characters.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Model represents the profile switcher overlay.
+type Model struct {
+	entries []Entry
+	cursor  int
+	adding  bool
+	input   textinput.Model
+	err     string
+	store   string // where credentials are stored, e.g. "macOS Keychain"
+}
+
+// New creates the switcher with the cursor on the active profile. store
+// describes where the credentials are kept.
+func New(entries []Entry, store string) Model {
+	ti := textinput.New()
+	ti.Placeholder = "client-name"
+	ti.CharLimit = 40
+	ti.Width = 30
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+
+	m := Model{entries: entries, input: ti, store: store}
+	for i, e := range entries {
+		if e.Active {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.adding {
+		switch keyMsg.String() {
+		case "esc":
+			m.adding = false
+			m.err = ""
+			m.input.Blur()
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(m.input.Value())
+			if !validName.MatchString(name) {
+				m.err = "Names may contain only letters, digits, - and _"
+				return m, nil
+			}
+			for _, e := range m.entries {
+				if e.Name == name {
+					m.err = fmt.Sprintf("Profile %q already exists", name)
+					return m, nil
+				}
+			}
+			return m, func() tea.Msg { return NewProfileMsg{Name: name} }
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		m.err = ""
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "esc", "ctrl+o":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "n":
+		m.adding = true
+		m.input.SetValue("")
+		m.input.Focus()
+	case "enter":
+		if m.cursor < len(m.entries) {
+			name := m.entries[m.cursor].Name
+			return m, func() tea.Msg { return SwitchMsg{Name: name} }
+		}
+	}
+	return m, nil
+}
+
+// authLabel is the short name of an auth method.
+func authLabel(method config.AuthMethod) string {
+	switch method {
+	case config.AuthMethodAPIKey:
+		return "API Key"
+	case config.AuthMethodAPIToken:
+		return "API Token"
+	case config.AuthMethodOAuth:
+		return "OAuth"
+	}
+	return "not set up"
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth / 2
+	if popupWidth < 50 {
+		popupWidth = 50
+	}
+	if popupWidth > 80 {
+		popupWidth = 80
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render("  Profiles")
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	var lines []string
+	for i, e := range m.entries {
+		cursor := "  "
+		style := theme.ActionItemStyle
+		if i == m.cursor && !m.adding {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		mark := "  "
+		if e.Active {
+			mark = theme.SuccessStyle.Render("● ")
+		}
+		desc := authLabel(e.AuthMethod)
+		if e.AccountID != "" {
+			desc += " · " + e.AccountID
+		}
+		if e.Bound {
+			desc += " · bound to this directory"
+		}
+		lines = append(lines, lineStyle.Render(fmt.Sprintf("%s%s%s  %s", cursor, mark, style.Render(e.Name), theme.DimStyle.Render(desc))))
+	}
+
+	if m.adding {
+		lines = append(lines, "", fmt.Sprintf("  %s  %s", theme.SelectedItemStyle.Render("New profile"), m.input.View()))
+	}
+	if m.err != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.err))
+	}
+	if m.store != "" {
+		lines = append(lines, "", lineStyle.Render(theme.DimStyle.Render("  Credentials stored in "+m.store)))
+	}
+
+	help := theme.DimStyle.Render("  esc close  |  enter switch  |  n new profile")
+	if m.adding {
+		help = theme.DimStyle.Render("  esc cancel  |  enter create and set up")
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, lines...)
+	parts = append(parts, sep, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
@@ -0,0 +1,816 @@
+// Package requestpopup provides the HTTP request composer: a request sent to
+// a project's dev server or deployed Worker, its response with headers and
+// timing, and the tail logs of the invocation that served it.
+package requestpopup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+)
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// SendMsg asks the app to send a request to a target.
+type SendMsg struct {
+	Request service.HTTPRequest
+	Target  Target
+}
+
+// SaveMsg asks the app to save a request to the project's collection.
+type SaveMsg struct {
+	Name    string
+	Request wcfg.SavedRequest
+}
+
+// DeleteMsg asks the app to delete a saved request.
+type DeleteMsg struct {
+	Name string
+}
+
+// TailMsg asks the app to start tailing a deployed Worker, so the logs of
+// the next request show up.
+type TailMsg struct {
+	Script string
+}
+
+// --- Messages received from app.go ---
+
+// ResponseMsg delivers the response to the last SendMsg.
+type ResponseMsg struct {
+	Resp *service.HTTPResponse
+	Err  error
+}
+
+// LogsMsg delivers tail lines correlated with the last request.
+type LogsMsg struct {
+	Lines []service.TailLine
+}
+
+// SavedMsg delivers the project's collection after a save or delete.
+type SavedMsg struct {
+	Requests map[string]wcfg.SavedRequest
+	Notice   string
+	Err      error
+}
+
+// --- Data model ---
+
+// Target is a base URL requests can be sent to.
+type Target struct {
+	Label   string // e.g. "dev · default" or "production · workers.dev"
+	BaseURL string // e.g. "http://localhost:8787"
+	Script  string // tail key whose logs are correlated: "dev:<name>" or the worker name
+	Dev     bool
+	Tailing bool // a deployed Worker's logs are being tailed
+}
+
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+type field int
+
+const (
+	fieldTarget field = iota
+	fieldMethod
+	fieldPath
+	fieldHeaders
+	fieldBody
+	fieldResponse
+	fieldCount
+)
+
+type mode int
+
+const (
+	modeCompose mode = iota
+	modeCollection
+	modeSaveName
+)
+
+type respTab int
+
+const (
+	tabBody respTab = iota
+	tabHeaders
+	tabLogs
+)
+
+// Model is the request composer state.
+type Model struct {
+	project string
+	targets []Target
+	target  int
+	method  int
+	path    textinput.Model
+	headers textarea.Model
+	body    textarea.Model
+	focus   field
+	mode    mode
+
+	saved     map[string]wcfg.SavedRequest
+	names     []string
+	savedPick int
+	nameInput textinput.Model
+
+	sending bool
+	sent    string // "METHOD URL" of the last request
+	resp    *service.HTTPResponse
+	respErr error
+	logs    []service.TailLine
+	tab     respTab
+	scroll  int
+
+	status    string
+	statusErr bool
+	spinner   spinner.Model
+	width     int
+	height    int
+}
+
+// New creates the composer for a project's targets and saved requests.
+func New(project string, targets []Target, saved map[string]wcfg.SavedRequest) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	path := textinput.New()
+	path.Prompt = ""
+	path.Placeholder = "/"
+	path.CharLimit = 0
+	path.TextStyle = theme.ValueStyle
+	path.PlaceholderStyle = theme.DimStyle
+
+	name := textinput.New()
+	name.Prompt = ""
+	name.Placeholder = "request name"
+	name.CharLimit = 60
+	name.TextStyle = theme.ValueStyle
+	name.PlaceholderStyle = theme.DimStyle
+
+	m := Model{
+		project:   project,
+		targets:   targets,
+		path:      path,
+		headers:   newArea("Content-Type: application/json", 3),
+		body:      newArea("request body", 5),
+		nameInput: name,
+		spinner:   s,
+		focus:     fieldPath,
+	}
+	m.setSaved(saved)
+	m.path.Focus()
+	return m
+}
+
+func newArea(placeholder string, height int) textarea.Model {
+	ta := textarea.New()
+	ta.Prompt = "  "
+	ta.ShowLineNumbers = false
+	ta.Placeholder = placeholder
+	ta.CharLimit = 0
+	ta.SetHeight(height)
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.Blur()
+	return ta
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// SetSize sets the terminal size the popup fits in.
+func (m *Model) SetSize(width, height int) {
+	m.width, m.height = width, height
+	w := m.innerWidth() - 2
+	m.headers.SetWidth(w)
+	m.body.SetWidth(w)
+	m.path.Width = w - 24
+}
+
+// SetTailing marks a deployed Worker's logs as tailed.
+func (m *Model) SetTailing(script string) {
+	for i := range m.targets {
+		if m.targets[i].Script == script {
+			m.targets[i].Tailing = true
+		}
+	}
+}
+
+func (m *Model) setSaved(saved map[string]wcfg.SavedRequest) {
+	m.saved = saved
+	m.names = nil
+	for name := range saved {
+		m.names = append(m.names, name)
+	}
+	sort.Strings(m.names)
+	m.savedPick = max(0, min(m.savedPick, len(m.names)-1))
+}
+
+func (m *Model) setStatus(s string) {
+	m.status, m.statusErr = s, false
+}
+
+func (m *Model) setError(s string) {
+	m.status, m.statusErr = s, true
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResponseMsg:
+		if !m.sending {
+			return m, nil
+		}
+		m.sending = false
+		m.resp, m.respErr = msg.Resp, msg.Err
+		m.tab, m.scroll = tabBody, 0
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+		} else {
+			m.setStatus("")
+		}
+		return m, nil
+
+	case LogsMsg:
+		m.logs = append(m.logs, msg.Lines...)
+		return m, nil
+
+	case SavedMsg:
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+			return m, nil
+		}
+		m.setSaved(msg.Requests)
+		m.setStatus(msg.Notice)
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.sending {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeCollection:
+			return m.updateCollection(msg)
+		case modeSaveName:
+			return m.updateSaveName(msg)
+		}
+		return m.updateCompose(msg)
+
+	default:
+		// Cursor blinks of the focused input
+		return m.updateFocused(msg)
+	}
+}
+
+func (m Model) updateCompose(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "tab":
+		return m.setFocus((m.focus + 1) % fieldCount)
+	case "shift+tab":
+		return m.setFocus((m.focus + fieldCount - 1) % fieldCount)
+	case "ctrl+s":
+		return m.send()
+	case "ctrl+o":
+		m.mode = modeCollection
+		return m, nil
+	case "ctrl+l":
+		if t, ok := m.currentTarget(); ok && !t.Dev && !t.Tailing {
+			script := t.Script
+			m.setStatus("Starting tail on " + script + "...")
+			return m, func() tea.Msg { return TailMsg{Script: script} }
+		}
+		return m, nil
+	}
+
+	switch m.focus {
+	case fieldTarget:
+		switch msg.String() {
+		case "left", "h":
+			if len(m.targets) > 0 {
+				m.target = (m.target + len(m.targets) - 1) % len(m.targets)
+			}
+		case "right", "l":
+			if len(m.targets) > 0 {
+				m.target = (m.target + 1) % len(m.targets)
+			}
+		case "enter":
+			return m.send()
+		}
+		return m, nil
+	case fieldMethod:
+		switch msg.String() {
+		case "left", "h":
+			m.method = (m.method + len(methods) - 1) % len(methods)
+		case "right", "l":
+			m.method = (m.method + 1) % len(methods)
+		case "enter":
+			return m.send()
+		}
+		return m, nil
+	case fieldPath:
+		if msg.String() == "enter" {
+			return m.send()
+		}
+	case fieldResponse:
+		switch msg.String() {
+		case "left", "h":
+			m.tab = (m.tab + 2) % 3
+			m.scroll = 0
+		case "right", "l":
+			m.tab = (m.tab + 1) % 3
+			m.scroll = 0
+		case "up", "k":
+			m.scroll = max(0, m.scroll-1)
+		case "down", "j":
+			m.scroll = min(m.scroll+1, max(0, len(m.responseLines())-m.responseHeight()))
+		case "pgup":
+			m.scroll = max(0, m.scroll-m.responseHeight())
+		case "pgdown":
+			m.scroll = min(m.scroll+m.responseHeight(), max(0, len(m.responseLines())-m.responseHeight()))
+		}
+		return m, nil
+	}
+	return m.updateFocused(msg)
+}
+
+// updateFocused passes msg to the focused text input.
+func (m Model) updateFocused(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch {
+	case m.mode == modeSaveName:
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	case m.focus == fieldPath:
+		m.path, cmd = m.path.Update(msg)
+	case m.focus == fieldHeaders:
+		m.headers, cmd = m.headers.Update(msg)
+	case m.focus == fieldBody:
+		m.body, cmd = m.body.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) setFocus(f field) (Model, tea.Cmd) {
+	m.focus = f
+	m.path.Blur()
+	m.headers.Blur()
+	m.body.Blur()
+	var cmd tea.Cmd
+	switch f {
+	case fieldPath:
+		cmd = m.path.Focus()
+	case fieldHeaders:
+		cmd = m.headers.Focus()
+	case fieldBody:
+		cmd = m.body.Focus()
+	}
+	return m, cmd
+}
+
+func (m Model) currentTarget() (Target, bool) {
+	if m.target < 0 || m.target >= len(m.targets) {
+		return Target{}, false
+	}
+	return m.targets[m.target], true
+}
+
+// send builds the request from the form and asks the app to send it.
+func (m Model) send() (Model, tea.Cmd) {
+	if m.sending {
+		return m, nil
+	}
+	t, ok := m.currentTarget()
+	if !ok {
+		m.setError("No target: start a dev server or deploy the Worker first")
+		return m, nil
+	}
+	req, err := m.request(t)
+	if err != nil {
+		m.setError(err.Error())
+		return m, nil
+	}
+	m.sending = true
+	m.sent = req.Method + " " + req.URL
+	m.resp, m.respErr, m.logs = nil, nil, nil
+	m.setStatus("")
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg { return SendMsg{Request: req, Target: t} })
+}
+
+// request builds the request to t from the form.
+func (m Model) request(t Target) (service.HTTPRequest, error) {
+	req := service.HTTPRequest{
+		Method: methods[m.method],
+		URL:    joinURL(t.BaseURL, m.path.Value()),
+		Body:   m.body.Value(),
+	}
+	for i, line := range strings.Split(m.headers.Value(), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		h, ok := service.ParseHTTPHeader(line)
+		if !ok {
+			return req, fmt.Errorf("header line %d: want \"Name: value\"", i+1)
+		}
+		req.Headers = append(req.Headers, h)
+	}
+	return req, nil
+}
+
+// joinURL resolves path against base; an absolute URL is used as is.
+func joinURL(base, path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+// savedRequest returns the form as a request for the collection.
+func (m Model) savedRequest() wcfg.SavedRequest {
+	r := wcfg.SavedRequest{Method: methods[m.method], Path: m.path.Value(), Body: m.body.Value()}
+	for _, line := range strings.Split(m.headers.Value(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			r.Headers = append(r.Headers, line)
+		}
+	}
+	return r
+}
+
+// load fills the form from a saved request.
+func (m *Model) load(r wcfg.SavedRequest) {
+	m.method = 0
+	for i, meth := range methods {
+		if strings.EqualFold(meth, r.Method) {
+			m.method = i
+		}
+	}
+	m.path.SetValue(r.Path)
+	m.headers.SetValue(strings.Join(r.Headers, "\n"))
+	m.body.SetValue(r.Body)
+}
+
+func (m Model) updateCollection(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeCompose
+	case "up", "k":
+		m.savedPick = max(0, m.savedPick-1)
+	case "down", "j":
+		m.savedPick = max(0, min(m.savedPick+1, len(m.names)-1))
+	case "enter":
+		if m.savedPick < len(m.names) {
+			name := m.names[m.savedPick]
+			m.load(m.saved[name])
+			m.mode = modeCompose
+			m.setStatus("Loaded " + name)
+			return m.setFocus(fieldPath)
+		}
+	case "s":
+		m.mode = modeSaveName
+		if m.savedPick < len(m.names) {
+			m.nameInput.SetValue(m.names[m.savedPick])
+		}
+		return m, m.nameInput.Focus()
+	case "d":
+		if m.savedPick < len(m.names) {
+			name := m.names[m.savedPick]
+			return m, func() tea.Msg { return DeleteMsg{Name: name} }
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateSaveName(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeCollection
+		m.nameInput.Blur()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.nameInput.Value())
+		if name == "" {
+			return m, nil
+		}
+		m.mode = modeCompose
+		m.nameInput.Blur()
+		m.nameInput.SetValue("")
+		req := m.savedRequest()
+		return m, func() tea.Msg { return SaveMsg{Name: name, Request: req} }
+	}
+	return m.updateFocused(msg)
+}
+
+// --- View ---
+
+func (m Model) popupWidth() int {
+	return max(60, m.width-8)
+}
+
+func (m Model) innerWidth() int {
+	return m.popupWidth() - 6 // border (2) + padding (4)
+}
+
+// responseHeight is how many response lines fit under the form.
+func (m Model) responseHeight() int {
+	return max(3, m.height-27)
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	innerWidth := m.innerWidth()
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	title := theme.TitleStyle.Render("  HTTP Request — " + m.project)
+
+	var body []string
+	switch m.mode {
+	case modeCollection, modeSaveName:
+		body = m.viewCollection()
+	default:
+		body = append(m.viewForm(), sep)
+		body = append(body, m.viewResponse(innerWidth)...)
+	}
+
+	status := ""
+	switch {
+	case m.sending:
+		status = fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Sending "+m.sent+"..."))
+	case m.statusErr:
+		status = theme.ErrorStyle.Render("  " + m.status)
+	case m.status != "":
+		status = theme.SuccessStyle.Render("  " + m.status)
+	}
+
+	var help string
+	switch m.mode {
+	case modeCollection:
+		help = "  enter load  |  s save current  |  d delete  |  esc back"
+	case modeSaveName:
+		help = "  enter save  |  esc back"
+	default:
+		help = "  tab next field  |  ←/→ change  |  ctrl+s send  |  ctrl+o saved requests  |  esc close"
+		if t, ok := m.currentTarget(); ok && !t.Dev && !t.Tailing {
+			help = "  tab next field  |  ctrl+s send  |  ctrl+o saved  |  ctrl+l tail logs  |  esc close"
+		}
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if status != "" {
+		parts = append(parts, status)
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(m.popupWidth()).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) label(f field, text string) string {
+	if m.focus == f {
+		return theme.SelectedItemStyle.Render("> " + text)
+	}
+	return theme.LabelStyle.Render("  " + text)
+}
+
+func (m Model) viewForm() []string {
+	target := theme.DimStyle.Render("no dev server or deployed Worker")
+	if t, ok := m.currentTarget(); ok {
+		target = theme.ValueStyle.Render(t.Label) + "  " + theme.DimStyle.Render(t.BaseURL)
+		if len(m.targets) > 1 {
+			target = "‹ " + target + " ›"
+		}
+	}
+	method := lipgloss.NewStyle().Foreground(theme.ColorOrange).Bold(true).Render(methods[m.method])
+	return []string{
+		m.label(fieldTarget, "Target   ") + target,
+		m.label(fieldMethod, "Method   ") + "‹ " + method + " ›   " + m.label(fieldPath, "Path ") + m.path.View(),
+		m.label(fieldHeaders, "Headers"),
+		m.headers.View(),
+		m.label(fieldBody, "Body"),
+		m.body.View(),
+	}
+}
+
+func (m Model) viewResponse(width int) []string {
+	var head string
+	switch {
+	case m.respErr != nil:
+		head = m.label(fieldResponse, "Response ") + theme.ErrorStyle.Render("request failed")
+	case m.resp == nil:
+		return append([]string{m.label(fieldResponse, "Response")}, padLines(nil, m.responseHeight())...)
+	default:
+		style := theme.SuccessStyle
+		if m.resp.StatusCode >= 400 {
+			style = theme.ErrorStyle
+		} else if m.resp.StatusCode >= 300 {
+			style = lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		}
+		head = m.label(fieldResponse, "Response ") + style.Render(m.resp.Status) +
+			theme.DimStyle.Render("  ·  "+formatTiming(m.resp.Timing)+"  ·  "+formatSize(len(m.resp.Body), m.resp.Truncated))
+	}
+
+	tabs := []string{"body", "headers", fmt.Sprintf("logs (%d)", len(m.logs))}
+	for i, t := range tabs {
+		if respTab(i) == m.tab {
+			tabs[i] = theme.SelectedItemStyle.Render("[" + t + "]")
+		} else {
+			tabs[i] = theme.DimStyle.Render(" " + t + " ")
+		}
+	}
+
+	lines := m.responseLines()
+	h := m.responseHeight()
+	start := min(m.scroll, max(0, len(lines)-h))
+	visible := lines[start:min(len(lines), start+h)]
+	for i, l := range visible {
+		visible[i] = truncate(l, width)
+	}
+	return append([]string{head, "  " + strings.Join(tabs, " ")}, padLines(visible, h-1)...)
+}
+
+// responseLines renders the selected response tab, one entry per line.
+func (m Model) responseLines() []string {
+	if m.respErr != nil {
+		return []string{theme.ErrorStyle.Render("  " + m.respErr.Error())}
+	}
+	if m.resp == nil {
+		return nil
+	}
+	switch m.tab {
+	case tabHeaders:
+		lines := []string{theme.DimStyle.Render("  " + m.resp.Proto + " " + m.resp.Status)}
+		for _, h := range m.resp.Headers {
+			lines = append(lines, "  "+theme.LabelStyle.Render(h.Name+":")+" "+h.Value)
+		}
+		return lines
+	case tabLogs:
+		if len(m.logs) == 0 {
+			return []string{theme.DimStyle.Render("  " + m.noLogsHint())}
+		}
+		var lines []string
+		for _, l := range m.logs {
+			for _, text := range strings.Split(l.Text, "\n") {
+				lines = append(lines, "  "+theme.DimStyle.Render(l.Timestamp.Format("15:04:05.000"))+" "+levelStyle(l.Level).Render(text))
+			}
+		}
+		return lines
+	}
+	return bodyLines(m.resp)
+}
+
+func (m Model) noLogsHint() string {
+	t, ok := m.currentTarget()
+	switch {
+	case !ok:
+		return ""
+	case t.Dev:
+		return "No output from the dev server for this request."
+	case !t.Tailing:
+		return "Not tailing " + t.Script + " — press ctrl+l, then send the request again."
+	}
+	return "Waiting for the tail event (they can take a few seconds)..."
+}
+
+// bodyLines renders a response body: JSON indented, text as is, binary as a
+// size note.
+func bodyLines(r *service.HTTPResponse) []string {
+	if len(r.Body) == 0 {
+		return []string{theme.DimStyle.Render("  (empty body)")}
+	}
+	if !utf8.Valid(r.Body) {
+		return []string{theme.DimStyle.Render(fmt.Sprintf("  (%d bytes of binary data)", len(r.Body)))}
+	}
+	text := string(r.Body)
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, r.Body, "", "  ") == nil {
+		text = pretty.String()
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.ReplaceAll(text, "\t", "    "), "\n") {
+		lines = append(lines, "  "+strings.TrimRight(l, "\r"))
+	}
+	return lines
+}
+
+func (m Model) viewCollection() []string {
+	lines := []string{
+		theme.LabelStyle.Render(fmt.Sprintf("  Saved requests — %s (.orangeshell.toml)", m.project)),
+		theme.DimStyle.Render("  Credential headers (Authorization, cookies, API keys and tokens) are saved without their values."),
+		"",
+	}
+	if len(m.names) == 0 {
+		lines = append(lines, theme.DimStyle.Render("  No saved requests yet. Press s to save the current one."))
+	}
+	for i, name := range m.names {
+		r := m.saved[name]
+		cursor := "  "
+		style := theme.ActionItemStyle
+		if i == m.savedPick {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		lines = append(lines, cursor+style.Render(name)+"  "+theme.DimStyle.Render(strings.ToUpper(r.Method)+" "+r.Path))
+	}
+	if m.mode == modeSaveName {
+		lines = append(lines, "", theme.LabelStyle.Render("  Save as: ")+m.nameInput.View())
+	}
+	return lines
+}
+
+func levelStyle(level string) lipgloss.Style {
+	switch level {
+	case "error", "exception":
+		return theme.ErrorStyle
+	case "warn":
+		return lipgloss.NewStyle().Foreground(theme.ColorYellow)
+	case "request":
+		return theme.ValueStyle
+	}
+	return lipgloss.NewStyle()
+}
+
+func formatTiming(t service.HTTPTiming) string {
+	s := formatDuration(t.Total)
+	var phases []string
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{{"dns", t.DNS}, {"connect", t.Connect}, {"tls", t.TLS}, {"ttfb", t.TTFB}} {
+		if p.d > 0 {
+			phases = append(phases, p.name+" "+formatDuration(p.d))
+		}
+	}
+	if len(phases) > 0 {
+		s += " (" + strings.Join(phases, ", ") + ")"
+	}
+	return s
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+func formatSize(n int, truncated bool) string {
+	s := fmt.Sprintf("%d B", n)
+	if n >= 1024 {
+		s = fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	if truncated {
+		s += " (truncated)"
+	}
+	return s
+}
+
+func padLines(lines []string, n int) []string {
+	for len(lines) < n {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// truncate cuts a rendered line to width cells.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(s)
+}
@@ -0,0 +1,41 @@
+package requestpopup
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSendBuildsRequest(t *testing.T) {
+	m := New("api", []Target{{Label: "dev · default", BaseURL: "http://localhost:8788/", Script: "dev:api", Dev: true}}, nil)
+	m.SetSize(120, 50)
+	m.method = 1 // POST
+	m.path.SetValue("users?limit=5")
+	m.headers.SetValue("Content-Type: application/json\n\nX-Trace:  abc ")
+	m.body.SetValue(`{"name":"a"}`)
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.sending || cmd == nil {
+		t.Fatal("ctrl+s should send the request")
+	}
+	var send SendMsg
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if s, ok := msg().(SendMsg); ok {
+			send = s
+		}
+	}
+	req := send.Request
+	if req.Method != "POST" || req.URL != "http://localhost:8788/users?limit=5" || req.Body != `{"name":"a"}` {
+		t.Errorf("request: %s %s %q", req.Method, req.URL, req.Body)
+	}
+	if len(req.Headers) != 2 || req.Headers[1].Name != "X-Trace" || req.Headers[1].Value != "abc" {
+		t.Errorf("headers: %v", req.Headers)
+	}
+
+	m = New("api", []Target{{BaseURL: "http://localhost:8788"}}, nil)
+	m.headers.SetValue("not a header")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.sending || !m.statusErr {
+		t.Error("a malformed header line should not be sent")
+	}
+}
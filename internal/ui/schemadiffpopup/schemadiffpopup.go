@@ -0,0 +1,398 @@
+// Package schemadiffpopup provides the D1 schema comparison overlay: pick a
+// second database (the local dev copy, the database bound in another
+// environment, or any other D1 database), see how the two schemas differ and
+// draft the migration that makes one match the other.
+package schemadiffpopup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// Candidate is a database the current one can be compared with.
+type Candidate struct {
+	Label  string // e.g. "local · my-db" or "env staging · my-db-staging"
+	Detail string // where it comes from
+	Local  bool
+}
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// CompareMsg asks the app to load the schemas of the current database and
+// Candidates[Index].
+type CompareMsg struct {
+	Index int
+}
+
+// WriteMsg asks the app to save the migration as a new file in the
+// migrations directory of the database it changes: the current one when
+// ForCurrent is set, else the candidate.
+type WriteMsg struct {
+	SQL        string
+	ForCurrent bool
+}
+
+// CopyMsg asks the app to copy the migration to the clipboard.
+type CopyMsg struct {
+	SQL string
+}
+
+// --- Messages received from app.go ---
+
+// SchemasMsg carries the two schemas to compare: A is the current
+// database, B the chosen candidate.
+type SchemasMsg struct {
+	A, B   []service.SchemaTable
+	ALabel string
+	BLabel string
+	Err    error
+}
+
+// StatusMsg reports the outcome of a write or copy.
+type StatusMsg struct {
+	Text string
+	Err  error
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modePick mode = iota
+	modeLoading
+	modeResult
+)
+
+// Model is the schema diff popup state.
+type Model struct {
+	name       string
+	candidates []Candidate
+
+	mode   mode
+	cursor int
+	height int
+
+	schemas SchemasMsg
+	aToB    bool // migration changes B to match A (else A to match B)
+	changes []service.SchemaChange
+	sql     string
+	scroll  int
+
+	status    string
+	statusErr bool
+	spinner   spinner.Model
+}
+
+// New creates the popup for the database name with its comparison candidates.
+func New(name string, candidates []Candidate) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+	return Model{name: name, candidates: candidates, spinner: s}
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// SetSize sets the terminal height used to size the scrollable result.
+func (m *Model) SetSize(height int) {
+	m.height = height
+}
+
+// Candidate returns the candidate being compared.
+func (m Model) Candidate() Candidate {
+	if m.cursor < len(m.candidates) {
+		return m.candidates[m.cursor]
+	}
+	return Candidate{}
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SchemasMsg:
+		if m.mode != modeLoading {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.mode = modePick
+			m.setError(msg.Err.Error())
+			return m, nil
+		}
+		m.mode = modeResult
+		m.schemas = msg
+		// Local dev is usually ahead: default to bringing the other side up to it
+		m.aToB = !m.Candidate().Local
+		m.recompute()
+		return m, nil
+
+	case StatusMsg:
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+		} else {
+			m.setStatus(msg.Text)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modePick:
+			return m.updatePick(msg)
+		case modeResult:
+			return m.updateResult(msg)
+		}
+		if msg.String() == "esc" {
+			m.mode = modePick
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updatePick(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.candidates)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if len(m.candidates) == 0 {
+			return m, nil
+		}
+		m.mode = modeLoading
+		m.status = ""
+		idx := m.cursor
+		return m, func() tea.Msg { return CompareMsg{Index: idx} }
+	}
+	return m, nil
+}
+
+func (m Model) updateResult(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modePick
+		m.status = ""
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		m.scroll = m.clampScroll(m.scroll + 1)
+	case "pgup":
+		m.scroll -= m.pageHeight()
+		if m.scroll < 0 {
+			m.scroll = 0
+		}
+	case "pgdown":
+		m.scroll = m.clampScroll(m.scroll + m.pageHeight())
+	case "s":
+		m.aToB = !m.aToB
+		m.recompute()
+	case "c":
+		if m.sql != "" {
+			sql := m.sql
+			return m, func() tea.Msg { return CopyMsg{SQL: sql} }
+		}
+	case "w":
+		if m.sql != "" {
+			sql, current := m.sql, !m.aToB
+			return m, func() tea.Msg { return WriteMsg{SQL: sql, ForCurrent: current} }
+		}
+	}
+	return m, nil
+}
+
+// recompute diffs the schemas in the chosen direction.
+func (m *Model) recompute() {
+	src, dst := m.schemas.A, m.schemas.B
+	srcLabel, dstLabel := m.schemas.ALabel, m.schemas.BLabel
+	if !m.aToB {
+		src, dst = dst, src
+		srcLabel, dstLabel = dstLabel, srcLabel
+	}
+	m.changes = service.DiffSchemas(src, dst)
+	m.sql = service.SchemaMigration(src, dst, srcLabel, dstLabel)
+	m.scroll = 0
+	m.status = ""
+}
+
+// clampScroll keeps the last page of the result in view.
+func (m Model) clampScroll(scroll int) int {
+	total := len(m.changes) + 2 + strings.Count(strings.TrimRight(m.sql, "\n"), "\n") + 1
+	if max := total - m.pageHeight(); scroll > max {
+		scroll = max
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll
+}
+
+func (m Model) pageHeight() int {
+	h := m.height - 14
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 3 / 4
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	title := theme.TitleStyle.Render(fmt.Sprintf("  D1 Schema Diff — %s", m.name))
+
+	var body []string
+	var help string
+	switch m.mode {
+	case modeLoading:
+		body = []string{fmt.Sprintf("  %s %s", m.spinner.View(),
+			theme.DimStyle.Render(fmt.Sprintf("Loading schemas of %s and %s...", m.name, m.Candidate().Label)))}
+		help = "  esc back"
+	case modeResult:
+		body = m.viewResult()
+		help = "  ↑/↓ scroll  |  s swap direction  |  w write migration  |  c copy  |  esc back"
+	default:
+		body = m.viewPick()
+		help = "  ↑/↓ select  |  enter compare  |  esc close"
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		parts = append(parts, lineStyle.Render(style.Render("  "+m.status)))
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewPick() []string {
+	if len(m.candidates) == 0 {
+		return []string{
+			theme.DimStyle.Render("  No other D1 database to compare with."),
+			theme.DimStyle.Render("  Start a dev session for the local copy, or bind the database in another environment."),
+		}
+	}
+	lines := []string{theme.DimStyle.Render("  Compare with:"), ""}
+	for i, c := range m.candidates {
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		lines = append(lines, "  "+cursor+style.Render(fmt.Sprintf("%-36s", c.Label))+theme.DimStyle.Render(c.Detail))
+	}
+	return lines
+}
+
+func (m Model) viewResult() []string {
+	src, dst := m.schemas.ALabel, m.schemas.BLabel
+	if !m.aToB {
+		src, dst = dst, src
+	}
+	header := []string{
+		theme.LabelStyle.Render("  Make ") + theme.ValueStyle.Render(dst) +
+			theme.LabelStyle.Render(" match ") + theme.ValueStyle.Render(src),
+		"",
+	}
+	if len(m.changes) == 0 {
+		return append(header, theme.DimStyle.Render("  The schemas match."))
+	}
+
+	var lines []string
+	for _, c := range m.changes {
+		text := "  " + c.String()
+		switch text[2] {
+		case '+':
+			lines = append(lines, lipgloss.NewStyle().Foreground(theme.ColorGreen).Render(text))
+		case '-':
+			lines = append(lines, theme.ErrorStyle.Render(text))
+		default:
+			lines = append(lines, lipgloss.NewStyle().Foreground(theme.ColorYellow).Render(text))
+		}
+	}
+	lines = append(lines, "", theme.SubtitleStyle.Render("  Draft migration"))
+	for _, l := range strings.Split(strings.TrimRight(m.sql, "\n"), "\n") {
+		if strings.HasPrefix(l, "--") {
+			lines = append(lines, theme.DimStyle.Render("  "+l))
+		} else {
+			lines = append(lines, "  "+l)
+		}
+	}
+
+	// Scroll the changes and SQL together
+	page := m.pageHeight()
+	scroll := m.clampScroll(m.scroll)
+	end := scroll + page
+	if end > len(lines) {
+		end = len(lines)
+	}
+	visible := lines[scroll:end]
+	if end < len(lines) {
+		visible = append(visible, theme.DimStyle.Render(fmt.Sprintf("  … %d more lines", len(lines)-end)))
+	}
+	return append(header, visible...)
+}
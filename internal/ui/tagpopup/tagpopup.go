@@ -0,0 +1,220 @@
+// Package tagpopup provides the "Tag Project" overlay: a checklist of the
+// repo's tags for one project, with an input for adding a new tag.
+package tagpopup
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// ToggleTagMsg asks the app to tag (Add) or untag a project.
+type ToggleTagMsg struct {
+	RelPath string
+	Tag     string
+	Add     bool
+}
+
+// CloseMsg signals that the popup should be dismissed.
+type CloseMsg struct{}
+
+// validTag matches tag names: they become keys under [tags] in
+// .orangeshell.toml, so stick to bare-key characters.
+var validTag = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Model represents the tag checklist overlay.
+type Model struct {
+	project string // display name
+	relPath string // project dir relative to the repo root
+	tags    []string
+	checked map[string]bool
+	cursor  int // 0..len(tags)-1 = tags, len(tags) = new tag input
+	input   textinput.Model
+	err     string
+}
+
+// New creates the popup for a project. allTags are the repo's known tags and
+// current the project's tags.
+func New(project, relPath string, allTags, current []string) Model {
+	ti := textinput.New()
+	ti.Placeholder = "new tag"
+	ti.CharLimit = 40
+	ti.Width = 30
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+
+	m := Model{
+		project: project,
+		relPath: relPath,
+		tags:    append([]string(nil), allTags...),
+		checked: make(map[string]bool),
+		input:   ti,
+	}
+	for _, t := range current {
+		m.checked[t] = true
+	}
+	if len(m.tags) == 0 {
+		m.input.Focus()
+	}
+	return m
+}
+
+// SetTagged overrides a tag's checkbox, e.g. to revert a failed toggle.
+func (m *Model) SetTagged(tag string, tagged bool) {
+	m.checked[tag] = tagged
+}
+
+// SetError shows an error under the list.
+func (m *Model) SetError(err string) {
+	m.err = err
+}
+
+// onInput reports whether the cursor is on the new tag input.
+func (m Model) onInput() bool {
+	return m.cursor == len(m.tags)
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "esc":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up":
+		m.moveCursor(-1)
+		return m, nil
+	case "down", "tab":
+		m.moveCursor(1)
+		return m, nil
+	}
+
+	if m.onInput() {
+		if keyMsg.String() == "enter" {
+			return m.addTag()
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		m.err = ""
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "k":
+		m.moveCursor(-1)
+	case "j":
+		m.moveCursor(1)
+	case " ", "enter":
+		tag := m.tags[m.cursor]
+		add := !m.checked[tag]
+		m.checked[tag] = add
+		m.err = ""
+		rel := m.relPath
+		return m, func() tea.Msg { return ToggleTagMsg{RelPath: rel, Tag: tag, Add: add} }
+	}
+	return m, nil
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.tags)))
+	if m.onInput() {
+		m.input.Focus()
+	} else {
+		m.input.Blur()
+	}
+}
+
+// addTag adds the typed tag to the list, checked, and asks the app to tag the
+// project with it.
+func (m Model) addTag() (Model, tea.Cmd) {
+	tag := strings.TrimPrefix(strings.TrimSpace(m.input.Value()), "#")
+	if tag == "" {
+		return m, nil
+	}
+	if !validTag.MatchString(tag) {
+		m.err = "Tags may contain only letters, digits, - and _"
+		return m, nil
+	}
+	m.input.SetValue("")
+	m.err = ""
+	known := false
+	for _, t := range m.tags {
+		if t == tag {
+			known = true
+		}
+	}
+	if !known {
+		m.tags = append(m.tags, tag)
+		m.cursor = len(m.tags)
+	}
+	if m.checked[tag] {
+		return m, nil
+	}
+	m.checked[tag] = true
+	rel := m.relPath
+	return m, func() tea.Msg { return ToggleTagMsg{RelPath: rel, Tag: tag, Add: true} }
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth / 2
+	if popupWidth < 44 {
+		popupWidth = 44
+	}
+	if popupWidth > 70 {
+		popupWidth = 70
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render(fmt.Sprintf("  Tags — %s", m.project))
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+
+	var lines []string
+	for i, tag := range m.tags {
+		cursor := "  "
+		style := theme.ActionItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		box := "[ ]"
+		if m.checked[tag] {
+			box = theme.SuccessStyle.Render("[x]")
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", cursor, box, style.Render("#"+tag)))
+	}
+	if len(m.tags) == 0 {
+		lines = append(lines, theme.DimStyle.Render("  No tags in this repo yet."))
+	}
+
+	cursor := "  "
+	if m.onInput() {
+		cursor = theme.SelectedItemStyle.Render("> ")
+	}
+	lines = append(lines, "", cursor+theme.DimStyle.Render("+ ")+m.input.View())
+	if m.err != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.err))
+	}
+
+	help := theme.DimStyle.Render("  esc close  |  space toggle  |  enter add new tag")
+
+	parts := []string{title, theme.DimStyle.Render("  " + m.relPath), sep}
+	parts = append(parts, lines...)
+	parts = append(parts, sep, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
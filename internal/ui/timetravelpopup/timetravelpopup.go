@@ -0,0 +1,516 @@
+// Package timetravelpopup provides the D1 Time Travel overlay: show a
+// database's current bookmark, find the bookmark for a point in time and
+// restore the database to it, or undo an earlier restore. Restores ask for
+// the database name to be typed before they run.
+package timetravelpopup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/config"
+	"github.com/oarafat/orangeshell/internal/service"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// Info describes the database and its recorded restores.
+type Info struct {
+	Name     string
+	ID       string
+	Restores []config.D1Restore // newest first
+}
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// RefreshMsg asks the app to fetch the current bookmark.
+type RefreshMsg struct{}
+
+// ResolveMsg asks the app for the bookmark at a point in time.
+type ResolveMsg struct {
+	At time.Time
+}
+
+// RestoreMsg asks the app to restore the database to Bookmark, or when it
+// is empty, to the point in time At. Target describes it for the log.
+type RestoreMsg struct {
+	Bookmark string
+	At       time.Time
+	Target   string
+}
+
+// CopyMsg asks the app to copy a bookmark to the clipboard.
+type CopyMsg struct {
+	Text string
+}
+
+// --- Messages received from app.go ---
+
+// BookmarkMsg carries the current bookmark.
+type BookmarkMsg struct {
+	Bookmark string
+	Err      error
+}
+
+// ResolvedMsg carries the bookmark at a point in time.
+type ResolvedMsg struct {
+	At       time.Time
+	Bookmark string
+	Err      error
+}
+
+// RestoredMsg reports a finished restore. LogErr is set when the restore
+// succeeded but couldn't be recorded for undo.
+type RestoredMsg struct {
+	Result *service.D1RestoreResult
+	Err    error
+	LogErr error
+}
+
+// InfoMsg refreshes the recorded restores.
+type InfoMsg struct {
+	Info Info
+}
+
+// StatusMsg shows a notice, e.g. that a bookmark was copied.
+type StatusMsg struct {
+	Text string
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modeMenu mode = iota
+	modeResolve
+	modeRestore
+	modeConfirm
+	modeRunning
+)
+
+const (
+	itemResolve = iota
+	itemRestore
+	itemUndo
+)
+
+// Model is the Time Travel popup state.
+type Model struct {
+	info Info
+
+	mode   mode
+	cursor int
+	input  textinput.Model
+	typed  textinput.Model // database name typed to confirm a restore
+
+	current        string
+	currentErr     string
+	loadingCurrent bool
+	resolved       ResolvedMsg // last resolved point in time
+
+	pending RestoreMsg // restore awaiting confirmation
+
+	formErr   string
+	status    string
+	statusErr bool
+	spinner   spinner.Model
+}
+
+// New creates the popup for a database. The app fetches the current
+// bookmark right away.
+func New(info Info) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	newInput := func() textinput.Model {
+		ti := textinput.New()
+		ti.CharLimit = 200
+		ti.Width = 60
+		ti.Prompt = ""
+		ti.TextStyle = theme.ValueStyle
+		ti.PlaceholderStyle = theme.DimStyle
+		return ti
+	}
+	return Model{info: info, input: newInput(), typed: newInput(), spinner: s, loadingCurrent: true}
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Info returns the database the popup works on.
+func (m Model) Info() Info {
+	return m.info
+}
+
+func (m Model) itemCount() int {
+	if len(m.info.Restores) > 0 {
+		return itemUndo + 1
+	}
+	return itemUndo
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case BookmarkMsg:
+		m.loadingCurrent = false
+		m.current, m.currentErr = msg.Bookmark, ""
+		if msg.Err != nil {
+			m.currentErr = msg.Err.Error()
+		}
+		return m, nil
+
+	case ResolvedMsg:
+		if m.mode == modeRunning {
+			m.mode = modeMenu
+		}
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+			return m, nil
+		}
+		m.resolved = msg
+		m.setStatus(fmt.Sprintf("Bookmark at %s found — restore to it with \"Restore to a point in time\"", formatTime(msg.At)))
+		return m, nil
+
+	case RestoredMsg:
+		m.mode = modeMenu
+		switch {
+		case msg.Err != nil:
+			m.setError(msg.Err.Error())
+		case msg.LogErr != nil:
+			m.setError(fmt.Sprintf("Restored, but the undo record wasn't saved (%v) — previous bookmark: %s", msg.LogErr, msg.Result.PreviousBookmark))
+		default:
+			m.setStatus(fmt.Sprintf("Restored %s — undo from this menu", m.info.Name))
+		}
+		if msg.Result != nil {
+			m.current, m.currentErr = msg.Result.Bookmark, ""
+		}
+		return m, nil
+
+	case InfoMsg:
+		m.info = msg.Info
+		if m.cursor >= m.itemCount() {
+			m.cursor = m.itemCount() - 1
+		}
+		return m, nil
+
+	case StatusMsg:
+		m.setStatus(msg.Text)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeResolve, modeRestore:
+			return m.updateInput(msg)
+		case modeConfirm:
+			return m.updateConfirm(msg)
+		case modeRunning:
+			return m, nil
+		}
+		return m.updateMenu(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < m.itemCount()-1 {
+			m.cursor++
+		}
+	case "r":
+		m.loadingCurrent = true
+		return m, func() tea.Msg { return RefreshMsg{} }
+	case "c":
+		if m.current == "" {
+			return m, nil
+		}
+		text := m.current
+		return m, func() tea.Msg { return CopyMsg{Text: text} }
+	case "enter":
+		m.formErr = ""
+		switch m.cursor {
+		case itemResolve:
+			m.mode = modeResolve
+			m.input.SetValue("")
+			m.input.Placeholder = "2h ago, 3d, 2024-05-01 14:30 or RFC 3339"
+			return m, m.input.Focus()
+		case itemRestore:
+			m.mode = modeRestore
+			m.input.SetValue(m.resolved.Bookmark)
+			m.input.CursorEnd()
+			m.input.Placeholder = "a time (2h ago, 2024-05-01 14:30) or a bookmark"
+			return m, m.input.Focus()
+		case itemUndo:
+			last := m.info.Restores[0]
+			m.askConfirm(RestoreMsg{
+				Bookmark: last.PreviousBookmark,
+				Target:   fmt.Sprintf("its state before the restore at %s", formatTime(last.Time)),
+			})
+			return m, m.typed.Focus()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeMenu
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		text := strings.TrimSpace(m.input.Value())
+		if m.mode == modeRestore && service.IsD1Bookmark(text) {
+			m.input.Blur()
+			m.askConfirm(RestoreMsg{Bookmark: text, Target: "bookmark " + text})
+			return m, m.typed.Focus()
+		}
+		at, err := service.ParsePointInTime(text, time.Now())
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		if err := checkWindow(at); err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		m.input.Blur()
+		if m.mode == modeResolve {
+			m.mode = modeRunning
+			m.status = ""
+			m.pending = RestoreMsg{}
+			return m, func() tea.Msg { return ResolveMsg{At: at} }
+		}
+		m.askConfirm(RestoreMsg{At: at, Target: formatTime(at)})
+		return m, m.typed.Focus()
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.formErr = ""
+	return m, cmd
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeMenu
+		m.typed.Blur()
+		return m, nil
+	case "enter":
+		if m.typed.Value() != m.info.Name {
+			m.formErr = fmt.Sprintf("Type %s exactly to restore", m.info.Name)
+			return m, nil
+		}
+		m.typed.Blur()
+		m.mode = modeRunning
+		m.status = ""
+		restore := m.pending
+		return m, func() tea.Msg { return restore }
+	}
+	var cmd tea.Cmd
+	m.typed, cmd = m.typed.Update(msg)
+	m.formErr = ""
+	return m, cmd
+}
+
+func (m *Model) askConfirm(r RestoreMsg) {
+	m.pending = r
+	m.mode = modeConfirm
+	m.formErr = ""
+	m.typed.SetValue("")
+	m.typed.Placeholder = m.info.Name
+}
+
+// checkWindow rejects times Time Travel can't reach.
+func checkWindow(at time.Time) error {
+	now := time.Now()
+	if at.After(now.Add(time.Minute)) {
+		return fmt.Errorf("%s is in the future", formatTime(at))
+	}
+	if at.Before(now.Add(-service.D1TimeTravelWindow)) {
+		return fmt.Errorf("Time Travel reaches back %d days at most", int(service.D1TimeTravelWindow.Hours()/24))
+	}
+	return nil
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 3 / 4
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 100 {
+		popupWidth = 100
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	title := theme.TitleStyle.Render(fmt.Sprintf("  D1 Time Travel — %s", m.info.Name))
+
+	var body []string
+	var help string
+	switch m.mode {
+	case modeResolve:
+		body = m.viewInput("Find the bookmark of this database at a point in time.")
+		help = "  enter find  |  esc back"
+	case modeRestore:
+		body = m.viewInput("Restore this database to a point in time or a bookmark.")
+		help = "  enter continue  |  esc back"
+	case modeConfirm:
+		body = m.viewConfirm()
+		help = "  enter restore  |  esc cancel"
+	case modeRunning:
+		text := "Working..."
+		if m.pending.Target != "" {
+			text = fmt.Sprintf("Restoring %s to %s...", m.info.Name, m.pending.Target)
+		}
+		body = []string{fmt.Sprintf("  %s %s", m.spinner.View(), theme.ValueStyle.Render(text))}
+		help = "  please wait"
+	default:
+		body = m.viewMenu()
+		help = "  ↑/↓ select  |  enter run  |  c copy bookmark  |  r refresh  |  esc close"
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		parts = append(parts, lineStyle.Render(style.Render("  "+m.status)))
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewMenu() []string {
+	current := theme.ValueStyle.Render(m.current)
+	switch {
+	case m.loadingCurrent:
+		current = m.spinner.View() + " " + theme.DimStyle.Render("Loading...")
+	case m.currentErr != "":
+		current = theme.ErrorStyle.Render(m.currentErr)
+	}
+	lines := []string{
+		theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "Current")) + current,
+	}
+	if m.resolved.Bookmark != "" {
+		lines = append(lines, theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "Found"))+
+			theme.ValueStyle.Render(m.resolved.Bookmark)+theme.DimStyle.Render("  at "+formatTime(m.resolved.At)))
+	}
+	lines = append(lines, "")
+
+	items := [][2]string{
+		{"Find bookmark...", "the bookmark of a point in time"},
+		{"Restore to a point in time...", "a time or bookmark within the last 30 days"},
+	}
+	if len(m.info.Restores) > 0 {
+		items = append(items, [2]string{"Undo last restore", "return to the state before " + formatTime(m.info.Restores[0].Time)})
+	}
+	for i, it := range items {
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		lines = append(lines, "  "+cursor+style.Render(fmt.Sprintf("%-32s", it[0]))+theme.DimStyle.Render(it[1]))
+	}
+
+	if len(m.info.Restores) == 0 {
+		return lines
+	}
+	lines = append(lines, "", theme.SubtitleStyle.Render("  Restores"))
+	for i, r := range m.info.Restores {
+		if i == 5 {
+			lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("    … %d more", len(m.info.Restores)-i)))
+			break
+		}
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("    %s  to %s  (before: %s)", formatTime(r.Time), r.Target, r.PreviousBookmark)))
+	}
+	return lines
+}
+
+func (m Model) viewInput(intro string) []string {
+	lines := []string{
+		theme.DimStyle.Render("  " + intro),
+		"",
+		theme.LabelStyle.Render(fmt.Sprintf("  %-10s", "When")) + m.input.View(),
+	}
+	if m.formErr != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.formErr))
+	}
+	return lines
+}
+
+func (m Model) viewConfirm() []string {
+	lines := []string{
+		theme.ErrorStyle.Render(fmt.Sprintf("  Restore %s to %s?", m.info.Name, m.pending.Target)),
+		theme.DimStyle.Render("  Every change since then is rolled back. The current state is recorded so"),
+		theme.DimStyle.Render("  the restore can be undone from this menu."),
+		"",
+		theme.LabelStyle.Render(fmt.Sprintf("  Type %s: ", m.info.Name)) + m.typed.View(),
+	}
+	if m.formErr != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.formErr))
+	}
+	return lines
+}
+
+// --- Helpers ---
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,823 @@
+// Package triggerspopup provides the Workers Builds triggers overlay: the
+// build triggers of a project's Workers with their branch and path filters,
+// commands, root directory and build variables, and edit / delete / copy to
+// another environment operations.
+package triggerspopup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/api"
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// secretMask stands in for a secret build variable, whose value the API
+// never returns. Leaving it in place keeps the secret unchanged.
+const secretMask = "••••"
+
+// Env is a wrangler environment of the project and the Worker it deploys.
+type Env struct {
+	Name   string // "default" for the top-level environment
+	Script string
+}
+
+// Trigger is a build trigger together with the environment it deploys and
+// its build environment variables.
+type Trigger struct {
+	Env Env
+	api.Trigger
+	Vars map[string]api.BuildEnvVar
+}
+
+// --- Messages emitted by this component (handled by app.go) ---
+
+// CloseMsg signals the popup should close.
+type CloseMsg struct{}
+
+// RefreshMsg asks the app to reload the triggers.
+type RefreshMsg struct{}
+
+// SaveMsg asks the app to update a trigger, or with Copy set, to create a
+// copy of it for the Target environment.
+type SaveMsg struct {
+	Trigger Trigger // the trigger edited or copied
+	Target  Env     // environment the saved trigger deploys
+	Copy    bool
+	Req     api.TriggerCreateRequest
+
+	SetVars        map[string]api.BuildEnvVar // variables to create or overwrite
+	DeleteVars     []string                   // variables to remove (edits only)
+	SkippedSecrets []string                   // secrets a copy can't carry over
+}
+
+// DeleteMsg asks the app to delete a trigger.
+type DeleteMsg struct {
+	Trigger Trigger
+}
+
+// --- Messages received from app.go ---
+
+// LoadedMsg delivers the triggers of the project's Workers.
+type LoadedMsg struct {
+	Triggers   []Trigger
+	Err        error
+	Restricted bool // the Builds API rejected the credentials
+}
+
+// ActionDoneMsg reports the result of a save or delete.
+type ActionDoneMsg struct {
+	Status string
+	Err    error
+}
+
+// --- Model ---
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeEdit
+	modePickEnv
+	modeConfirmDelete
+)
+
+type field int
+
+const (
+	fieldName field = iota
+	fieldBranchIncludes
+	fieldBranchExcludes
+	fieldPathIncludes
+	fieldPathExcludes
+	fieldBuildCmd
+	fieldDeployCmd
+	fieldRootDir
+	fieldVars
+	fieldCount // sentinel — total number of fields
+)
+
+var fieldDefs = [fieldCount]struct {
+	label string
+	hint  string
+}{
+	fieldName:           {"Name", ""},
+	fieldBranchIncludes: {"Branches", "comma-separated, * for all"},
+	fieldBranchExcludes: {"Excluded branches", "comma-separated"},
+	fieldPathIncludes:   {"Watch paths", "comma-separated, * for all"},
+	fieldPathExcludes:   {"Excluded paths", "e.g. docs/*, *.md"},
+	fieldBuildCmd:       {"Build command", "empty to skip"},
+	fieldDeployCmd:      {"Deploy command", "e.g. npx wrangler deploy"},
+	fieldRootDir:        {"Root directory", "relative to the repo root"},
+	fieldVars:           {"Build variables", `KEY=value; KEY2="a;b" (` + secretMask + " keeps a secret)"},
+}
+
+// Model is the build triggers popup state.
+type Model struct {
+	title string
+	envs  []Env
+
+	triggers []Trigger
+	loading  bool
+	err      string
+	cursor   int
+	scroll   int
+	height   int // list rows that fit
+
+	mode mode
+
+	// Edit / copy form
+	inputs  [fieldCount]textinput.Model
+	focus   field
+	formErr string
+	copying bool
+	target  Env
+
+	envCursor int // modePickEnv
+
+	status    string // feedback of the last action
+	statusErr bool
+	saving    bool
+	spinner   spinner.Model
+}
+
+// New creates the popup for a project's environments.
+func New(title string, envs []Env) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.ColorOrange)
+
+	m := Model{
+		title:   title,
+		envs:    envs,
+		loading: true,
+		spinner: s,
+		height:  8,
+	}
+	for i := range m.inputs {
+		ti := textinput.New()
+		ti.CharLimit = 500
+		ti.Width = 60
+		ti.Prompt = ""
+		ti.TextStyle = theme.ValueStyle
+		ti.PlaceholderStyle = theme.DimStyle
+		m.inputs[i] = ti
+	}
+	return m
+}
+
+// SetSize fits the trigger list to the terminal height, leaving room for the
+// selected trigger's details.
+func (m *Model) SetSize(termHeight int) {
+	m.height = termHeight - 28
+	if m.height < 3 {
+		m.height = 3
+	}
+	m.clampScroll()
+}
+
+// SpinnerInit returns the initial spinner tick command.
+func (m Model) SpinnerInit() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Envs returns the environments whose triggers are listed.
+func (m Model) Envs() []Env {
+	return m.envs
+}
+
+// selected returns the trigger under the cursor.
+func (m Model) selected() (Trigger, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.triggers) {
+		return Trigger{}, false
+	}
+	return m.triggers[m.cursor], true
+}
+
+// copyTargets returns the environments a trigger can be copied to.
+func (m Model) copyTargets() []Env {
+	t, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	var envs []Env
+	for _, e := range m.envs {
+		if e.Script != t.Env.Script {
+			envs = append(envs, e)
+		}
+	}
+	return envs
+}
+
+// --- Update ---
+
+// Update handles messages for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadedMsg:
+		m.loading = false
+		m.err = ""
+		if msg.Err != nil {
+			if msg.Restricted {
+				m.err = "Builds API unavailable — add a fallback token with Workers CI Read for this account"
+			} else {
+				m.err = msg.Err.Error()
+			}
+			return m, nil
+		}
+		var keep string
+		if t, ok := m.selected(); ok {
+			keep = t.UUID
+		}
+		m.triggers = msg.Triggers
+		m.cursor = 0
+		for i, t := range m.triggers {
+			if t.UUID == keep {
+				m.cursor = i
+			}
+		}
+		m.clampScroll()
+		return m, nil
+
+	case ActionDoneMsg:
+		m.saving = false
+		if msg.Err != nil {
+			m.setError(msg.Err.Error())
+			return m, nil
+		}
+		m.mode = modeList
+		m.setStatus(msg.Status)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		if m.saving {
+			return m, nil
+		}
+		switch m.mode {
+		case modeEdit:
+			return m.updateEdit(msg)
+		case modePickEnv:
+			return m.updatePickEnv(msg)
+		case modeConfirmDelete:
+			return m.updateConfirmDelete(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		m.clampScroll()
+	case "down", "j":
+		if m.cursor < len(m.triggers)-1 {
+			m.cursor++
+		}
+		m.clampScroll()
+	case "R":
+		m.loading = true
+		return m, func() tea.Msg { return RefreshMsg{} }
+	case "enter", "e":
+		if t, ok := m.selected(); ok {
+			return m.startForm(t, t.Env, false)
+		}
+	case "c":
+		if _, ok := m.selected(); !ok {
+			return m, nil
+		}
+		if len(m.copyTargets()) == 0 {
+			m.setError("No other environment to copy to — add one under [env] in the wrangler config")
+			return m, nil
+		}
+		m.envCursor = 0
+		m.mode = modePickEnv
+	case "d", "delete":
+		if _, ok := m.selected(); ok {
+			m.mode = modeConfirmDelete
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updatePickEnv(msg tea.KeyMsg) (Model, tea.Cmd) {
+	targets := m.copyTargets()
+	switch msg.String() {
+	case "esc":
+		m.mode = modeList
+	case "up", "k":
+		if m.envCursor > 0 {
+			m.envCursor--
+		}
+	case "down", "j":
+		if m.envCursor < len(targets)-1 {
+			m.envCursor++
+		}
+	case "enter":
+		t, ok := m.selected()
+		if !ok || m.envCursor >= len(targets) {
+			return m, nil
+		}
+		return m.startForm(t, targets[m.envCursor], true)
+	}
+	return m, nil
+}
+
+func (m Model) updateConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		t, _ := m.selected()
+		m.mode = modeList
+		m.saving = true
+		m.setStatus(fmt.Sprintf("Deleting trigger %s...", t.Name))
+		return m, func() tea.Msg { return DeleteMsg{Trigger: t} }
+	case "n", "N", "esc":
+		m.mode = modeList
+	}
+	return m, nil
+}
+
+// startForm opens the edit form on a trigger's settings. For a copy, the
+// name and the deploy command's --env flag are adjusted to the target.
+func (m Model) startForm(t Trigger, target Env, copying bool) (Model, tea.Cmd) {
+	m.copying = copying
+	m.target = target
+	m.formErr = ""
+	m.status = ""
+
+	name, deployCmd := t.Name, t.DeployCommand
+	if copying {
+		name = fmt.Sprintf("%s deploy", target.Script)
+		deployCmd = withEnvFlag(deployCmd, target.Name)
+	}
+	values := [fieldCount]string{
+		fieldName:           name,
+		fieldBranchIncludes: strings.Join(t.BranchIncludes, ", "),
+		fieldBranchExcludes: strings.Join(t.BranchExcludes, ", "),
+		fieldPathIncludes:   strings.Join(t.PathIncludes, ", "),
+		fieldPathExcludes:   strings.Join(t.PathExcludes, ", "),
+		fieldBuildCmd:       t.BuildCommand,
+		fieldDeployCmd:      deployCmd,
+		fieldRootDir:        t.RootDirectory,
+		fieldVars:           formatVars(t.Vars),
+	}
+	for i := range m.inputs {
+		m.inputs[i].SetValue(values[i])
+		m.inputs[i].Blur()
+	}
+	m.focus = fieldName
+	m.inputs[m.focus].Focus()
+	m.mode = modeEdit
+	return m, textinput.Blink
+}
+
+func (m Model) updateEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeList
+		return m, nil
+	case "tab", "down":
+		return m.focusField((m.focus + 1) % fieldCount)
+	case "shift+tab", "up":
+		return m.focusField((m.focus + fieldCount - 1) % fieldCount)
+	case "enter":
+		return m.submit()
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m Model) focusField(f field) (Model, tea.Cmd) {
+	m.inputs[m.focus].Blur()
+	m.focus = f
+	m.formErr = ""
+	return m, m.inputs[m.focus].Focus()
+}
+
+// submit validates the form and emits a SaveMsg.
+func (m Model) submit() (Model, tea.Cmd) {
+	t, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	value := func(f field) string { return strings.TrimSpace(m.inputs[f].Value()) }
+
+	if value(fieldName) == "" {
+		m.formErr = "Name is required"
+		return m, nil
+	}
+	if value(fieldDeployCmd) == "" {
+		m.formErr = "Deploy command is required"
+		return m, nil
+	}
+	if len(splitList(value(fieldBranchIncludes))) == 0 {
+		m.formErr = "At least one branch is required (* for all)"
+		return m, nil
+	}
+	setVars, deleteVars, skipped, err := diffVars(t.Vars, value(fieldVars), m.copying)
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+
+	req := t.TriggerRequest()
+	req.TriggerName = value(fieldName)
+	req.BranchIncludes = splitList(value(fieldBranchIncludes))
+	req.BranchExcludes = splitList(value(fieldBranchExcludes))
+	req.PathIncludes = splitList(value(fieldPathIncludes))
+	req.PathExcludes = splitList(value(fieldPathExcludes))
+	req.BuildCommand = value(fieldBuildCmd)
+	req.DeployCommand = value(fieldDeployCmd)
+	req.RootDirectory = value(fieldRootDir)
+
+	save := SaveMsg{
+		Trigger:        t,
+		Target:         m.target,
+		Copy:           m.copying,
+		Req:            req,
+		SetVars:        setVars,
+		DeleteVars:     deleteVars,
+		SkippedSecrets: skipped,
+	}
+	m.saving = true
+	if m.copying {
+		m.setStatus(fmt.Sprintf("Copying trigger to %s...", m.target.Script))
+	} else {
+		m.setStatus(fmt.Sprintf("Saving trigger %s...", req.TriggerName))
+	}
+	return m, func() tea.Msg { return save }
+}
+
+func (m *Model) setStatus(s string) {
+	m.status = s
+	m.statusErr = false
+}
+
+func (m *Model) setError(s string) {
+	m.status = s
+	m.statusErr = true
+}
+
+func (m *Model) clampScroll() {
+	if m.cursor < m.scroll {
+		m.scroll = m.cursor
+	}
+	if m.cursor >= m.scroll+m.height {
+		m.scroll = m.cursor - m.height + 1
+	}
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+}
+
+// --- View ---
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth * 3 / 4
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupWidth > 110 {
+		popupWidth = 110
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	var title string
+	var body []string
+	var help string
+	switch m.mode {
+	case modeEdit:
+		t, _ := m.selected()
+		if m.copying {
+			title = theme.TitleStyle.Render(fmt.Sprintf("  Copy Trigger — %s → %s", t.Env.Script, m.target.Script))
+		} else {
+			title = theme.TitleStyle.Render("  Edit Trigger — " + t.Name)
+		}
+		body = m.viewForm()
+		help = "  tab/shift+tab navigate  |  enter save  |  esc cancel"
+	case modePickEnv:
+		title = theme.TitleStyle.Render("  Copy Trigger — choose an environment")
+		body = m.viewPickEnv()
+		help = "  ↑/↓ select  |  enter continue  |  esc cancel"
+	default:
+		title = theme.TitleStyle.Render("  Build Triggers — " + m.title)
+		body = m.viewList(innerWidth)
+		help = "  esc close  |  e edit  |  c copy to env  |  d delete  |  R refresh"
+		if m.mode == modeConfirmDelete {
+			t, _ := m.selected()
+			body = append(body, "", theme.ErrorStyle.Render(fmt.Sprintf("  Delete trigger %q of %s? Pushes will no longer build it. (y/n)", t.Name, t.Env.Script)))
+			help = "  y delete  |  n keep it"
+		}
+	}
+
+	for i, l := range body {
+		body[i] = lineStyle.Render(l)
+	}
+
+	parts := []string{title, sep}
+	parts = append(parts, body...)
+	parts = append(parts, sep)
+	if m.status != "" {
+		style := theme.DimStyle
+		if m.statusErr {
+			style = theme.ErrorStyle
+		}
+		status := "  " + m.status
+		if m.saving {
+			status = "  " + m.spinner.View() + " " + m.status
+		}
+		parts = append(parts, lineStyle.Render(style.Render(status)))
+	}
+	parts = append(parts, theme.DimStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) viewList(width int) []string {
+	if m.loading && len(m.triggers) == 0 {
+		return []string{fmt.Sprintf("  %s %s", m.spinner.View(), theme.DimStyle.Render("Loading triggers..."))}
+	}
+	if m.err != "" {
+		return []string{theme.ErrorStyle.Render("  " + m.err)}
+	}
+	if len(m.triggers) == 0 {
+		return []string{
+			theme.DimStyle.Render("  No build triggers yet."),
+			theme.DimStyle.Render("  Connect a repository with Setup CI/CD to create one."),
+		}
+	}
+
+	const envW, scriptW = 12, 24
+	nameW := width - 4 - envW - scriptW - 4
+	if nameW < 10 {
+		nameW = 10
+	}
+	lines := []string{theme.DimStyle.Render(fmt.Sprintf("    %-*s  %-*s  %s", envW, "ENV", scriptW, "WORKER", "TRIGGER"))}
+
+	end := m.scroll + m.height
+	if end > len(m.triggers) {
+		end = len(m.triggers)
+	}
+	for i := m.scroll; i < end; i++ {
+		t := m.triggers[i]
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.cursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		row := fmt.Sprintf("%-*s  %-*s  %s", envW, truncate(t.Env.Name, envW), scriptW, truncate(t.Env.Script, scriptW), truncate(t.Name, nameW))
+		lines = append(lines, "  "+cursor+style.Render(row))
+	}
+	if len(m.triggers) > m.height {
+		lines = append(lines, theme.DimStyle.Render(fmt.Sprintf("  %d–%d of %d triggers", m.scroll+1, end, len(m.triggers))))
+	}
+
+	t, ok := m.selected()
+	if !ok {
+		return lines
+	}
+	label := func(k, v string) string {
+		if v == "" {
+			v = theme.DimStyle.Render("—")
+		} else {
+			v = theme.ValueStyle.Render(v)
+		}
+		return fmt.Sprintf("  %s %s", theme.DimStyle.Render(fmt.Sprintf("%-17s", k)), v)
+	}
+	repo := ""
+	if rc := t.RepoConnection; rc != nil {
+		repo = fmt.Sprintf("%s %s/%s", rc.ProviderType, rc.ProviderAccountName, rc.RepoName)
+	}
+	caching := "disabled"
+	if t.BuildCaching {
+		caching = "enabled"
+	}
+	lines = append(lines, "",
+		label("Repository", repo),
+		label("Branches", strings.Join(t.BranchIncludes, ", ")),
+		label("Excluded branches", strings.Join(t.BranchExcludes, ", ")),
+		label("Watch paths", strings.Join(t.PathIncludes, ", ")),
+		label("Excluded paths", strings.Join(t.PathExcludes, ", ")),
+		label("Build command", t.BuildCommand),
+		label("Deploy command", t.DeployCommand),
+		label("Root directory", t.RootDirectory),
+		label("Build caching", caching),
+		label("Build variables", truncate(formatVars(t.Vars), width-22)),
+	)
+	return lines
+}
+
+func (m Model) viewForm() []string {
+	var lines []string
+	if m.copying {
+		lines = append(lines, theme.SubtitleStyle.Render(fmt.Sprintf("  Creates a trigger for %s (env %s) on the same repository", m.target.Script, m.target.Name)), "")
+	}
+	hintStyle := lipgloss.NewStyle().Foreground(theme.ColorYellowDim)
+	for i := range m.inputs {
+		f := field(i)
+		labelStyle := theme.SubtitleStyle
+		if f == m.focus {
+			labelStyle = theme.LabelStyle
+		}
+		line := labelStyle.Render(fmt.Sprintf("  %-19s", fieldDefs[f].label)) + m.inputs[f].View()
+		if f == m.focus && fieldDefs[f].hint != "" {
+			line += hintStyle.Render("  " + fieldDefs[f].hint)
+		}
+		lines = append(lines, line)
+	}
+	if m.formErr != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.formErr))
+	}
+	return lines
+}
+
+func (m Model) viewPickEnv() []string {
+	t, _ := m.selected()
+	lines := []string{theme.SubtitleStyle.Render(fmt.Sprintf("  Copy %q to:", t.Name)), ""}
+	for i, e := range m.copyTargets() {
+		cursor := "  "
+		style := theme.NormalItemStyle
+		if i == m.envCursor {
+			cursor = theme.SelectedItemStyle.Render("> ")
+			style = theme.SelectedItemStyle
+		}
+		lines = append(lines, "  "+cursor+style.Render(fmt.Sprintf("%-12s %s", e.Name, e.Script)))
+	}
+	return lines
+}
+
+// --- Helpers ---
+
+// formatVars renders build variables as "KEY=value; KEY2=value", sorted by
+// key, with secrets masked. Values that wouldn't survive diffVars — with a
+// ";", a leading quote or surrounding spaces — are written as Go-quoted
+// strings.
+func formatVars(vars map[string]api.BuildEnvVar) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		v := vars[k].Value
+		if vars[k].IsSecret {
+			v = secretMask
+		} else if strings.Contains(v, ";") || strings.HasPrefix(v, `"`) || strings.TrimSpace(v) != v {
+			v = strconv.Quote(v)
+		}
+		pairs[i] = k + "=" + v
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// diffVars parses the form's "KEY=value; ..." text against the trigger's
+// current variables. It returns the variables to set and, for an edit, the
+// ones to delete. A masked secret is kept as is; a copy can't read its
+// value, so it is reported as skipped instead. A value in double quotes is
+// unquoted as a Go string and may contain ";".
+func diffVars(old map[string]api.BuildEnvVar, text string, copying bool) (set map[string]api.BuildEnvVar, del, skipped []string, err error) {
+	pairs, err := splitVars(text)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	set = make(map[string]api.BuildEnvVar)
+	seen := make(map[string]bool)
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, nil, nil, fmt.Errorf("build variable %q: expected KEY=value", pair)
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			if value, err = strconv.Unquote(value); err != nil {
+				return nil, nil, nil, fmt.Errorf("build variable %s: bad quoted value", key)
+			}
+		}
+		seen[key] = true
+
+		prev, exists := old[key]
+		if exists && prev.IsSecret && value == secretMask {
+			if copying {
+				skipped = append(skipped, key)
+			}
+			continue
+		}
+		if exists && !copying && !prev.IsSecret && prev.Value == value {
+			continue
+		}
+		set[key] = api.BuildEnvVar{Value: value, IsSecret: exists && prev.IsSecret}
+	}
+	if !copying {
+		for key := range old {
+			if !seen[key] {
+				del = append(del, key)
+			}
+		}
+		sort.Strings(del)
+	}
+	return set, del, skipped, nil
+}
+
+// splitVars splits the form's variables on ";", except inside a value that
+// opens with a double quote.
+func splitVars(text string) ([]string, error) {
+	var pairs []string
+	start, quoted := 0, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case quoted && c == '"':
+			quoted = false
+		case quoted:
+		case c == '"' && strings.HasSuffix(strings.TrimSpace(text[start:i]), "="):
+			quoted = true
+		case c == ';':
+			pairs = append(pairs, text[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("build variables: unterminated quote")
+	}
+	return append(pairs, text[start:]), nil
+}
+
+// envFlagPattern matches a wrangler --env / -e flag and its value.
+var envFlagPattern = regexp.MustCompile(`\s+(?:--env|-e)(?:=|\s+)\S+`)
+
+// withEnvFlag points a wrangler deploy command at another environment:
+// an existing --env flag is replaced in place (or dropped for the default
+// environment), otherwise one is appended. Commands that don't run wrangler
+// are returned unchanged.
+func withEnvFlag(cmd, env string) string {
+	if !strings.Contains(cmd, "wrangler") {
+		return cmd
+	}
+	flag := ""
+	if env != "" && env != "default" {
+		flag = " --env " + env
+	}
+	if loc := envFlagPattern.FindStringIndex(cmd); loc != nil {
+		rest := envFlagPattern.ReplaceAllString(cmd[loc[1]:], "")
+		return cmd[:loc[0]] + flag + rest
+	}
+	return cmd + flag
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 1 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,88 @@
+package triggerspopup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oarafat/orangeshell/internal/api"
+)
+
+func TestWithEnvFlag(t *testing.T) {
+	tests := []struct {
+		cmd, env, want string
+	}{
+		{"npx wrangler deploy", "staging", "npx wrangler deploy --env staging"},
+		{"npx wrangler deploy --env staging", "production", "npx wrangler deploy --env production"},
+		{"npx wrangler deploy -e=staging --minify", "default", "npx wrangler deploy --minify"},
+		{"npx wrangler deploy --env staging && echo done", "prod", "npx wrangler deploy --env prod && echo done"},
+		{"npm run deploy", "staging", "npm run deploy"},
+	}
+	for _, tt := range tests {
+		if got := withEnvFlag(tt.cmd, tt.env); got != tt.want {
+			t.Errorf("withEnvFlag(%q, %q) = %q, want %q", tt.cmd, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDiffVars(t *testing.T) {
+	old := map[string]api.BuildEnvVar{
+		"NODE_VERSION": {Value: "20"},
+		"API_KEY":      {IsSecret: true},
+		"DEBUG":        {Value: "1"},
+	}
+	text := "NODE_VERSION=22; API_KEY=" + secretMask + "; SENTRY_ORG=acme"
+
+	set, del, skipped, err := diffVars(old, text, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSet := map[string]api.BuildEnvVar{"NODE_VERSION": {Value: "22"}, "SENTRY_ORG": {Value: "acme"}}
+	if !reflect.DeepEqual(set, wantSet) || !reflect.DeepEqual(del, []string{"DEBUG"}) || skipped != nil {
+		t.Errorf("edit: set=%v del=%v skipped=%v", set, del, skipped)
+	}
+
+	set, del, skipped, err = diffVars(old, text, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 || del != nil || !reflect.DeepEqual(skipped, []string{"API_KEY"}) {
+		t.Errorf("copy: set=%v del=%v skipped=%v", set, del, skipped)
+	}
+
+	if _, _, _, err := diffVars(old, "NOVALUE", false); err == nil {
+		t.Error("expected an error for a pair without =")
+	}
+}
+
+func TestDiffVarsQuoted(t *testing.T) {
+	old := map[string]api.BuildEnvVar{
+		"DATABASE_URL": {Value: "host=db;user=app"},
+		"NODE_OPTIONS": {Value: `--max-old-space-size=4096;--title="x"`},
+		"GREETING":     {Value: `say "hi"`},
+	}
+	text := formatVars(old)
+	if text != `DATABASE_URL="host=db;user=app"; GREETING=say "hi"; NODE_OPTIONS="--max-old-space-size=4096;--title=\"x\""` {
+		t.Fatalf("formatVars = %s", text)
+	}
+
+	// Unchanged values round-trip: nothing to set, nothing deleted
+	set, del, _, err := diffVars(old, text, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 0 || del != nil {
+		t.Errorf("round trip: set=%v del=%v", set, del)
+	}
+
+	set, del, _, err = diffVars(old, text+`; EXTRA="a;b"`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(set, map[string]api.BuildEnvVar{"EXTRA": {Value: "a;b"}}) || del != nil {
+		t.Errorf("quoted add: set=%v del=%v", set, del)
+	}
+
+	if _, _, _, err := diffVars(old, `KEY="a;b`, false); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
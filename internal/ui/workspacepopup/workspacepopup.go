@@ -0,0 +1,168 @@
+// Package workspacepopup provides the "Save Workspace" overlay: a name for
+// the current monorepo view (root and tag filter) and its default environment.
+package workspacepopup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// SaveMsg asks the app to save the workspace.
+type SaveMsg struct {
+	Name string
+	Env  string // "" = no default env
+}
+
+// CloseMsg signals that the popup should be dismissed.
+type CloseMsg struct{}
+
+// Model represents the save workspace overlay.
+type Model struct {
+	root   string
+	tags   []string
+	envs   []string // "" (none) first
+	envIdx int
+	input  textinput.Model
+	onEnv  bool // focus on the env selector instead of the name
+	err    string
+	exists func(name string) bool
+}
+
+// New creates the popup. name pre-fills the name (e.g. the open workspace),
+// envs are the selectable environments and env the preselected one. exists
+// reports whether a name is already taken, to warn before overwriting.
+func New(root string, tags []string, name string, envs []string, env string, exists func(string) bool) Model {
+	ti := textinput.New()
+	ti.Placeholder = "billing"
+	ti.CharLimit = 40
+	ti.Width = 30
+	ti.Prompt = ""
+	ti.TextStyle = theme.ValueStyle
+	ti.PlaceholderStyle = theme.DimStyle
+	ti.SetValue(name)
+	ti.Focus()
+
+	m := Model{
+		root:   root,
+		tags:   tags,
+		envs:   append([]string{""}, envs...),
+		input:  ti,
+		exists: exists,
+	}
+	for i, e := range m.envs {
+		if e == env {
+			m.envIdx = i
+		}
+	}
+	return m
+}
+
+// Update handles key events for the popup.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "esc":
+		return m, func() tea.Msg { return CloseMsg{} }
+	case "tab", "shift+tab", "up", "down":
+		m.onEnv = !m.onEnv
+		if m.onEnv {
+			m.input.Blur()
+		} else {
+			m.input.Focus()
+		}
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.input.Value())
+		if name == "" {
+			m.err = "Name the workspace"
+			return m, nil
+		}
+		env := m.envs[m.envIdx]
+		return m, func() tea.Msg { return SaveMsg{Name: name, Env: env} }
+	}
+
+	if m.onEnv {
+		switch keyMsg.String() {
+		case "left", "h":
+			m.envIdx = (m.envIdx + len(m.envs) - 1) % len(m.envs)
+		case "right", "l", " ":
+			m.envIdx = (m.envIdx + 1) % len(m.envs)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
+// View renders the popup as a centered overlay.
+func (m Model) View(termWidth, termHeight int) string {
+	popupWidth := termWidth / 2
+	if popupWidth < 50 {
+		popupWidth = 50
+	}
+	if popupWidth > 80 {
+		popupWidth = 80
+	}
+	innerWidth := popupWidth - 6 // border (2) + padding (4)
+
+	title := theme.TitleStyle.Render("  Save Workspace")
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDarkGray).Render(strings.Repeat("─", innerWidth))
+	field := lipgloss.NewStyle().MaxWidth(innerWidth)
+
+	tags := "all projects"
+	if len(m.tags) > 0 {
+		tags = "#" + strings.Join(m.tags, "  #")
+	}
+
+	nameLabel, envLabel := theme.LabelStyle, theme.LabelStyle
+	if m.onEnv {
+		envLabel = theme.SelectedItemStyle
+	} else {
+		nameLabel = theme.SelectedItemStyle
+	}
+	env := m.envs[m.envIdx]
+	if env == "" {
+		env = theme.DimStyle.Render("none")
+	} else {
+		env = theme.ValueStyle.Render(env)
+	}
+
+	lines := []string{
+		field.Render(fmt.Sprintf("  %s  %s", theme.LabelStyle.Render("Root"), theme.DimStyle.Render(m.root))),
+		field.Render(fmt.Sprintf("  %s  %s", theme.LabelStyle.Render("Tags"), theme.DimStyle.Render(tags))),
+		"",
+		fmt.Sprintf("  %s  %s", nameLabel.Render("Name"), m.input.View()),
+		fmt.Sprintf("  %s   ‹ %s ›", envLabel.Render("Env"), env),
+	}
+	name := strings.TrimSpace(m.input.Value())
+	if m.err != "" {
+		lines = append(lines, "", theme.ErrorStyle.Render("  "+m.err))
+	} else if name != "" && m.exists != nil && m.exists(name) {
+		lines = append(lines, "", theme.DimStyle.Render(fmt.Sprintf("  Replaces the saved workspace %q", name)))
+	}
+
+	help := theme.DimStyle.Render("  esc cancel  |  tab switch field  |  ←/→ env  |  enter save")
+
+	parts := []string{title, sep}
+	parts = append(parts, lines...)
+	parts = append(parts, sep, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorOrange).
+		Padding(1, 2).
+		Width(popupWidth).
+		Render(strings.Join(parts, "\n"))
+}
@@ -34,14 +34,15 @@ func renderHyperlink(url, text string) string {
 // The inner cursor covers all navigable items: the worker name at position 0,
 // followed by bindings at positions 1..N, then env vars.
 type EnvBox struct {
-	EnvName    string             // "default" or named env
-	WorkerName string             // resolved worker name
-	CompatDate string             // resolved compat date
-	Routes     []wcfg.RouteConfig // routes for this env
-	Bindings   []wcfg.Binding     // bindings for this env
-	Vars       map[string]string  // vars for this env (names only)
-	cursor     int                // inner cursor position (0=worker, 1..N=bindings, then env vars)
-	Index      int                // position in the envBoxes slice (for zone IDs)
+	EnvName    string                // "default" or named env
+	WorkerName string                // resolved worker name
+	CompatDate string                // resolved compat date
+	Routes     []wcfg.RouteConfig    // routes for this env
+	Bindings   []wcfg.Binding        // bindings for this env
+	Vars       map[string]string     // vars for this env (names only)
+	Settings   wcfg.ResolvedSettings // assets, runtime, tail consumers, build (display only)
+	cursor     int                   // inner cursor position (0=worker, 1..N=bindings, then env vars)
+	Index      int                   // position in the envBoxes slice (for zone IDs)
 
 	// Deployment info (fetched async from API)
 	Deployment        *DeploymentDisplay // active deployment for this env
@@ -53,6 +54,7 @@ type EnvBox struct {
 	DevKind   string // "local", "remote"
 	DevPort   string // e.g. "8787"
 	DevError  string // short error message for failed state
+	DevHealth string // "", "up", "error", "down" — last HTTP probe
 
 	// Access protection status (set by app layer via syncAccessBadges)
 	AccessProtected bool // true if this Worker is protected by Cloudflare Access
@@ -70,6 +72,7 @@ func NewEnvBox(cfg *wcfg.WranglerConfig, envName string, idx ...int) EnvBox {
 		Routes:     cfg.EnvRoutes(envName),
 		Bindings:   cfg.EnvBindings(envName),
 		Vars:       cfg.EnvVars(envName),
+		Settings:   cfg.EnvSettings(envName),
 		cursor:     0,
 	}
 	if len(idx) > 0 {
@@ -180,6 +183,7 @@ func (b EnvBox) View(width int, focused, inside bool) string {
 		}
 		badge += "]"
 		title += " " + lipgloss.NewStyle().Foreground(theme.ColorYellow).Bold(true).Render(badge)
+		title += DevHealthMarker(b.DevHealth)
 	case "failed":
 		errText := "[dev failed]"
 		if b.DevError != "" {
@@ -241,6 +245,10 @@ func (b EnvBox) View(width int, focused, inside bool) string {
 		deployLine = fmt.Sprintf("  %s %s",
 			theme.DimStyle.Render(fmt.Sprintf("%-10s", "Deploy")),
 			strings.Join(versionParts, theme.DimStyle.Render(" / ")))
+		if len(b.Deployment.Versions) > 1 {
+			// Gradual split — point at the per-version comparison
+			deployLine += theme.DimStyle.Render("  (v compare)")
+		}
 	} else if b.DeploymentFetched {
 		// API responded but no deployment found for this account
 		deployLine = fmt.Sprintf("  %s %s",
@@ -335,6 +343,7 @@ func (b EnvBox) View(width int, focused, inside bool) string {
 	if len(routeLines) > 0 {
 		contentParts = append(contentParts, strings.Join(routeLines, "\n"))
 	}
+	contentParts = append(contentParts, settingsSections(b.Settings)...)
 	if len(bindingLines) > 0 {
 		contentParts = append(contentParts, strings.Join(bindingLines, "\n"))
 	}
@@ -358,3 +367,18 @@ func (b EnvBox) View(width int, focused, inside bool) string {
 
 	return boxStyle.Render(content)
 }
+
+// DevHealthMarker renders the health of a running dev server as a colored
+// dot after its badge: green when it answers, red when it answers with a
+// server error, gray when it doesn't answer. Empty until the first probe.
+func DevHealthMarker(health string) string {
+	switch health {
+	case "up":
+		return " " + lipgloss.NewStyle().Foreground(theme.ColorGreen).Render("●")
+	case "error":
+		return " " + lipgloss.NewStyle().Foreground(theme.ColorRed).Render("●")
+	case "down":
+		return " " + lipgloss.NewStyle().Foreground(theme.ColorGray).Render("○")
+	}
+	return ""
+}
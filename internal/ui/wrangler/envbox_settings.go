@@ -0,0 +1,141 @@
+package wrangler
+
+import (
+	"fmt"
+	"strings"
+
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// settingsSections renders the read-only config sections of an env box:
+// Assets, Runtime (observability, placement, limits, logpush), Tail
+// consumers and Build (custom build + module rules). Each section is a
+// label line followed by indented key/value lines. Values inherited from the
+// top-level config are tagged "(inherited)".
+func settingsSections(s wcfg.ResolvedSettings) []string {
+	var sections []string
+
+	if a := s.Assets; a != nil {
+		lines := []string{sectionLabel("Assets", s.Inherited["assets"])}
+		lines = appendKV(lines, "directory", a.Directory)
+		lines = appendKV(lines, "binding", a.Binding)
+		lines = appendKV(lines, "not found", a.NotFoundHandling)
+		lines = appendKV(lines, "html", a.HTMLHandling)
+		if len(a.RunWorkerFirst) > 0 {
+			lines = appendKV(lines, "worker 1st", strings.Join(a.RunWorkerFirst, ", "))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	var runtime []string
+	if o := s.Observability; o != nil {
+		var parts []string
+		if o.Enabled != nil {
+			parts = append(parts, onOff(o.Enabled))
+		}
+		if o.HeadSamplingRate != nil {
+			parts = append(parts, fmt.Sprintf("sampling %g", *o.HeadSamplingRate))
+		}
+		if o.LogsEnabled != nil {
+			parts = append(parts, "logs "+onOff(o.LogsEnabled))
+		}
+		if o.InvocationLogs != nil {
+			parts = append(parts, "invocation logs "+onOff(o.InvocationLogs))
+		}
+		if len(parts) > 0 {
+			runtime = appendKV(runtime, "observ.", strings.Join(parts, ", ")+inheritedTag(s.Inherited["observability"]))
+		}
+	}
+	if p := s.Placement; p != nil {
+		val := p.Mode
+		if p.Hint != "" {
+			val += " (hint " + p.Hint + ")"
+		}
+		runtime = appendKV(runtime, "placement", val+inheritedTag(s.Inherited["placement"]))
+	}
+	if l := s.Limits; l != nil {
+		var parts []string
+		if l.CPUMs > 0 {
+			parts = append(parts, fmt.Sprintf("cpu %dms", l.CPUMs))
+		}
+		if l.Subrequests > 0 {
+			parts = append(parts, fmt.Sprintf("%d subrequests", l.Subrequests))
+		}
+		if len(parts) > 0 {
+			runtime = appendKV(runtime, "limits", strings.Join(parts, ", ")+inheritedTag(s.Inherited["limits"]))
+		}
+	}
+	if s.Logpush != nil {
+		runtime = appendKV(runtime, "logpush", onOff(s.Logpush)+inheritedTag(s.Inherited["logpush"]))
+	}
+	if len(runtime) > 0 {
+		sections = append(sections, theme.LabelStyle.Render("Runtime")+"\n"+strings.Join(runtime, "\n"))
+	}
+
+	if len(s.TailConsumers) > 0 {
+		lines := []string{theme.LabelStyle.Render("Tail Consumers")}
+		for _, tc := range s.TailConsumers {
+			line := "  " + theme.ValueStyle.Render(tc.Service)
+			if tc.Environment != "" {
+				line += theme.DimStyle.Render(fmt.Sprintf(" (env %s)", tc.Environment))
+			}
+			lines = append(lines, line)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	var build []string
+	if b := s.Build; b != nil {
+		build = appendKV(build, "command", b.Command)
+		build = appendKV(build, "cwd", b.Cwd)
+		if len(b.WatchDir) > 0 {
+			build = appendKV(build, "watch", strings.Join(b.WatchDir, ", "))
+		}
+	}
+	for _, r := range s.Rules {
+		val := strings.Join(r.Globs, ", ")
+		if r.FallThrough {
+			val += theme.DimStyle.Render(" (fallthrough)")
+		}
+		build = appendKV(build, r.Type, val)
+	}
+	if len(build) > 0 {
+		inherited := s.Inherited["build"] || (s.Build == nil && s.Inherited["rules"])
+		sections = append(sections, sectionLabel("Build", inherited)+"\n"+strings.Join(build, "\n"))
+	}
+
+	return sections
+}
+
+func sectionLabel(label string, inherited bool) string {
+	return theme.LabelStyle.Render(label) + inheritedTag(inherited)
+}
+
+func inheritedTag(inherited bool) string {
+	if !inherited {
+		return ""
+	}
+	return theme.DimStyle.Render(" (inherited)")
+}
+
+// appendKV adds an indented "key  value" line, skipping empty values.
+func appendKV(lines []string, key, value string) []string {
+	if value == "" {
+		return lines
+	}
+	return append(lines, fmt.Sprintf("  %s %s",
+		theme.DimStyle.Render(fmt.Sprintf("%-10s", key)),
+		theme.ValueStyle.Render(value)))
+}
+
+func onOff(b *bool) string {
+	if b == nil {
+		return "unset"
+	}
+	if *b {
+		return "on"
+	}
+	return "off"
+}
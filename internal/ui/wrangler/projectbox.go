@@ -32,6 +32,8 @@ type VersionSplit struct {
 type ProjectBox struct {
 	Name              string                        // directory basename
 	RelPath           string                        // relative path from CWD
+	Group             string                        // group from .orangeshell.toml ("" if ungrouped)
+	Tags              []string                      // tags from .orangeshell.toml
 	Config            *wcfg.WranglerConfig          // parsed config (nil on error)
 	Err               error                         // parse error
 	Deployments       map[string]*DeploymentDisplay // envName -> deployment info
@@ -50,8 +52,14 @@ func (b ProjectBox) View(width int, focused bool) string {
 	// Title: project name
 	title := theme.TitleStyle.Render(b.Name)
 
-	// Subtitle: relative path
+	// Subtitle: relative path, and group and tags if any
 	pathLine := theme.DimStyle.Render(b.RelPath)
+	if b.Group != "" {
+		pathLine += theme.DimStyle.Render("  · ") + theme.LabelStyle.Render(b.Group)
+	}
+	for _, tag := range b.Tags {
+		pathLine += "  " + theme.ActionNavArrowStyle.Render("#"+tag)
+	}
 
 	// Error state
 	if b.Err != nil {
@@ -121,6 +129,7 @@ func (b ProjectBox) renderEnvSection(envName string) string {
 			}
 			tag += "]"
 			devBadgeStr = " " + lipgloss.NewStyle().Foreground(theme.ColorYellow).Bold(true).Render(tag)
+			devBadgeStr += DevHealthMarker(badge.Health)
 		case "failed":
 			devBadgeStr = " " + theme.ErrorStyle.Render("[dev failed]")
 		}
@@ -150,6 +150,21 @@ func (p VersionPicker) updateSelect(msg tea.KeyMsg) (VersionPicker, tea.Cmd) {
 			}
 		}
 
+	case "a":
+		// Compare the highlighted version (and version A, when choosing B)
+		// in the per-version analytics breakdown.
+		if len(p.versions) == 0 {
+			return p, nil
+		}
+		ids := []string{p.versions[p.cursor].ID}
+		if p.step == stepSelectB && p.selectedA >= 0 {
+			ids = append([]string{p.versions[p.selectedA].ID}, ids...)
+		}
+		envName := p.envName
+		return p, func() tea.Msg {
+			return VersionAnalyticsMsg{EnvName: envName, VersionIDs: ids}
+		}
+
 	case "enter":
 		if len(p.versions) == 0 {
 			return p, nil
@@ -276,9 +291,9 @@ func (p VersionPicker) View(termWidth, termHeight int, spinnerView string) strin
 	case stepLoading:
 		help = "  esc cancel"
 	case stepSelectA:
-		help = "  esc cancel  |  enter select  |  j/k navigate"
+		help = "  esc cancel  |  enter select  |  j/k navigate  |  a analytics"
 	case stepSelectB:
-		help = "  esc back  |  enter select  |  j/k navigate"
+		help = "  esc back  |  enter select  |  j/k navigate  |  a analytics"
 	case stepPercentage:
 		help = "  esc back  |  enter confirm"
 	}
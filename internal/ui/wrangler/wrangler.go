@@ -36,6 +36,14 @@ type Model struct {
 	rootName       string         // CWD basename (monorepo name)
 	rootDir        string         // absolute path to the monorepo root directory
 
+	// Project groups, tag filter and saved workspace
+	tagFilter     []string        // show only projects with any of these tags (nil = all)
+	collapsed     map[string]bool // group → collapsed
+	onHeader      bool            // cursor is on a group header instead of a project
+	headerGroup   string          // the focused group header when onHeader
+	defaultEnv    string          // env focused when drilling in (from a workspace)
+	workspaceName string          // saved workspace the list was opened from
+
 	// Empty state menu (shown when no config found)
 	emptyMenuCursor int // 0 = create project, 1 = browse directory
 
@@ -148,7 +156,7 @@ func (m Model) SelectedProjectConfigPath() string {
 	if !m.IsOnProjectList() {
 		return ""
 	}
-	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+	if m.hasSelectedProject() {
 		return m.projects[m.projectCursor].configPath
 	}
 	return ""
@@ -160,7 +168,7 @@ func (m Model) SelectedProjectConfig() *wcfg.WranglerConfig {
 	if !m.IsOnProjectList() {
 		return nil
 	}
-	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+	if m.hasSelectedProject() {
 		return m.projects[m.projectCursor].config
 	}
 	return nil
@@ -172,19 +180,43 @@ func (m Model) SelectedProjectRelPath() string {
 	if !m.IsOnProjectList() {
 		return ""
 	}
-	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+	if m.hasSelectedProject() {
 		return m.projects[m.projectCursor].box.RelPath
 	}
 	return ""
 }
 
+// SelectedProjectName returns the directory name of the currently selected
+// project on the monorepo project list. Returns "" if not applicable.
+func (m Model) SelectedProjectName() string {
+	if !m.IsOnProjectList() {
+		return ""
+	}
+	if m.hasSelectedProject() {
+		return m.projects[m.projectCursor].box.Name
+	}
+	return ""
+}
+
+// SelectedProjectTags returns the tags of the currently selected project on
+// the monorepo project list. Returns nil if not applicable.
+func (m Model) SelectedProjectTags() []string {
+	if !m.IsOnProjectList() {
+		return nil
+	}
+	if m.hasSelectedProject() {
+		return m.projects[m.projectCursor].box.Tags
+	}
+	return nil
+}
+
 // SelectedProjectGitInfo returns the git info for the currently selected
 // project on the monorepo project list. Returns nil if not applicable.
 func (m Model) SelectedProjectGitInfo() *wcfg.GitInfo {
 	if !m.IsOnProjectList() {
 		return nil
 	}
-	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+	if m.hasSelectedProject() {
 		return m.projects[m.projectCursor].gitInfo
 	}
 	return nil
@@ -196,7 +228,7 @@ func (m Model) SelectedProjectDir() string {
 	if !m.IsOnProjectList() {
 		return ""
 	}
-	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+	if m.hasSelectedProject() {
 		return filepath.Dir(m.projects[m.projectCursor].configPath)
 	}
 	return ""
@@ -272,6 +304,11 @@ func (m *Model) SetConfigLoading() {
 	m.activeProject = -1
 	m.rootName = ""
 	m.rootDir = ""
+	m.tagFilter = nil
+	m.collapsed = nil
+	m.onHeader = false
+	m.defaultEnv = ""
+	m.workspaceName = ""
 }
 
 // SetSize updates the view dimensions.
@@ -291,13 +328,18 @@ func (m Model) FocusedEnvName() string {
 // ReloadConfig re-parses a config file and refreshes the UI state.
 // In monorepo mode, finds the matching project by config path and updates it.
 // In single-project mode, replaces the config and rebuilds env boxes.
+// The focused env box stays on the same env when it still exists.
 func (m *Model) ReloadConfig(configPath string, cfg *wcfg.WranglerConfig) {
+	focused := m.FocusedEnvName()
+	defer m.refocusEnv(focused)
+
 	if m.IsMonorepo() {
 		// Find the project with this config path and update its config
 		for i, p := range m.projects {
 			if p.configPath == configPath {
 				m.projects[i].config = cfg
 				m.projects[i].box.Config = cfg
+				m.projects[i].box.Err = nil
 				break
 			}
 		}
@@ -338,6 +380,21 @@ func (m *Model) ReloadConfig(configPath string, cfg *wcfg.WranglerConfig) {
 	}
 }
 
+// refocusEnv moves the env cursor back to the named env after the env boxes
+// were rebuilt, or clamps it if that env no longer exists.
+func (m *Model) refocusEnv(name string) {
+	for i, n := range m.envNames {
+		if n == name {
+			m.focusedEnv = i
+			return
+		}
+	}
+	if m.focusedEnv >= len(m.envNames) {
+		m.focusedEnv = max(len(m.envNames)-1, 0)
+		m.insideBox = false
+	}
+}
+
 // InsideBox returns whether the user is navigating inside an env box.
 func (m Model) InsideBox() bool {
 	return m.insideBox
@@ -639,14 +696,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					}
 				}
 			}
+			// Check group header clicks (toggle collapse)
+			for _, row := range m.listRows() {
+				if !row.header {
+					continue
+				}
+				if z := zone.Get(ProjectGroupZoneID(row.group)); z != nil && z.InBounds(msg) {
+					m.onHeader = true
+					m.headerGroup = row.group
+					m.toggleGroup(row.group)
+					return m, nil
+				}
+			}
 			// Check project box clicks
 			n := len(m.projects)
 			for i := 0; i < n; i++ {
 				if z := zone.Get(ProjectBoxZoneID(i)); z != nil && z.InBounds(msg) {
-					if i == m.projectCursor {
+					if i == m.projectCursor && !m.onHeader {
 						return m.drillIntoProject(i)
 					}
 					m.projectCursor = i
+					m.onHeader = false
 					m.adjustProjectScroll()
 					return m, nil
 				}
@@ -709,12 +779,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// return to the project list instead of doing nothing.
 		if m.IsMonorepo() && m.activeProject >= 0 && !m.insideBox {
 			if msg.String() == "esc" || msg.String() == "backspace" {
-				m.activeProject = -1
-				// Restore monorepo project's config to nil so single-project view doesn't show
-				m.config = nil
-				m.configPath = ""
-				m.envNames = nil
-				m.envBoxes = nil
+				m.BackToProjectList()
 				return m, nil
 			}
 		}
@@ -760,6 +825,24 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
+		// Handle "v" key — open the per-version analytics breakdown for the
+		// focused env, highlighting the versions of its active deployment.
+		if msg.String() == "v" {
+			workerName := m.FocusedWorkerName()
+			if workerName != "" && m.focusedEnv >= 0 && m.focusedEnv < len(m.envBoxes) {
+				envName := m.FocusedEnvName()
+				var versionIDs []string
+				if dep := m.envBoxes[m.focusedEnv].Deployment; dep != nil {
+					for _, v := range dep.Versions {
+						versionIDs = append(versionIDs, v.ShortID)
+					}
+				}
+				return m, func() tea.Msg {
+					return VersionAnalyticsMsg{ScriptName: workerName, EnvName: envName, VersionIDs: versionIDs}
+				}
+			}
+		}
+
 		if m.insideBox {
 			return m.updateInside(msg)
 		}
@@ -0,0 +1,389 @@
+package wrangler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	wcfg "github.com/oarafat/orangeshell/internal/wrangler"
+
+	"github.com/oarafat/orangeshell/internal/ui/theme"
+)
+
+// ProjectGroupZoneID returns the bubblezone marker ID for a group header in
+// the monorepo project list.
+func ProjectGroupZoneID(group string) string {
+	return "proj-group-" + group
+}
+
+// ungroupedLabel names the section of projects without a group when the
+// list is grouped.
+const ungroupedLabel = "ungrouped"
+
+// listRow is one navigable row of the project list: a group header, or a
+// grid row of one or two project boxes.
+type listRow struct {
+	group    string
+	header   bool
+	projects []int // project indices (grid rows)
+	count    int   // visible projects in the group (header rows)
+}
+
+// hasGroups reports whether any project belongs to a group, in which case
+// the list is shown in sections with collapsible headers.
+func (m Model) hasGroups() bool {
+	for _, p := range m.projects {
+		if p.box.Group != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// projectVisible reports whether a project passes the tag filter (it has any
+// of the filter's tags).
+func (m Model) projectVisible(i int) bool {
+	if len(m.tagFilter) == 0 {
+		return true
+	}
+	for _, tag := range m.projects[i].box.Tags {
+		for _, f := range m.tagFilter {
+			if tag == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listRows lays out the visible projects: a 2-column grid, split into group
+// sections (groups in order of first appearance, ungrouped last) when any
+// project has a group. Collapsed groups show only their header.
+func (m Model) listRows() []listRow {
+	var order []string
+	members := make(map[string][]int)
+	for i := range m.projects {
+		if !m.projectVisible(i) {
+			continue
+		}
+		g := m.projects[i].box.Group
+		if _, ok := members[g]; !ok {
+			order = append(order, g)
+		}
+		members[g] = append(members[g], i)
+	}
+
+	if !m.hasGroups() {
+		return gridRows("", members[""])
+	}
+	if _, ok := members[""]; ok {
+		for i, g := range order {
+			if g == "" {
+				order = append(append(order[:i:i], order[i+1:]...), "")
+				break
+			}
+		}
+	}
+
+	var rows []listRow
+	for _, g := range order {
+		rows = append(rows, listRow{group: g, header: true, count: len(members[g])})
+		if !m.collapsed[g] {
+			rows = append(rows, gridRows(g, members[g])...)
+		}
+	}
+	return rows
+}
+
+// gridRows pairs up project indices into 2-column rows.
+func gridRows(group string, idxs []int) []listRow {
+	var rows []listRow
+	for i := 0; i < len(idxs); i += 2 {
+		rows = append(rows, listRow{group: group, projects: idxs[i:min(i+2, len(idxs))]})
+	}
+	return rows
+}
+
+// cursorRow returns the row and column of the cursor, or -1 if the cursor
+// is on nothing visible.
+func (m Model) cursorRow(rows []listRow) (row, col int) {
+	for r, lr := range rows {
+		if lr.header {
+			if m.onHeader && lr.group == m.headerGroup {
+				return r, 0
+			}
+			continue
+		}
+		if m.onHeader {
+			continue
+		}
+		for c, idx := range lr.projects {
+			if idx == m.projectCursor {
+				return r, c
+			}
+		}
+	}
+	return -1, 0
+}
+
+// focusRow moves the cursor to row r, keeping the column where possible.
+func (m *Model) focusRow(rows []listRow, r, col int) {
+	lr := rows[r]
+	if lr.header {
+		m.onHeader = true
+		m.headerGroup = lr.group
+		return
+	}
+	m.onHeader = false
+	m.projectCursor = lr.projects[min(col, len(lr.projects)-1)]
+}
+
+// ensureListCursor moves the cursor onto a visible row after the rows
+// changed (filter, collapse, rediscovery): to the focused project's group
+// header if it was collapsed, otherwise to the first project.
+func (m *Model) ensureListCursor() {
+	rows := m.listRows()
+	if r, _ := m.cursorRow(rows); r >= 0 || len(rows) == 0 {
+		return
+	}
+	if !m.onHeader && m.projectCursor >= 0 && m.projectCursor < len(m.projects) && m.projectVisible(m.projectCursor) {
+		g := m.projects[m.projectCursor].box.Group
+		for r, lr := range rows {
+			if lr.header && lr.group == g {
+				m.focusRow(rows, r, 0)
+				return
+			}
+		}
+	}
+	for r, lr := range rows {
+		if !lr.header {
+			m.focusRow(rows, r, 0)
+			return
+		}
+	}
+	m.focusRow(rows, 0, 0)
+}
+
+// hasSelectedProject reports whether the cursor is on a visible project box.
+func (m Model) hasSelectedProject() bool {
+	return !m.onHeader && m.projectCursor >= 0 && m.projectCursor < len(m.projects) &&
+		m.projectVisible(m.projectCursor)
+}
+
+// toggleGroup collapses or expands a group. Collapsing the group the cursor
+// is in moves the cursor to its header.
+func (m *Model) toggleGroup(group string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[group] = !m.collapsed[group]
+	if m.collapsed[group] && m.hasSelectedProject() && m.projects[m.projectCursor].box.Group == group {
+		m.onHeader = true
+		m.headerGroup = group
+	}
+	m.ensureListCursor()
+	m.adjustProjectScroll()
+}
+
+// AllTags returns the tags used by any project, in order of first appearance.
+func (m Model) AllTags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, p := range m.projects {
+		for _, t := range p.box.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags
+}
+
+// TagFilter returns the active tag filter (nil = all projects).
+func (m Model) TagFilter() []string {
+	return m.tagFilter
+}
+
+// SetTagFilter shows only projects with any of the given tags (nil = all).
+func (m *Model) SetTagFilter(tags []string) {
+	m.tagFilter = tags
+	m.ensureListCursor()
+	m.adjustProjectScroll()
+}
+
+// ToggleTagFilter adds a tag to the filter, or removes it if present.
+func (m *Model) ToggleTagFilter(tag string) {
+	var filter []string
+	found := false
+	for _, t := range m.tagFilter {
+		if t == tag {
+			found = true
+			continue
+		}
+		filter = append(filter, t)
+	}
+	if !found {
+		filter = append(filter, tag)
+	}
+	m.SetTagFilter(filter)
+}
+
+// cycleTagFilter steps the filter through no filter and each single tag.
+func (m *Model) cycleTagFilter() {
+	tags := m.AllTags()
+	if len(tags) == 0 {
+		return
+	}
+	next := 0
+	if len(m.tagFilter) == 1 {
+		for i, t := range tags {
+			if t == m.tagFilter[0] {
+				next = i + 1
+			}
+		}
+	} else if len(m.tagFilter) > 1 {
+		next = len(tags)
+	}
+	if next >= len(tags) {
+		m.SetTagFilter(nil)
+		return
+	}
+	m.SetTagFilter([]string{tags[next]})
+}
+
+// SetWorkspace applies a saved workspace's tag filter and default env.
+func (m *Model) SetWorkspace(name string, tags []string, defaultEnv string) {
+	m.workspaceName = name
+	m.defaultEnv = defaultEnv
+	m.SetTagFilter(tags)
+}
+
+// WorkspaceName returns the saved workspace the list was opened from.
+func (m Model) WorkspaceName() string {
+	return m.workspaceName
+}
+
+// DefaultEnv returns the environment focused by default (from a saved
+// workspace), or "".
+func (m Model) DefaultEnv() string {
+	return m.defaultEnv
+}
+
+// focusedGroup returns the group of the focused row and whether the list is
+// grouped at all.
+func (m Model) focusedGroup() (string, bool) {
+	if !m.hasGroups() {
+		return "", false
+	}
+	if m.onHeader {
+		return m.headerGroup, true
+	}
+	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+		return m.projects[m.projectCursor].box.Group, true
+	}
+	return "", true
+}
+
+// inScope reports whether project-wide commands act on project i.
+func (m Model) inScope(i int) bool {
+	if !m.projectVisible(i) {
+		return false
+	}
+	group, grouped := m.focusedGroup()
+	return !grouped || m.projects[i].box.Group == group
+}
+
+// ScopedProjectConfigs returns (config, configPath) pairs for the projects
+// that project-wide commands (Deploy All, Parallel Tail) act on: those
+// passing the tag filter, narrowed to the focused group when the list is
+// grouped.
+func (m Model) ScopedProjectConfigs() [](struct {
+	Config     *wcfg.WranglerConfig
+	ConfigPath string
+}) {
+	all := m.ProjectConfigs()
+	scoped := all[:0:0]
+	for i, pc := range all {
+		if m.inScope(i) {
+			scoped = append(scoped, pc)
+		}
+	}
+	return scoped
+}
+
+// ScopedProjectNames returns the names of the projects ScopedProjectConfigs
+// returns, in the same order.
+func (m Model) ScopedProjectNames() []string {
+	var names []string
+	for i, p := range m.projects {
+		if m.inScope(i) {
+			names = append(names, p.box.Name)
+		}
+	}
+	return names
+}
+
+// ScopedEnvNames returns the union of env names across the scoped projects,
+// with the default env first.
+func (m Model) ScopedEnvNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, pc := range m.ScopedProjectConfigs() {
+		if pc.Config == nil {
+			continue
+		}
+		for _, name := range pc.Config.EnvNames() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if m.defaultEnv != "" && seen[m.defaultEnv] {
+		for i, name := range names {
+			if name == m.defaultEnv {
+				names = append(append([]string{name}, names[:i]...), names[i+1:]...)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// ScopeLabel describes the scope of project-wide commands, e.g.
+// "edge · #billing", or "" when they act on every project.
+func (m Model) ScopeLabel() string {
+	var parts []string
+	if group, grouped := m.focusedGroup(); grouped {
+		if group == "" {
+			group = ungroupedLabel
+		}
+		parts = append(parts, group)
+	}
+	for _, t := range m.tagFilter {
+		parts = append(parts, "#"+t)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// listHeaderLine renders a group header row.
+func (m Model) listHeaderLine(lr listRow, width int) string {
+	arrow := "▾"
+	if m.collapsed[lr.group] {
+		arrow = "▸"
+	}
+	name := lr.group
+	if name == "" {
+		name = ungroupedLabel
+	}
+	nameStyle := theme.ActionSectionStyle
+	cursor := "  "
+	if m.onHeader && m.headerGroup == lr.group {
+		nameStyle = theme.SelectedItemStyle
+		cursor = theme.SelectedItemStyle.Render("▸ ")
+	}
+	line := cursor + nameStyle.Render(arrow+" "+name) +
+		theme.DimStyle.Render(fmt.Sprintf("  %d projects", lr.count))
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
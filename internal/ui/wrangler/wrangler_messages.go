@@ -122,6 +122,10 @@ type ProjectsDiscoveredMsg struct {
 	Projects []wcfg.ProjectInfo
 	RootName string // CWD basename (monorepo name)
 	RootDir  string // absolute path to the monorepo root directory
+	// SettingsErr reports a malformed .orangeshell.toml (discovery then used defaults)
+	SettingsErr error
+	// Profile is the credential profile bound to the root by .orangeshell.toml ("" = none)
+	Profile string
 }
 
 // ProjectDeploymentLoadedMsg delivers deployment data for a single project+env.
@@ -153,6 +157,17 @@ type TailStartMsg struct {
 // TailStoppedMsg signals that the wrangler-initiated tail was stopped.
 type TailStoppedMsg struct{}
 
+// VersionAnalyticsMsg requests the app to open the per-version analytics
+// breakdown for a worker. Emitted by 'v' on a deployed env (with the versions
+// of the active deployment) and by 'a' in the version picker. VersionIDs may
+// be full IDs or 8-char prefixes. ScriptName is empty when sent from the
+// version picker — the app resolves it from EnvName.
+type VersionAnalyticsMsg struct {
+	ScriptName string
+	EnvName    string
+	VersionIDs []string
+}
+
 // projectEntry holds data for a single project in the monorepo list.
 type projectEntry struct {
 	box        ProjectBox
@@ -166,6 +181,7 @@ type DevBadge struct {
 	Kind   string // "local" or "remote"
 	Port   string // e.g. "8787"
 	Status string // "starting", "running", "failed"
+	Health string // "", "up", "error", "down" — last HTTP probe of a running server
 }
 
 // WorkerInfo describes a single worker resolved from a wrangler config.
@@ -19,45 +19,114 @@ func (m *Model) SetProjects(projects []wcfg.ProjectInfo, rootName, rootDir strin
 	m.rootName = rootName
 	m.rootDir = rootDir
 
-	// Use rootDir for relative paths if available, otherwise fall back to CWD
-	baseDir := rootDir
-	if baseDir == "" {
-		baseDir, _ = filepath.Abs(".")
-	}
-
 	m.projects = make([]projectEntry, len(projects))
 	for i, p := range projects {
-		cfg, err := wcfg.Parse(p.ConfigPath)
+		m.projects[i] = m.newProjectEntry(p, i)
+	}
 
-		// Compute relative path from root dir
-		relPath, _ := filepath.Rel(baseDir, p.Dir)
-		if relPath == "" {
-			relPath = "."
-		}
+	m.projectCursor = 0
+	m.projectScrollY = 0
+	m.activeProject = -1
+	m.tagFilter = nil
+	m.collapsed = nil
+	m.onHeader = false
+	m.defaultEnv = ""
+	m.workspaceName = ""
+	m.ensureListCursor()
+}
 
-		name := filepath.Base(p.Dir)
+// UpdateProjects replaces the project list after a rediscovery. Projects that
+// are still present keep their parsed config, deployments and badges; new ones
+// are parsed. The cursor and the drilled-in project stay on the same project
+// where it still exists.
+func (m *Model) UpdateProjects(projects []wcfg.ProjectInfo) {
+	var cursorPath, activePath string
+	if m.projectCursor >= 0 && m.projectCursor < len(m.projects) {
+		cursorPath = m.projects[m.projectCursor].configPath
+	}
+	if m.activeProject >= 0 && m.activeProject < len(m.projects) {
+		activePath = m.projects[m.activeProject].configPath
+	}
+	known := make(map[string]projectEntry, len(m.projects))
+	for _, p := range m.projects {
+		known[p.configPath] = p
+	}
 
-		box := ProjectBox{
-			Name:              name,
-			RelPath:           relPath,
-			Config:            cfg,
-			Err:               err,
-			Deployments:       make(map[string]*DeploymentDisplay),
-			DeploymentFetched: make(map[string]bool),
-			Index:             i,
+	entries := make([]projectEntry, len(projects))
+	cursor, active := -1, -1
+	for i, p := range projects {
+		if e, ok := known[p.ConfigPath]; ok {
+			e.gitInfo = p.Git
+			e.box.Group = p.Group
+			e.box.Tags = p.Tags
+			e.box.Index = i
+			entries[i] = e
+		} else {
+			entries[i] = m.newProjectEntry(p, i)
 		}
-
-		m.projects[i] = projectEntry{
-			box:        box,
-			config:     cfg,
-			configPath: p.ConfigPath,
-			gitInfo:    p.Git,
+		if p.ConfigPath == cursorPath {
+			cursor = i
+		}
+		if p.ConfigPath == activePath {
+			active = i
 		}
 	}
+	m.projects = entries
 
-	m.projectCursor = 0
-	m.projectScrollY = 0
-	m.activeProject = -1
+	if cursor < 0 {
+		// The focused project is gone — stay at the same position
+		cursor = min(m.projectCursor, len(entries)-1)
+	}
+	m.projectCursor = max(cursor, 0)
+	m.activeProject = active
+	m.ensureListCursor()
+	if activePath != "" && active < 0 {
+		// The drilled-in project is gone — back to the list
+		m.config = nil
+		m.configPath = ""
+		m.envNames = nil
+		m.envBoxes = nil
+		m.focusedEnv = 0
+		m.insideBox = false
+		m.scrollY = 0
+	}
+	m.adjustProjectScroll()
+}
+
+// newProjectEntry parses a discovered project's config and builds its entry.
+func (m Model) newProjectEntry(p wcfg.ProjectInfo, idx int) projectEntry {
+	// Use rootDir for relative paths if available, otherwise fall back to CWD
+	baseDir := m.rootDir
+	if baseDir == "" {
+		baseDir, _ = filepath.Abs(".")
+	}
+
+	cfg, err := wcfg.Parse(p.ConfigPath)
+
+	// Compute relative path from root dir
+	relPath, _ := filepath.Rel(baseDir, p.Dir)
+	if relPath == "" {
+		relPath = "."
+	}
+
+	box := ProjectBox{
+		Name:              filepath.Base(p.Dir),
+		RelPath:           relPath,
+		Group:             p.Group,
+		Tags:              p.Tags,
+		Config:            cfg,
+		Err:               err,
+		Deployments:       make(map[string]*DeploymentDisplay),
+		DeploymentFetched: make(map[string]bool),
+		Index:             idx,
+	}
+
+	return projectEntry{
+		box:        box,
+		config:     cfg,
+		configPath: p.ConfigPath,
+		gitInfo:    p.Git,
+	}
 }
 
 // SetProjectDeployment updates deployment data for a specific project and environment.
@@ -181,44 +250,56 @@ func (m Model) WorkerList() []WorkerInfo {
 	return workers
 }
 
-// updateProjectList handles 2D grid navigation on the monorepo project list.
-// Projects are arranged in a 2-column grid. The linear cursor maps to:
-//
-//	column = cursor % 2, row = cursor / 2
+// updateProjectList handles navigation on the monorepo project list.
+// Projects are arranged in a 2-column grid, split into sections under
+// collapsible group headers when any project has a group. up/down move
+// between rows (headers are rows of their own), left/right between columns.
 func (m Model) updateProjectList(msg tea.KeyMsg) (Model, tea.Cmd) {
-	n := len(m.projects)
+	rows := m.listRows()
+	if len(rows) == 0 {
+		if msg.String() == "f" {
+			m.cycleTagFilter()
+		}
+		return m, nil
+	}
+	row, col := m.cursorRow(rows)
+	if row < 0 {
+		m.ensureListCursor()
+		m.adjustProjectScroll()
+		return m, nil
+	}
 	switch msg.String() {
 	case "up", "k":
-		// Move up one row (stride of 2)
-		if m.projectCursor-2 >= 0 {
-			m.projectCursor -= 2
+		if row > 0 {
+			m.focusRow(rows, row-1, col)
 			m.adjustProjectScroll()
 		}
 	case "down", "j":
-		// Move down one row (stride of 2)
-		if m.projectCursor+2 < n {
-			m.projectCursor += 2
+		if row+1 < len(rows) {
+			m.focusRow(rows, row+1, col)
 			m.adjustProjectScroll()
-		} else if m.projectCursor+2 >= n && m.projectCursor%2 == 0 && m.projectCursor+1 < n {
-			// On left column of last full row, but there's an item on the right in this row
-			// and no row below — stay put
 		}
 	case "left", "h":
-		// Move to left column if currently on the right
-		if m.projectCursor%2 == 1 {
-			m.projectCursor--
-			m.adjustProjectScroll()
+		if !rows[row].header && col == 1 {
+			m.focusRow(rows, row, 0)
 		}
 	case "right", "l":
-		// Move to right column if currently on the left and right exists
-		if m.projectCursor%2 == 0 && m.projectCursor+1 < n {
-			m.projectCursor++
-			m.adjustProjectScroll()
+		if !rows[row].header && col == 0 {
+			m.focusRow(rows, row, 1)
 		}
+	case "z":
+		group := rows[row].group
+		if m.hasGroups() {
+			m.toggleGroup(group)
+		}
+	case "f":
+		m.cycleTagFilter()
 	case "enter":
-		if m.projectCursor >= 0 && m.projectCursor < n {
-			return m.drillIntoProject(m.projectCursor)
+		if rows[row].header {
+			m.toggleGroup(rows[row].group)
+			return m, nil
 		}
+		return m.drillIntoProject(m.projectCursor)
 	}
 	return m, nil
 }
@@ -260,6 +341,14 @@ func (m Model) drillIntoProject(idx int) (Model, tea.Cmd) {
 				m.envBoxes[i].CICDConnected = true
 			}
 		}
+		// Focus the workspace's default env when the project has it
+		for i, name := range m.envNames {
+			if name == m.defaultEnv {
+				m.focusedEnv = i
+				m.adjustScroll()
+				break
+			}
+		}
 	} else {
 		m.envNames = nil
 		m.envBoxes = nil
@@ -268,18 +357,49 @@ func (m Model) drillIntoProject(idx int) (Model, tea.Cmd) {
 	return m, nil
 }
 
-// adjustProjectScroll ensures the focused project row is visible in the scroll window.
-// projectScrollY is a line offset. We estimate ~12 lines per grid row (box height + spacer)
-// and convert between row index and line offset.
+// BackToProjectList leaves the drilled-in project and shows the project list.
+// No-op outside monorepo mode.
+func (m *Model) BackToProjectList() {
+	if !m.IsMonorepo() || m.activeProject < 0 {
+		return
+	}
+	m.activeProject = -1
+	// Restore monorepo project's config to nil so single-project view doesn't show
+	m.config = nil
+	m.configPath = ""
+	m.envNames = nil
+	m.envBoxes = nil
+}
+
+// adjustProjectScroll ensures the focused row is visible in the scroll window.
+// projectScrollY is a line offset. We estimate ~12 lines per grid row (box
+// height + spacer) and 2 per group header, and convert between row index and
+// line offset.
 func (m *Model) adjustProjectScroll() {
-	const estRowHeight = 12 // estimated lines per grid row (box + spacer)
-	const headerLines = 3   // title + separator + blank line
+	const estRowHeight = 12   // estimated lines per grid row (box + spacer)
+	const estHeaderHeight = 2 // group header + spacer
+	const headerLines = 3     // title + separator + blank line
 
-	focusedRow := m.projectCursor / 2
+	rows := m.listRows()
+	focusedRow, _ := m.cursorRow(rows)
+	if focusedRow < 0 {
+		m.projectScrollY = 0
+		return
+	}
 
-	// Estimate the line offset where this row starts
-	rowStartLine := headerLines + focusedRow*estRowHeight
+	// Estimate the line range of the focused row
+	rowStartLine := headerLines
+	for _, r := range rows[:focusedRow] {
+		if r.header {
+			rowStartLine += estHeaderHeight
+		} else {
+			rowStartLine += estRowHeight
+		}
+	}
 	rowEndLine := rowStartLine + estRowHeight
+	if rows[focusedRow].header {
+		rowEndLine = rowStartLine + estHeaderHeight
+	}
 
 	// How many content lines are visible
 	visibleHeight := m.height - 4 // approximate content area
@@ -301,36 +421,63 @@ func (m *Model) adjustProjectScroll() {
 	}
 }
 
-// viewProjectList renders the monorepo project list view as a 2-column grid.
+// viewProjectList renders the monorepo project list view as a 2-column grid,
+// with group headers when projects are grouped.
 func (m Model) viewProjectList(contentHeight, boxWidth int, title, sep string) string {
+	rows := m.listRows()
+
 	// Monorepo title uses the root name with project count inline
+	visible := 0
+	for i := range m.projects {
+		if m.projectVisible(i) {
+			visible++
+		}
+	}
+	count := fmt.Sprintf("%d projects", len(m.projects))
+	if visible != len(m.projects) {
+		count = fmt.Sprintf("%d of %d projects", visible, len(m.projects))
+	}
 	monoTitle := theme.TitleStyle.Render(fmt.Sprintf("  %s", m.rootName)) +
-		"  " + theme.DimStyle.Render(fmt.Sprintf("%d projects", len(m.projects)))
+		"  " + theme.DimStyle.Render(count)
+	if m.workspaceName != "" {
+		monoTitle += "  " + theme.LabelStyle.Render("["+m.workspaceName+"]")
+	}
+	for _, tag := range m.tagFilter {
+		monoTitle += "  " + theme.ActionNavArrowStyle.Render("#"+tag)
+	}
 
-	helpText := theme.DimStyle.Render("  h/j/k/l navigate  |  enter drill into  |  ctrl+p actions  |  ctrl+l services")
+	help := "  h/j/k/l navigate  |  enter drill into"
+	if m.hasGroups() {
+		help += "  |  z collapse"
+	}
+	if len(m.AllTags()) > 0 {
+		help += "  |  f filter"
+	}
+	helpText := theme.DimStyle.Render(help + "  |  ctrl+p actions  |  ctrl+l services")
 
-	n := len(m.projects)
-	totalRows := (n + 1) / 2 // ceiling division
 	colWidth := boxWidth / 2
 
-	// Build all lines by rendering each grid row
+	// Build all lines by rendering each row
 	var allLines []string
 	allLines = append(allLines, monoTitle, sep, "")
 
-	// Track the starting line index for each grid row (for scroll adjustment)
-	rowLineOffsets := make([]int, totalRows)
-	for row := 0; row < totalRows; row++ {
-		rowLineOffsets[row] = len(allLines)
-
-		leftIdx := row * 2
-		rightIdx := leftIdx + 1
+	if len(rows) == 0 {
+		allLines = append(allLines, theme.DimStyle.Render("  No projects match the tag filter (f to change)"), "")
+	}
+	for _, row := range rows {
+		if row.header {
+			allLines = append(allLines, zone.Mark(ProjectGroupZoneID(row.group), m.listHeaderLine(row, boxWidth)), "")
+			continue
+		}
 
-		leftFocused := leftIdx == m.projectCursor
+		leftIdx := row.projects[0]
+		leftFocused := !m.onHeader && leftIdx == m.projectCursor
 		leftView := zone.Mark(ProjectBoxZoneID(leftIdx), m.projects[leftIdx].box.View(colWidth, leftFocused))
 
 		var rightView string
-		if rightIdx < n {
-			rightFocused := rightIdx == m.projectCursor
+		if len(row.projects) > 1 {
+			rightIdx := row.projects[1]
+			rightFocused := !m.onHeader && rightIdx == m.projectCursor
 			rightView = zone.Mark(ProjectBoxZoneID(rightIdx), m.projects[rightIdx].box.View(colWidth, rightFocused))
 		} else {
 			// Empty placeholder for odd count — match the left box height
@@ -365,13 +512,13 @@ func (m Model) viewProjectList(contentHeight, boxWidth int, title, sep string) s
 		endIdx = len(allLines)
 	}
 
-	visible := allLines[offset:endIdx]
+	lines := allLines[offset:endIdx]
 
 	// Pad to exact height
-	for len(visible) < contentHeight {
-		visible = append(visible, "")
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
 	}
 
-	content := strings.Join(visible, "\n")
+	content := strings.Join(lines, "\n")
 	return m.renderBorder(content, contentHeight)
 }
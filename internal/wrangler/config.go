@@ -23,6 +23,7 @@ type WranglerConfig struct {
 	Bindings     []Binding               // all bindings (top-level)
 	Vars         map[string]string       // environment variables (top-level, names only for display)
 	Crons        []string                // cron triggers (top-level only, e.g. "*/5 * * * *")
+	Settings     WorkerSettings          // assets, observability, placement, limits, ... (top-level)
 	Environments map[string]*Environment // named environments
 }
 
@@ -34,6 +35,7 @@ type Environment struct {
 	Routes      []RouteConfig     // environment-specific routes
 	Bindings    []Binding         // environment-specific bindings (non-inheritable)
 	Vars        map[string]string // environment-specific vars (non-inheritable)
+	Settings    WorkerSettings    // environment-level settings; see EnvSettings for inheritance
 }
 
 // RouteConfig holds a route pattern and optional zone.
@@ -47,7 +49,9 @@ type Binding struct {
 	Name        string // JS binding name (e.g. "MY_KV")
 	Type        string // normalized type: kv_namespace, r2_bucket, d1, service, etc.
 	ResourceID  string // the identifying value (namespace_id, bucket_name, database_id, etc.)
-	DisplayName string // human-readable name for CLI commands (e.g. D1 database_name); empty if same as Name
+	DisplayName string // human-readable name for CLI commands (e.g. D1 database_name), or a rate limit's "100 per 60s"; empty if same as Name
+
+	MigrationsDir string // D1 only: migrations_dir, empty for wrangler's default
 }
 
 // NavService returns the dashboard service name for cross-linking, or empty if not navigable.
@@ -105,6 +109,18 @@ func (b Binding) TypeLabel() string {
 		return "mTLS"
 	case "workflow":
 		return "Workflow"
+	case "secrets_store_secret":
+		return "Secrets Store"
+	case "ratelimit":
+		return "Rate Limit"
+	case "dispatch_namespace":
+		return "Dispatch"
+	case "send_email":
+		return "Email"
+	case "pipeline":
+		return "Pipeline"
+	case "container":
+		return "Container"
 	case "secret_text":
 		return "Secret"
 	case "plain_text":
@@ -175,8 +191,15 @@ type rawConfig struct {
 	Images           *rawImages        `toml:"images" json:"images"`
 	MTLSCertificates []rawMTLS         `toml:"mtls_certificates" json:"mtls_certificates"`
 	Workflows        []rawWorkflow     `toml:"workflows" json:"workflows"`
+	SecretsStore     []rawSecretsStore `toml:"secrets_store_secrets" json:"secrets_store_secrets"`
+	Unsafe           *rawUnsafe        `toml:"unsafe" json:"unsafe"`
+	Dispatch         []rawDispatch     `toml:"dispatch_namespaces" json:"dispatch_namespaces"`
+	SendEmail        []rawSendEmail    `toml:"send_email" json:"send_email"`
+	Pipelines        []rawPipeline     `toml:"pipelines" json:"pipelines"`
+	Containers       []rawContainer    `toml:"containers" json:"containers"`
 	Triggers         *rawTriggers      `toml:"triggers" json:"triggers"`
 	Env              map[string]rawEnv `toml:"env" json:"env"`
+	rawSettings
 }
 
 // rawTriggers represents the [triggers] section in wrangler config (top-level only).
@@ -185,26 +208,33 @@ type rawTriggers struct {
 }
 
 type rawEnv struct {
-	Name             string          `toml:"name" json:"name"`
-	CompatDate       string          `toml:"compatibility_date" json:"compatibility_date"`
-	CompatFlags      []string        `toml:"compatibility_flags" json:"compatibility_flags"`
-	Route            *rawRoute       `toml:"route" json:"route"`
-	Routes           []rawRoute      `toml:"routes" json:"routes"`
-	Vars             map[string]any  `toml:"vars" json:"vars"`
-	KVNamespaces     []rawKV         `toml:"kv_namespaces" json:"kv_namespaces"`
-	R2Buckets        []rawR2         `toml:"r2_buckets" json:"r2_buckets"`
-	D1Databases      []rawD1         `toml:"d1_databases" json:"d1_databases"`
-	Services         []rawService    `toml:"services" json:"services"`
-	DurableObjects   *rawDO          `toml:"durable_objects" json:"durable_objects"`
-	Queues           *rawQueues      `toml:"queues" json:"queues"`
-	AI               *rawAI          `toml:"ai" json:"ai"`
-	Vectorize        []rawVectorize  `toml:"vectorize" json:"vectorize"`
-	Hyperdrive       []rawHyperdrive `toml:"hyperdrive" json:"hyperdrive"`
-	AnalyticsEngine  []rawAnalytics  `toml:"analytics_engine_datasets" json:"analytics_engine_datasets"`
-	Browser          *rawBrowser     `toml:"browser" json:"browser"`
-	Images           *rawImages      `toml:"images" json:"images"`
-	MTLSCertificates []rawMTLS       `toml:"mtls_certificates" json:"mtls_certificates"`
-	Workflows        []rawWorkflow   `toml:"workflows" json:"workflows"`
+	Name             string            `toml:"name" json:"name"`
+	CompatDate       string            `toml:"compatibility_date" json:"compatibility_date"`
+	CompatFlags      []string          `toml:"compatibility_flags" json:"compatibility_flags"`
+	Route            *rawRoute         `toml:"route" json:"route"`
+	Routes           []rawRoute        `toml:"routes" json:"routes"`
+	Vars             map[string]any    `toml:"vars" json:"vars"`
+	KVNamespaces     []rawKV           `toml:"kv_namespaces" json:"kv_namespaces"`
+	R2Buckets        []rawR2           `toml:"r2_buckets" json:"r2_buckets"`
+	D1Databases      []rawD1           `toml:"d1_databases" json:"d1_databases"`
+	Services         []rawService      `toml:"services" json:"services"`
+	DurableObjects   *rawDO            `toml:"durable_objects" json:"durable_objects"`
+	Queues           *rawQueues        `toml:"queues" json:"queues"`
+	AI               *rawAI            `toml:"ai" json:"ai"`
+	Vectorize        []rawVectorize    `toml:"vectorize" json:"vectorize"`
+	Hyperdrive       []rawHyperdrive   `toml:"hyperdrive" json:"hyperdrive"`
+	AnalyticsEngine  []rawAnalytics    `toml:"analytics_engine_datasets" json:"analytics_engine_datasets"`
+	Browser          *rawBrowser       `toml:"browser" json:"browser"`
+	Images           *rawImages        `toml:"images" json:"images"`
+	MTLSCertificates []rawMTLS         `toml:"mtls_certificates" json:"mtls_certificates"`
+	Workflows        []rawWorkflow     `toml:"workflows" json:"workflows"`
+	SecretsStore     []rawSecretsStore `toml:"secrets_store_secrets" json:"secrets_store_secrets"`
+	Unsafe           *rawUnsafe        `toml:"unsafe" json:"unsafe"`
+	Dispatch         []rawDispatch     `toml:"dispatch_namespaces" json:"dispatch_namespaces"`
+	SendEmail        []rawSendEmail    `toml:"send_email" json:"send_email"`
+	Pipelines        []rawPipeline     `toml:"pipelines" json:"pipelines"`
+	Containers       []rawContainer    `toml:"containers" json:"containers"`
+	rawSettings
 }
 
 type rawRoute struct {
@@ -223,9 +253,10 @@ type rawR2 struct {
 }
 
 type rawD1 struct {
-	Binding    string `toml:"binding" json:"binding"`
-	DatabaseID string `toml:"database_id" json:"database_id"`
-	Name       string `toml:"database_name" json:"database_name"`
+	Binding       string `toml:"binding" json:"binding"`
+	DatabaseID    string `toml:"database_id" json:"database_id"`
+	Name          string `toml:"database_name" json:"database_name"`
+	MigrationsDir string `toml:"migrations_dir" json:"migrations_dir"`
 }
 
 type rawService struct {
@@ -295,6 +326,50 @@ type rawWorkflow struct {
 	ScriptName string `toml:"script_name" json:"script_name"`
 }
 
+type rawSecretsStore struct {
+	Binding    string `toml:"binding" json:"binding"`
+	StoreID    string `toml:"store_id" json:"store_id"`
+	SecretName string `toml:"secret_name" json:"secret_name"`
+}
+
+// rawUnsafe represents the [unsafe] section. Only rate limiter bindings
+// (type = "ratelimit") are surfaced; other unsafe bindings are ignored.
+type rawUnsafe struct {
+	Bindings []rawUnsafeBinding `toml:"bindings" json:"bindings"`
+}
+
+type rawUnsafeBinding struct {
+	Name        string `toml:"name" json:"name"`
+	Type        string `toml:"type" json:"type"`
+	NamespaceID string `toml:"namespace_id" json:"namespace_id"`
+	Simple      *struct {
+		Limit  int `toml:"limit" json:"limit"`
+		Period int `toml:"period" json:"period"`
+	} `toml:"simple" json:"simple"`
+}
+
+type rawDispatch struct {
+	Binding   string `toml:"binding" json:"binding"`
+	Namespace string `toml:"namespace" json:"namespace"`
+}
+
+type rawSendEmail struct {
+	Name                        string   `toml:"name" json:"name"`
+	DestinationAddress          string   `toml:"destination_address" json:"destination_address"`
+	AllowedDestinationAddresses []string `toml:"allowed_destination_addresses" json:"allowed_destination_addresses"`
+}
+
+type rawPipeline struct {
+	Binding  string `toml:"binding" json:"binding"`
+	Pipeline string `toml:"pipeline" json:"pipeline"`
+}
+
+type rawContainer struct {
+	ClassName    string `toml:"class_name" json:"class_name"`
+	Image        string `toml:"image" json:"image"`
+	MaxInstances int    `toml:"max_instances" json:"max_instances"`
+}
+
 // --- Parsers ---
 
 func parseTOML(data []byte) (*WranglerConfig, error) {
@@ -331,6 +406,7 @@ func normalizeRaw(raw *rawConfig) *WranglerConfig {
 		Bindings:     extractBindings(raw),
 		Vars:         normalizeVars(raw.Vars),
 		Crons:        crons,
+		Settings:     normalizeSettings(raw.rawSettings),
 		Environments: make(map[string]*Environment),
 	}
 
@@ -342,6 +418,7 @@ func normalizeRaw(raw *rawConfig) *WranglerConfig {
 			Routes:      normalizeRoutes(rawEnv.Route, rawEnv.Routes),
 			Bindings:    extractEnvBindings(&rawEnv),
 			Vars:        normalizeVars(rawEnv.Vars),
+			Settings:    normalizeSettings(rawEnv.rawSettings),
 		}
 		cfg.Environments[envName] = env
 	}
@@ -380,7 +457,8 @@ func extractBindings(raw *rawConfig) []Binding {
 		raw.DurableObjects, raw.Queues, raw.AI,
 		raw.Vectorize, raw.Hyperdrive, raw.AnalyticsEngine,
 		raw.Browser, raw.Images, raw.MTLSCertificates,
-		raw.Workflows,
+		raw.Workflows, raw.SecretsStore, raw.Unsafe, raw.Dispatch,
+		raw.SendEmail, raw.Pipelines, raw.Containers,
 	)
 }
 
@@ -391,7 +469,8 @@ func extractEnvBindings(raw *rawEnv) []Binding {
 		raw.DurableObjects, raw.Queues, raw.AI,
 		raw.Vectorize, raw.Hyperdrive, raw.AnalyticsEngine,
 		raw.Browser, raw.Images, raw.MTLSCertificates,
-		raw.Workflows,
+		raw.Workflows, raw.SecretsStore, raw.Unsafe, raw.Dispatch,
+		raw.SendEmail, raw.Pipelines, raw.Containers,
 	)
 }
 
@@ -400,7 +479,8 @@ func collectBindings(
 	do *rawDO, queues *rawQueues, ai *rawAI,
 	vectorize []rawVectorize, hyperdrive []rawHyperdrive, analytics []rawAnalytics,
 	browser *rawBrowser, images *rawImages, mtls []rawMTLS,
-	workflows []rawWorkflow,
+	workflows []rawWorkflow, secrets []rawSecretsStore, unsafe *rawUnsafe, dispatch []rawDispatch,
+	email []rawSendEmail, pipelines []rawPipeline, containers []rawContainer,
 ) []Binding {
 	var bindings []Binding
 
@@ -415,7 +495,7 @@ func collectBindings(
 		if id == "" {
 			id = b.Name
 		}
-		bindings = append(bindings, Binding{Name: b.Binding, Type: "d1", ResourceID: id, DisplayName: b.Name})
+		bindings = append(bindings, Binding{Name: b.Binding, Type: "d1", ResourceID: id, DisplayName: b.Name, MigrationsDir: b.MigrationsDir})
 	}
 	for _, b := range svcs {
 		bindings = append(bindings, Binding{Name: b.Binding, Type: "service", ResourceID: b.Service})
@@ -457,6 +537,40 @@ func collectBindings(
 	for _, b := range workflows {
 		bindings = append(bindings, Binding{Name: b.Binding, Type: "workflow", ResourceID: b.ClassName})
 	}
+	for _, b := range secrets {
+		bindings = append(bindings, Binding{Name: b.Binding, Type: "secrets_store_secret", ResourceID: b.StoreID + "/" + b.SecretName})
+	}
+	if unsafe != nil {
+		for _, b := range unsafe.Bindings {
+			if b.Type != "ratelimit" {
+				continue
+			}
+			binding := Binding{Name: b.Name, Type: "ratelimit", ResourceID: b.NamespaceID}
+			if b.Simple != nil {
+				binding.DisplayName = fmt.Sprintf("%d per %ds", b.Simple.Limit, b.Simple.Period)
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+	for _, b := range dispatch {
+		bindings = append(bindings, Binding{Name: b.Binding, Type: "dispatch_namespace", ResourceID: b.Namespace})
+	}
+	for _, b := range email {
+		dest := b.DestinationAddress
+		if dest == "" && len(b.AllowedDestinationAddresses) > 0 {
+			dest = strings.Join(b.AllowedDestinationAddresses, ", ")
+		}
+		if dest == "" {
+			dest = "any verified address"
+		}
+		bindings = append(bindings, Binding{Name: b.Name, Type: "send_email", ResourceID: dest})
+	}
+	for _, b := range pipelines {
+		bindings = append(bindings, Binding{Name: b.Binding, Type: "pipeline", ResourceID: b.Pipeline})
+	}
+	for _, b := range containers {
+		bindings = append(bindings, Binding{Name: b.ClassName, Type: "container", ResourceID: b.Image})
+	}
 
 	return bindings
 }
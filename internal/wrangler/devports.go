@@ -0,0 +1,46 @@
+package wrangler
+
+import (
+	"fmt"
+	"net"
+)
+
+// Default ports of wrangler dev. Concurrent dev sessions each need their own
+// HTTP and inspector port, allocated upwards from these.
+const (
+	DefaultDevPort       = 8787
+	DefaultInspectorPort = 9229
+)
+
+// maxPortProbe bounds how far FreePort searches from its start port.
+const maxPortProbe = 200
+
+// FreePort returns the first port from start upwards that is not in taken and
+// can be listened on locally. Ports of sessions still starting aren't bound
+// yet, so callers pass them in taken.
+func FreePort(start int, taken map[int]bool) (int, error) {
+	for port := start; port < start+maxPortProbe && port <= 65535; port++ {
+		if taken[port] || !portFree(port) {
+			continue
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port in %d-%d", start, start+maxPortProbe-1)
+}
+
+// portFree reports whether nothing is listening on port on the loopback
+// interface.
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+// DevPortArgs returns the wrangler dev flags that pin a session to port and
+// inspectorPort.
+func DevPortArgs(port, inspectorPort int) []string {
+	return []string{"--port", fmt.Sprint(port), "--inspector-port", fmt.Sprint(inspectorPort)}
+}
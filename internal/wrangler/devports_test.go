@@ -0,0 +1,32 @@
+package wrangler
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFreePort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	defer ln.Close()
+	busy := ln.Addr().(*net.TCPAddr).Port
+
+	port, err := FreePort(busy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port == busy {
+		t.Errorf("FreePort returned port %d that is in use", busy)
+	}
+
+	taken := map[int]bool{port: true}
+	next, err := FreePort(busy, taken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == busy || next == port {
+		t.Errorf("FreePort returned %d, want a port other than %d and %d", next, busy, port)
+	}
+}
@@ -0,0 +1,160 @@
+package wrangler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table; larger changes fall back to showing the
+// changed region as one removal and one addition.
+const maxDiffCells = 4_000_000
+
+// UnifiedDiff returns a unified diff of two texts (without file headers):
+// "@@" hunk headers followed by " ", "-" and "+" lines, with the given
+// number of context lines. Returns nil if the texts are equal.
+func UnifiedDiff(before, after string, context int) []string {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// Trim the common prefix and suffix; config edits are small, so the LCS
+	// only runs over the changed region.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	if pre == len(a) && pre == len(b) {
+		return nil
+	}
+
+	// ops over the full texts: ' ' keep, '-' delete from a, '+' insert from b
+	type op struct {
+		kind byte
+		text string
+	}
+	var ops []op
+	for _, l := range a[:pre] {
+		ops = append(ops, op{' ', l})
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if (len(ma)+1)*(len(mb)+1) > maxDiffCells {
+		for _, l := range ma {
+			ops = append(ops, op{'-', l})
+		}
+		for _, l := range mb {
+			ops = append(ops, op{'+', l})
+		}
+	} else {
+		// lcs[i][j] = LCS length of ma[i:] and mb[j:]
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				ops = append(ops, op{' ', ma[i]})
+				i++
+				j++
+			case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, op{'-', ma[i]})
+				i++
+			default:
+				ops = append(ops, op{'+', mb[j]})
+				j++
+			}
+		}
+	}
+	for _, l := range a[len(a)-suf:] {
+		ops = append(ops, op{' ', l})
+	}
+
+	// Group changes into hunks with context
+	var out []string
+	aLine, bLine := 1, 1
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			aLine++
+			bLine++
+			k++
+			continue
+		}
+		// Hunk start: back up over context lines
+		start := max(k-context, 0)
+		for start > 0 && ops[start-1].kind != ' ' {
+			start--
+		}
+		aStart, bStart := aLine-(k-start), bLine-(k-start)
+
+		// Extend until a gap of more than 2*context unchanged lines
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		var body []string
+		aCount, bCount := 0, 0
+		for _, o := range ops[start:end] {
+			body = append(body, string(o.kind)+o.text)
+			if o.kind != '+' {
+				aCount++
+			}
+			if o.kind != '-' {
+				bCount++
+			}
+		}
+		// An empty range is numbered by the line before it
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart, aCount, bStart, bCount))
+		out = append(out, body...)
+
+		for _, o := range ops[k:end] {
+			if o.kind != '+' {
+				aLine++
+			}
+			if o.kind != '-' {
+				bLine++
+			}
+		}
+		k = end
+	}
+	return out
+}
+
+// splitLines splits text into lines without their newlines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,240 @@
+package wrangler
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxGlobDepth bounds how deep a "**" pattern segment descends.
+const maxGlobDepth = 16
+
+// workspaceGlobs returns the package manager workspace globs declared at
+// root (package.json "workspaces" for npm/yarn, pnpm-workspace.yaml for pnpm).
+// Negated globs ("!**/test/**") are returned as excludes.
+func workspaceGlobs(root string) (include, exclude []string) {
+	var globs []string
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 {
+			var list []string
+			var obj struct {
+				Packages []string `json:"packages"`
+			}
+			if json.Unmarshal(pkg.Workspaces, &list) == nil {
+				globs = append(globs, list...)
+			} else if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+				globs = append(globs, obj.Packages...)
+			}
+		}
+	}
+	globs = append(globs, pnpmWorkspaceGlobs(filepath.Join(root, "pnpm-workspace.yaml"))...)
+
+	for _, g := range globs {
+		if neg, ok := strings.CutPrefix(g, "!"); ok {
+			exclude = append(exclude, neg)
+		} else {
+			include = append(include, g)
+		}
+	}
+	return include, exclude
+}
+
+// pnpmWorkspaceGlobs reads the "packages:" list of a pnpm-workspace.yaml.
+// Only the block sequence form pnpm documents is understood.
+func pnpmWorkspaceGlobs(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var globs []string
+	inPackages := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' && line[0] != '-' {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "-"); ok && inPackages {
+			globs = append(globs, strings.Trim(strings.TrimSpace(item), `"'`))
+		}
+	}
+	return globs
+}
+
+// scanner walks the directories that can hold projects under a repo root,
+// applying the repo settings, workspace globs and .gitignore. It is shared by
+// DiscoverProjects and the Watcher so both see the same directories.
+type scanner struct {
+	root     string
+	settings *RepoSettings
+	maxDepth int
+	include  []string // project dir globs (settings + workspaces)
+	exclude  []string
+	ignore   *gitignore // nil when .gitignore handling is disabled
+
+	visit   func(dir string) bool
+	entered map[string]bool // dir → visit result
+}
+
+// newScanner prepares a scan of root (an absolute path). The settings error,
+// if any, is returned alongside a scanner using default settings.
+func newScanner(root string) (*scanner, error) {
+	settings, err := LoadRepoSettings(root)
+	d := settings.Discovery
+	s := &scanner{
+		root:     root,
+		settings: settings,
+		maxDepth: maxDiscoverDepth,
+		include:  d.Include,
+		exclude:  d.Exclude,
+	}
+	if d.MaxDepth > 0 {
+		s.maxDepth = d.MaxDepth
+	}
+	if d.Workspaces == nil || *d.Workspaces {
+		inc, exc := workspaceGlobs(root)
+		s.include = append(s.include, inc...)
+		s.exclude = append(s.exclude, exc...)
+	}
+	if d.Gitignore == nil || *d.Gitignore {
+		s.ignore = newGitignore(root)
+	}
+	return s, err
+}
+
+// run calls visit for every directory that may hold a project. visit returns
+// false to stop descending (the directory is a project). The configured roots
+// are walked up to the depth limit; include and workspace globs are expanded
+// from the repo root. With globs and no explicit roots, only the globs (and
+// the root itself) are searched.
+func (s *scanner) run(visit func(dir string) bool) {
+	s.visit = visit
+	s.entered = make(map[string]bool)
+
+	roots := s.settings.Discovery.Roots
+	depth := s.maxDepth
+	if len(roots) == 0 {
+		roots = []string{"."}
+		if len(s.include) > 0 {
+			depth = 0
+		}
+	}
+	for _, r := range roots {
+		dir := filepath.Clean(filepath.Join(s.root, filepath.FromSlash(r)))
+		if filepath.IsAbs(r) {
+			dir = filepath.Clean(r)
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			s.walk(dir, 0, depth)
+		}
+	}
+	for _, pattern := range s.include {
+		if p := cleanGlob(pattern); p != "" {
+			s.expand(s.root, strings.Split(p, "/"), 0)
+		}
+	}
+}
+
+// enter visits dir once and returns whether to descend into it.
+func (s *scanner) enter(dir string) bool {
+	if descend, ok := s.entered[dir]; ok {
+		return descend
+	}
+	descend := s.visit(dir)
+	s.entered[dir] = descend
+	return descend
+}
+
+// rel returns dir relative to the repo root with forward slashes.
+func (s *scanner) rel(dir string) string {
+	rel, err := filepath.Rel(s.root, dir)
+	if err != nil {
+		return dir
+	}
+	return filepath.ToSlash(rel)
+}
+
+// excluded reports whether dir matches an exclude glob or is git-ignored.
+func (s *scanner) excluded(dir string) bool {
+	rel := s.rel(dir)
+	for _, pattern := range s.exclude {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return s.ignore != nil && s.ignore.ignored(dir)
+}
+
+// skip reports whether a subdirectory should never be searched.
+func (s *scanner) skip(dir string) bool {
+	return skipDirName(filepath.Base(dir)) || s.excluded(dir)
+}
+
+func (s *scanner) walk(dir string, depth, limit int) {
+	if !s.enter(dir) || depth >= limit {
+		return
+	}
+	for _, child := range subdirs(dir) {
+		if !s.skip(child) {
+			s.walk(child, depth+1, limit)
+		}
+	}
+}
+
+// expand follows a glob from dir, entering each directory on the way.
+func (s *scanner) expand(dir string, pat []string, depth int) {
+	if !s.enter(dir) || len(pat) == 0 || depth > maxGlobDepth {
+		return
+	}
+	seg, rest := pat[0], pat[1:]
+	switch {
+	case seg == "**":
+		s.expand(dir, rest, depth)
+		for _, child := range subdirs(dir) {
+			if !s.skip(child) {
+				s.expand(child, pat, depth+1)
+			}
+		}
+	case !strings.ContainsAny(seg, `*?[\`):
+		child := filepath.Join(dir, seg)
+		if info, err := os.Stat(child); err == nil && info.IsDir() && !s.excluded(child) {
+			s.expand(child, rest, depth+1)
+		}
+	default:
+		for _, child := range subdirs(dir) {
+			if ok, _ := filepath.Match(seg, filepath.Base(child)); ok && !s.skip(child) {
+				s.expand(child, rest, depth+1)
+			}
+		}
+	}
+}
+
+// subdirs lists the subdirectories of dir.
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	return dirs
+}
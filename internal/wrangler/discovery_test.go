@@ -0,0 +1,267 @@
+package wrangler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files (relative path → content) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// discovered returns the discovered project dirs relative to root, with
+// their group after a colon when set.
+func discovered(t *testing.T, root string) []string {
+	t.Helper()
+	projects, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range projects {
+		rel, _ := filepath.Rel(root, p.Dir)
+		rel = filepath.ToSlash(rel)
+		if p.Group != "" {
+			rel += ":" + p.Group
+		}
+		got = append(got, rel)
+	}
+	return got
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"apps/*", "apps/api", true},
+		{"apps/*", "apps/api/src", false},
+		{"./apps/*/", "apps/api", true},
+		{"apps/**", "apps", true},
+		{"apps/**", "apps/a/b/c", true},
+		{"**/workers/*", "apps/shop/workers/cart", true},
+		{"**/workers/*", "workers/cart", true},
+		{"apps/*/workers/*", "apps/shop/workers", false},
+		{"legacy", "legacy", true},
+		{"legacy", "apps/legacy", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":                   "tmp/\n/generated\n",
+		"api/wrangler.toml":            `name = "api"`,
+		"tmp/scratch/wrangler.toml":    `name = "scratch"`,
+		"generated/x/wrangler.toml":    `name = "x"`,
+		"tools/.gitignore":             "*\n!keep\n",
+		"tools/keep/wrangler.toml":     `name = "keep"`,
+		"tools/drop/wrangler.toml":     `name = "drop"`,
+		"node_modules/w/wrangler.toml": `name = "w"`,
+	})
+	want := []string{"api", "tools/keep"}
+	if got := discovered(t, root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"package.json":                             `{"workspaces": {"packages": ["apps/*/workers/*", "!apps/legacy/**"]}}`,
+		"pnpm-workspace.yaml":                      "packages:\n  - 'tools/*' # shared tooling\nonlyBuiltDependencies:\n  - esbuild\n",
+		"apps/shop/workers/cart/wrangler.jsonc":    `{"name": "cart"}`,
+		"apps/shop/workers/cart/sub/wrangler.toml": `name = "nested"`,
+		"apps/legacy/workers/old/wrangler.toml":    `name = "old"`,
+		"tools/lint/wrangler.toml":                 `name = "lint"`,
+		// Outside the workspaces: not searched once globs are declared
+		"scripts/w/wrangler.toml": `name = "w"`,
+	})
+	want := []string{"apps/shop/workers/cart", "tools/lint"}
+	if got := discovered(t, root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverSettings(t *testing.T) {
+	root := t.TempDir()
+	shared := filepath.Join(filepath.Dir(root), filepath.Base(root)+"-shared")
+	t.Cleanup(func() { os.RemoveAll(shared) })
+	writeTree(t, shared, map[string]string{"auth/wrangler.toml": `name = "auth"`})
+
+	writeTree(t, root, map[string]string{
+		SettingsFileName: `
+[discovery]
+roots = [".", "../` + filepath.Base(shared) + `"]
+include = ["deep/a/b/c/d/e/*"]
+exclude = ["examples/**"]
+workspaces = false
+
+[groups]
+edge = ["deep/**"]
+apps = ["apps/*"]
+`,
+		"package.json":                   `{"workspaces": ["nothing/*"]}`,
+		"apps/web/wrangler.toml":         `name = "web"`,
+		"examples/demo/wrangler.toml":    `name = "demo"`,
+		"deep/a/b/c/d/e/f/wrangler.toml": `name = "f"`,
+	})
+	want := []string{"../" + filepath.Base(shared) + "/auth", "apps/web:apps", "deep/a/b/c/d/e/f:edge"}
+	if got := discovered(t, root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// A malformed settings file falls back to defaults and reports the error
+	writeTree(t, root, map[string]string{SettingsFileName: "[discovery\n"})
+	if err := os.Remove(filepath.Join(root, "package.json")); err != nil {
+		t.Fatal(err)
+	}
+	projects, err := Discover(root)
+	if err == nil {
+		t.Fatal("expected a settings error")
+	}
+	if len(projects) != 2 {
+		t.Fatalf("expected the 2 projects within the default depth, got %d", len(projects))
+	}
+}
+
+func TestProjectTags(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		SettingsFileName:           "# shared settings\n[tags]\ninternal = [\"tools/*\"]\n",
+		"api/wrangler.toml":        `name = "api"`,
+		"billing/wrangler.toml":    `name = "billing"`,
+		"tools/lint/wrangler.toml": `name = "lint"`,
+	})
+	tagsOf := func() map[string][]string {
+		projects, err := Discover(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string][]string)
+		for _, p := range projects {
+			if len(p.Tags) > 0 {
+				got[filepath.Base(p.Dir)] = p.Tags
+			}
+		}
+		return got
+	}
+
+	for _, step := range []struct{ rel, tag string }{{"api", "edge"}, {"billing", "edge"}, {"billing", "billing"}, {"api", "edge"}} {
+		if err := AddProjectTag(root, step.rel, step.tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string][]string{"api": {"edge"}, "billing": {"edge", "billing"}, "lint": {"internal"}}
+	if got := tagsOf(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after tagging: got %v, want %v", got, want)
+	}
+
+	if err := RemoveProjectTag(root, "api", "edge"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveProjectTag(root, "billing", "billing"); err != nil {
+		t.Fatal(err)
+	}
+	want = map[string][]string{"billing": {"edge"}, "lint": {"internal"}}
+	if got := tagsOf(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after untagging: got %v, want %v", got, want)
+	}
+
+	// Tagged by a pattern: can't be untagged individually
+	if err := RemoveProjectTag(root, "tools/lint", "internal"); err == nil {
+		t.Fatal("expected an error untagging a pattern match")
+	}
+	data, err := os.ReadFile(filepath.Join(root, SettingsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# shared settings\n") {
+		t.Fatalf("comment not preserved:\n%s", data)
+	}
+}
+
+func TestD1SavedQueries(t *testing.T) {
+	root := t.TempDir()
+	if err := SaveD1Query(root, "prod-db", "active", "SELECT * FROM users WHERE name = \"a\""); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveD1Query(root, "prod-db", "count", "SELECT count(*) FROM users"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveD1Query(root, "prod-db", "active", "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteD1Query(root, "prod-db", "count"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteD1Query(root, "prod-db", "missing"); err == nil {
+		t.Fatal("expected an error deleting a missing query")
+	}
+	settings, err := LoadRepoSettings(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]string{"prod-db": {"active": "SELECT 1"}}
+	if !reflect.DeepEqual(settings.D1Queries, want) {
+		t.Fatalf("got %v, want %v", settings.D1Queries, want)
+	}
+}
+
+func TestSavedHTTPRequests(t *testing.T) {
+	root := t.TempDir()
+	create := SavedRequest{Method: "POST", Path: "/users", Headers: []string{"Content-Type: application/json"}, Body: `{"name":"a"}`}
+	if _, err := SaveHTTPRequest(root, "api", "create", create); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveHTTPRequest(root, "api", "list", SavedRequest{Method: "GET", Path: "/users"}); err != nil {
+		t.Fatal(err)
+	}
+	authed := SavedRequest{Method: "GET", Path: "/users?limit=5", Headers: []string{
+		"Authorization: Bearer abc", "Accept: application/json", "X-API-Key: k", "cookie: s=1",
+	}}
+	redacted, err := SaveHTTPRequest(root, "api", "list", authed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Authorization", "X-API-Key", "cookie"}; !reflect.DeepEqual(redacted, want) {
+		t.Fatalf("redacted %v, want %v", redacted, want)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, SettingsFileName)); strings.Contains(string(data), "Bearer") {
+		t.Fatalf("credential saved:\n%s", data)
+	}
+	if err := DeleteHTTPRequest(root, "api", "missing"); err == nil {
+		t.Fatal("expected an error deleting a missing request")
+	}
+	settings, err := LoadRepoSettings(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]SavedRequest{"api": {
+		"create": create,
+		"list": {Method: "GET", Path: "/users?limit=5", Headers: []string{
+			"Authorization:", "Accept: application/json", "X-API-Key:", "cookie:",
+		}},
+	}}
+	if !reflect.DeepEqual(settings.Requests, want) {
+		t.Fatalf("got %v, want %v", settings.Requests, want)
+	}
+}
@@ -0,0 +1,297 @@
+package wrangler
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Shared text helpers for the format-preserving JSONC and TOML editors
+// (jsonc_edit.go, toml_edit.go). Both editors locate byte spans in the
+// original source and splice only what changes, so comments, key order,
+// indentation and blank lines elsewhere in the file survive every edit.
+
+// span is a half-open byte range [start, end) in the source.
+type span struct {
+	start, end int
+}
+
+// commentSyntax describes the comment forms a format allows.
+type commentSyntax struct {
+	line  string // line comment marker: "//" or "#"
+	block bool   // whether /* */ block comments are allowed
+}
+
+var (
+	jsoncComments = commentSyntax{line: "//", block: true}
+	tomlComments  = commentSyntax{line: "#"}
+)
+
+// splice returns src with [start, end) replaced by text.
+func splice(src []byte, start, end int, text string) []byte {
+	out := make([]byte, 0, len(src)-(end-start)+len(text))
+	out = append(out, src[:start]...)
+	out = append(out, text...)
+	return append(out, src[end:]...)
+}
+
+// lineStart returns the offset of the first byte of the line containing pos.
+func lineStart(src []byte, pos int) int {
+	for pos > 0 && src[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd returns the offset of the newline ending the line containing pos,
+// or len(src) on the last line.
+func lineEnd(src []byte, pos int) int {
+	if i := bytes.IndexByte(src[pos:], '\n'); i >= 0 {
+		return pos + i
+	}
+	return len(src)
+}
+
+// lineIndent returns the leading whitespace of the line containing pos.
+func lineIndent(src []byte, pos int) string {
+	s := lineStart(src, pos)
+	e := s
+	for e < len(src) && (src[e] == ' ' || src[e] == '\t') {
+		e++
+	}
+	return string(src[s:e])
+}
+
+// ownLine reports whether only whitespace precedes pos on its line.
+func ownLine(src []byte, pos int) bool {
+	return strings.TrimSpace(string(src[lineStart(src, pos):pos])) == ""
+}
+
+// detectIndentUnit returns the indentation step used by the document: a tab
+// if indented lines start with tabs, otherwise the smallest run of spaces.
+// Defaults to two spaces.
+func detectIndentUnit(src []byte) string {
+	smallest := 0
+	for _, line := range bytes.Split(src, []byte("\n")) {
+		if len(line) == 0 || len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] == '\t' {
+			return "\t"
+		}
+		n := 0
+		for n < len(line) && line[n] == ' ' {
+			n++
+		}
+		if n > 0 && (smallest == 0 || n < smallest) {
+			smallest = n
+		}
+	}
+	if smallest == 0 {
+		return "  "
+	}
+	return strings.Repeat(" ", smallest)
+}
+
+// isCommentLine reports whether a line holds only a comment.
+func isCommentLine(line []byte, syn commentSyntax) bool {
+	t := bytes.TrimSpace(line)
+	if bytes.HasPrefix(t, []byte(syn.line)) {
+		return true
+	}
+	return syn.block && bytes.HasPrefix(t, []byte("/*")) && bytes.HasSuffix(t, []byte("*/"))
+}
+
+// precedingComments moves start (a line start) up over comment-only lines
+// directly above it, so comments describing an item are removed with it.
+// A blank line ends the run.
+func precedingComments(src []byte, start int, syn commentSyntax) int {
+	for start > 0 {
+		prev := lineStart(src, start-1)
+		if !isCommentLine(src[prev:start-1], syn) {
+			break
+		}
+		start = prev
+	}
+	return start
+}
+
+// afterItem scans past the end of a sequence item: spaces, an optional comma
+// and an optional trailing comment on the same line. It returns the offset of
+// the comma (-1 if none) and the offset where the scan stopped — the newline
+// ending the line, or the next non-comment character.
+func afterItem(src []byte, end int, syn commentSyntax) (comma, stop int) {
+	comma = -1
+	i := end
+	for i < len(src) {
+		switch c := src[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == ',' && comma < 0:
+			comma = i
+			i++
+		case bytes.HasPrefix(src[i:], []byte(syn.line)):
+			return comma, lineEnd(src, i)
+		case syn.block && bytes.HasPrefix(src[i:], []byte("/*")):
+			close := bytes.Index(src[i+2:], []byte("*/"))
+			if close < 0 {
+				return comma, len(src)
+			}
+			i += close + 4
+		default:
+			return comma, i
+		}
+	}
+	return comma, i
+}
+
+// seqStyle controls how items are added to an empty sequence.
+type seqStyle struct {
+	syn         commentSyntax
+	unit        string // indentation step
+	expandEmpty bool   // lay out a new first item on its own line
+	pad         string // padding inside single-line brackets ("{ a = 1 }")
+}
+
+// insertSeqItem appends an item to a bracketed sequence (JSON object or array,
+// TOML array or inline table). open and closeAt are the offsets of the
+// brackets; items are the spans of the existing items. compact is the item's
+// single-line form and pretty renders it for a line at the given indent.
+// The existing layout is kept: multi-line sequences get the item on a new
+// line (mirroring the trailing-comma style), single-line ones get ", item".
+func insertSeqItem(src []byte, open, closeAt int, items []span, compact string, pretty func(indent string) string, st seqStyle) []byte {
+	if len(items) == 0 {
+		inner := string(src[open+1 : closeAt])
+		if !st.expandEmpty && !strings.Contains(inner, "\n") {
+			return splice(src, open+1, closeAt, st.pad+compact+st.pad)
+		}
+		closeIndent := lineIndent(src, closeAt)
+		indent := closeIndent + st.unit
+		if strings.TrimSpace(inner) == "" {
+			return splice(src, open+1, closeAt, "\n"+indent+pretty(indent)+"\n"+closeIndent)
+		}
+		// Keep comments inside the empty sequence; add the item after them
+		if ownLine(src, closeAt) {
+			ls := lineStart(src, closeAt)
+			return splice(src, ls, ls, indent+pretty(indent)+"\n")
+		}
+		return splice(src, closeAt, closeAt, "\n"+indent+pretty(indent)+"\n"+closeIndent)
+	}
+
+	last := items[len(items)-1]
+	comma, stop := afterItem(src, last.end, st.syn)
+	if ownLine(src, items[0].start) {
+		indent := lineIndent(src, last.start)
+		text := "\n" + indent + pretty(indent)
+		if comma >= 0 {
+			return splice(src, stop, stop, text+",")
+		}
+		out := splice(src, stop, stop, text)
+		return splice(out, last.end, last.end, ",")
+	}
+	if comma >= 0 {
+		return splice(src, comma+1, comma+1, " "+compact+",")
+	}
+	return splice(src, last.end, last.end, ", "+compact)
+}
+
+// removeSeqItem removes item k from a bracketed sequence, together with its
+// separator, its trailing comment and (for items on their own line) the
+// comment lines directly above it. A sequence left empty collapses to "[]"/"{}".
+func removeSeqItem(src []byte, open, closeAt int, items []span, k int, syn commentSyntax) []byte {
+	it := items[k]
+	comma, stop := afterItem(src, it.end, syn)
+
+	var out []byte
+	var removed int
+	if ownLine(src, it.start) && (stop >= len(src) || src[stop] == '\n') {
+		start := precedingComments(src, lineStart(src, it.start), syn)
+		end := stop
+		if end < len(src) {
+			end++ // the newline
+		}
+		out = splice(src, start, end, "")
+		removed = end - start
+		// The previous item's comma becomes dangling when the last item goes
+		// away in a list without trailing commas.
+		if comma < 0 && k > 0 && k == len(items)-1 {
+			if pc, _ := afterItem(out, items[k-1].end, syn); pc >= 0 && pc < start {
+				out = splice(out, pc, pc+1, "")
+				removed++
+			}
+		}
+	} else {
+		var start, end int
+		switch {
+		case k < len(items)-1:
+			start, end = it.start, items[k+1].start
+		case k > 0:
+			start, end = items[k-1].end, it.end
+			if pc, _ := afterItem(src, items[k-1].end, syn); pc >= 0 && comma >= 0 {
+				// "a, b," → "a," (keep the trailing-comma style)
+				start, end = pc+1, comma+1
+			}
+		default:
+			start, end = it.start, it.end
+			if comma >= 0 {
+				end = comma + 1
+			}
+		}
+		out = splice(src, start, end, "")
+		removed = end - start
+	}
+
+	if len(items) == 1 {
+		newClose := closeAt - removed
+		if strings.TrimSpace(string(out[open+1:newClose])) == "" {
+			out = splice(out, open+1, newClose, "")
+		}
+	}
+	return out
+}
+
+// collapseBlankLines reduces runs of blank lines touching pos to a single
+// blank line, and trims blank lines at the end of the document.
+func collapseBlankLines(src []byte, pos int) []byte {
+	if pos > len(src) {
+		pos = len(src)
+	}
+	start := lineStart(src, pos)
+	// Walk up over blank lines
+	for start > 0 {
+		prev := lineStart(src, start-1)
+		if len(bytes.TrimSpace(src[prev:start])) != 0 {
+			break
+		}
+		start = prev
+	}
+	end := start
+	blanks := 0
+	for end < len(src) {
+		le := lineEnd(src, end)
+		if len(bytes.TrimSpace(src[end:le])) != 0 {
+			break
+		}
+		blanks++
+		if le >= len(src) {
+			end = len(src)
+			break
+		}
+		end = le + 1
+	}
+	if end >= len(src) {
+		// Trailing blank lines: keep the document's final newline only
+		out := splice(src, start, end, "")
+		if len(out) > 0 && out[len(out)-1] != '\n' {
+			out = append(out, '\n')
+		}
+		return out
+	}
+	if blanks > 1 {
+		keep := "\n"
+		if start == 0 {
+			keep = ""
+		}
+		return splice(src, start, end, keep)
+	}
+	return src
+}
@@ -52,6 +52,8 @@ type ProjectInfo struct {
 	ConfigPath string   // absolute path to wrangler config file
 	Dir        string   // absolute path to the project directory
 	Git        *GitInfo // local git repo info (nil if not in a git repo)
+	Group      string   // group from .orangeshell.toml ("" if ungrouped)
+	Tags       []string // tags from .orangeshell.toml
 }
 
 // skipDirs are directory names that should never contain wrangler projects.
@@ -68,63 +70,56 @@ var skipDirs = map[string]bool{
 	"vendor":       true,
 }
 
-// maxDiscoverDepth limits how deep DiscoverProjects walks the tree.
+// skipDirName reports whether a directory name is never searched: skipDirs
+// and hidden directories.
+func skipDirName(name string) bool {
+	return skipDirs[name] || len(name) > 0 && name[0] == '.'
+}
+
+// maxDiscoverDepth is the default limit on how deep DiscoverProjects walks
+// the tree (overridable with max_depth in .orangeshell.toml).
 const maxDiscoverDepth = 5
 
-// DiscoverProjects walks the directory tree from root, finding all directories
-// containing a wrangler config file. Skips known non-project dirs (node_modules,
-// .git, dist, etc.) and limits depth to 5 levels. Returns results sorted by
-// directory path.
+// DiscoverProjects finds all directories containing a wrangler config under
+// root. See Discover; a malformed .orangeshell.toml is ignored.
 func DiscoverProjects(root string) []ProjectInfo {
-	absRoot, err := filepath.Abs(root)
-	if err != nil {
-		return nil
-	}
-
-	var projects []ProjectInfo
-	discoverWalk(absRoot, absRoot, 0, &projects)
-
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].Dir < projects[j].Dir
-	})
+	projects, _ := Discover(root)
 	return projects
 }
 
-// discoverWalk recursively scans directories for wrangler configs.
-func discoverWalk(dir, root string, depth int, projects *[]ProjectInfo) {
-	if depth > maxDiscoverDepth {
-		return
+// Discover finds all directories containing a wrangler config under root.
+// It walks the tree skipping known non-project dirs (node_modules, .git,
+// dist, etc.), hidden and git-ignored dirs, down to 5 levels. npm/yarn/pnpm
+// workspace globs and the roots, include/exclude globs, groups and tags of
+// an optional .orangeshell.toml refine the search. A project is a leaf: its
+// subdirectories are not searched. Returns results sorted by directory path,
+// and the error from reading .orangeshell.toml, if any.
+func Discover(root string) ([]ProjectInfo, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, nil
 	}
 
-	// Check if this directory has a wrangler config
-	if configPath := FindConfig(dir); configPath != "" {
-		*projects = append(*projects, ProjectInfo{
+	s, settingsErr := newScanner(absRoot)
+	var projects []ProjectInfo
+	s.run(func(dir string) bool {
+		configPath := FindConfig(dir)
+		if configPath == "" {
+			return true
+		}
+		projects = append(projects, ProjectInfo{
 			ConfigPath: configPath,
 			Dir:        dir,
 			Git:        DetectGit(dir),
+			Group:      s.settings.GroupOf(s.rel(dir)),
+			Tags:       s.settings.TagsOf(s.rel(dir)),
 		})
 		// Don't recurse into a project directory — a project is a leaf
-		return
-	}
-
-	// Read directory entries
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return
-	}
+		return false
+	})
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if skipDirs[name] {
-			continue
-		}
-		// Skip hidden directories (other than those in skipDirs which are already handled)
-		if len(name) > 0 && name[0] == '.' {
-			continue
-		}
-		discoverWalk(filepath.Join(dir, name), root, depth+1, projects)
-	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Dir < projects[j].Dir
+	})
+	return projects, settingsErr
 }
@@ -22,7 +22,8 @@ type GitInfo struct {
 	Branch       string // current branch name (or "HEAD" if detached)
 	RemoteURL    string // origin remote URL (first remote if no origin)
 	RemoteName   string // name of the remote ("origin" or first found)
-	ProviderType string // "github", "gitlab", or "" (unknown)
+	Host         string // remote hostname (SSH aliases resolved via ~/.ssh/config)
+	ProviderType string // "github", "gitlab", "bitbucket", or "" (unknown)
 	Owner        string // repository owner (org or user)
 	RepoName     string // repository name (without .git suffix)
 	OwnerID      string // numeric provider account/user ID (resolved via API)
@@ -58,7 +59,7 @@ func DetectGit(dir string) *GitInfo {
 
 	// Parse provider/owner/repo from the remote URL
 	if info.RemoteURL != "" {
-		info.ProviderType, info.Owner, info.RepoName = parseRemoteURL(info.RemoteURL)
+		info.Host, info.ProviderType, info.Owner, info.RepoName = parseRemoteURL(info.RemoteURL)
 	}
 
 	return info
@@ -154,44 +155,116 @@ func readGitRemote(gitDir string) (remoteName, remoteURL string) {
 	return firstRemote, firstURL
 }
 
-// SSH pattern: git@github.com:owner/repo.git
-var sshRemotePattern = regexp.MustCompile(`^git@([^:]+):([^/]+)/(.+?)(?:\.git)?$`)
-
-// HTTPS pattern: https://github.com/owner/repo.git
-var httpsRemotePattern = regexp.MustCompile(`^https?://([^/]+)/([^/]+)/(.+?)(?:\.git)?$`)
+// URL pattern: https://github.com/owner/repo.git, ssh://git@host:7999/owner/repo.git
+// The last path segment is the repository; everything before it is the
+// owner (GitLab subgroups nest, e.g. group/sub/repo).
+var urlRemotePattern = regexp.MustCompile(`^(?:https?|ssh|git)://(?:[^@/]+@)?([^/:]+)(?::\d+)?/(.+)/([^/]+?)(?:\.git)?/?$`)
+
+// SCP-like pattern: git@github.com:owner/repo.git, or an SSH alias such as
+// work-gh:owner/repo with the user taken from ~/.ssh/config.
+var scpRemotePattern = regexp.MustCompile(`^(?:([^@/]+)@)?([^:/]+):/?(.+)/([^/]+?)(?:\.git)?/?$`)
+
+// gitHosts maps lowercase hostnames to provider types for hosts whose names
+// don't give the provider away (GitHub Enterprise, self-hosted GitLab).
+var gitHosts map[string]string
+
+// SetGitHosts registers host → provider mappings ("github", "gitlab" or
+// "bitbucket") consulted before the hostname heuristics. Call once at startup.
+func SetGitHosts(hosts map[string]string) {
+	m := make(map[string]string, len(hosts))
+	for host, provider := range hosts {
+		m[strings.ToLower(host)] = strings.ToLower(provider)
+	}
+	gitHosts = m
+}
 
-// parseRemoteURL extracts provider type, owner, and repo name from a git remote URL.
-// Handles both SSH (git@github.com:owner/repo.git) and HTTPS (https://github.com/owner/repo) formats.
-func parseRemoteURL(url string) (providerType, owner, repoName string) {
-	// Try SSH format first
-	if m := sshRemotePattern.FindStringSubmatch(url); m != nil {
-		return detectProvider(m[1]), m[2], m[3]
+// parseRemoteURL extracts the host, provider type, owner, and repo name from
+// a git remote URL. Handles HTTPS and ssh:// URLs as well as the SCP-like
+// form (git@github.com:owner/repo.git). SSH hosts are resolved through
+// ~/.ssh/config so aliases map to their real HostName.
+func parseRemoteURL(url string) (host, providerType, owner, repoName string) {
+	if m := urlRemotePattern.FindStringSubmatch(url); m != nil {
+		host = m[1]
+		if strings.HasPrefix(url, "ssh://") {
+			host = resolveSSHHost(host)
+		}
+		return host, detectProvider(host), m[2], m[3]
 	}
 
-	// Try HTTPS format
-	if m := httpsRemotePattern.FindStringSubmatch(url); m != nil {
-		return detectProvider(m[1]), m[2], m[3]
+	if m := scpRemotePattern.FindStringSubmatch(url); m != nil {
+		host = resolveSSHHost(m[2])
+		return host, detectProvider(host), m[3], m[4]
 	}
 
-	return "", "", ""
+	return "", "", "", ""
 }
 
-// detectProvider maps a hostname to a provider type string.
+// detectProvider maps a hostname to a provider type string. Configured
+// git_hosts mappings win over the substring heuristics.
 func detectProvider(host string) string {
 	host = strings.ToLower(host)
+	if provider, ok := gitHosts[host]; ok {
+		return provider
+	}
 	switch {
 	case strings.Contains(host, "github"):
 		return "github"
 	case strings.Contains(host, "gitlab"):
 		return "gitlab"
+	case strings.Contains(host, "bitbucket"):
+		return "bitbucket"
 	default:
 		return ""
 	}
 }
 
-// ResolveGitIDs queries the GitHub or GitLab API to resolve the Owner and
-// RepoName strings to their numeric IDs (OwnerID, RepoID). The Cloudflare
-// Builds API requires numeric IDs, not string names.
+// ProviderLabel returns a display name for the provider, marking
+// self-hosted instances (e.g. "GitHub Enterprise (git.acme.com)").
+func (g *GitInfo) ProviderLabel() string {
+	switch g.ProviderType {
+	case "github":
+		if g.IsSelfHosted() {
+			return fmt.Sprintf("GitHub Enterprise (%s)", g.Host)
+		}
+		return "GitHub"
+	case "gitlab":
+		if g.IsSelfHosted() {
+			return fmt.Sprintf("GitLab (%s)", g.Host)
+		}
+		return "GitLab"
+	case "bitbucket":
+		if g.IsSelfHosted() {
+			return fmt.Sprintf("Bitbucket (%s)", g.Host)
+		}
+		return "Bitbucket"
+	default:
+		return g.ProviderType
+	}
+}
+
+// IsSelfHosted reports whether the remote lives on a host other than the
+// provider's public SaaS domain.
+func (g *GitInfo) IsSelfHosted() bool {
+	host := strings.ToLower(g.Host)
+	if host == "" {
+		return false
+	}
+	switch g.ProviderType {
+	case "github":
+		return host != "github.com" && host != "www.github.com"
+	case "gitlab":
+		return host != "gitlab.com" && host != "www.gitlab.com"
+	case "bitbucket":
+		return host != "bitbucket.org" && host != "www.bitbucket.org"
+	default:
+		return false
+	}
+}
+
+// ResolveGitIDs queries the GitHub, GitLab or Bitbucket API to resolve the
+// Owner and RepoName strings to their provider IDs (OwnerID, RepoID). The
+// Cloudflare Builds API requires provider IDs, not string names.
+// Self-hosted GitHub Enterprise and GitLab instances are queried on Host.
 // This is a no-op if the provider is unsupported or the IDs are already set.
 func (g *GitInfo) ResolveGitIDs(ctx context.Context) error {
 	if g.OwnerID != "" && g.RepoID != "" {
@@ -202,18 +275,28 @@ func (g *GitInfo) ResolveGitIDs(ctx context.Context) error {
 		return g.resolveGitHubIDs(ctx)
 	case "gitlab":
 		return g.resolveGitLabIDs(ctx)
+	case "bitbucket":
+		if g.IsSelfHosted() {
+			return fmt.Errorf("self-hosted Bitbucket (%s) is not supported by Workers Builds", g.Host)
+		}
+		return g.resolveBitbucketIDs(ctx)
 	default:
 		return fmt.Errorf("unsupported provider: %s", g.ProviderType)
 	}
 }
 
 // resolveGitHubIDs fetches numeric user/org ID and repo ID from the GitHub API.
-// These are public endpoints that don't require authentication.
+// These are public endpoints that don't require authentication (on GitHub
+// Enterprise, only for public or internal repos visible anonymously).
 func (g *GitInfo) resolveGitHubIDs(ctx context.Context) error {
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	// Resolve repo (includes owner info)
-	path := fmt.Sprintf("https://api.github.com/repos/%s/%s", g.Owner, g.RepoName)
+	apiBase := "https://api.github.com"
+	if g.IsSelfHosted() {
+		apiBase = fmt.Sprintf("https://%s/api/v3", g.Host)
+	}
+	path := fmt.Sprintf("%s/repos/%s/%s", apiBase, g.Owner, g.RepoName)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return err
@@ -260,7 +343,11 @@ func (g *GitInfo) resolveGitLabIDs(ctx context.Context) error {
 	projectPath := fmt.Sprintf("%s/%s", g.Owner, g.RepoName)
 	encodedPath := strings.ReplaceAll(projectPath, "/", "%2F")
 
-	path := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s", encodedPath)
+	apiBase := "https://gitlab.com/api/v4"
+	if g.IsSelfHosted() {
+		apiBase = fmt.Sprintf("https://%s/api/v4", g.Host)
+	}
+	path := fmt.Sprintf("%s/projects/%s", apiBase, encodedPath)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return err
@@ -295,3 +382,45 @@ func (g *GitInfo) resolveGitLabIDs(ctx context.Context) error {
 	g.RepoID = fmt.Sprintf("%d", project.ID)
 	return nil
 }
+
+// resolveBitbucketIDs fetches the repository and workspace UUIDs from the
+// Bitbucket Cloud API. Bitbucket identifies both by "{uuid}" strings rather
+// than numeric IDs.
+func (g *GitInfo) resolveBitbucketIDs(ctx context.Context) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	path := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s", g.Owner, g.RepoName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Bitbucket API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading Bitbucket response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Bitbucket API returned %d for %s/%s", resp.StatusCode, g.Owner, g.RepoName)
+	}
+
+	var repo struct {
+		UUID      string `json:"uuid"`
+		Workspace struct {
+			UUID string `json:"uuid"`
+		} `json:"workspace"`
+	}
+	if err := json.Unmarshal(body, &repo); err != nil {
+		return fmt.Errorf("parsing Bitbucket response: %w", err)
+	}
+
+	g.OwnerID = repo.Workspace.UUID
+	g.RepoID = repo.UUID
+	return nil
+}
@@ -0,0 +1,90 @@
+package wrangler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	dir := t.TempDir()
+	sshDir := filepath.Join(dir, ".ssh")
+	writeTree(t, sshDir, map[string]string{
+		"config": `Include conf.d/*
+
+# personal
+Host work-gh
+    HostName github.com
+    User git
+
+Host *.internal !legacy.internal
+  HostName=%h.acme.com
+`,
+		"conf.d/gitlab": "Host ci\n  HostName git.acme.com\n",
+	})
+	prevPath, prevHosts := sshConfigPath, gitHosts
+	sshConfigPath = func() string { return filepath.Join(sshDir, "config") }
+	SetGitHosts(map[string]string{"Git.Acme.com": "GitLab", "code.acme.com": "github"})
+	t.Cleanup(func() { sshConfigPath, gitHosts = prevPath, prevHosts })
+
+	tests := []struct {
+		url                         string
+		host, provider, owner, repo string
+	}{
+		{"git@github.com:oarafat/orangeshell.git", "github.com", "github", "oarafat", "orangeshell"},
+		{"https://github.com/oarafat/orangeshell", "github.com", "github", "oarafat", "orangeshell"},
+		{"https://gitlab.com/group/sub/app.git", "gitlab.com", "gitlab", "group/sub", "app"},
+		{"git@bitbucket.org:team/site.git", "bitbucket.org", "bitbucket", "team", "site"},
+		{"https://jane@bitbucket.org/team/site.git", "bitbucket.org", "bitbucket", "team", "site"},
+		{"ssh://git@code.acme.com:2222/platform/api.git", "code.acme.com", "github", "platform", "api"},
+		{"git@work-gh:acme/worker.git", "github.com", "github", "acme", "worker"},
+		{"ci:infra/deploy", "git.acme.com", "gitlab", "infra", "deploy"},
+		{"git@tools.internal:ops/edge.git", "tools.internal.acme.com", "", "ops", "edge"},
+		{"git@legacy.internal:ops/edge.git", "legacy.internal", "", "ops", "edge"},
+		{"/srv/git/repo.git", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		host, provider, owner, repo := parseRemoteURL(tt.url)
+		if host != tt.host || provider != tt.provider || owner != tt.owner || repo != tt.repo {
+			t.Errorf("parseRemoteURL(%q) = %q, %q, %q, %q; want %q, %q, %q, %q",
+				tt.url, host, provider, owner, repo, tt.host, tt.provider, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestGitInfoSelfHosted(t *testing.T) {
+	tests := []struct {
+		info  GitInfo
+		self  bool
+		label string
+	}{
+		{GitInfo{ProviderType: "github", Host: "github.com"}, false, "GitHub"},
+		{GitInfo{ProviderType: "github", Host: "code.acme.com"}, true, "GitHub Enterprise (code.acme.com)"},
+		{GitInfo{ProviderType: "gitlab", Host: "git.acme.com"}, true, "GitLab (git.acme.com)"},
+		{GitInfo{ProviderType: "bitbucket", Host: "bitbucket.org"}, false, "Bitbucket"},
+		{GitInfo{ProviderType: "gitlab"}, false, "GitLab"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.IsSelfHosted(); got != tt.self {
+			t.Errorf("%+v IsSelfHosted() = %v, want %v", tt.info, got, tt.self)
+		}
+		if got := tt.info.ProviderLabel(); got != tt.label {
+			t.Errorf("%+v ProviderLabel() = %q, want %q", tt.info, got, tt.label)
+		}
+	}
+}
+
+func TestDetectGitReadsRemote(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".git/HEAD":   "ref: refs/heads/main\n",
+		".git/config": "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = git@bitbucket.org:team/site.git\n",
+	})
+	if err := os.MkdirAll(filepath.Join(dir, "apps", "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	info := DetectGit(filepath.Join(dir, "apps", "web"))
+	if !info.IsRepo || info.Branch != "main" || info.ProviderType != "bitbucket" || info.Host != "bitbucket.org" {
+		t.Errorf("DetectGit = %+v", info)
+	}
+}
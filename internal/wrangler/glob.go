@@ -0,0 +1,132 @@
+package wrangler
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// matchGlob reports whether a slash-separated relative path matches a glob
+// pattern. Segments are matched with path.Match; a "**" segment matches any
+// number of segments, including none.
+func matchGlob(pattern, rel string) bool {
+	pattern = cleanGlob(pattern)
+	if pattern == "" {
+		return false
+	}
+	var parts []string
+	if rel != "." && rel != "" {
+		parts = strings.Split(rel, "/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), parts)
+}
+
+func matchSegments(pat, parts []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			rest := pat[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], parts[0]); !ok {
+			return false
+		}
+		pat, parts = pat[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// cleanGlob normalizes a pattern from a config file: "./apps/*/" → "apps/*".
+func cleanGlob(pattern string) string {
+	pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+	pattern = strings.TrimPrefix(pattern, "./")
+	return strings.Trim(pattern, "/")
+}
+
+// ignoreRule is one line of a .gitignore file.
+type ignoreRule struct {
+	pattern  string // relative to the .gitignore's directory
+	negate   bool   // "!pattern" re-includes
+	anchored bool   // contains a slash: matched from the .gitignore's directory
+}
+
+// gitignore evaluates the .gitignore files of a tree for directories. Only
+// directories are ever checked, so dir-only rules ("build/") apply as-is.
+// Files are loaded lazily and cached.
+type gitignore struct {
+	root  string
+	rules map[string][]ignoreRule // dir → rules of its .gitignore
+}
+
+func newGitignore(root string) *gitignore {
+	return &gitignore{root: root, rules: make(map[string][]ignoreRule)}
+}
+
+func (g *gitignore) load(dir string) []ignoreRule {
+	if rules, ok := g.rules[dir]; ok {
+		return rules
+	}
+	var rules []ignoreRule
+	if f, err := os.Open(filepath.Join(dir, ".gitignore")); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), " \t\r")
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			var r ignoreRule
+			if strings.HasPrefix(line, "!") {
+				r.negate = true
+				line = line[1:]
+			}
+			line = strings.TrimPrefix(line, `\`)
+			line = strings.TrimSuffix(line, "/")
+			r.anchored = strings.Contains(line, "/")
+			r.pattern = strings.TrimPrefix(line, "/")
+			if r.pattern != "" {
+				rules = append(rules, r)
+			}
+		}
+		f.Close()
+	}
+	g.rules[dir] = rules
+	return rules
+}
+
+// ignored reports whether dir is ignored by the .gitignore files between the
+// root and dir. As in git, the last matching rule wins and deeper files take
+// precedence. Directories outside the root are never ignored.
+func (g *gitignore) ignored(dir string) bool {
+	rel, err := filepath.Rel(g.root, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	ignored := false
+	base := g.root
+	for i := range parts {
+		sub := strings.Join(parts[i:], "/")
+		for _, r := range g.load(base) {
+			var ok bool
+			if r.anchored {
+				ok = matchGlob(r.pattern, sub)
+			} else {
+				ok = matchGlob(r.pattern, parts[len(parts)-1])
+			}
+			if ok {
+				ignored = !r.negate
+			}
+		}
+		base = filepath.Join(base, parts[i])
+	}
+	return ignored
+}
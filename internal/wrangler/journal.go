@@ -0,0 +1,214 @@
+package wrangler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxJournalEntries caps the history kept per config file; the oldest edits
+// are dropped first.
+const maxJournalEntries = 50
+
+// ErrConfigChanged is returned by Undo and Redo when the config file no longer
+// matches the journal — it was edited outside orangeshell since.
+var ErrConfigChanged = errors.New("config file changed outside orangeshell since this edit")
+
+// JournalEntry is one recorded config edit with full before/after snapshots.
+type JournalEntry struct {
+	Time   time.Time `json:"time"`
+	Op     string    `json:"op"` // e.g. "Add binding MY_KV (kv)"
+	Before string    `json:"before"`
+	After  string    `json:"after"`
+}
+
+// ConfigHistory is the edit history of one config file. Entries before
+// Cursor are applied; entries from Cursor on have been undone and can be
+// redone until the next new edit discards them.
+type ConfigHistory struct {
+	ConfigPath string         `json:"config_path"`
+	Cursor     int            `json:"cursor"`
+	Entries    []JournalEntry `json:"entries"`
+}
+
+// CanUndo reports whether there is an applied edit to undo.
+func (h *ConfigHistory) CanUndo() bool { return h.Cursor > 0 }
+
+// CanRedo reports whether there is an undone edit to redo.
+func (h *ConfigHistory) CanRedo() bool { return h.Cursor < len(h.Entries) }
+
+// Journal records before/after snapshots of the config edits orangeshell
+// makes, one history file per config under dir (~/.orangeshell/history/).
+// Safe for concurrent use.
+type Journal struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJournal creates a journal stored in dir.
+func NewJournal(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// DefaultJournalDir returns ~/.orangeshell/history.
+func DefaultJournalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".orangeshell", "history"), nil
+}
+
+// Record runs edit against configPath and journals the change. Edits that
+// fail or leave the file unchanged are not recorded. A nil journal just runs
+// the edit. Failing to write the journal doesn't fail the edit.
+func (j *Journal) Record(configPath, op string, edit func() error) error {
+	if j == nil {
+		return edit()
+	}
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return edit()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	before, readErr := os.ReadFile(absPath)
+	if err := edit(); err != nil {
+		return err
+	}
+	after, err := os.ReadFile(absPath)
+	if readErr != nil || err != nil || bytes.Equal(before, after) {
+		return nil
+	}
+
+	h := j.load(absPath)
+	h.Entries = append(h.Entries[:h.Cursor], JournalEntry{
+		Time:   time.Now(),
+		Op:     op,
+		Before: string(before),
+		After:  string(after),
+	})
+	if over := len(h.Entries) - maxJournalEntries; over > 0 {
+		h.Entries = h.Entries[over:]
+	}
+	h.Cursor = len(h.Entries)
+	_ = j.save(h)
+	return nil
+}
+
+// History returns the edit history of a config file (empty if none).
+func (j *Journal) History(configPath string) *ConfigHistory {
+	absPath, _ := filepath.Abs(configPath)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.load(absPath)
+}
+
+// Undo restores the config to its state before the last applied edit and
+// returns that edit. Refuses with ErrConfigChanged if the file isn't in the
+// state the edit left it in.
+func (j *Journal) Undo(configPath string) (JournalEntry, error) {
+	return j.step(configPath, true)
+}
+
+// Redo re-applies the most recently undone edit and returns it. Refuses with
+// ErrConfigChanged if the file isn't in the state the undo left it in.
+func (j *Journal) Redo(configPath string) (JournalEntry, error) {
+	return j.step(configPath, false)
+}
+
+func (j *Journal) step(configPath string, undo bool) (JournalEntry, error) {
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	h := j.load(absPath)
+	var e JournalEntry
+	var expect, write string
+	if undo {
+		if !h.CanUndo() {
+			return JournalEntry{}, fmt.Errorf("nothing to undo")
+		}
+		e = h.Entries[h.Cursor-1]
+		expect, write = e.After, e.Before
+	} else {
+		if !h.CanRedo() {
+			return JournalEntry{}, fmt.Errorf("nothing to redo")
+		}
+		e = h.Entries[h.Cursor]
+		expect, write = e.Before, e.After
+	}
+
+	current, err := os.ReadFile(absPath)
+	if err != nil {
+		return JournalEntry{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if string(current) != expect {
+		return JournalEntry{}, ErrConfigChanged
+	}
+	if err := os.WriteFile(absPath, []byte(write), 0644); err != nil {
+		return JournalEntry{}, err
+	}
+
+	if undo {
+		h.Cursor--
+	} else {
+		h.Cursor++
+	}
+	if err := j.save(h); err != nil {
+		return e, fmt.Errorf("config restored, but the journal could not be saved: %w", err)
+	}
+	return e, nil
+}
+
+// historyFile returns the journal file of a config: a hash of its path, so
+// configs with the same name in different projects don't collide.
+func (j *Journal) historyFile(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	return filepath.Join(j.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func (j *Journal) load(absPath string) *ConfigHistory {
+	h := &ConfigHistory{ConfigPath: absPath}
+	data, err := os.ReadFile(j.historyFile(absPath))
+	if err != nil {
+		return h
+	}
+	if json.Unmarshal(data, h) != nil || h.ConfigPath != absPath {
+		return &ConfigHistory{ConfigPath: absPath}
+	}
+	if h.Cursor < 0 || h.Cursor > len(h.Entries) {
+		h.Cursor = len(h.Entries)
+	}
+	return h
+}
+
+func (j *Journal) save(h *ConfigHistory) error {
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write-then-rename so a crash never leaves a truncated journal
+	path := j.historyFile(h.ConfigPath)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,61 @@
+package wrangler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournalUndoRedo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wrangler.toml")
+	original := "name = \"api\"\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	j := NewJournal(filepath.Join(dir, "history"))
+
+	if err := j.Record(path, "Set var A", func() error { return SetVar(path, "default", "A", "1") }); err != nil {
+		t.Fatal(err)
+	}
+	edited, _ := os.ReadFile(path)
+
+	// Failed and no-op edits are not recorded
+	_ = j.Record(path, "Remove var B", func() error { return RemoveVar(path, "default", "B") })
+	_ = j.Record(path, "Set var A again", func() error { return SetVar(path, "default", "A", "1") })
+	if h := j.History(path); len(h.Entries) != 1 || h.Cursor != 1 {
+		t.Fatalf("expected 1 applied entry, got %d (cursor %d)", len(h.Entries), h.Cursor)
+	}
+
+	if _, err := j.Undo(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != original {
+		t.Fatalf("undo left %q, want %q", got, original)
+	}
+	if _, err := j.Redo(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(edited) {
+		t.Fatalf("redo left %q, want %q", got, edited)
+	}
+
+	// An external edit blocks undo and leaves the file alone
+	external := string(edited) + "# hand edit\n"
+	if err := os.WriteFile(path, []byte(external), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Undo(path); !errors.Is(err, ErrConfigChanged) {
+		t.Fatalf("expected ErrConfigChanged, got %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != external {
+		t.Fatalf("refused undo modified the file: %q", got)
+	}
+
+	diff := strings.Join(UnifiedDiff(original, string(edited), 3), "\n")
+	if !strings.Contains(diff, "+[vars]") || !strings.Contains(diff, `+A = "1"`) {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+}
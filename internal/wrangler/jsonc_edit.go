@@ -0,0 +1,356 @@
+package wrangler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/jsonc"
+)
+
+// jsoncDoc is a comment- and formatting-preserving editor for JSON/JSONC
+// config files. It parses the source into a tree of byte spans; each edit
+// splices only the affected bytes and re-parses, so comments, key order,
+// indentation and trailing commas elsewhere in the file are untouched.
+//
+// Paths are lists of object keys and array indexes. The index "-1" appends
+// to an array.
+type jsoncDoc struct {
+	src  []byte
+	root *jsonNode
+	unit string // indentation step
+}
+
+// jsonNode is a value in the document with its byte span.
+type jsonNode struct {
+	kind    byte // '{', '[', or 0 for scalars
+	start   int
+	end     int
+	members []jsonMember // objects
+	elems   []*jsonNode  // arrays
+}
+
+// jsonMember is an object member. keyStart is the offset of the key's quote.
+type jsonMember struct {
+	key      string
+	keyStart int
+	value    *jsonNode
+}
+
+// parseJSONCDoc parses JSON or JSONC (comments and trailing commas allowed).
+func parseJSONCDoc(src []byte) (*jsoncDoc, error) {
+	d := &jsoncDoc{src: src, unit: detectIndentUnit(src)}
+	if err := d.reparse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the current document text.
+func (d *jsoncDoc) Bytes() []byte {
+	return d.src
+}
+
+// reparse rebuilds the span tree. jsonc.ToJSON blanks out comments and
+// trailing commas without moving any byte, so offsets in the clean text are
+// offsets in the source.
+func (d *jsoncDoc) reparse() error {
+	clean := jsonc.ToJSON(d.src)
+	if !json.Valid(clean) {
+		return fmt.Errorf("invalid JSON config")
+	}
+	p := &jsonScanner{src: clean}
+	p.skipSpace()
+	root, err := p.value()
+	if err != nil {
+		return err
+	}
+	if root.kind != '{' {
+		return fmt.Errorf("invalid JSON config: top level is not an object")
+	}
+	d.root = root
+	return nil
+}
+
+// Get returns the value at path as a gjson result (comments stripped).
+func (d *jsoncDoc) Get(path []string) gjson.Result {
+	n, _, _ := d.lookup(path)
+	if n == nil {
+		return gjson.Result{}
+	}
+	return gjson.ParseBytes(jsonc.ToJSON(d.src[n.start:n.end]))
+}
+
+// Has reports whether a value exists at path.
+func (d *jsoncDoc) Has(path []string) bool {
+	n, _, _ := d.lookup(path)
+	return n != nil
+}
+
+// lookup returns the node at path, its parent container and its index in
+// the parent (member or element index). All nil/-1 if not found.
+func (d *jsoncDoc) lookup(path []string) (node, parent *jsonNode, idx int) {
+	node, idx = d.root, -1
+	for _, seg := range path {
+		parent = node
+		node, idx = node.child(seg)
+		if node == nil {
+			return nil, nil, -1
+		}
+	}
+	return node, parent, idx
+}
+
+// child returns an object member or array element by key/index.
+func (n *jsonNode) child(seg string) (*jsonNode, int) {
+	switch n.kind {
+	case '{':
+		// Last wins, as in encoding/json
+		for i := len(n.members) - 1; i >= 0; i-- {
+			if n.members[i].key == seg {
+				return n.members[i].value, i
+			}
+		}
+	case '[':
+		i, err := strconv.Atoi(seg)
+		if err == nil && i >= 0 && i < len(n.elems) {
+			return n.elems[i], i
+		}
+	}
+	return nil, -1
+}
+
+// items returns the spans of a container's members or elements.
+func (n *jsonNode) items() []span {
+	var items []span
+	for _, m := range n.members {
+		items = append(items, span{m.keyStart, m.value.end})
+	}
+	for _, e := range n.elems {
+		items = append(items, span{e.start, e.end})
+	}
+	return items
+}
+
+// Set sets the value at path to raw JSON, creating missing parent objects
+// (and arrays, for "-1" segments). Existing values are replaced in place.
+func (d *jsoncDoc) Set(path []string, raw []byte) error {
+	if len(path) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	if !json.Valid(raw) {
+		return fmt.Errorf("invalid JSON value for %s", joinPath(path))
+	}
+
+	// Walk down to the deepest existing node
+	node := d.root
+	itemStart := node.start
+	i := 0
+	for ; i < len(path); i++ {
+		c, idx := node.child(path[i])
+		if c == nil {
+			break
+		}
+		if node.kind == '{' {
+			itemStart = node.members[idx].keyStart
+		} else {
+			itemStart = c.start
+		}
+		node = c
+	}
+
+	if i == len(path) {
+		text := compactJSON(raw)
+		if ownLine(d.src, itemStart) {
+			text = d.pretty(raw, lineIndent(d.src, itemStart))
+		}
+		d.src = splice(d.src, node.start, node.end, text)
+		return d.reparse()
+	}
+
+	// Wrap the value in the containers the remaining path needs
+	value := raw
+	for j := len(path) - 1; j > i; j-- {
+		if path[j] == "-1" {
+			value = append(append([]byte("["), value...), ']')
+		} else {
+			key, _ := json.Marshal(path[j])
+			value = []byte(fmt.Sprintf("{%s:%s}", key, value))
+		}
+	}
+
+	seg := path[i]
+	var compact string
+	var pretty func(string) string
+	switch node.kind {
+	case '{':
+		if seg == "-1" {
+			return fmt.Errorf("cannot append to %s: not an array", joinPath(path[:i]))
+		}
+		key, _ := json.Marshal(seg)
+		compact = string(key) + ": " + compactJSON(value)
+		pretty = func(indent string) string { return string(key) + ": " + d.pretty(value, indent) }
+	case '[':
+		if seg != "-1" {
+			return fmt.Errorf("index %s out of range in %s", seg, joinPath(path[:i]))
+		}
+		compact = compactJSON(value)
+		pretty = func(indent string) string { return d.pretty(value, indent) }
+	default:
+		return fmt.Errorf("cannot set %s: %s is not an object", joinPath(path), joinPath(path[:i]))
+	}
+
+	d.src = insertSeqItem(d.src, node.start, node.end-1, node.items(), compact, pretty, seqStyle{
+		syn:         jsoncComments,
+		unit:        d.unit,
+		expandEmpty: bytes.Contains(d.src, []byte("\n")),
+	})
+	return d.reparse()
+}
+
+// Delete removes the value at path. Returns false if nothing was there.
+func (d *jsoncDoc) Delete(path []string) (bool, error) {
+	node, parent, idx := d.lookup(path)
+	if node == nil || parent == nil {
+		return false, nil
+	}
+	d.src = removeSeqItem(d.src, parent.start, parent.end-1, parent.items(), idx, jsoncComments)
+	return true, d.reparse()
+}
+
+// pretty indents raw JSON for a value starting on a line with the given indent.
+func (d *jsoncDoc) pretty(raw []byte, indent string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, indent, d.unit); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// compactJSON renders raw JSON on a single line.
+func compactJSON(raw []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// jsonScanner builds the span tree from comment-free JSON.
+type jsonScanner struct {
+	src []byte
+	pos int
+}
+
+func (p *jsonScanner) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonScanner) value() (*jsonNode, error) {
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("invalid JSON config: unexpected end of input")
+	}
+	n := &jsonNode{start: p.pos}
+	switch p.src[p.pos] {
+	case '{':
+		n.kind = '{'
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.pos >= len(p.src) {
+				return nil, fmt.Errorf("invalid JSON config: unterminated object")
+			}
+			if p.src[p.pos] == '}' {
+				p.pos++
+				break
+			}
+			keyStart := p.pos
+			key, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			p.pos++ // ':'
+			p.skipSpace()
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.members = append(n.members, jsonMember{key: key, keyStart: keyStart, value: v})
+			p.skipSpace()
+			if p.pos < len(p.src) && p.src[p.pos] == ',' {
+				p.pos++
+			}
+		}
+	case '[':
+		n.kind = '['
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.pos >= len(p.src) {
+				return nil, fmt.Errorf("invalid JSON config: unterminated array")
+			}
+			if p.src[p.pos] == ']' {
+				p.pos++
+				break
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.elems = append(n.elems, v)
+			p.skipSpace()
+			if p.pos < len(p.src) && p.src[p.pos] == ',' {
+				p.pos++
+			}
+		}
+	case '"':
+		if _, err := p.str(); err != nil {
+			return nil, err
+		}
+	default:
+		for p.pos < len(p.src) && !bytes.ContainsRune([]byte(" \t\r\n,]}"), rune(p.src[p.pos])) {
+			p.pos++
+		}
+	}
+	n.end = p.pos
+	return n, nil
+}
+
+// str scans a string literal and returns its decoded value.
+func (p *jsonScanner) str() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			var s string
+			err := json.Unmarshal(p.src[start:p.pos], &s)
+			return s, err
+		}
+		p.pos++
+	}
+	return "", fmt.Errorf("invalid JSON config: unterminated string")
+}
+
+// joinPath renders a path for error messages.
+func joinPath(path []string) string {
+	if len(path) == 0 {
+		return "(root)"
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,360 @@
+package wrangler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity ranks a config diagnostic. Errors block deploys (unless the user
+// overrides the gate); warnings are shown but don't block.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a single problem found in a wrangler config.
+type Diagnostic struct {
+	Severity Severity
+	Rule     string // short rule ID (e.g. "duplicate-binding")
+	// Env is the environment the problem belongs to: "default" for top-level
+	// keys that are not inherited (bindings, vars), a named env, or "" for
+	// top-level keys every environment inherits (compatibility date, crons).
+	Env     string
+	Message string
+}
+
+// AppliesTo reports whether the diagnostic affects a deploy of envName.
+func (d Diagnostic) AppliesTo(envName string) bool {
+	if envName == "" {
+		envName = "default"
+	}
+	return d.Env == "" || d.Env == envName
+}
+
+// AccountResources lists the resources that exist in the account, keyed by
+// dashboard service name (see Binding.NavService). Each set holds resource
+// IDs and names. A service without an entry hasn't been loaded, and bindings
+// to it are not checked.
+type AccountResources map[string]map[string]bool
+
+// LintOptions configures Lint.
+type LintOptions struct {
+	Now     time.Time        // reference time for compatibility date checks
+	Account AccountResources // nil skips the account checks
+}
+
+// outdatedCompatAge is how old a compatibility date can get before it's flagged.
+const outdatedCompatAge = 365 * 24 * time.Hour
+
+// Lint validates a parsed config and returns its diagnostics, errors first.
+func Lint(cfg *WranglerConfig, opts LintOptions) []Diagnostic {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	var diags []Diagnostic
+	add := func(sev Severity, rule, env, format string, args ...any) {
+		diags = append(diags, Diagnostic{Severity: sev, Rule: rule, Env: env, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Top-level compatibility date is inherited by envs that don't override it
+	lintCompatDate(cfg.CompatDate, "", opts.Now, add)
+	for _, cron := range cfg.Crons {
+		if err := ValidateCron(cron); err != nil {
+			add(SeverityError, "invalid-cron", "", "cron %q: %v", cron, err)
+		}
+	}
+
+	envs := sortedEnvNames(cfg)
+	for _, envName := range append([]string{"default"}, envs...) {
+		bindings := cfg.EnvBindings(envName)
+		lintDuplicateNames(bindings, cfg.EnvVars(envName), envName, add)
+		for _, b := range bindings {
+			lintBinding(b, envName, opts.Account, add)
+		}
+	}
+
+	for _, envName := range envs {
+		env := cfg.Environments[envName]
+		if env.CompatDate != "" {
+			lintCompatDate(env.CompatDate, envName, opts.Now, add)
+		}
+		lintMissingInherited(cfg, env, envName, add)
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].Severity > diags[j].Severity })
+	return diags
+}
+
+// HasErrors reports whether any diagnostic is an error.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagnosticsFor returns the diagnostics affecting a deploy of envName.
+func DiagnosticsFor(diags []Diagnostic, envName string) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range diags {
+		if d.AppliesTo(envName) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+type addDiagFunc func(sev Severity, rule, env, format string, args ...any)
+
+func sortedEnvNames(cfg *WranglerConfig) []string {
+	names := make([]string, 0, len(cfg.Environments))
+	for name := range cfg.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lintCompatDate(date, env string, now time.Time, add addDiagFunc) {
+	where := "compatibility_date"
+	if env != "" {
+		where = "env." + env + ".compatibility_date"
+	}
+	if date == "" {
+		if env == "" {
+			add(SeverityWarning, "compat-date", env, "%s is not set; wrangler will use its own default", where)
+		}
+		return
+	}
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		add(SeverityError, "compat-date", env, "%s %q is not a YYYY-MM-DD date", where, date)
+		return
+	}
+	// Compare calendar dates, in UTC like wrangler. A day of slack keeps
+	// today's local date valid east of UTC, where it can be tomorrow in UTC.
+	today := now.UTC().Truncate(24 * time.Hour)
+	switch {
+	case d.After(today.AddDate(0, 0, 1)):
+		add(SeverityError, "compat-date", env, "%s %s is in the future", where, date)
+	case now.Sub(d) > outdatedCompatAge:
+		add(SeverityWarning, "compat-date", env, "%s %s is more than a year old", where, date)
+	}
+}
+
+// lintDuplicateNames flags binding names used more than once in an env.
+// Vars share the same namespace on the env object.
+func lintDuplicateNames(bindings []Binding, vars map[string]string, env string, add addDiagFunc) {
+	seen := make(map[string]string)
+	var dups []string
+	for _, b := range bindings {
+		// Consumers and containers don't define a JS binding
+		if b.Type == "queue_consumer" || b.Type == "container" || b.Name == "" {
+			continue
+		}
+		if prev, ok := seen[b.Name]; ok {
+			add(SeverityError, "duplicate-binding", env, "binding %s is defined twice (%s and %s)", b.Name, prev, b.TypeLabel())
+			dups = append(dups, b.Name)
+			continue
+		}
+		seen[b.Name] = b.TypeLabel()
+	}
+	for _, name := range sortedKeys(vars) {
+		if prev, ok := seen[name]; ok {
+			add(SeverityError, "duplicate-binding", env, "var %s has the same name as a %s binding", name, prev)
+		}
+	}
+}
+
+// placeholderRe matches IDs left over from templates and docs.
+var placeholderRe = regexp.MustCompile(`(?i)^(<.*>|\{\{.*\}\}|x{3,}|.*\byour[-_ ].*|placeholder.*|todo|tbd|changeme|replace[-_]?me|0+|0{8}-0{4}-0{4}-0{4}-0{12})$`)
+
+func lintBinding(b Binding, env string, account AccountResources, add addDiagFunc) {
+	id := b.ResourceID
+	if id != "" && placeholderRe.MatchString(id) {
+		add(SeverityError, "placeholder-id", env, "%s binding %s has placeholder ID %q", b.TypeLabel(), b.Name, id)
+		return
+	}
+
+	svcName := b.NavService()
+	known, loaded := account[svcName]
+	if !loaded || b.ResourceID == "" {
+		return
+	}
+	if known[b.ResourceID] || (b.DisplayName != "" && known[b.DisplayName]) {
+		return
+	}
+	if b.Type == "service" {
+		add(SeverityWarning, "missing-script", env, "service binding %s points to script %q, which doesn't exist in this account", b.Name, b.ResourceID)
+		return
+	}
+	add(SeverityWarning, "missing-resource", env, "%s binding %s points to %q, which doesn't exist in this account", b.TypeLabel(), b.Name, b.ResourceID)
+}
+
+// lintMissingInherited flags top-level bindings and vars that a named env
+// doesn't redefine. Wrangler doesn't inherit these, so the env deploys
+// without them.
+func lintMissingInherited(cfg *WranglerConfig, env *Environment, envName string, add addDiagFunc) {
+	have := make(map[string]bool)
+	for _, b := range env.Bindings {
+		have[b.Type+":"+b.Name] = true
+	}
+	var missing []string
+	for _, b := range cfg.Bindings {
+		if b.Type == "queue_consumer" {
+			continue
+		}
+		if !have[b.Type+":"+b.Name] {
+			missing = append(missing, b.Name)
+		}
+	}
+	if len(missing) > 0 {
+		add(SeverityWarning, "missing-inherited", envName, "bindings %s are defined at top level but not in env.%s (bindings are not inherited)",
+			strings.Join(missing, ", "), envName)
+	}
+
+	missing = nil
+	for _, name := range sortedKeys(cfg.Vars) {
+		if _, ok := env.Vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		add(SeverityWarning, "missing-inherited", envName, "vars %s are defined at top level but not in env.%s (vars are not inherited)",
+			strings.Join(missing, ", "), envName)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cronField describes one field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // symbolic values, starting at min (months, weekdays)
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// ValidateCron checks a Cron Trigger expression: five fields (minute, hour,
+// day of month, month, day of week) of values, ranges, lists and steps, plus
+// the L, W and # forms Cloudflare supports.
+func ValidateCron(expr string) error {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return fmt.Errorf("expected 5 fields (min hour day month weekday), got %d", len(parts))
+	}
+	for i, part := range parts {
+		f := cronFields[i]
+		for _, item := range strings.Split(part, ",") {
+			if err := f.validate(strings.ToUpper(item)); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (f cronField) validate(item string) error {
+	if item == "" {
+		return fmt.Errorf("empty list item")
+	}
+
+	// Special forms: L (last day), nW (nearest weekday), nL / n#k (weekday occurrences)
+	switch f.name {
+	case "day of month":
+		if item == "L" || item == "LW" {
+			return nil
+		}
+		if n, ok := strings.CutSuffix(item, "W"); ok {
+			return f.value(n)
+		}
+	case "day of week":
+		if n, ok := strings.CutSuffix(item, "L"); ok && n != "" {
+			return f.value(n)
+		}
+		if n, k, ok := strings.Cut(item, "#"); ok {
+			if occ, err := strconv.Atoi(k); err != nil || occ < 1 || occ > 5 {
+				return fmt.Errorf("invalid occurrence %q", k)
+			}
+			return f.value(n)
+		}
+	}
+
+	rng, step, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 || n > f.max {
+			return fmt.Errorf("invalid step %q", step)
+		}
+	}
+	if rng == "*" || (rng == "?" && (f.name == "day of month" || f.name == "day of week")) {
+		return nil
+	}
+	lo, hi, isRange := strings.Cut(rng, "-")
+	if err := f.value(lo); err != nil {
+		return err
+	}
+	if isRange {
+		if err := f.value(hi); err != nil {
+			return err
+		}
+		a, _ := f.parse(lo)
+		b, _ := f.parse(hi)
+		if a > b {
+			return fmt.Errorf("range %s is backwards", rng)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) error {
+	if _, err := f.parse(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f cronField) parse(s string) (int, error) {
+	for i, name := range f.names {
+		if s == name {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%d out of range %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,106 @@
+package wrangler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateCron(t *testing.T) {
+	valid := []string{"* * * * *", "*/5 * * * *", "0 0 * * 0", "0 9-17 * * MON-FRI", "0 0 L * *", "0 0 15W * *", "0 0 * * 6#3", "0 0 * * 5L", "30 2 1,15 JAN,jul ?"}
+	for _, c := range valid {
+		if err := ValidateCron(c); err != nil {
+			t.Fatalf("ValidateCron(%q) = %v, want nil", c, err)
+		}
+	}
+	invalid := []string{"* * * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "0 0 * 13 *", "*/0 * * * *", "0 17-9 * * *", "0 0 * * FUNDAY", "0 0 * * 1#6", "0 0 ? * *x"}
+	for _, c := range invalid {
+		if err := ValidateCron(c); err == nil {
+			t.Fatalf("ValidateCron(%q) = nil, want error", c)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	cfg := &WranglerConfig{
+		Name:       "api",
+		CompatDate: "2023-01-01",
+		Crons:      []string{"0 * * * *", "61 * * * *"},
+		Bindings: []Binding{
+			{Name: "DB", Type: "d1", ResourceID: "<your-database-id>"},
+			{Name: "CACHE", Type: "kv_namespace", ResourceID: "abc"},
+			{Name: "CACHE", Type: "r2_bucket", ResourceID: "assets"},
+			{Name: "AUTH", Type: "service", ResourceID: "auth-worker"},
+		},
+		Vars: map[string]string{"LOG_LEVEL": "info"},
+		Environments: map[string]*Environment{
+			"staging": {
+				CompatDate: "2099-01-01",
+				Bindings:   []Binding{{Name: "CACHE", Type: "kv_namespace", ResourceID: "def"}},
+			},
+		},
+	}
+	diags := Lint(cfg, LintOptions{
+		Now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Account: AccountResources{
+			"KV":      {"abc": true},
+			"Workers": {"api": true},
+		},
+	})
+
+	want := map[string]string{
+		"invalid-cron":      "",
+		"compat-date":       "staging", // future date; the outdated top-level one is ""
+		"placeholder-id":    "default",
+		"duplicate-binding": "default",
+		"missing-resource":  "staging",
+		"missing-script":    "default",
+		"missing-inherited": "staging",
+	}
+	for rule, env := range want {
+		found := false
+		for _, d := range diags {
+			if d.Rule == rule && d.Env == env {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("missing %s diagnostic for env %q in %+v", rule, env, diags)
+		}
+	}
+
+	if !HasErrors(DiagnosticsFor(diags, "default")) {
+		t.Fatalf("expected errors for the default env")
+	}
+	for _, d := range DiagnosticsFor(diags, "staging") {
+		if d.Env == "default" {
+			t.Fatalf("top-level binding diagnostic leaked into staging: %+v", d)
+		}
+		if d.Rule == "missing-inherited" && strings.HasPrefix(d.Message, "bindings") && !strings.Contains(d.Message, "DB, CACHE, AUTH") {
+			t.Fatalf("unexpected missing bindings: %s", d.Message)
+		}
+	}
+}
+
+func TestLintCompatDateTimezones(t *testing.T) {
+	// 08:00 on Oct 18 in Tokyo is still Oct 17 in UTC
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, tokyo)
+	tests := []struct {
+		date   string
+		future bool
+	}{
+		{"2026-10-17", false},
+		{"2026-10-18", false}, // today, locally
+		{"2026-10-19", true},
+	}
+	for _, tt := range tests {
+		var got []string
+		lintCompatDate(tt.date, "", now, func(_ Severity, rule, _, _ string, _ ...any) {
+			got = append(got, rule)
+		})
+		if future := len(got) > 0; future != tt.future {
+			t.Errorf("lintCompatDate(%s): got %v, want future=%v", tt.date, got, tt.future)
+		}
+	}
+}
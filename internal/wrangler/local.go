@@ -1,6 +1,7 @@
 package wrangler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -28,6 +29,18 @@ type LocalD1QueryResult struct {
 	Rows      [][]interface{}
 	Meta      string // "rows_read: N, rows_written: N"
 	ChangedDB bool
+
+	// Sets holds one result per statement; Columns, Rows and Meta above
+	// are those of the first.
+	Sets []LocalD1ResultSet
+}
+
+// LocalD1ResultSet is the result of one statement of a local query.
+type LocalD1ResultSet struct {
+	Columns []string
+	Rows    [][]interface{}
+	Meta    string
+	Changes float64
 }
 
 // ExecuteLocalD1Query runs a SQL query against a local D1 database via wrangler CLI.
@@ -61,12 +74,13 @@ func ExecuteLocalD1Query(ctx context.Context, lr LocalResource, sql string) (*Lo
 }
 
 // parseLocalD1Output parses the JSON output from `wrangler d1 execute --json`.
-// Format: [{"results": [...], "success": true, "meta": {...}}]
+// Format: [{"results": [...], "success": true, "meta": {...}}], one entry
+// per statement.
 func parseLocalD1Output(data []byte) (*LocalD1QueryResult, error) {
 	// wrangler d1 execute --json outputs an array of result objects
 	var results []struct {
-		Results []map[string]interface{} `json:"results"`
-		Success bool                     `json:"success"`
+		Results []json.RawMessage `json:"results"`
+		Success bool              `json:"success"`
 		Meta    struct {
 			ChangedDB   bool    `json:"changed_db"`
 			Changes     float64 `json:"changes"`
@@ -86,47 +100,73 @@ func parseLocalD1Output(data []byte) (*LocalD1QueryResult, error) {
 		return &LocalD1QueryResult{}, nil
 	}
 
-	r := results[0]
-
-	// Build meta string
-	var metaParts []string
-	if r.Meta.RowsRead > 0 {
-		metaParts = append(metaParts, fmt.Sprintf("Read: %.0f", r.Meta.RowsRead))
-	}
-	if r.Meta.RowsWritten > 0 {
-		metaParts = append(metaParts, fmt.Sprintf("Written: %.0f", r.Meta.RowsWritten))
-	}
-	if r.Meta.Duration > 0 {
-		metaParts = append(metaParts, fmt.Sprintf("%.1fms", r.Meta.Duration))
-	}
-	if r.Meta.Changes > 0 {
-		metaParts = append(metaParts, fmt.Sprintf("Changes: %.0f", r.Meta.Changes))
-	}
-
-	result := &LocalD1QueryResult{
-		Meta:      strings.Join(metaParts, "  "),
-		ChangedDB: r.Meta.ChangedDB,
-	}
-
-	// Extract columns from the first result row
-	if len(r.Results) > 0 {
-		first := r.Results[0]
-		for k := range first {
-			result.Columns = append(result.Columns, k)
+	result := &LocalD1QueryResult{}
+	for _, r := range results {
+		// Build meta string
+		var metaParts []string
+		if r.Meta.RowsRead > 0 {
+			metaParts = append(metaParts, fmt.Sprintf("Read: %.0f", r.Meta.RowsRead))
+		}
+		if r.Meta.RowsWritten > 0 {
+			metaParts = append(metaParts, fmt.Sprintf("Written: %.0f", r.Meta.RowsWritten))
+		}
+		if r.Meta.Duration > 0 {
+			metaParts = append(metaParts, fmt.Sprintf("%.1fms", r.Meta.Duration))
 		}
-		// Convert to rows
-		for _, row := range r.Results {
-			var vals []interface{}
-			for _, col := range result.Columns {
-				vals = append(vals, row[col])
+		if r.Meta.Changes > 0 {
+			metaParts = append(metaParts, fmt.Sprintf("Changes: %.0f", r.Meta.Changes))
+		}
+		set := LocalD1ResultSet{Meta: strings.Join(metaParts, "  "), Changes: r.Meta.Changes}
+		result.ChangedDB = result.ChangedDB || r.Meta.ChangedDB
+
+		// Columns come from the first row's keys, in the order SQLite returned them
+		for i, raw := range r.Results {
+			keys, values, err := decodeOrderedRow(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse d1 output: %w", err)
+			}
+			if i == 0 {
+				set.Columns = keys
 			}
-			result.Rows = append(result.Rows, vals)
+			row := make([]interface{}, len(set.Columns))
+			for j, col := range set.Columns {
+				row[j] = values[col]
+			}
+			set.Rows = append(set.Rows, row)
 		}
+		result.Sets = append(result.Sets, set)
 	}
 
+	first := result.Sets[0]
+	result.Columns, result.Rows, result.Meta = first.Columns, first.Rows, first.Meta
 	return result, nil
 }
 
+// decodeOrderedRow decodes a JSON object, returning its keys in document order.
+func decodeOrderedRow(raw json.RawMessage) ([]string, map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // opening brace
+		return nil, nil, err
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, tok.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
+}
+
 // LocalKVKeyEntry represents a single key from a local KV namespace.
 type LocalKVKeyEntry struct {
 	Name       string
@@ -299,8 +339,10 @@ type LocalKVKeyValueEntry struct {
 // LocalSchemaTable mirrors service.SchemaTable for local D1 schema introspection.
 type LocalSchemaTable struct {
 	Name    string
+	SQL     string
 	Columns []LocalSchemaColumn
 	FKs     []LocalSchemaFK
+	Indexes []LocalSchemaIndex
 }
 
 // LocalSchemaColumn mirrors service.SchemaColumn.
@@ -309,6 +351,13 @@ type LocalSchemaColumn struct {
 	Type    string
 	NotNull bool
 	PK      bool
+	Default string
+}
+
+// LocalSchemaIndex mirrors service.SchemaIndex.
+type LocalSchemaIndex struct {
+	Name string
+	SQL  string
 }
 
 // LocalSchemaFK mirrors service.SchemaFK.
@@ -323,7 +372,7 @@ type LocalSchemaFK struct {
 func QueryLocalD1Schema(ctx context.Context, lr LocalResource) ([]LocalSchemaTable, error) {
 	// Step 1: Get all user table names
 	tableResult, err := ExecuteLocalD1Query(ctx, lr,
-		"SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE '_cf_%' ORDER BY name")
+		"SELECT name, sql FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE '_cf_%' ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -333,14 +382,12 @@ func QueryLocalD1Schema(ctx context.Context, lr LocalResource) ([]LocalSchemaTab
 	}
 
 	// Extract table names from the result
-	nameColIdx := -1
+	masterIdx := make(map[string]int)
 	for i, col := range tableResult.Columns {
-		if col == "name" {
-			nameColIdx = i
-			break
-		}
+		masterIdx[col] = i
 	}
-	if nameColIdx < 0 {
+	nameColIdx, ok := masterIdx["name"]
+	if !ok {
 		return nil, fmt.Errorf("no 'name' column in sqlite_master result")
 	}
 
@@ -354,7 +401,7 @@ func QueryLocalD1Schema(ctx context.Context, lr LocalResource) ([]LocalSchemaTab
 			continue
 		}
 
-		table := LocalSchemaTable{Name: tableName}
+		table := LocalSchemaTable{Name: tableName, SQL: localStrFromRow(row, masterIdx, "sql")}
 
 		// Step 2: Get columns via PRAGMA table_info
 		colResult, err := ExecuteLocalD1Query(ctx, lr,
@@ -367,8 +414,9 @@ func QueryLocalD1Schema(ctx context.Context, lr LocalResource) ([]LocalSchemaTab
 			}
 			for _, cr := range colResult.Rows {
 				col := LocalSchemaColumn{
-					Name: localStrFromRow(cr, colIdx, "name"),
-					Type: localStrFromRow(cr, colIdx, "type"),
+					Name:    localStrFromRow(cr, colIdx, "name"),
+					Type:    localStrFromRow(cr, colIdx, "type"),
+					Default: localStrFromRow(cr, colIdx, "dflt_value"),
 				}
 				if localNumFromRow(cr, colIdx, "notnull") == 1 {
 					col.NotNull = true
@@ -403,6 +451,27 @@ func QueryLocalD1Schema(ctx context.Context, lr LocalResource) ([]LocalSchemaTab
 		tables = append(tables, table)
 	}
 
+	// Step 4: Get explicit indexes (automatic ones have no SQL)
+	idxResult, err := ExecuteLocalD1Query(ctx, lr,
+		"SELECT name, tbl_name, sql FROM sqlite_master WHERE type='index' AND sql IS NOT NULL ORDER BY name")
+	if err == nil && len(idxResult.Columns) > 0 {
+		idxIdx := make(map[string]int)
+		for i, c := range idxResult.Columns {
+			idxIdx[c] = i
+		}
+		for _, ir := range idxResult.Rows {
+			tbl := localStrFromRow(ir, idxIdx, "tbl_name")
+			for i := range tables {
+				if tables[i].Name == tbl {
+					tables[i].Indexes = append(tables[i].Indexes, LocalSchemaIndex{
+						Name: localStrFromRow(ir, idxIdx, "name"),
+						SQL:  localStrFromRow(ir, idxIdx, "sql"),
+					})
+				}
+			}
+		}
+	}
+
 	return tables, nil
 }
 
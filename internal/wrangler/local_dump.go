@@ -0,0 +1,61 @@
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExportLocalD1 dumps a local D1 database as SQL into outPath via wrangler CLI.
+// Uses: npx wrangler d1 export <DB_NAME> --local --output <path> [--no-schema|--no-data] --config <path>
+func ExportLocalD1(ctx context.Context, lr LocalResource, noSchema, noData bool, outPath string) error {
+	args := []string{"wrangler", "d1", "export", lr.BindingName,
+		"--local",
+		"--output", outPath,
+		"--config", lr.ConfigPath,
+	}
+	if noSchema {
+		args = append(args, "--no-schema")
+	}
+	if noData {
+		args = append(args, "--no-data")
+	}
+	if lr.EnvName != "" && lr.EnvName != "default" {
+		args = append(args, "--env", lr.EnvName)
+	}
+	return runLocalD1(ctx, lr, args, "wrangler d1 export")
+}
+
+// ExecLocalD1Batch runs one or more semicolon-separated statements against a
+// local D1 database, discarding their results.
+// Uses: npx wrangler d1 execute <DB_NAME> --local --command="<SQL>" --json --config <path>
+func ExecLocalD1Batch(ctx context.Context, lr LocalResource, sql string) error {
+	args := []string{"wrangler", "d1", "execute", lr.BindingName,
+		"--local",
+		"--command=" + sql,
+		"--json",
+		"--config", lr.ConfigPath,
+	}
+	if lr.EnvName != "" && lr.EnvName != "default" {
+		args = append(args, "--env", lr.EnvName)
+	}
+	return runLocalD1(ctx, lr, args, "wrangler d1 execute")
+}
+
+func runLocalD1(ctx context.Context, lr LocalResource, args []string, what string) error {
+	cmd := exec.CommandContext(ctx, "npx", args...)
+	cmd.Dir = lr.ProjectDir
+	cmd.Env = append(os.Environ(), "CI=true")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		outStr := strings.TrimSpace(string(out))
+		if outStr != "" {
+			return fmt.Errorf("%s", outStr)
+		}
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package wrangler
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultMigrationsDir is where wrangler keeps D1 migrations unless a
+// binding sets migrations_dir.
+const defaultMigrationsDir = "migrations"
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_.*\.sql$`)
+
+// MigrationsDir returns the absolute migrations directory of a D1 binding
+// in the config at configPath.
+func MigrationsDir(configPath, migrationsDir string) string {
+	if migrationsDir == "" {
+		migrationsDir = defaultMigrationsDir
+	}
+	if filepath.IsAbs(migrationsDir) {
+		return migrationsDir
+	}
+	return filepath.Join(filepath.Dir(configPath), migrationsDir)
+}
+
+// NextMigrationPath returns the path of a new migration in dir, numbered
+// after the existing ones the way `wrangler d1 migrations create` does
+// (0001_name.sql, 0002_name.sql, ...).
+func NextMigrationPath(dir, name string) string {
+	next := 1
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if m := migrationFileRe.FindStringSubmatch(e.Name()); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	return filepath.Join(dir, padMigrationNumber(next)+"_"+name+".sql")
+}
+
+func padMigrationNumber(n int) string {
+	s := strconv.Itoa(n)
+	for len(s) < 4 {
+		s = "0" + s
+	}
+	return s
+}
@@ -0,0 +1,9 @@
+package wrangler
+
+import "syscall"
+
+// setParentDeathSignal has the kernel send SIGTERM to the group leader if
+// orangeshell dies without stopping it, e.g. when it is killed outright.
+func setParentDeathSignal(attr *syscall.SysProcAttr) {
+	attr.Pdeathsig = syscall.SIGTERM
+}
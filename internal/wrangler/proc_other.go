@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package wrangler
+
+import "syscall"
+
+// setParentDeathSignal is Linux-only; elsewhere main's signal handling and
+// Shutdown stop the process trees.
+func setParentDeathSignal(attr *syscall.SysProcAttr) {}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package wrangler
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts cmd as the leader of a new process group, so its
+// children can be signalled together. Out of the terminal's foreground
+// group, they no longer get its SIGHUP: main stops them on hangup instead.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	setParentDeathSignal(cmd.SysProcAttr)
+}
+
+// stopProcessTree sends SIGTERM to cmd's process group and SIGKILL after
+// grace if the group's leader is still running by then.
+func stopProcessTree(cmd *exec.Cmd, grace time.Duration) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	pgid := cmd.Process.Pid
+	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
+		return cmd.Process.Kill()
+	}
+	time.AfterFunc(grace, func() {
+		// Kill the rest of the group even if the leader already exited:
+		// workerd can outlive node.
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	})
+	return nil
+}
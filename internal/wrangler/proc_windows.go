@@ -0,0 +1,25 @@
+//go:build windows
+
+package wrangler
+
+import (
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// setProcessGroup is a no-op on Windows: taskkill /T walks the process tree
+// instead.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// stopProcessTree kills cmd and every process it started.
+func stopProcessTree(cmd *exec.Cmd, grace time.Duration) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package wrangler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SettingsFileName is the optional repo settings file, read from the root
+// orangeshell discovers projects in.
+const SettingsFileName = ".orangeshell.toml"
+
+// RepoSettings holds the contents of .orangeshell.toml:
+//
+//	profile = "acme"                    # credential profile to use in this repo
+//
+//	[discovery]
+//	roots = [".", "../shared-workers"]  # trees to walk (default: the repo root)
+//	include = ["apps/*/workers/*"]      # globs of project dirs, any depth
+//	exclude = ["examples/**"]
+//	max_depth = 5
+//	workspaces = true                   # use npm/yarn/pnpm workspace globs
+//	gitignore = true                    # skip git-ignored directories
+//
+//	[groups]
+//	edge = ["apps/*/workers/*"]
+//	frontend = ["apps/web", "apps/docs"]
+//
+//	[tags]
+//	billing = ["apps/billing", "apps/invoices"]
+//	internal = ["tools/*"]
+//
+//	[d1_snapshots]
+//	databases = ["prod-db"]             # remote D1 databases, by name
+//	local = ["my-db"]                   # local D1 databases, by database_name
+//	interval = "24h"                    # default 24h
+//	keep = 7                            # snapshots kept per database (default 7)
+//	dir = ".orangeshell/d1-snapshots"   # relative to the repo root
+//
+//	[d1_queries.prod-db]                # saved D1 console queries, by database name
+//	active_users = "SELECT * FROM users WHERE active = 1"
+//
+//	[http_requests.api]                 # saved HTTP requests, by project name
+//	create_user = { method = "POST", path = "/users", headers = ["Content-Type: application/json"], body = '{"name":"a"}' }
+//
+// Groups partition the project list (a project is in the first group that
+// matches); a project can have any number of tags.
+type RepoSettings struct {
+	Profile   string                             `toml:"profile"`
+	Discovery DiscoverySettings                  `toml:"discovery"`
+	Groups    map[string][]string                `toml:"groups"`
+	Tags      map[string][]string                `toml:"tags"`
+	Snapshots SnapshotSettings                   `toml:"d1_snapshots"`
+	D1Queries map[string]map[string]string       `toml:"d1_queries"`
+	Requests  map[string]map[string]SavedRequest `toml:"http_requests"`
+
+	groupOrder []string // group names in file order
+	tagOrder   []string // tag names in file order
+}
+
+// SavedRequest is a request of the HTTP composer saved to a project's
+// collection. Path is relative to the target the request is sent to.
+type SavedRequest struct {
+	Method  string   `toml:"method"`
+	Path    string   `toml:"path"`
+	Headers []string `toml:"headers"` // "Name: value"
+	Body    string   `toml:"body"`
+}
+
+// DiscoverySettings controls how projects are found. Paths and globs are
+// relative to the repo root and use forward slashes.
+type DiscoverySettings struct {
+	Roots      []string `toml:"roots"`
+	Include    []string `toml:"include"`
+	Exclude    []string `toml:"exclude"`
+	MaxDepth   int      `toml:"max_depth"`
+	Workspaces *bool    `toml:"workspaces"`
+	Gitignore  *bool    `toml:"gitignore"`
+}
+
+// LoadRepoSettings reads .orangeshell.toml from root. A missing file yields
+// empty settings; a malformed one yields empty settings and an error.
+func LoadRepoSettings(root string) (*RepoSettings, error) {
+	s := &RepoSettings{}
+	path := filepath.Join(root, SettingsFileName)
+	if _, err := os.Stat(path); err != nil {
+		return s, nil
+	}
+	md, err := toml.DecodeFile(path, s)
+	if err != nil {
+		return &RepoSettings{}, fmt.Errorf("%s: %w", SettingsFileName, err)
+	}
+	for _, key := range md.Keys() {
+		if len(key) != 2 {
+			continue
+		}
+		switch key[0] {
+		case "groups":
+			s.groupOrder = append(s.groupOrder, key[1])
+		case "tags":
+			s.tagOrder = append(s.tagOrder, key[1])
+		}
+	}
+	return s, nil
+}
+
+// GroupNames returns the configured group names in file order.
+func (s *RepoSettings) GroupNames() []string {
+	return s.groupOrder
+}
+
+// GroupOf returns the first group whose patterns match a project directory
+// (relative to the repo root, slash-separated), or "".
+func (s *RepoSettings) GroupOf(rel string) string {
+	for _, name := range s.groupOrder {
+		for _, pattern := range s.Groups[name] {
+			if matchGlob(pattern, rel) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// TagNames returns the configured tag names in file order.
+func (s *RepoSettings) TagNames() []string {
+	return s.tagOrder
+}
+
+// TagsOf returns the tags whose patterns match a project directory
+// (relative to the repo root, slash-separated), in file order.
+func (s *RepoSettings) TagsOf(rel string) []string {
+	var tags []string
+	for _, name := range s.tagOrder {
+		for _, pattern := range s.Tags[name] {
+			if matchGlob(pattern, rel) {
+				tags = append(tags, name)
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// AddProjectTag tags a project directory (relative to root) by adding it to
+// the tag's list in root's .orangeshell.toml, creating the file if needed.
+func AddProjectTag(root, rel, tag string) error {
+	path := []string{"tags", tag}
+	rel = filepath.ToSlash(rel)
+	return editSettings(root, func(doc *tomlDoc) error {
+		list, ok := doc.ArrayStrings(path)
+		if !ok {
+			return doc.Set(path, "["+tomlString(rel)+"]")
+		}
+		for _, p := range list {
+			if cleanGlob(p) == rel {
+				return nil
+			}
+		}
+		return doc.ArrayAppend(path, tomlString(rel))
+	})
+}
+
+// RemoveProjectTag removes a project directory from a tag's list. A project
+// tagged by a glob pattern can't be untagged individually.
+func RemoveProjectTag(root, rel, tag string) error {
+	path := []string{"tags", tag}
+	rel = filepath.ToSlash(rel)
+	return editSettings(root, func(doc *tomlDoc) error {
+		list, _ := doc.ArrayStrings(path)
+		for i, p := range list {
+			if cleanGlob(p) != rel {
+				continue
+			}
+			if len(list) == 1 {
+				_, err := doc.Delete(path)
+				return err
+			}
+			return doc.ArrayRemove(path, i)
+		}
+		return fmt.Errorf("%s is tagged %q by a pattern; edit %s to untag it", rel, tag, SettingsFileName)
+	})
+}
+
+// SaveD1Query stores a named query of a D1 database in root's
+// .orangeshell.toml, replacing one of the same name.
+func SaveD1Query(root, database, name, sql string) error {
+	return editSettings(root, func(doc *tomlDoc) error {
+		return doc.Set([]string{"d1_queries", database, name}, tomlString(sql))
+	})
+}
+
+// DeleteD1Query removes a named query of a D1 database.
+func DeleteD1Query(root, database, name string) error {
+	return editSettings(root, func(doc *tomlDoc) error {
+		ok, err := doc.Delete([]string{"d1_queries", database, name})
+		if err == nil && !ok {
+			err = fmt.Errorf("no saved query %q", name)
+		}
+		return err
+	})
+}
+
+// SaveHTTPRequest stores a named request in a project's collection in root's
+// .orangeshell.toml, replacing one of the same name. The file is meant to be
+// committed, so credential headers are saved without their values; it
+// returns the names of those headers.
+func SaveHTTPRequest(root, project, name string, req SavedRequest) ([]string, error) {
+	var redacted []string
+	headers := make([]string, len(req.Headers))
+	for i, h := range req.Headers {
+		if hname, _, ok := strings.Cut(h, ":"); ok && credentialHeader(hname) {
+			hname = strings.TrimSpace(hname)
+			redacted = append(redacted, hname)
+			h = hname + ":"
+		}
+		headers[i] = tomlString(h)
+	}
+	fields := []string{"method = " + tomlString(req.Method), "path = " + tomlString(req.Path)}
+	if len(headers) > 0 {
+		fields = append(fields, "headers = ["+strings.Join(headers, ", ")+"]")
+	}
+	if req.Body != "" {
+		fields = append(fields, "body = "+tomlString(req.Body))
+	}
+	return redacted, editSettings(root, func(doc *tomlDoc) error {
+		return doc.Set([]string{"http_requests", project, name}, "{ "+strings.Join(fields, ", ")+" }")
+	})
+}
+
+// credentialHeader reports whether a header carries a credential:
+// Authorization and cookies, and API keys, tokens and secrets by name.
+func credentialHeader(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	for _, s := range []string{"auth", "key", "token", "secret", "password", "session"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteHTTPRequest removes a named request from a project's collection.
+func DeleteHTTPRequest(root, project, name string) error {
+	return editSettings(root, func(doc *tomlDoc) error {
+		ok, err := doc.Delete([]string{"http_requests", project, name})
+		if err == nil && !ok {
+			err = fmt.Errorf("no saved request %q", name)
+		}
+		return err
+	})
+}
+
+// editSettings applies a format-preserving edit to root's .orangeshell.toml.
+func editSettings(root string, edit func(*tomlDoc) error) error {
+	path := filepath.Join(root, SettingsFileName)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", SettingsFileName, err)
+	}
+	doc, err := parseTOMLDoc(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", SettingsFileName, err)
+	}
+	if err := edit(doc); err != nil {
+		return err
+	}
+	return os.WriteFile(path, doc.Bytes(), 0644)
+}
@@ -43,18 +43,27 @@ type Runner struct {
 	cancel  context.CancelFunc
 	linesCh chan OutputLine
 	doneCh  chan RunResult
+	exited  chan struct{} // closed once the process has been waited for, or failed to start
 
 	mu      sync.Mutex
 	started bool // once true, never reset — enforces single-use
 	running bool
 }
 
+// live holds the runners whose processes are running, so StopAll can reach
+// them from outside the UI, e.g. from a signal handler.
+var live = struct {
+	sync.Mutex
+	runners map[*Runner]struct{}
+}{runners: make(map[*Runner]struct{})}
+
 // NewRunner creates a Runner. It does NOT start the command — call Start().
 // Each Runner is single-use.
 func NewRunner() *Runner {
 	return &Runner{
 		linesCh: make(chan OutputLine, 256),
 		doneCh:  make(chan RunResult, 1),
+		exited:  make(chan struct{}),
 	}
 }
 
@@ -86,6 +95,10 @@ func buildArgs(wcmd Command) []string {
 // scannerBufSize is the max line size for reading command output (1 MB).
 const scannerBufSize = 1024 * 1024
 
+// stopGrace is how long a stopped command's process tree has to exit after
+// SIGTERM before it is killed.
+const stopGrace = 3 * time.Second
+
 // Start begins executing the wrangler command in a background goroutine.
 // Output lines are sent to LinesCh(). When all output is consumed,
 // LinesCh() is closed. Then the final RunResult is sent to DoneCh().
@@ -104,6 +117,13 @@ func (r *Runner) Start(ctx context.Context, wcmd Command) error {
 	args := buildArgs(wcmd)
 	r.cmd = exec.CommandContext(runCtx, "npx", args...)
 
+	// npx starts node, which starts workerd for wrangler dev: run them in their
+	// own process group so stopping the command takes down the whole tree
+	// instead of orphaning the server (and its port).
+	setProcessGroup(r.cmd)
+	cmd := r.cmd
+	r.cmd.Cancel = func() error { return stopProcessTree(cmd, stopGrace) }
+
 	// Set CI=true so wrangler skips all interactive prompts (we have no stdin pipe).
 	// Also pass account ID via environment variable so wrangler doesn't prompt
 	// for account selection.
@@ -122,23 +142,29 @@ func (r *Runner) Start(ctx context.Context, wcmd Command) error {
 	stdout, err := r.cmd.StdoutPipe()
 	if err != nil {
 		cancel()
+		close(r.exited)
 		return fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	stderr, err := r.cmd.StderrPipe()
 	if err != nil {
 		cancel()
+		close(r.exited)
 		return fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
 	if err := r.cmd.Start(); err != nil {
 		cancel()
+		close(r.exited)
 		return fmt.Errorf("failed to start wrangler: %w", err)
 	}
 
 	r.mu.Lock()
 	r.running = true
 	r.mu.Unlock()
+	live.Lock()
+	live.runners[r] = struct{}{}
+	live.Unlock()
 
 	// Read stdout and stderr concurrently, merge into linesCh
 	var wg sync.WaitGroup
@@ -217,6 +243,10 @@ func (r *Runner) Start(ctx context.Context, wcmd Command) error {
 		r.mu.Lock()
 		r.running = false
 		r.mu.Unlock()
+		live.Lock()
+		delete(live.runners, r)
+		live.Unlock()
+		close(r.exited)
 
 		r.doneCh <- RunResult{ExitCode: exitCode, Err: err}
 		close(r.doneCh)
@@ -235,6 +265,43 @@ func (r *Runner) Stop() {
 	}
 }
 
+// Wait blocks until a started command has exited, or timeout passes.
+// Reports whether it exited. A runner that was never started, or failed to
+// start, counts as exited.
+func (r *Runner) Wait(timeout time.Duration) bool {
+	r.mu.Lock()
+	started := r.started
+	r.mu.Unlock()
+	if !started {
+		return true
+	}
+	select {
+	case <-r.exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// StopAll stops every running command and waits up to timeout for their
+// process trees to exit.
+func StopAll(timeout time.Duration) {
+	live.Lock()
+	runners := make([]*Runner, 0, len(live.runners))
+	for r := range live.runners {
+		runners = append(runners, r)
+	}
+	live.Unlock()
+
+	for _, r := range runners {
+		r.Stop()
+	}
+	deadline := time.Now().Add(timeout)
+	for _, r := range runners {
+		r.Wait(time.Until(deadline))
+	}
+}
+
 // IsRunning returns whether a command is currently executing.
 func (r *Runner) IsRunning() bool {
 	r.mu.Lock()
@@ -0,0 +1,264 @@
+package wrangler
+
+import "fmt"
+
+// Non-binding worker settings: static assets, observability, smart placement,
+// limits, tail consumers, logpush, custom build and module rules.
+//
+// Inheritance follows wrangler: every setting here except tail_consumers is
+// inheritable — a named environment that doesn't set it uses the top-level
+// value. tail_consumers must be repeated per environment.
+
+// AssetsConfig describes Workers Static Assets ([assets]).
+type AssetsConfig struct {
+	Directory        string
+	Binding          string
+	NotFoundHandling string   // "none", "404-page", "single-page-application"
+	HTMLHandling     string   // "auto-trailing-slash", "force-trailing-slash", ...
+	RunWorkerFirst   []string // ["*"] when set to true; route patterns when a list
+}
+
+// ObservabilityConfig describes Workers Logs settings ([observability]).
+type ObservabilityConfig struct {
+	Enabled          *bool
+	HeadSamplingRate *float64
+	LogsEnabled      *bool // [observability.logs] enabled
+	InvocationLogs   *bool // [observability.logs] invocation_logs
+}
+
+// PlacementConfig describes Smart Placement ([placement]).
+type PlacementConfig struct {
+	Mode string // "smart" or "off"
+	Hint string // optional region hint
+}
+
+// LimitsConfig describes runtime limits ([limits]).
+type LimitsConfig struct {
+	CPUMs       int
+	Subrequests int
+}
+
+// TailConsumer is a Worker receiving this Worker's tail events.
+type TailConsumer struct {
+	Service     string
+	Environment string
+}
+
+// BuildConfig describes a custom build step ([build]).
+type BuildConfig struct {
+	Command  string
+	Cwd      string
+	WatchDir []string
+}
+
+// ModuleRule describes a module import rule ([[rules]]).
+type ModuleRule struct {
+	Type        string // "ESModule", "CommonJS", "Text", "Data", "CompiledWasm", ...
+	Globs       []string
+	FallThrough bool
+}
+
+// WorkerSettings groups the non-binding settings of the top-level config or
+// a named environment. Nil/empty fields are unset.
+type WorkerSettings struct {
+	Assets        *AssetsConfig
+	Observability *ObservabilityConfig
+	Placement     *PlacementConfig
+	Limits        *LimitsConfig
+	TailConsumers []TailConsumer
+	Logpush       *bool
+	Build         *BuildConfig
+	Rules         []ModuleRule
+}
+
+// IsEmpty reports whether no setting is present.
+func (s WorkerSettings) IsEmpty() bool {
+	return s.Assets == nil && s.Observability == nil && s.Placement == nil &&
+		s.Limits == nil && len(s.TailConsumers) == 0 && s.Logpush == nil &&
+		s.Build == nil && len(s.Rules) == 0
+}
+
+// ResolvedSettings is the effective settings of one environment, with a flag
+// per inheritable key telling whether the value came from the top level.
+type ResolvedSettings struct {
+	WorkerSettings
+	Inherited map[string]bool // keyed by wrangler key: "assets", "observability", ...
+}
+
+// EnvSettings returns the effective non-binding settings for an environment,
+// applying wrangler's inheritance rules. For "default", returns the top-level
+// settings with nothing marked inherited.
+func (c *WranglerConfig) EnvSettings(envName string) ResolvedSettings {
+	top := c.Settings
+	res := ResolvedSettings{Inherited: make(map[string]bool)}
+	if envName == "" || envName == "default" {
+		res.WorkerSettings = top
+		return res
+	}
+
+	env, ok := c.Environments[envName]
+	if !ok {
+		return res
+	}
+	own := env.Settings
+	res.WorkerSettings = own
+
+	if own.Assets == nil && top.Assets != nil {
+		res.Assets, res.Inherited["assets"] = top.Assets, true
+	}
+	if own.Observability == nil && top.Observability != nil {
+		res.Observability, res.Inherited["observability"] = top.Observability, true
+	}
+	if own.Placement == nil && top.Placement != nil {
+		res.Placement, res.Inherited["placement"] = top.Placement, true
+	}
+	if own.Limits == nil && top.Limits != nil {
+		res.Limits, res.Inherited["limits"] = top.Limits, true
+	}
+	if own.Logpush == nil && top.Logpush != nil {
+		res.Logpush, res.Inherited["logpush"] = top.Logpush, true
+	}
+	if own.Build == nil && top.Build != nil {
+		res.Build, res.Inherited["build"] = top.Build, true
+	}
+	if len(own.Rules) == 0 && len(top.Rules) > 0 {
+		res.Rules, res.Inherited["rules"] = top.Rules, true
+	}
+	// tail_consumers is not inheritable
+	return res
+}
+
+// --- Internal raw types for deserialization ---
+
+// rawSettings is embedded in both rawConfig and rawEnv.
+type rawSettings struct {
+	Assets        *rawAssets        `toml:"assets" json:"assets"`
+	Observability *rawObservability `toml:"observability" json:"observability"`
+	Placement     *rawPlacement     `toml:"placement" json:"placement"`
+	Limits        *rawLimits        `toml:"limits" json:"limits"`
+	TailConsumers []rawTailConsumer `toml:"tail_consumers" json:"tail_consumers"`
+	Logpush       *bool             `toml:"logpush" json:"logpush"`
+	Build         *rawBuild         `toml:"build" json:"build"`
+	Rules         []rawRule         `toml:"rules" json:"rules"`
+}
+
+type rawAssets struct {
+	Directory        string `toml:"directory" json:"directory"`
+	Binding          string `toml:"binding" json:"binding"`
+	NotFoundHandling string `toml:"not_found_handling" json:"not_found_handling"`
+	HTMLHandling     string `toml:"html_handling" json:"html_handling"`
+	RunWorkerFirst   any    `toml:"run_worker_first" json:"run_worker_first"` // bool or []string
+}
+
+type rawObservability struct {
+	Enabled          *bool    `toml:"enabled" json:"enabled"`
+	HeadSamplingRate *float64 `toml:"head_sampling_rate" json:"head_sampling_rate"`
+	Logs             *struct {
+		Enabled        *bool `toml:"enabled" json:"enabled"`
+		InvocationLogs *bool `toml:"invocation_logs" json:"invocation_logs"`
+	} `toml:"logs" json:"logs"`
+}
+
+type rawPlacement struct {
+	Mode string `toml:"mode" json:"mode"`
+	Hint string `toml:"hint" json:"hint"`
+}
+
+type rawLimits struct {
+	CPUMs       int `toml:"cpu_ms" json:"cpu_ms"`
+	Subrequests int `toml:"subrequests" json:"subrequests"`
+}
+
+type rawTailConsumer struct {
+	Service     string `toml:"service" json:"service"`
+	Environment string `toml:"environment" json:"environment"`
+}
+
+type rawBuild struct {
+	Command  string `toml:"command" json:"command"`
+	Cwd      string `toml:"cwd" json:"cwd"`
+	WatchDir any    `toml:"watch_dir" json:"watch_dir"` // string or []string
+}
+
+type rawRule struct {
+	Type        string   `toml:"type" json:"type"`
+	Globs       []string `toml:"globs" json:"globs"`
+	FallThrough bool     `toml:"fallthrough" json:"fallthrough"`
+}
+
+// normalizeSettings converts the raw settings into WorkerSettings.
+func normalizeSettings(raw rawSettings) WorkerSettings {
+	var s WorkerSettings
+
+	if a := raw.Assets; a != nil {
+		s.Assets = &AssetsConfig{
+			Directory:        a.Directory,
+			Binding:          a.Binding,
+			NotFoundHandling: a.NotFoundHandling,
+			HTMLHandling:     a.HTMLHandling,
+		}
+		switch v := a.RunWorkerFirst.(type) {
+		case bool:
+			if v {
+				s.Assets.RunWorkerFirst = []string{"*"}
+			}
+		default:
+			s.Assets.RunWorkerFirst = stringList(v)
+		}
+	}
+
+	if o := raw.Observability; o != nil {
+		s.Observability = &ObservabilityConfig{
+			Enabled:          o.Enabled,
+			HeadSamplingRate: o.HeadSamplingRate,
+		}
+		if o.Logs != nil {
+			s.Observability.LogsEnabled = o.Logs.Enabled
+			s.Observability.InvocationLogs = o.Logs.InvocationLogs
+		}
+	}
+
+	if p := raw.Placement; p != nil {
+		s.Placement = &PlacementConfig{Mode: p.Mode, Hint: p.Hint}
+	}
+	if l := raw.Limits; l != nil {
+		s.Limits = &LimitsConfig{CPUMs: l.CPUMs, Subrequests: l.Subrequests}
+	}
+	for _, tc := range raw.TailConsumers {
+		if tc.Service != "" {
+			s.TailConsumers = append(s.TailConsumers, TailConsumer{Service: tc.Service, Environment: tc.Environment})
+		}
+	}
+	s.Logpush = raw.Logpush
+	if b := raw.Build; b != nil {
+		s.Build = &BuildConfig{Command: b.Command, Cwd: b.Cwd, WatchDir: stringList(b.WatchDir)}
+	}
+	for _, r := range raw.Rules {
+		s.Rules = append(s.Rules, ModuleRule{Type: r.Type, Globs: r.Globs, FallThrough: r.FallThrough})
+	}
+
+	return s
+}
+
+// stringList accepts a string or a list of values decoded into `any` (TOML
+// and JSON both produce []any / []interface{} for arrays).
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, fmt.Sprintf("%v", item))
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package wrangler
+
+import (
+	"reflect"
+	"testing"
+)
+
+const settingsFixture = `
+name = "api"
+main = "src/index.ts"
+logpush = true
+
+[assets]
+directory = "./public"
+binding = "ASSETS"
+not_found_handling = "single-page-application"
+run_worker_first = true
+
+[observability]
+enabled = true
+head_sampling_rate = 0.5
+
+[observability.logs]
+invocation_logs = false
+
+[placement]
+mode = "smart"
+
+[limits]
+cpu_ms = 100
+
+[build]
+command = "npm run build"
+watch_dir = "src"
+
+[[tail_consumers]]
+service = "log-sink"
+
+[[rules]]
+type = "Text"
+globs = ["**/*.md"]
+fallthrough = true
+
+[env.staging]
+name = "api-staging"
+
+[env.production]
+name = "api-production"
+logpush = false
+
+[env.production.placement]
+mode = "off"
+
+[env.production.limits]
+cpu_ms = 300
+subrequests = 100
+
+[env.production.assets]
+directory = "./dist"
+run_worker_first = ["/api/*", "/auth/*"]
+
+[[env.production.tail_consumers]]
+service = "log-sink"
+environment = "production"
+
+[[env.production.rules]]
+type = "Data"
+globs = ["**/*.bin"]
+`
+
+func TestEnvSettings(t *testing.T) {
+	cfg, err := parseTOML([]byte(settingsFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	yes, no, half := true, false, 0.5
+
+	topAssets := &AssetsConfig{Directory: "./public", Binding: "ASSETS", NotFoundHandling: "single-page-application", RunWorkerFirst: []string{"*"}}
+	topObservability := &ObservabilityConfig{Enabled: &yes, HeadSamplingRate: &half, InvocationLogs: &no}
+	topPlacement := &PlacementConfig{Mode: "smart"}
+	topLimits := &LimitsConfig{CPUMs: 100}
+	topBuild := &BuildConfig{Command: "npm run build", WatchDir: []string{"src"}}
+	topRules := []ModuleRule{{Type: "Text", Globs: []string{"**/*.md"}, FallThrough: true}}
+
+	tests := []struct {
+		env       string
+		want      WorkerSettings
+		inherited []string
+	}{
+		{
+			env: "default",
+			want: WorkerSettings{
+				Assets: topAssets, Observability: topObservability, Placement: topPlacement, Limits: topLimits,
+				TailConsumers: []TailConsumer{{Service: "log-sink"}}, Logpush: &yes, Build: topBuild, Rules: topRules,
+			},
+		},
+		{
+			// Everything but tail_consumers comes from the top level
+			env: "staging",
+			want: WorkerSettings{
+				Assets: topAssets, Observability: topObservability, Placement: topPlacement, Limits: topLimits,
+				Logpush: &yes, Build: topBuild, Rules: topRules,
+			},
+			inherited: []string{"assets", "build", "limits", "logpush", "observability", "placement", "rules"},
+		},
+		{
+			// Own values replace the top-level ones as a whole
+			env: "production",
+			want: WorkerSettings{
+				Assets:        &AssetsConfig{Directory: "./dist", RunWorkerFirst: []string{"/api/*", "/auth/*"}},
+				Observability: topObservability,
+				Placement:     &PlacementConfig{Mode: "off"},
+				Limits:        &LimitsConfig{CPUMs: 300, Subrequests: 100},
+				TailConsumers: []TailConsumer{{Service: "log-sink", Environment: "production"}},
+				Logpush:       &no,
+				Build:         topBuild,
+				Rules:         []ModuleRule{{Type: "Data", Globs: []string{"**/*.bin"}}},
+			},
+			inherited: []string{"build", "observability"},
+		},
+		{env: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			got := cfg.EnvSettings(tt.env)
+			if !reflect.DeepEqual(got.WorkerSettings, tt.want) {
+				t.Errorf("settings:\n got %+v\nwant %+v", got.WorkerSettings, tt.want)
+			}
+			var inherited []string
+			for _, key := range []string{"assets", "build", "limits", "logpush", "observability", "placement", "rules", "tail_consumers"} {
+				if got.Inherited[key] {
+					inherited = append(inherited, key)
+				}
+			}
+			if !reflect.DeepEqual(inherited, tt.inherited) {
+				t.Errorf("inherited = %v, want %v", inherited, tt.inherited)
+			}
+		})
+	}
+}
+
+func TestNormalizeSettingsJSON(t *testing.T) {
+	cfg, err := parseJSON([]byte(`{
+		"name": "api",
+		"assets": {"directory": "./public", "run_worker_first": false},
+		"build": {"command": "make", "watch_dir": ["src", "lib"]},
+		"tail_consumers": [{"service": ""}, {"service": "sink"}],
+		"env": {"preview": {"tail_consumers": [{"service": "preview-sink"}]}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := cfg.EnvSettings("default")
+	if s.Assets == nil || s.Assets.RunWorkerFirst != nil {
+		t.Errorf("assets = %+v, want run_worker_first unset", s.Assets)
+	}
+	if s.Build == nil || !reflect.DeepEqual(s.Build.WatchDir, []string{"src", "lib"}) {
+		t.Errorf("build = %+v", s.Build)
+	}
+	// Consumers without a service are dropped
+	if !reflect.DeepEqual(s.TailConsumers, []TailConsumer{{Service: "sink"}}) {
+		t.Errorf("tail consumers = %+v", s.TailConsumers)
+	}
+	preview := cfg.EnvSettings("preview")
+	if !reflect.DeepEqual(preview.TailConsumers, []TailConsumer{{Service: "preview-sink"}}) || preview.Inherited["tail_consumers"] {
+		t.Errorf("preview tail consumers = %+v", preview.TailConsumers)
+	}
+}
@@ -0,0 +1,169 @@
+package wrangler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotSettings configures scheduled local snapshots of D1 databases.
+type SnapshotSettings struct {
+	Databases []string `toml:"databases"`
+	Local     []string `toml:"local"`
+	Interval  string   `toml:"interval"`
+	Keep      int      `toml:"keep"`
+	Dir       string   `toml:"dir"`
+}
+
+const (
+	defaultSnapshotInterval = 24 * time.Hour
+	defaultSnapshotKeep     = 7
+	defaultSnapshotDir      = ".orangeshell/d1-snapshots"
+)
+
+// Every returns the snapshot interval, falling back to a day when it is
+// unset or malformed. Intervals under a minute are raised to a minute.
+func (s SnapshotSettings) Every() time.Duration {
+	d, err := time.ParseDuration(s.Interval)
+	if err != nil || d <= 0 {
+		return defaultSnapshotInterval
+	}
+	if d < time.Minute {
+		return time.Minute
+	}
+	return d
+}
+
+// KeepCount returns how many snapshots are kept per database.
+func (s SnapshotSettings) KeepCount() int {
+	if s.Keep <= 0 {
+		return defaultSnapshotKeep
+	}
+	return s.Keep
+}
+
+// Enabled reports whether a database is scheduled for snapshots.
+func (s SnapshotSettings) Enabled(name string, local bool) bool {
+	list := s.Databases
+	if local {
+		list = s.Local
+	}
+	for _, n := range list {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotDir returns the directory a database's snapshots are written to:
+// <root>/<dir>/{remote,local}/<name>.
+func (s SnapshotSettings) SnapshotDir(root, name string, local bool) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = defaultSnapshotDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, filepath.FromSlash(dir))
+	}
+	kind := "remote"
+	if local {
+		kind = "local"
+	}
+	return filepath.Join(dir, kind, snapshotFileName(name))
+}
+
+// SnapshotPath returns the file a snapshot taken at t is written to.
+func (s SnapshotSettings) SnapshotPath(root, name string, local bool, t time.Time) string {
+	file := fmt.Sprintf("%s-%s.sql", snapshotFileName(name), t.Format("20060102-150405"))
+	return filepath.Join(s.SnapshotDir(root, name, local), file)
+}
+
+// ListSnapshots returns a database's snapshot files, newest first.
+func (s SnapshotSettings) ListSnapshots(root, name string, local bool) []string {
+	dir := s.SnapshotDir(root, name, local)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := snapshotFileName(name) + "-"
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) && strings.HasSuffix(e.Name(), ".sql") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	// Timestamps in the names sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	return files
+}
+
+// SnapshotDue reports whether a database's newest snapshot is older than the
+// interval (or there is none).
+func (s SnapshotSettings) SnapshotDue(root, name string, local bool, now time.Time) bool {
+	files := s.ListSnapshots(root, name, local)
+	if len(files) == 0 {
+		return true
+	}
+	info, err := os.Stat(files[0])
+	if err != nil {
+		return true
+	}
+	return now.Sub(info.ModTime()) >= s.Every()
+}
+
+// PruneSnapshots deletes all but the newest KeepCount snapshots of a database.
+func (s SnapshotSettings) PruneSnapshots(root, name string, local bool) error {
+	files := s.ListSnapshots(root, name, local)
+	for i := s.KeepCount(); i < len(files); i++ {
+		if err := os.Remove(files[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotFileName makes a database name safe to use in a file name.
+func snapshotFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// SetD1Snapshot adds or removes a database from the scheduled snapshots in
+// root's .orangeshell.toml.
+func SetD1Snapshot(root, name string, local, enabled bool) error {
+	key := "databases"
+	if local {
+		key = "local"
+	}
+	path := []string{"d1_snapshots", key}
+	return editSettings(root, func(doc *tomlDoc) error {
+		list, ok := doc.ArrayStrings(path)
+		idx := -1
+		for i, n := range list {
+			if n == name {
+				idx = i
+			}
+		}
+		switch {
+		case enabled && !ok:
+			return doc.Set(path, "["+tomlString(name)+"]")
+		case enabled && idx < 0:
+			return doc.ArrayAppend(path, tomlString(name))
+		case !enabled && idx >= 0 && len(list) == 1:
+			_, err := doc.Delete(path)
+			return err
+		case !enabled && idx >= 0:
+			return doc.ArrayRemove(path, idx)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package wrangler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestD1SnapshotSettings(t *testing.T) {
+	root := t.TempDir()
+	for _, step := range []struct {
+		name           string
+		local, enabled bool
+	}{{"prod-db", false, true}, {"dev-db", true, true}, {"logs", false, true}, {"prod-db", false, true}, {"logs", false, false}} {
+		if err := SetD1Snapshot(root, step.name, step.local, step.enabled); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settings, err := LoadRepoSettings(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := settings.Snapshots
+	if !reflect.DeepEqual(s.Databases, []string{"prod-db"}) || !reflect.DeepEqual(s.Local, []string{"dev-db"}) {
+		t.Fatalf("got databases %v, local %v", s.Databases, s.Local)
+	}
+	if !s.Enabled("dev-db", true) || s.Enabled("dev-db", false) {
+		t.Fatal("Enabled should tell remote and local databases apart")
+	}
+	if s.Every() != 24*time.Hour || s.KeepCount() != 7 {
+		t.Fatalf("defaults: interval %v, keep %d", s.Every(), s.KeepCount())
+	}
+}
+
+func TestD1SnapshotPrune(t *testing.T) {
+	root := t.TempDir()
+	s := SnapshotSettings{Interval: "1h", Keep: 2}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if !s.SnapshotDue(root, "db", false, now) {
+		t.Fatal("a database without snapshots should be due")
+	}
+	for i := 0; i < 4; i++ {
+		path := s.SnapshotPath(root, "db", false, now.Add(time.Duration(i)*time.Hour))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("-- dump\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.SnapshotDue(root, "db", false, time.Now()) {
+		t.Fatal("a fresh snapshot should not be due")
+	}
+	if err := s.PruneSnapshots(root, "db", false); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range s.ListSnapshots(root, "db", false) {
+		got = append(got, filepath.Base(f))
+	}
+	want := []string{"db-20260301-150000.sql", "db-20260301-140000.sql"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
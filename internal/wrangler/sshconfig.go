@@ -0,0 +1,117 @@
+package wrangler
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sshConfigPath returns the user's OpenSSH client config. Overridden in tests.
+var sshConfigPath = func() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// resolveSSHHost maps an SSH host alias to the HostName configured for it in
+// ~/.ssh/config (e.g. "work-gh" → "github.com"). Like ssh, the first
+// matching HostName wins. Returns alias unchanged when nothing matches.
+func resolveSSHHost(alias string) string {
+	path := sshConfigPath()
+	if path == "" {
+		return alias
+	}
+	if hostName := lookupSSHHostName(path, filepath.Dir(path), alias, 0); hostName != "" {
+		return strings.ReplaceAll(hostName, "%h", alias)
+	}
+	return alias
+}
+
+// lookupSSHHostName scans one ssh config file for the HostName applying to
+// alias, following Include directives (relative paths resolve against
+// baseDir, i.e. ~/.ssh). Match blocks are skipped.
+func lookupSSHHostName(path, baseDir, alias string, depth int) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	active := true // options before the first Host block apply to every host
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		keyword, args := splitSSHConfigLine(scanner.Text())
+		switch keyword {
+		case "host":
+			active = matchSSHHost(args, alias)
+		case "match":
+			active = false
+		case "include":
+			if !active || depth >= 8 {
+				continue
+			}
+			for _, pattern := range args {
+				if strings.HasPrefix(pattern, "~/") {
+					if home, err := os.UserHomeDir(); err == nil {
+						pattern = filepath.Join(home, pattern[2:])
+					}
+				} else if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(baseDir, pattern)
+				}
+				matches, _ := filepath.Glob(pattern)
+				for _, inc := range matches {
+					if hostName := lookupSSHHostName(inc, baseDir, alias, depth+1); hostName != "" {
+						return hostName
+					}
+				}
+			}
+		case "hostname":
+			if active && len(args) > 0 {
+				return args[0]
+			}
+		}
+	}
+	return ""
+}
+
+// splitSSHConfigLine splits "Keyword value..." or "Keyword=value" into a
+// lowercase keyword and its whitespace-separated arguments.
+func splitSSHConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	keyword := strings.ToLower(line[:end])
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimPrefix(rest, "=")
+	var args []string
+	for _, arg := range strings.Fields(rest) {
+		args = append(args, strings.Trim(arg, `"`))
+	}
+	return keyword, args
+}
+
+// matchSSHHost reports whether host matches a Host line's patterns. Patterns
+// support * and ? wildcards; a matching "!pattern" excludes the host.
+func matchSSHHost(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	matched := false
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.ToLower(strings.TrimPrefix(pattern, "!"))
+		if ok, _ := filepath.Match(pattern, host); ok {
+			if negate {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
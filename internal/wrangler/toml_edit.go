@@ -0,0 +1,834 @@
+package wrangler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlDoc is a comment- and formatting-preserving editor for wrangler.toml.
+// The source is split into statements (table headers, key/value pairs and
+// trivia lines) with byte spans; values keep a span tree for arrays and
+// inline tables. Each edit splices only the affected bytes and re-parses.
+//
+// A path addresses a value regardless of how the file spells it: a key under
+// a [table] header, a dotted key (vars.FOO = ...) or a member of an inline
+// table (vars = { FOO = ... }) all resolve the same way.
+type tomlDoc struct {
+	src    []byte
+	stmts  []tomlStmt
+	tables []tomlTable
+	unit   string
+}
+
+type tomlStmtKind int
+
+const (
+	tomlTrivia tomlStmtKind = iota // blank or comment-only line
+	tomlHeader                     // [table] or [[array.of.tables]]
+	tomlKeyVal                     // key = value
+)
+
+// tomlStmt is one logical line. Multi-line arrays and strings make a
+// statement span several physical lines.
+type tomlStmt struct {
+	kind     tomlStmtKind
+	start    int // first byte of the line
+	end      int // past the trailing newline
+	key      []string
+	keyStart int
+	value    *tomlValue // key/value statements
+	table    int        // index of the owning table in tomlDoc.tables
+}
+
+// tomlTable is the root table or a table opened by a header.
+type tomlTable struct {
+	path    []string
+	entry   bool // declared as [[path]]
+	array   bool // an array-of-tables entry or one of its sub-tables
+	header  int  // statement index, -1 for the root table
+	keyvals []int
+}
+
+// tomlValue is a value span; arrays and inline tables keep their items.
+type tomlValue struct {
+	kind    byte // '[', '{', or 0 for scalars
+	start   int
+	end     int
+	elems   []*tomlValue
+	members []tomlMember
+}
+
+// tomlMember is an inline-table member.
+type tomlMember struct {
+	key      []string
+	keyStart int
+	value    *tomlValue
+}
+
+// tomlField is a key/value pair for a new [[table]] entry. Literal is
+// already-formatted TOML (see tomlString).
+type tomlField struct {
+	Key     string
+	Literal string
+}
+
+// parseTOMLDoc parses TOML source for editing.
+func parseTOMLDoc(src []byte) (*tomlDoc, error) {
+	d := &tomlDoc{src: src, unit: detectIndentUnit(src)}
+	if err := d.reparse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the current document text.
+func (d *tomlDoc) Bytes() []byte {
+	return d.src
+}
+
+func (d *tomlDoc) reparse() error {
+	var check map[string]any
+	if _, err := toml.Decode(string(d.src), &check); err != nil {
+		return fmt.Errorf("invalid TOML config: %w", err)
+	}
+
+	d.stmts = nil
+	d.tables = []tomlTable{{header: -1}}
+	var arrayPaths [][]string
+	cur := 0
+
+	p := &tomlScanner{src: d.src}
+	for p.pos < len(d.src) {
+		st := tomlStmt{start: p.pos, table: cur}
+		p.skipSpace()
+		switch c := p.peek(); {
+		case c == 0 || c == '\n' || c == '\r' || c == '#':
+			st.kind = tomlTrivia
+		case c == '[':
+			st.kind = tomlHeader
+			array := p.peekAt(1) == '['
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			p.skipSpace()
+			st.keyStart = p.pos
+			st.key = p.key()
+			p.skipSpace()
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			inArray := array
+			for _, ap := range arrayPaths {
+				if len(st.key) > len(ap) && hasPathPrefix(st.key, ap) {
+					inArray = true
+				}
+			}
+			if array {
+				arrayPaths = append(arrayPaths, st.key)
+			}
+			d.tables = append(d.tables, tomlTable{path: st.key, entry: array, array: inArray, header: len(d.stmts)})
+			cur = len(d.tables) - 1
+			st.table = cur
+		default:
+			st.kind = tomlKeyVal
+			st.keyStart = p.pos
+			st.key = p.key()
+			p.skipSpace()
+			p.pos++ // '='
+			p.skipSpace()
+			st.value = p.value()
+			d.tables[cur].keyvals = append(d.tables[cur].keyvals, len(d.stmts))
+		}
+		p.toNextLine()
+		st.end = p.pos
+		d.stmts = append(d.stmts, st)
+	}
+	return nil
+}
+
+// fullPath returns the absolute path of a key/value statement.
+func (d *tomlDoc) fullPath(si int) []string {
+	st := d.stmts[si]
+	t := d.tables[st.table]
+	return append(append([]string{}, t.path...), st.key...)
+}
+
+// tomlRef locates a value: a key/value statement, or a member of an inline
+// table held by that statement.
+type tomlRef struct {
+	stmt   int
+	value  *tomlValue
+	parent *tomlValue // inline table holding the member; nil for statement values
+	member int
+}
+
+// lookup resolves path to a value outside array-of-tables entries.
+func (d *tomlDoc) lookup(path []string) (tomlRef, bool) {
+	for si, st := range d.stmts {
+		if st.kind != tomlKeyVal || d.tables[st.table].array {
+			continue
+		}
+		full := d.fullPath(si)
+		if equalPath(full, path) {
+			return tomlRef{stmt: si, value: st.value}, true
+		}
+		if len(path) > len(full) && hasPathPrefix(path, full) {
+			v, rest := st.value, path[len(full):]
+			for v.kind == '{' {
+				next := -1
+				for mi, m := range v.members {
+					if equalPath(m.key, rest) {
+						return tomlRef{stmt: si, value: m.value, parent: v, member: mi}, true
+					}
+					if len(rest) > len(m.key) && hasPathPrefix(rest, m.key) {
+						next = mi
+					}
+				}
+				if next < 0 {
+					break
+				}
+				rest = rest[len(v.members[next].key):]
+				v = v.members[next].value
+			}
+		}
+	}
+	return tomlRef{}, false
+}
+
+// Has reports whether anything is defined at or below path: a value, a table
+// header or an array-of-tables entry.
+func (d *tomlDoc) Has(path []string) bool {
+	if _, ok := d.lookup(path); ok {
+		return true
+	}
+	for _, t := range d.tables[1:] {
+		if hasPathPrefix(t.path, path) {
+			return true
+		}
+	}
+	for si, st := range d.stmts {
+		if st.kind == tomlKeyVal && hasPathPrefix(d.fullPath(si), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// table returns the index of the (non-array) table with exactly this path.
+func (d *tomlDoc) table(path []string) int {
+	for i, t := range d.tables {
+		if i > 0 && !t.array && equalPath(t.path, path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set sets the value at path to a TOML literal. Existing values are replaced
+// in place; new keys go into the matching [table], next to sibling dotted
+// keys, or into an inline table — a new [table] is created only when none
+// of those exist.
+func (d *tomlDoc) Set(path []string, literal string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	if ref, ok := d.lookup(path); ok {
+		d.src = splice(d.src, ref.value.start, ref.value.end, literal)
+		return d.reparse()
+	}
+
+	// Inside an existing inline table (or error if a scalar is in the way)
+	for k := len(path) - 1; k >= 1; k-- {
+		ref, ok := d.lookup(path[:k])
+		if !ok {
+			continue
+		}
+		if ref.value.kind != '{' {
+			return fmt.Errorf("cannot set %s: %s is not a table", joinPath(path), joinPath(path[:k]))
+		}
+		v := ref.value
+		member := formatTOMLKey(path[k:]) + " = " + literal
+		d.src = insertSeqItem(d.src, v.start, v.end-1, v.memberSpans(), member,
+			func(string) string { return member }, seqStyle{syn: tomlComments, pad: " "})
+		return d.reparse()
+	}
+
+	parent := path[:len(path)-1]
+	if len(parent) == 0 {
+		return d.insertKeyVal(0, path, literal)
+	}
+	if ti := d.table(parent); ti >= 0 {
+		return d.insertKeyVal(ti, path[len(parent):], literal)
+	}
+
+	// Next to dotted keys that already define the parent (vars.A = "1")
+	lastSibling := -1
+	for si, st := range d.stmts {
+		if st.kind == tomlKeyVal && !d.tables[st.table].array && len(st.key) > 1 &&
+			hasPathPrefix(d.fullPath(si), parent) {
+			lastSibling = si
+		}
+	}
+	if lastSibling >= 0 {
+		st := d.stmts[lastSibling]
+		key := path[len(d.tables[st.table].path):]
+		d.src = splice(d.src, st.end, st.end, d.newlineIfNeeded(st.end)+
+			lineIndent(d.src, st.start)+formatTOMLKey(key)+" = "+literal+"\n")
+		return d.reparse()
+	}
+
+	block := "[" + formatTOMLKey(parent) + "]\n" + formatTOMLKey(path[len(parent):]) + " = " + literal + "\n"
+	return d.insertTableBlock(parent, block)
+}
+
+// insertKeyVal adds key = literal after the last key/value of table ti.
+func (d *tomlDoc) insertKeyVal(ti int, key []string, literal string) error {
+	t := d.tables[ti]
+	pos, indent := 0, ""
+	switch {
+	case len(t.keyvals) > 0:
+		last := d.stmts[t.keyvals[len(t.keyvals)-1]]
+		pos, indent = last.end, lineIndent(d.src, last.start)
+	case t.header >= 0:
+		pos = d.stmts[t.header].end
+	}
+	d.src = splice(d.src, pos, pos, d.newlineIfNeeded(pos)+indent+formatTOMLKey(key)+" = "+literal+"\n")
+	return d.reparse()
+}
+
+// newlineIfNeeded returns "\n" when pos is at the end of a file that lacks
+// a final newline.
+func (d *tomlDoc) newlineIfNeeded(pos int) string {
+	if pos > 0 && pos == len(d.src) && d.src[pos-1] != '\n' {
+		return "\n"
+	}
+	return ""
+}
+
+// Delete removes the value, table or dotted keys at path (including all
+// sub-tables and array-of-tables entries below it). Returns false if
+// nothing was there.
+func (d *tomlDoc) Delete(path []string) (bool, error) {
+	if ref, ok := d.lookup(path); ok {
+		if ref.parent != nil {
+			v := ref.parent
+			d.src = removeSeqItem(d.src, v.start, v.end-1, v.memberSpans(), ref.member, tomlComments)
+		} else {
+			d.removeSpan(d.stmtSpan(ref.stmt))
+		}
+		return true, d.reparse()
+	}
+
+	// Collect every table and dotted key under path, then remove bottom-up
+	var spans []span
+	for ti := len(d.tables) - 1; ti >= 1; ti-- {
+		if hasPathPrefix(d.tables[ti].path, path) {
+			spans = append(spans, d.tableSpan(ti))
+		}
+	}
+	for si := len(d.stmts) - 1; si >= 0; si-- {
+		st := d.stmts[si]
+		if st.kind == tomlKeyVal && !d.tables[st.table].array && !hasPathPrefix(d.tables[st.table].path, path) &&
+			hasPathPrefix(d.fullPath(si), path) {
+			spans = append(spans, d.stmtSpan(si))
+		}
+	}
+	if len(spans) == 0 {
+		return false, nil
+	}
+	d.removeSpans(spans)
+	return true, d.reparse()
+}
+
+// arrayTables returns the table indexes of the [[path]] entries, in order.
+func (d *tomlDoc) arrayTables(path []string) []int {
+	var out []int
+	for ti, t := range d.tables {
+		if t.entry && equalPath(t.path, path) {
+			out = append(out, ti)
+		}
+	}
+	return out
+}
+
+// AppendArrayTable adds an entry to the array of tables at path. When the
+// array is written inline (path = [{ ... }]) the entry is appended as an
+// inline table instead of a [[path]] block.
+func (d *tomlDoc) AppendArrayTable(path []string, fields []tomlField) error {
+	if ref, ok := d.lookup(path); ok {
+		if ref.value.kind != '[' {
+			return fmt.Errorf("cannot add to %s: not an array", joinPath(path))
+		}
+		parts := make([]string, len(fields))
+		for i, f := range fields {
+			parts[i] = formatTOMLKey([]string{f.Key}) + " = " + f.Literal
+		}
+		return d.ArrayAppend(path, "{ "+strings.Join(parts, ", ")+" }")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[[" + formatTOMLKey(path) + "]]\n")
+	for _, f := range fields {
+		sb.WriteString(formatTOMLKey([]string{f.Key}) + " = " + f.Literal + "\n")
+	}
+	return d.insertTableBlock(path, sb.String())
+}
+
+// RemoveArrayTable removes the first entry of the array of tables at path
+// whose field equals value — a [[path]] block with its sub-tables, or an
+// element of an inline array. Returns false if no entry matched.
+func (d *tomlDoc) RemoveArrayTable(path []string, field, value string) (bool, error) {
+	for _, ti := range d.arrayTables(path) {
+		for _, si := range d.tables[ti].keyvals {
+			st := d.stmts[si]
+			if equalPath(st.key, []string{field}) && d.scalarString(st.value) == value {
+				s := d.tableSpan(ti)
+				// Sub-tables of this entry ([path.simple]) go with it
+				for next := ti + 1; next < len(d.tables) && len(d.tables[next].path) > len(path) &&
+					hasPathPrefix(d.tables[next].path, path) && !d.tables[next].entry; next++ {
+					s.end = d.tableSpan(next).end
+				}
+				d.removeSpan(s)
+				return true, d.reparse()
+			}
+		}
+	}
+
+	if ref, ok := d.lookup(path); ok && ref.value.kind == '[' {
+		for i, e := range ref.value.elems {
+			if e.kind != '{' {
+				continue
+			}
+			for _, m := range e.members {
+				if equalPath(m.key, []string{field}) && d.scalarString(m.value) == value {
+					return true, d.ArrayRemove(path, i)
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// ArrayAppend appends a literal to the array at path, keeping its layout.
+func (d *tomlDoc) ArrayAppend(path []string, literal string) error {
+	ref, ok := d.lookup(path)
+	if !ok || ref.value.kind != '[' {
+		return fmt.Errorf("%s is not an array", joinPath(path))
+	}
+	v := ref.value
+	d.src = insertSeqItem(d.src, v.start, v.end-1, v.elemSpans(), literal,
+		func(string) string { return literal }, seqStyle{syn: tomlComments, unit: d.unit})
+	return d.reparse()
+}
+
+// ArrayRemove removes element i of the array at path.
+func (d *tomlDoc) ArrayRemove(path []string, i int) error {
+	ref, ok := d.lookup(path)
+	if !ok || ref.value.kind != '[' || i < 0 || i >= len(ref.value.elems) {
+		return fmt.Errorf("%s has no element %d", joinPath(path), i)
+	}
+	v := ref.value
+	d.src = removeSeqItem(d.src, v.start, v.end-1, v.elemSpans(), i, tomlComments)
+	return d.reparse()
+}
+
+// ArrayStrings returns the string elements of the array at path.
+func (d *tomlDoc) ArrayStrings(path []string) ([]string, bool) {
+	ref, ok := d.lookup(path)
+	if !ok || ref.value.kind != '[' {
+		return nil, false
+	}
+	out := make([]string, len(ref.value.elems))
+	for i, e := range ref.value.elems {
+		out[i] = d.scalarString(e)
+	}
+	return out, true
+}
+
+// insertTableBlock inserts a new [table] or [[table]] block for path:
+//   - after the last table at or below path (other [[path]] entries),
+//   - else after the tables of the closest existing ancestor ([env.x]),
+//   - else, for top-level keys, before the first [env.*] table, since
+//     wrangler configs conventionally keep environments last,
+//   - else at the end of the file.
+func (d *tomlDoc) insertTableBlock(path []string, block string) error {
+	for k := len(path); k >= 1; k-- {
+		last := -1
+		for ti := 1; ti < len(d.tables); ti++ {
+			if hasPathPrefix(d.tables[ti].path, path[:k]) {
+				last = ti
+			}
+		}
+		if last >= 0 {
+			pos := d.tableBodyEnd(last)
+			d.src = splice(d.src, pos, pos, d.newlineIfNeeded(pos)+"\n"+block)
+			return d.reparse()
+		}
+	}
+
+	if path[0] != "env" {
+		for ti := 1; ti < len(d.tables); ti++ {
+			if d.tables[ti].path[0] == "env" {
+				hs := d.stmts[d.tables[ti].header].start
+				pos := precedingComments(d.src, hs, tomlComments)
+				d.src = splice(d.src, pos, pos, block+"\n")
+				return d.reparse()
+			}
+		}
+	}
+
+	pos := len(d.src)
+	sep := "\n"
+	if pos == 0 {
+		sep = ""
+	}
+	d.src = splice(d.src, pos, pos, d.newlineIfNeeded(pos)+sep+block)
+	return d.reparse()
+}
+
+// tableBodyEnd returns the offset just past the last key/value of a table
+// (or its header, if it has none).
+func (d *tomlDoc) tableBodyEnd(ti int) int {
+	t := d.tables[ti]
+	if len(t.keyvals) > 0 {
+		return d.stmts[t.keyvals[len(t.keyvals)-1]].end
+	}
+	return d.stmts[t.header].end
+}
+
+// tableSpan returns the span of a table from its leading comments to the end
+// of its body, including comment lines directly below it (commented-out
+// keys). Comments separated from the body by a blank line belong to whatever
+// follows and are kept.
+func (d *tomlDoc) tableSpan(ti int) span {
+	hs := d.stmts[d.tables[ti].header].start
+	start := precedingComments(d.src, hs, tomlComments)
+	end := d.tableBodyEnd(ti)
+	if end > 0 && end < len(d.src) && d.src[end-1] != '\n' {
+		end = lineEnd(d.src, end) + 1
+	}
+	for end < len(d.src) {
+		le := lineEnd(d.src, end)
+		if !isCommentLine(d.src[end:le], tomlComments) {
+			break
+		}
+		end = min(le+1, len(d.src))
+	}
+	if ti+1 < len(d.tables) {
+		next := d.stmts[d.tables[ti+1].header].start
+		if nc := precedingComments(d.src, next, tomlComments); nc < end && nc >= d.tableBodyEnd(ti) {
+			end = nc
+		}
+	}
+	return span{start, end}
+}
+
+// stmtSpan returns the span of a statement and its leading comment lines.
+func (d *tomlDoc) stmtSpan(si int) span {
+	st := d.stmts[si]
+	return span{precedingComments(d.src, st.start, tomlComments), st.end}
+}
+
+// removeSpan deletes s and tidies the blank lines around it.
+func (d *tomlDoc) removeSpan(s span) {
+	d.removeSpans([]span{s})
+}
+
+// removeSpans deletes several spans (merging overlapping ones), then tidies
+// the blank lines left at each removal point.
+func (d *tomlDoc) removeSpans(spans []span) {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.start <= merged[n-1].end {
+			if s.end > merged[n-1].end {
+				merged[n-1].end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	// Removal points in the final text
+	points := make([]int, len(merged))
+	removed := 0
+	for i, s := range merged {
+		points[i] = s.start - removed
+		removed += s.end - s.start
+	}
+	for i := len(merged) - 1; i >= 0; i-- {
+		d.src = splice(d.src, merged[i].start, merged[i].end, "")
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		d.src = collapseBlankLines(d.src, points[i])
+	}
+}
+
+// scalarString decodes a scalar value (strings unquoted); other values are
+// returned as written.
+func (d *tomlDoc) scalarString(v *tomlValue) string {
+	text := string(d.src[v.start:v.end])
+	if v.kind != 0 {
+		return text
+	}
+	var out struct{ V any }
+	if _, err := toml.Decode("V = "+text, &out); err == nil {
+		if s, ok := out.V.(string); ok {
+			return s
+		}
+	}
+	return text
+}
+
+func (v *tomlValue) elemSpans() []span {
+	spans := make([]span, len(v.elems))
+	for i, e := range v.elems {
+		spans[i] = span{e.start, e.end}
+	}
+	return spans
+}
+
+func (v *tomlValue) memberSpans() []span {
+	spans := make([]span, len(v.members))
+	for i, m := range v.members {
+		spans[i] = span{m.keyStart, m.value.end}
+	}
+	return spans
+}
+
+// --- Scanner ---
+
+// tomlScanner scans TOML that has already been validated by the decoder, so
+// it only needs to find boundaries, not report syntax errors.
+type tomlScanner struct {
+	src []byte
+	pos int
+}
+
+func (p *tomlScanner) peek() byte {
+	return p.peekAt(0)
+}
+
+func (p *tomlScanner) peekAt(off int) byte {
+	if p.pos+off < len(p.src) {
+		return p.src[p.pos+off]
+	}
+	return 0
+}
+
+func (p *tomlScanner) skipSpace() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments (inside arrays).
+func (p *tomlScanner) skipBlank() {
+	for {
+		switch c := p.peek(); c {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.pos = lineEnd(p.src, p.pos)
+		default:
+			return
+		}
+	}
+}
+
+// toNextLine skips the rest of the line (trailing comment included) and the
+// newline itself.
+func (p *tomlScanner) toNextLine() {
+	p.pos = lineEnd(p.src, p.pos)
+	if p.pos < len(p.src) {
+		p.pos++
+	}
+}
+
+// key scans a (possibly dotted, possibly quoted) key.
+func (p *tomlScanner) key() []string {
+	var parts []string
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case '"', '\'':
+			start := p.pos
+			p.str()
+			var out struct{ V string }
+			if _, err := toml.Decode("V = "+string(p.src[start:p.pos]), &out); err == nil {
+				parts = append(parts, out.V)
+			} else {
+				parts = append(parts, string(p.src[start+1:p.pos-1]))
+			}
+		default:
+			start := p.pos
+			for c := p.peek(); isBareKeyChar(c); c = p.peek() {
+				p.pos++
+			}
+			parts = append(parts, string(p.src[start:p.pos]))
+		}
+		p.skipSpace()
+		if p.peek() != '.' {
+			return parts
+		}
+		p.pos++
+	}
+}
+
+// str scans a basic, literal or multi-line string.
+func (p *tomlScanner) str() {
+	q := p.peek()
+	delim := string([]byte{q})
+	if p.peekAt(1) == q && p.peekAt(2) == q {
+		delim = strings.Repeat(delim, 3)
+	}
+	p.pos += len(delim)
+	for p.pos < len(p.src) {
+		if q == '"' && p.src[p.pos] == '\\' {
+			p.pos += 2
+			continue
+		}
+		if strings.HasPrefix(string(p.src[p.pos:]), delim) {
+			p.pos += len(delim)
+			// Multi-line strings may end with up to two extra quotes
+			for n := 0; len(delim) == 3 && n < 2 && p.peek() == q; n++ {
+				p.pos++
+			}
+			return
+		}
+		p.pos++
+	}
+}
+
+var tomlDateTimeRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[ ]\d`)
+
+// value scans a value and returns its span tree.
+func (p *tomlScanner) value() *tomlValue {
+	v := &tomlValue{start: p.pos}
+	switch p.peek() {
+	case '"', '\'':
+		p.str()
+	case '[':
+		v.kind = '['
+		p.pos++
+		for {
+			p.skipBlank()
+			if p.peek() == ']' || p.peek() == 0 {
+				p.pos++
+				break
+			}
+			v.elems = append(v.elems, p.value())
+			p.skipBlank()
+			if p.peek() == ',' {
+				p.pos++
+			}
+		}
+	case '{':
+		v.kind = '{'
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.peek() == '}' || p.peek() == 0 {
+				p.pos++
+				break
+			}
+			keyStart := p.pos
+			key := p.key()
+			p.skipSpace()
+			p.pos++ // '='
+			p.skipSpace()
+			v.members = append(v.members, tomlMember{key: key, keyStart: keyStart, value: p.value()})
+			p.skipSpace()
+			if p.peek() == ',' {
+				p.pos++
+			}
+		}
+	default:
+		// Scalars: numbers, booleans, dates. Local date-times may contain a space.
+		if tomlDateTimeRe.Match(p.src[p.pos:]) {
+			p.pos += 11
+		}
+		for c := p.peek(); c != 0 && !strings.ContainsRune(" \t\r\n,]}#", rune(c)); c = p.peek() {
+			p.pos++
+		}
+	}
+	v.end = p.pos
+	return v
+}
+
+// --- Path and formatting helpers ---
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// formatTOMLKey renders a dotted key, quoting segments that aren't bare keys.
+func formatTOMLKey(path []string) string {
+	parts := make([]string, len(path))
+	for i, seg := range path {
+		parts[i] = seg
+		if seg == "" {
+			parts[i] = `""`
+			continue
+		}
+		for j := 0; j < len(seg); j++ {
+			if !isBareKeyChar(seg[j]) {
+				parts[i] = tomlString(seg)
+				break
+			}
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlString renders a TOML basic string literal.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func equalPath(a, b []string) bool {
+	return len(a) == len(b) && hasPathPrefix(a, b)
+}
+
+func hasPathPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,324 @@
+package wrangler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the watcher waits for a burst of file events
+// (editor atomic saves, git checkouts, formatters) to settle before reporting.
+const watchDebounce = 300 * time.Millisecond
+
+// WatchEvent is one settled batch of changes under a watched tree.
+type WatchEvent struct {
+	// Configs are the known config files whose content changed.
+	Configs []string
+	// Rediscovered is true when projects were added or removed; Projects then
+	// holds the new project list (possibly empty).
+	Rediscovered bool
+	Projects     []ProjectInfo
+	// SettingsErr is set when .orangeshell.toml became malformed; a later
+	// event with Rediscovered set and no SettingsErr means it was fixed.
+	SettingsErr error
+}
+
+// Watcher watches wrangler config files for changes. When rooted, it also
+// watches the directory tree the projects were discovered in and re-runs
+// DiscoverProjects when projects appear or disappear.
+type Watcher struct {
+	fsw    *fsnotify.Watcher
+	root   string // "" = watch only the given projects, never rediscover
+	events chan WatchEvent
+	done   chan struct{}
+	once   sync.Once
+
+	// Owned by the run goroutine after NewWatcher returns
+	configs     map[string]string // known config path → projectLabels
+	projectDirs map[string]bool   // directories holding a known config
+	dirs        map[string]bool   // every watched directory
+	scan        *scanner          // discovery rules of the last tree scan
+	settingsErr string            // last reported .orangeshell.toml error
+}
+
+// NewWatcher starts watching the given projects. If root is non-empty, the
+// directories under root that discovery searches are watched as well.
+func NewWatcher(root string, projects []ProjectInfo) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		fsw:    fsw,
+		events: make(chan WatchEvent),
+		done:   make(chan struct{}),
+		dirs:   make(map[string]bool),
+	}
+	if root != "" {
+		if w.root, err = filepath.Abs(root); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+	w.setProjects(projects)
+	for dir := range w.projectDirs {
+		w.watchDir(dir)
+	}
+	if w.root != "" {
+		w.watchTree()
+	}
+	if len(w.dirs) == 0 {
+		fsw.Close()
+		return nil, errors.New("nothing to watch")
+	}
+	go w.run()
+	return w, nil
+}
+
+// Events returns the channel of settled changes. It is closed when the
+// watcher stops.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Close stops the watcher. Safe to call more than once.
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+func (w *Watcher) setProjects(projects []ProjectInfo) {
+	w.configs = make(map[string]string, len(projects))
+	w.projectDirs = make(map[string]bool, len(projects))
+	for _, p := range projects {
+		w.configs[p.ConfigPath] = projectLabels(p)
+		w.projectDirs[p.Dir] = true
+	}
+}
+
+func (w *Watcher) watchDir(dir string) {
+	if w.dirs[dir] {
+		return
+	}
+	if err := w.fsw.Add(dir); err == nil {
+		w.dirs[dir] = true
+	}
+}
+
+// watchTree watches every directory discovery searches under the root.
+// Project directories are watched but not descended into.
+func (w *Watcher) watchTree() {
+	w.scan, _ = newScanner(w.root)
+	w.scan.run(func(dir string) bool {
+		w.watchDir(dir)
+		return FindConfig(dir) == ""
+	})
+}
+
+// discoveryFiles are the files at the root that change what discovery finds.
+var discoveryFiles = map[string]bool{
+	SettingsFileName:      true,
+	"package.json":        true,
+	"pnpm-workspace.yaml": true,
+	".gitignore":          true,
+}
+
+func isConfigFileName(name string) bool {
+	for _, n := range configFileNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// classify records what an fsnotify event means for the watched projects.
+// Returns true if the event is relevant.
+func (w *Watcher) classify(ev fsnotify.Event, changed map[string]bool, rescan *bool) bool {
+	name := ev.Name
+	parent := filepath.Dir(name)
+	removed := ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename)
+
+	if isConfigFileName(filepath.Base(name)) {
+		if _, known := w.configs[name]; known {
+			if ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create) || removed {
+				changed[name] = true
+			}
+			// Atomic saves remove and recreate the file; rescanning tells
+			// those apart from a deleted project.
+			if removed && w.root != "" {
+				*rescan = true
+			}
+			return true
+		}
+		// A config appearing or disappearing next to an unknown one may
+		// add a project or change which file a project uses.
+		if (ev.Has(fsnotify.Create) || removed) && w.root != "" {
+			*rescan = true
+			return true
+		}
+		return false
+	}
+
+	if w.root == "" {
+		return false
+	}
+	base := filepath.Base(name)
+	if parent == w.root && discoveryFiles[base] || base == ".gitignore" && !w.projectDirs[parent] {
+		*rescan = true
+		return true
+	}
+	if w.projectDirs[parent] {
+		// Files and subdirectories inside a project don't affect discovery
+		return false
+	}
+	if ev.Has(fsnotify.Create) {
+		// New directories are watched by the rescan
+		info, err := os.Stat(name)
+		if err != nil || !info.IsDir() || w.scan.skip(name) {
+			return false
+		}
+		*rescan = true
+		return true
+	}
+	if removed && (w.dirs[name] || w.projectDirs[name]) {
+		// The kernel drops the watches of removed directories; forget them
+		// so they're re-added if the directories come back.
+		prefix := name + string(filepath.Separator)
+		for dir := range w.dirs {
+			if dir == name || strings.HasPrefix(dir, prefix) {
+				delete(w.dirs, dir)
+			}
+		}
+		*rescan = true
+		return true
+	}
+	return false
+}
+
+// flush turns the collected changes into a WatchEvent.
+func (w *Watcher) flush(changed map[string]bool, rescan bool) (WatchEvent, bool) {
+	var ev WatchEvent
+	if rescan {
+		// Watch first so nothing created during discovery is missed
+		w.watchTree()
+		projects, err := Discover(w.root)
+		errText := ""
+		if err != nil {
+			errText = err.Error()
+		}
+		if !w.sameProjects(projects) || errText != w.settingsErr {
+			ev.Rediscovered = true
+			ev.Projects = projects
+			ev.SettingsErr = err
+			w.settingsErr = errText
+			w.setProjects(projects)
+			for dir := range w.projectDirs {
+				w.watchDir(dir)
+			}
+		}
+	}
+	for path := range changed {
+		if _, known := w.configs[path]; !known {
+			continue
+		}
+		// Mid-rescan the file may be briefly missing; skip it unless there's
+		// no rediscovery to report the removal.
+		if _, err := os.Stat(path); err != nil && w.root != "" {
+			continue
+		}
+		ev.Configs = append(ev.Configs, path)
+	}
+	sort.Strings(ev.Configs)
+	return ev, ev.Rediscovered || len(ev.Configs) > 0
+}
+
+// projectLabels summarizes a project's group and tags, so regrouping or
+// retagging counts as a change of the project set.
+func projectLabels(p ProjectInfo) string {
+	return p.Group + "|" + strings.Join(p.Tags, ",")
+}
+
+func (w *Watcher) sameProjects(projects []ProjectInfo) bool {
+	if len(projects) != len(w.configs) {
+		return false
+	}
+	for _, p := range projects {
+		if labels, ok := w.configs[p.ConfigPath]; !ok || labels != projectLabels(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Watcher) run() {
+	defer close(w.events)
+
+	changed := make(map[string]bool)
+	rescan := false
+	var timer *time.Timer
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if !w.classify(ev, changed, &rescan) {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				timer.Reset(watchDebounce)
+			}
+			fire = timer.C
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			// Dropped events: re-check everything
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				for path := range w.configs {
+					changed[path] = true
+				}
+				rescan = w.root != ""
+				if timer == nil {
+					timer = time.NewTimer(watchDebounce)
+				} else {
+					timer.Reset(watchDebounce)
+				}
+				fire = timer.C
+			}
+
+		case <-fire:
+			fire = nil
+			ev, ok := w.flush(changed, rescan)
+			changed = make(map[string]bool)
+			rescan = false
+			if !ok {
+				continue
+			}
+			select {
+			case w.events <- ev:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
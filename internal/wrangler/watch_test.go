@@ -0,0 +1,75 @@
+package wrangler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func nextWatchEvent(t *testing.T, w *Watcher) WatchEvent {
+	t.Helper()
+	select {
+	case ev := <-w.Events():
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a watch event")
+		return WatchEvent{}
+	}
+}
+
+func TestWatcherReloadAndRediscover(t *testing.T) {
+	root := t.TempDir()
+	apiDir := filepath.Join(root, "apps", "api")
+	if err := os.MkdirAll(apiDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	apiConfig := filepath.Join(apiDir, "wrangler.toml")
+	if err := os.WriteFile(apiConfig, []byte("name = \"api\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWatcher(root, DiscoverProjects(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// A burst of writes to a known config settles into one change
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(apiConfig, []byte("name = \"api\"\nmain = \"src/index.ts\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ev := nextWatchEvent(t, w)
+	if ev.Rediscovered || len(ev.Configs) != 1 || ev.Configs[0] != apiConfig {
+		t.Fatalf("expected a change to %s, got %+v", apiConfig, ev)
+	}
+
+	// Files inside a project don't matter
+	if err := os.WriteFile(filepath.Join(apiDir, "README.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new project in a new directory is rediscovered
+	webDir := filepath.Join(root, "apps", "web")
+	if err := os.Mkdir(webDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(webDir, "wrangler.jsonc"), []byte(`{"name": "web"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ev = nextWatchEvent(t, w)
+	if !ev.Rediscovered || len(ev.Projects) != 2 {
+		t.Fatalf("expected 2 rediscovered projects, got %+v", ev)
+	}
+
+	// Removing a project is rediscovered too
+	if err := os.RemoveAll(apiDir); err != nil {
+		t.Fatal(err)
+	}
+	ev = nextWatchEvent(t, w)
+	if !ev.Rediscovered || len(ev.Projects) != 1 || ev.Projects[0].Dir != webDir {
+		t.Fatalf("expected only the web project, got %+v", ev)
+	}
+}
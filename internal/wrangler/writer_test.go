@@ -0,0 +1,185 @@
+package wrangler
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// writerOps are the edits every config in testdata/golden goes through. Each
+// runs against a fresh copy of the input.
+var writerOps = []struct {
+	name string
+	edit func(path string) error
+}{
+	{"AddEnvironment preview", func(p string) error { return AddEnvironment(p, "preview") }},
+	{"AddEnvironment staging", func(p string) error { return AddEnvironment(p, "staging") }},
+	{"DeleteEnvironment staging", func(p string) error { return DeleteEnvironment(p, "staging") }},
+	{"AddBinding d1", func(p string) error {
+		return AddBinding(p, "default", BindingDef{Type: "d1", BindingName: "ANALYTICS_DB", ResourceID: "d1-id", ResourceName: "analytics"})
+	}},
+	{"AddBinding kv staging", func(p string) error {
+		return AddBinding(p, "staging", BindingDef{Type: "kv", BindingName: "FLAGS", ResourceID: "flags-id"})
+	}},
+	{"AddBinding ai", func(p string) error {
+		return AddBinding(p, "default", BindingDef{Type: "ai", BindingName: "AI2"})
+	}},
+	{"AddBinding ratelimit", func(p string) error {
+		return AddBinding(p, "default", BindingDef{Type: "ratelimit", BindingName: "AUTH_LIMITER", ResourceID: "1002",
+			ExtraFields: map[string]string{"limit": "10", "period": "10"}})
+	}},
+	{"AddBinding send_email", func(p string) error {
+		return AddBinding(p, "default", BindingDef{Type: "send_email", BindingName: "MAILER",
+			ExtraFields: map[string]string{"allowed_destination_addresses": "a@example.com, b@example.com"}})
+	}},
+	{"RemoveBinding kv CACHE", func(p string) error { return RemoveBinding(p, "default", "CACHE", "kv_namespace") }},
+	{"RemoveBinding kv SESSIONS", func(p string) error { return RemoveBinding(p, "default", "SESSIONS", "kv_namespace") }},
+	{"RemoveBinding kv KV", func(p string) error { return RemoveBinding(p, "default", "KV", "kv_namespace") }},
+	{"RemoveBinding kv staging CACHE", func(p string) error { return RemoveBinding(p, "staging", "CACHE", "kv_namespace") }},
+	{"RemoveBinding d1 DB", func(p string) error { return RemoveBinding(p, "default", "DB", "d1") }},
+	{"RemoveBinding ai", func(p string) error { return RemoveBinding(p, "default", "AI", "ai") }},
+	{"RemoveBinding ratelimit", func(p string) error { return RemoveBinding(p, "default", "LIMITER", "ratelimit") }},
+	{"SetVar LOG_LEVEL", func(p string) error { return SetVar(p, "default", "LOG_LEVEL", "warn") }},
+	{"SetVar NEW_VAR", func(p string) error { return SetVar(p, "default", "NEW_VAR", `say "hi"`) }},
+	{"SetVar staging LOG_LEVEL", func(p string) error { return SetVar(p, "staging", "LOG_LEVEL", "trace") }},
+	{"SetVar dev API_HOST", func(p string) error { return SetVar(p, "dev", "API_HOST", "http://127.0.0.1:8787") }},
+	{"RemoveVar LOG_LEVEL", func(p string) error { return RemoveVar(p, "default", "LOG_LEVEL") }},
+	{"RemoveVar FEATURE_FLAG", func(p string) error { return RemoveVar(p, "default", "FEATURE_FLAG") }},
+	{"AddCron", func(p string) error { return AddCron(p, "30 2 * * 1") }},
+	{"RemoveCron", func(p string) error { return RemoveCron(p, "0 * * * *") }},
+	{"RemoveCron all", func(p string) error {
+		crons, err := parseCrons(p)
+		if err != nil {
+			return err
+		}
+		for _, c := range crons {
+			if err := RemoveCron(p, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+}
+
+func parseCrons(path string) ([]string, error) {
+	cfg, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.CronTriggers(), nil
+}
+
+// TestWriterGolden round-trips each config in testdata/golden through every
+// writer operation and compares the results with <config>.golden. Run with
+// -update to regenerate the golden files after an intended change.
+func TestWriterGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "golden", "*.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		if strings.HasSuffix(input, ".golden") {
+			continue
+		}
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out strings.Builder
+			for _, op := range writerOps {
+				path := filepath.Join(t.TempDir(), filepath.Base(input))
+				if err := os.WriteFile(path, src, 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				fmt.Fprintf(&out, "=== %s ===\n", op.name)
+				if err := op.edit(path); err != nil {
+					fmt.Fprintf(&out, "error: %v\n", err)
+					continue
+				}
+				result, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := Parse(path); err != nil {
+					t.Fatalf("%s: result does not parse: %v\n%s", op.name, err, result)
+				}
+				out.Write(result)
+			}
+
+			golden := input + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(out.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if out.String() != string(want) {
+				t.Fatalf("output differs from %s (run with -update to accept):\n%s", golden, out.String())
+			}
+		})
+	}
+}
+
+// TestWriterNoOpEdits checks that setting a value to what it already is
+// leaves the file byte-for-byte unchanged.
+func TestWriterNoOpEdits(t *testing.T) {
+	for _, name := range []string{"api.toml", "app.jsonc"} {
+		src, err := os.ReadFile(filepath.Join("testdata", "golden", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := SetVar(path, "default", "LOG_LEVEL", "info"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, _ := os.ReadFile(path)
+		if string(got) != string(src) {
+			t.Fatalf("%s: no-op SetVar changed the file:\n%s", name, got)
+		}
+	}
+}
+
+// TestRateLimitBinding checks that a rate limiter's binding keeps its
+// namespace ID as the resource ID, with the limit as its display name.
+func TestRateLimitBinding(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "golden", "api.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "api.toml")
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddBinding(path, "default", BindingDef{Type: "ratelimit", BindingName: "AUTH_LIMITER", ResourceID: "1002",
+		ExtraFields: map[string]string{"limit": "10", "period": "60"}}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range cfg.Bindings {
+		if b.Name == "AUTH_LIMITER" {
+			if b.ResourceID != "1002" || b.DisplayName != "10 per 60s" {
+				t.Fatalf("got ResourceID %q, DisplayName %q", b.ResourceID, b.DisplayName)
+			}
+			return
+		}
+	}
+	t.Fatalf("AUTH_LIMITER not found in %+v", cfg.Bindings)
+}
@@ -4,14 +4,22 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	zone "github.com/lrstanley/bubblezone"
+	"golang.org/x/term"
 
 	"github.com/oarafat/orangeshell/internal/config"
 	"github.com/oarafat/orangeshell/internal/ui/app"
+	"github.com/oarafat/orangeshell/internal/wrangler"
 )
 
+// shutdownTimeout bounds how long a hangup waits for dev servers to exit.
+const shutdownTimeout = 5 * time.Second
+
 func main() {
 	// Ensure Node.js and npm are available (npx ships with npm since v5.2+).
 	if _, err := exec.LookPath("npx"); err != nil {
@@ -25,6 +33,7 @@ func main() {
 		os.Exit(1)
 	}
 
+	config.PromptPassphrase = promptPassphrase
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -50,7 +59,14 @@ func main() {
 
 	model := app.NewModel(cfg, scanDir)
 
+	// From here on the TUI owns the terminal: a save that needs the
+	// passphrase of a new secrets file fails with config.ErrSecretsLocked,
+	// and the app asks for it from its event loop.
+	config.PromptPassphrase = nil
+	app.PromptPassphrase = promptPassphrase
+
 	p := tea.NewProgram(model,
+		tea.WithOutput(app.Output),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -59,8 +75,57 @@ func main() {
 	// background goroutine → UI communication (e.g., provisioning progress).
 	go func() { p.Send(app.SetProgramMsg{Program: p}) }()
 
-	if _, err := p.Run(); err != nil {
+	// Bubble Tea handles SIGINT and SIGTERM. Dev servers run outside the
+	// terminal's process group and miss its SIGHUP, so closing the terminal
+	// window must stop them here, as must SIGQUIT.
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP, syscall.SIGQUIT)
+	go func() {
+		<-hangup
+		wrangler.StopAll(shutdownTimeout)
+		p.Kill()
+	}()
+
+	final, err := p.Run()
+	// Dev servers run in their own process groups: stop them (and anything
+	// they spawned) rather than leave them holding their ports.
+	if fm, ok := final.(app.Model); ok {
+		fm.Shutdown()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running orangeshell: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// promptPassphrase reads the passphrase of the encrypted secrets file from the
+// terminal, asking twice when the file is being created.
+func promptPassphrase(create bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("credentials are encrypted: set ORANGESHELL_PASSPHRASE")
+	}
+	read := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(b), err
+	}
+
+	if !create {
+		return read("Passphrase for orangeshell credentials: ")
+	}
+	fmt.Fprintln(os.Stderr, "orangeshell stores credentials in an encrypted file (no system keyring found).")
+	p, err := read("Choose a passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	again, err := read("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p != again {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+	return p, nil
+}